@@ -1,8 +1,11 @@
 import express, { Request, Response, NextFunction } from 'express';
 import cors from 'cors';
 import helmet from 'helmet';
+import compression from 'compression';
 import { config } from './config/config';
 import analyticsRoutes from './routes/analyticsRoutes';
+import reportRoutes from './routes/reportRoutes';
+import experimentRoutes, { internalExperimentRouter } from './routes/experimentRoutes';
 
 const app = express();
 
@@ -26,6 +29,9 @@ app.use(cors({
   allowedHeaders: ['Content-Type', 'Authorization'],
 }));
 
+// Report and dashboard payloads are large and compress well
+app.use(compression());
+
 // Body parsing middleware
 app.use(express.json({ limit: '10mb' }));
 app.use(express.urlencoded({ extended: true, limit: '10mb' }));
@@ -54,6 +60,9 @@ app.get('/ready', (req, res) => {
 });
 
 // API routes
+app.use('/api/v1/analytics/reports', reportRoutes);
+app.use('/api/v1/analytics/experiments', experimentRoutes);
+app.use('/api/v1/analytics/internal/experiments', internalExperimentRouter);
 app.use('/api/v1/analytics', analyticsRoutes);
 
 // 404 handler
@@ -44,6 +44,12 @@ export const config = {
   ENROLLMENT_SERVICE_URL: process.env.ENROLLMENT_SERVICE_URL || 'http://localhost:3003',
   ASSESSMENT_SERVICE_URL: process.env.ASSESSMENT_SERVICE_URL || 'http://localhost:3004',
   PAYMENT_SERVICE_URL: process.env.PAYMENT_SERVICE_URL || 'http://localhost:3005',
+  NOTIFICATION_SERVICE_URL: process.env.NOTIFICATION_SERVICE_URL || 'http://localhost:3007',
+  INTERNAL_SERVICE_KEY: process.env.INTERNAL_SERVICE_KEY || '',
+  
+  // Scheduled reports
+  REPORT_SCHEDULER_INTERVAL_MS: parseInt(process.env.REPORT_SCHEDULER_INTERVAL_MS || '60000', 10), // 1 minute
+  REPORT_DELIVERY_TIMEOUT_MS: parseInt(process.env.REPORT_DELIVERY_TIMEOUT_MS || '10000', 10),
   
   // Logging
   LOG_LEVEL: process.env.LOG_LEVEL || 'info',
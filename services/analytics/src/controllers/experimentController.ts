@@ -0,0 +1,244 @@
+import { Request, Response } from 'express';
+import { experimentService, ExperimentValidationError } from '../services/experimentService';
+
+interface AuthenticatedRequest extends Request {
+  user?: {
+    id: string;
+    email: string;
+    role: string;
+  };
+}
+
+// Instructors manage their own experiments; admins manage all of them
+async function loadOwnedExperiment(req: AuthenticatedRequest, res: Response) {
+  const experiment = await experimentService.getExperiment(req.params.id);
+  if (!experiment || (req.user?.role !== 'admin' && experiment.createdBy !== req.user?.id)) {
+    res.status(404).json({ error: 'Experiment not found' });
+    return null;
+  }
+  return experiment;
+}
+
+function handleError(res: Response, action: string, error: unknown): void {
+  if (error instanceof ExperimentValidationError) {
+    res.status(400).json({ error: error.message });
+    return;
+  }
+  console.error(`Error ${action}:`, error);
+  res.status(500).json({
+    error: `Failed ${action}`,
+    details: error instanceof Error ? error.message : 'Unknown error'
+  });
+}
+
+export class ExperimentController {
+
+  async createExperiment(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const { key, name, description, surface, courseId, unit, variants, status } = req.body;
+
+      if (!key || !name || !surface || !variants) {
+        res.status(400).json({ error: 'key, name, surface and variants are required' });
+        return;
+      }
+
+      const experiment = await experimentService.createExperiment(
+        { key, name, description, surface, courseId, unit, variants, status },
+        req.user!.id
+      );
+
+      res.status(201).json({
+        success: true,
+        message: 'Experiment created successfully',
+        data: experiment,
+      });
+    } catch (error) {
+      handleError(res, 'to create experiment', error);
+    }
+  }
+
+  async listExperiments(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const experiments = await experimentService.listExperiments({
+        status: req.query.status as string,
+        courseId: req.query.courseId as string,
+        createdBy: req.user?.role === 'admin' ? undefined : req.user?.id,
+      });
+
+      res.json({
+        success: true,
+        data: experiments,
+      });
+    } catch (error) {
+      handleError(res, 'to list experiments', error);
+    }
+  }
+
+  async getExperiment(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const experiment = await loadOwnedExperiment(req, res);
+      if (!experiment) {
+        return;
+      }
+
+      res.json({
+        success: true,
+        data: experiment,
+      });
+    } catch (error) {
+      handleError(res, 'to get experiment', error);
+    }
+  }
+
+  async updateExperiment(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      if (!(await loadOwnedExperiment(req, res))) {
+        return;
+      }
+
+      const { key, name, description, surface, courseId, unit, variants, status } = req.body;
+      const experiment = await experimentService.updateExperiment(req.params.id, {
+        key, name, description, surface, courseId, unit, variants, status,
+      });
+
+      res.json({
+        success: true,
+        message: 'Experiment updated successfully',
+        data: experiment,
+      });
+    } catch (error) {
+      handleError(res, 'to update experiment', error);
+    }
+  }
+
+  async deleteExperiment(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      if (!(await loadOwnedExperiment(req, res))) {
+        return;
+      }
+
+      await experimentService.deleteExperiment(req.params.id);
+
+      res.json({
+        success: true,
+        message: 'Experiment deleted successfully',
+      });
+    } catch (error) {
+      handleError(res, 'to delete experiment', error);
+    }
+  }
+
+  async getResults(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      if (!(await loadOwnedExperiment(req, res))) {
+        return;
+      }
+
+      const results = await experimentService.getResults(req.params.id);
+
+      res.json({
+        success: true,
+        data: results,
+      });
+    } catch (error) {
+      handleError(res, 'to get experiment results', error);
+    }
+  }
+
+  // Assign the caller (or their tenant) to a variant of a running experiment
+  async assign(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const unitId = req.body.unitId || req.user?.id;
+      if (!unitId) {
+        res.status(400).json({ error: 'unitId is required for anonymous callers' });
+        return;
+      }
+
+      const assignment = await experimentService.assign(req.params.key, unitId);
+      if (!assignment) {
+        res.status(404).json({ error: 'Running experiment not found' });
+        return;
+      }
+
+      if (req.body.logExposure !== false) {
+        await experimentService.logExposures([{
+          experimentKey: assignment.experiment.key,
+          variantKey: assignment.variant.key,
+          unitId,
+          courseId: assignment.experiment.courseId || undefined,
+        }]);
+      }
+
+      res.json({
+        success: true,
+        data: {
+          experimentKey: assignment.experiment.key,
+          variant: assignment.variant.key,
+          config: assignment.variant.config || {},
+        },
+      });
+    } catch (error) {
+      handleError(res, 'to assign variant', error);
+    }
+  }
+
+  async trackMetric(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const { experimentKey, metric, value } = req.body;
+      const unitId = req.body.unitId || req.user?.id;
+
+      if (!experimentKey || !metric || !unitId) {
+        res.status(400).json({ error: 'experimentKey, metric and unitId are required' });
+        return;
+      }
+      if (value !== undefined && typeof value !== 'number') {
+        res.status(400).json({ error: 'value must be a number' });
+        return;
+      }
+
+      const recorded = await experimentService.recordMetric({ experimentKey, unitId, metric, value });
+      if (!recorded) {
+        res.status(404).json({ error: 'Running experiment not found' });
+        return;
+      }
+
+      res.status(201).json({
+        success: true,
+        message: 'Metric recorded successfully',
+      });
+    } catch (error) {
+      handleError(res, 'to record metric', error);
+    }
+  }
+
+  // Internal: running experiments for course-management to assign locally
+  async getActiveExperiments(req: Request, res: Response): Promise<void> {
+    try {
+      const experiments = await experimentService.getActiveExperiments(req.query.courseId as string);
+
+      res.json({
+        success: true,
+        data: experiments,
+      });
+    } catch (error) {
+      handleError(res, 'to get active experiments', error);
+    }
+  }
+
+  // Internal: batched exposure logging from other services
+  async logExposures(req: Request, res: Response): Promise<void> {
+    try {
+      const exposures = Array.isArray(req.body.exposures) ? req.body.exposures : [];
+      const valid = exposures.filter((e: any) => e && e.experimentKey && e.variantKey && e.unitId);
+
+      const recorded = await experimentService.logExposures(valid);
+
+      res.status(202).json({
+        success: true,
+        data: { received: exposures.length, recorded },
+      });
+    } catch (error) {
+      handleError(res, 'to log exposures', error);
+    }
+  }
+}
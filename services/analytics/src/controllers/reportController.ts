@@ -0,0 +1,168 @@
+import { Request, Response } from 'express';
+import { reportService, ReportOwner, ReportValidationError } from '../services/reportService';
+
+interface AuthenticatedRequest extends Request {
+  user?: {
+    id: string;
+    email: string;
+    role: string;
+  };
+}
+
+function ownerFrom(req: AuthenticatedRequest): ReportOwner {
+  return { id: req.user!.id, role: req.user!.role };
+}
+
+export class ReportController {
+
+  async createSubscription(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const { reportType, frequency, courseId, channel, destination, isActive } = req.body;
+
+      if (!reportType || !frequency || !channel || !destination) {
+        res.status(400).json({
+          error: 'reportType, frequency, channel and destination are required'
+        });
+        return;
+      }
+
+      const subscription = await reportService.createSubscription(
+        { reportType, frequency, courseId, channel, destination, isActive },
+        ownerFrom(req)
+      );
+
+      res.status(201).json({
+        success: true,
+        message: 'Report subscription created successfully',
+        data: subscription,
+      });
+    } catch (error) {
+      if (error instanceof ReportValidationError) {
+        res.status(400).json({ error: error.message });
+        return;
+      }
+      console.error('Error creating report subscription:', error);
+      res.status(500).json({
+        error: 'Failed to create report subscription',
+        details: error instanceof Error ? error.message : 'Unknown error'
+      });
+    }
+  }
+
+  async listSubscriptions(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const subscriptions = await reportService.listSubscriptions(ownerFrom(req));
+
+      res.json({
+        success: true,
+        data: subscriptions,
+      });
+    } catch (error) {
+      console.error('Error listing report subscriptions:', error);
+      res.status(500).json({
+        error: 'Failed to list report subscriptions',
+        details: error instanceof Error ? error.message : 'Unknown error'
+      });
+    }
+  }
+
+  async getSubscription(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const subscription = await reportService.getSubscription(req.params.id, ownerFrom(req));
+      if (!subscription) {
+        res.status(404).json({ error: 'Report subscription not found' });
+        return;
+      }
+
+      const runs = await reportService.listRuns(subscription.id);
+
+      res.json({
+        success: true,
+        data: { ...subscription, recentRuns: runs },
+      });
+    } catch (error) {
+      console.error('Error getting report subscription:', error);
+      res.status(500).json({
+        error: 'Failed to get report subscription',
+        details: error instanceof Error ? error.message : 'Unknown error'
+      });
+    }
+  }
+
+  async updateSubscription(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const { reportType, frequency, courseId, channel, destination, isActive } = req.body;
+
+      const subscription = await reportService.updateSubscription(
+        req.params.id,
+        { reportType, frequency, courseId, channel, destination, isActive },
+        ownerFrom(req)
+      );
+      if (!subscription) {
+        res.status(404).json({ error: 'Report subscription not found' });
+        return;
+      }
+
+      res.json({
+        success: true,
+        message: 'Report subscription updated successfully',
+        data: subscription,
+      });
+    } catch (error) {
+      if (error instanceof ReportValidationError) {
+        res.status(400).json({ error: error.message });
+        return;
+      }
+      console.error('Error updating report subscription:', error);
+      res.status(500).json({
+        error: 'Failed to update report subscription',
+        details: error instanceof Error ? error.message : 'Unknown error'
+      });
+    }
+  }
+
+  async deleteSubscription(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const deleted = await reportService.deleteSubscription(req.params.id, ownerFrom(req));
+      if (!deleted) {
+        res.status(404).json({ error: 'Report subscription not found' });
+        return;
+      }
+
+      res.json({
+        success: true,
+        message: 'Report subscription deleted successfully',
+      });
+    } catch (error) {
+      console.error('Error deleting report subscription:', error);
+      res.status(500).json({
+        error: 'Failed to delete report subscription',
+        details: error instanceof Error ? error.message : 'Unknown error'
+      });
+    }
+  }
+
+  // Generate and deliver a report now, without moving the schedule
+  async runSubscription(req: AuthenticatedRequest, res: Response): Promise<void> {
+    try {
+      const subscription = await reportService.getSubscription(req.params.id, ownerFrom(req));
+      if (!subscription) {
+        res.status(404).json({ error: 'Report subscription not found' });
+        return;
+      }
+
+      const run = await reportService.runSubscription(subscription, 'manual');
+
+      res.status(run.status === 'delivered' ? 200 : 502).json({
+        success: run.status === 'delivered',
+        data: run,
+      });
+    } catch (error) {
+      console.error('Error running report:', error);
+      res.status(500).json({
+        error: 'Failed to run report',
+        details: error instanceof Error ? error.message : 'Unknown error'
+      });
+    }
+  }
+}
@@ -2,6 +2,7 @@ import app from './app';
 import { config } from './config/config';
 import { testConnection, closeConnection } from './config/database';
 import { redisClient } from './config/redis';
+import { reportService } from './services/reportService';
 
 const PORT = config.PORT || 3006;
 
@@ -26,9 +27,17 @@ async function startServer() {
       console.log(`Redis: Connected`);
     });
 
+    // Run due report subscriptions on an interval
+    const reportScheduler = setInterval(() => {
+      reportService.runDueSubscriptions().catch((error) => {
+        console.error('Error running scheduled reports:', error);
+      });
+    }, config.REPORT_SCHEDULER_INTERVAL_MS);
+
     // Graceful shutdown
     const gracefulShutdown = async (signal: string) => {
       console.log(`\n${signal} received. Starting graceful shutdown...`);
+      clearInterval(reportScheduler);
       
       server.close(async () => {
         try {
@@ -1,5 +1,6 @@
 import { Request, Response, NextFunction } from 'express';
 import jwt from 'jsonwebtoken';
+import { timingSafeEqual } from 'crypto';
 import { config } from '../config/config';
 
 interface AuthenticatedRequest extends Request {
@@ -46,3 +47,30 @@ export function optionalAuth(req: AuthenticatedRequest, res: Response, next: Nex
     next();
   });
 }
+
+export function requireRole(...roles: string[]) {
+  return (req: AuthenticatedRequest, res: Response, next: NextFunction): void => {
+    if (!req.user || !roles.includes(req.user.role)) {
+      res.status(403).json({ error: `Access denied. ${roles.join(' or ')} role required` });
+      return;
+    }
+    next();
+  };
+}
+
+// Guards internal endpoints called by other platform services
+export function requireServiceKey(req: Request, res: Response, next: NextFunction): void {
+  const expected = config.INTERNAL_SERVICE_KEY;
+  if (!expected) {
+    res.status(503).json({ error: 'Internal service key not configured' });
+    return;
+  }
+
+  const provided = Buffer.from(req.get('X-Service-Key') || '');
+  const wanted = Buffer.from(expected);
+  if (provided.length !== wanted.length || !timingSafeEqual(provided, wanted)) {
+    res.status(401).json({ error: 'Invalid service key' });
+    return;
+  }
+  next();
+}
@@ -111,6 +111,127 @@ export const dailyAnalytics = pgTable('daily_analytics', {
   createdAt: timestamp('created_at').defaultNow().notNull(),
 });
 
+// Scheduled report enums
+export const reportTypeEnum = pgEnum('report_type', [
+  'weekly_engagement',
+  'pending_grading'
+]);
+
+export const reportFrequencyEnum = pgEnum('report_frequency', [
+  'daily',
+  'weekly',
+  'monthly'
+]);
+
+export const reportChannelEnum = pgEnum('report_channel', [
+  'email',
+  'webhook'
+]);
+
+export const reportRunStatusEnum = pgEnum('report_run_status', [
+  'running',
+  'delivered',
+  'failed'
+]);
+
+// Recurring report subscriptions for instructors and admins
+export const reportSubscriptions = pgTable('report_subscriptions', {
+  id: uuid('id').primaryKey().defaultRandom(),
+  ownerId: uuid('owner_id').notNull(),
+  ownerRole: varchar('owner_role', { length: 20 }).notNull(),
+  
+  // Report definition
+  reportType: reportTypeEnum('report_type').notNull(),
+  frequency: reportFrequencyEnum('frequency').notNull(),
+  courseId: uuid('course_id'), // optional scope; admins may omit for platform-wide reports
+  
+  // Delivery
+  channel: reportChannelEnum('channel').notNull(),
+  destination: text('destination').notNull(), // email address or webhook URL
+  webhookSecret: varchar('webhook_secret', { length: 64 }),
+  
+  // Scheduling
+  isActive: boolean('is_active').default(true).notNull(),
+  lastRunAt: timestamp('last_run_at'),
+  nextRunAt: timestamp('next_run_at').notNull(),
+  
+  // Timestamps
+  createdAt: timestamp('created_at').defaultNow().notNull(),
+  updatedAt: timestamp('updated_at').defaultNow().notNull(),
+});
+
+// History of generated reports
+export const reportRuns = pgTable('report_runs', {
+  id: uuid('id').primaryKey().defaultRandom(),
+  subscriptionId: uuid('subscription_id').notNull(),
+  trigger: varchar('trigger', { length: 20 }).notNull(), // 'schedule' or 'manual'
+  status: reportRunStatusEnum('status').notNull(),
+  report: text('report'), // JSON string
+  error: text('error'),
+  
+  // Timestamps
+  startedAt: timestamp('started_at').defaultNow().notNull(),
+  completedAt: timestamp('completed_at'),
+});
+
+// Experimentation enums
+export const experimentStatusEnum = pgEnum('experiment_status', [
+  'draft',
+  'running',
+  'paused',
+  'completed'
+]);
+
+export const experimentUnitEnum = pgEnum('experiment_unit', [
+  'user',
+  'tenant'
+]);
+
+// A/B experiments on catalog and pricing surfaces
+export const experiments = pgTable('experiments', {
+  id: uuid('id').primaryKey().defaultRandom(),
+  key: varchar('key', { length: 100 }).notNull().unique(),
+  name: varchar('name', { length: 255 }).notNull(),
+  description: text('description'),
+  
+  // Targeting
+  surface: varchar('surface', { length: 50 }).notNull(), // thumbnail, pricing, course_page_layout
+  courseId: uuid('course_id'), // null targets every course
+  unit: experimentUnitEnum('unit').default('user').notNull(),
+  variants: text('variants').notNull(), // JSON string: [{ key, weight, config }]
+  
+  // Lifecycle
+  status: experimentStatusEnum('status').default('draft').notNull(),
+  createdBy: uuid('created_by').notNull(),
+  startedAt: timestamp('started_at'),
+  endedAt: timestamp('ended_at'),
+  
+  // Timestamps
+  createdAt: timestamp('created_at').defaultNow().notNull(),
+  updatedAt: timestamp('updated_at').defaultNow().notNull(),
+});
+
+// First time a unit saw a variant
+export const experimentExposures = pgTable('experiment_exposures', {
+  id: uuid('id').primaryKey().defaultRandom(),
+  experimentId: uuid('experiment_id').notNull(),
+  variantKey: varchar('variant_key', { length: 100 }).notNull(),
+  unitId: varchar('unit_id', { length: 255 }).notNull(),
+  courseId: uuid('course_id'),
+  createdAt: timestamp('created_at').defaultNow().notNull(),
+});
+
+// Outcome events attributed to a variant (e.g. enrollment, revenue)
+export const experimentMetrics = pgTable('experiment_metrics', {
+  id: uuid('id').primaryKey().defaultRandom(),
+  experimentId: uuid('experiment_id').notNull(),
+  variantKey: varchar('variant_key', { length: 100 }).notNull(),
+  unitId: varchar('unit_id', { length: 255 }).notNull(),
+  metric: varchar('metric', { length: 100 }).notNull(),
+  value: decimal('value', { precision: 12, scale: 2 }).default('1').notNull(),
+  createdAt: timestamp('created_at').defaultNow().notNull(),
+});
+
 // Relations
 export const userEventsRelations = relations(userEvents, ({ one }) => ({
   userProgress: one(userProgress, {
@@ -126,3 +247,14 @@ export const courseAnalyticsRelations = relations(courseAnalytics, ({ many }) =>
 export const userProgressRelations = relations(userProgress, ({ many }) => ({
   events: many(userEvents),
 }));
+
+export const reportSubscriptionsRelations = relations(reportSubscriptions, ({ many }) => ({
+  runs: many(reportRuns),
+}));
+
+export const reportRunsRelations = relations(reportRuns, ({ one }) => ({
+  subscription: one(reportSubscriptions, {
+    fields: [reportRuns.subscriptionId],
+    references: [reportSubscriptions.id],
+  }),
+}));
@@ -0,0 +1,26 @@
+import { Router } from 'express';
+import { ExperimentController } from '../controllers/experimentController';
+import { authenticateToken, optionalAuth, requireRole, requireServiceKey } from '../middleware/auth';
+
+const router = Router();
+const experimentController = new ExperimentController();
+
+// Assignment and metric events (anonymous callers pass a unitId)
+router.post('/:key/assign', optionalAuth, experimentController.assign);
+router.post('/metrics', optionalAuth, experimentController.trackMetric);
+
+// Experiment management and results
+router.get('/', authenticateToken, requireRole('instructor', 'admin'), experimentController.listExperiments);
+router.post('/', authenticateToken, requireRole('instructor', 'admin'), experimentController.createExperiment);
+router.get('/:id', authenticateToken, requireRole('instructor', 'admin'), experimentController.getExperiment);
+router.put('/:id', authenticateToken, requireRole('instructor', 'admin'), experimentController.updateExperiment);
+router.delete('/:id', authenticateToken, requireRole('instructor', 'admin'), experimentController.deleteExperiment);
+router.get('/:id/results', authenticateToken, requireRole('instructor', 'admin'), experimentController.getResults);
+
+export const internalExperimentRouter = Router();
+
+// Used by course-management to assign variants without a call per request
+internalExperimentRouter.get('/active', requireServiceKey, experimentController.getActiveExperiments);
+internalExperimentRouter.post('/exposures', requireServiceKey, experimentController.logExposures);
+
+export default router;
@@ -0,0 +1,20 @@
+import { Router } from 'express';
+import { ReportController } from '../controllers/reportController';
+import { authenticateToken, requireRole } from '../middleware/auth';
+
+const router = Router();
+const reportController = new ReportController();
+
+// Scheduled report subscriptions are available to instructors and admins
+router.use(authenticateToken, requireRole('instructor', 'admin'));
+
+router.get('/subscriptions', reportController.listSubscriptions);
+router.post('/subscriptions', reportController.createSubscription);
+router.get('/subscriptions/:id', reportController.getSubscription);
+router.put('/subscriptions/:id', reportController.updateSubscription);
+router.delete('/subscriptions/:id', reportController.deleteSubscription);
+
+// On-demand run
+router.post('/subscriptions/:id/run', reportController.runSubscription);
+
+export default router;
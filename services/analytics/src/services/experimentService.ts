@@ -0,0 +1,362 @@
+import { eq, and, or, isNull, desc, sql, InferModel } from 'drizzle-orm';
+import { createHash } from 'crypto';
+import { db } from '../config/database';
+import { redisClient } from '../config/redis';
+import { experiments, experimentExposures, experimentMetrics } from '../models/schema';
+
+export type ExperimentStatus = 'draft' | 'running' | 'paused' | 'completed';
+export type ExperimentUnit = 'user' | 'tenant';
+
+export const EXPERIMENT_SURFACES = ['thumbnail', 'pricing', 'course_page_layout'];
+const EXPERIMENT_STATUSES: ExperimentStatus[] = ['draft', 'running', 'paused', 'completed'];
+const EXPERIMENT_UNITS: ExperimentUnit[] = ['user', 'tenant'];
+
+export interface ExperimentVariant {
+  key: string;
+  weight: number;
+  config?: Record<string, any>;
+}
+
+export interface ExperimentInput {
+  key: string;
+  name: string;
+  description?: string;
+  surface: string;
+  courseId?: string | null;
+  unit?: ExperimentUnit;
+  variants: ExperimentVariant[];
+  status?: ExperimentStatus;
+}
+
+export interface ExposureInput {
+  experimentKey: string;
+  variantKey: string;
+  unitId: string;
+  courseId?: string;
+}
+
+export interface MetricInput {
+  experimentKey: string;
+  unitId: string;
+  metric: string;
+  value?: number;
+}
+
+type ExperimentRow = InferModel<typeof experiments>;
+export type Experiment = Omit<ExperimentRow, 'variants'> & { variants: ExperimentVariant[] };
+
+export class ExperimentValidationError extends Error {}
+
+const EXPOSURE_DEDUPE_TTL = 30 * 24 * 60 * 60; // 30 days
+const ACTIVE_CACHE_TTL = 60;
+
+// Deterministically bucket a unit into a variant by weight. The Go services
+// use the same sha256("<experimentKey>:<unitId>") scheme, so assignments agree.
+export function assignVariant(experimentKey: string, unitId: string, variants: ExperimentVariant[]): ExperimentVariant {
+  const total = variants.reduce((sum, variant) => sum + variant.weight, 0);
+  const digest = createHash('sha256').update(`${experimentKey}:${unitId}`).digest();
+  let bucket = Number(digest.readBigUInt64BE(0) % BigInt(total));
+
+  for (const variant of variants) {
+    if (bucket < variant.weight) {
+      return variant;
+    }
+    bucket -= variant.weight;
+  }
+  return variants[variants.length - 1];
+}
+
+function toExperiment(row: ExperimentRow): Experiment {
+  return { ...row, variants: JSON.parse(row.variants) };
+}
+
+class ExperimentService {
+
+  validateExperiment(input: Partial<ExperimentInput>): void {
+    if (input.key !== undefined && !/^[a-z0-9][a-z0-9_-]{1,99}$/.test(input.key)) {
+      throw new ExperimentValidationError('key must be 2-100 lowercase letters, digits, "-" or "_"');
+    }
+    if (input.surface !== undefined && !EXPERIMENT_SURFACES.includes(input.surface)) {
+      throw new ExperimentValidationError(`surface must be one of: ${EXPERIMENT_SURFACES.join(', ')}`);
+    }
+    if (input.unit !== undefined && !EXPERIMENT_UNITS.includes(input.unit)) {
+      throw new ExperimentValidationError(`unit must be one of: ${EXPERIMENT_UNITS.join(', ')}`);
+    }
+    if (input.status !== undefined && !EXPERIMENT_STATUSES.includes(input.status)) {
+      throw new ExperimentValidationError(`status must be one of: ${EXPERIMENT_STATUSES.join(', ')}`);
+    }
+
+    if (input.variants !== undefined) {
+      if (!Array.isArray(input.variants) || input.variants.length < 2) {
+        throw new ExperimentValidationError('an experiment needs at least two variants');
+      }
+      const keys = new Set<string>();
+      for (const variant of input.variants) {
+        if (!variant.key || keys.has(variant.key)) {
+          throw new ExperimentValidationError('variant keys must be present and unique');
+        }
+        if (!Number.isInteger(variant.weight) || variant.weight <= 0) {
+          throw new ExperimentValidationError('variant weights must be positive integers');
+        }
+        keys.add(variant.key);
+      }
+    }
+  }
+
+  async createExperiment(input: ExperimentInput, createdBy: string): Promise<Experiment> {
+    this.validateExperiment(input);
+
+    const status = input.status || 'draft';
+    const [row] = await db
+      .insert(experiments)
+      .values({
+        key: input.key,
+        name: input.name,
+        description: input.description,
+        surface: input.surface,
+        courseId: input.courseId || null,
+        unit: input.unit || 'user',
+        variants: JSON.stringify(input.variants),
+        status,
+        createdBy,
+        startedAt: status === 'running' ? new Date() : null,
+      })
+      .returning();
+
+    await this.invalidateActive();
+    return toExperiment(row);
+  }
+
+  async listExperiments(filter: { status?: string; courseId?: string; createdBy?: string }): Promise<Experiment[]> {
+    const conditions = [];
+    if (filter.status) {
+      conditions.push(eq(experiments.status, filter.status as ExperimentStatus));
+    }
+    if (filter.courseId) {
+      conditions.push(eq(experiments.courseId, filter.courseId));
+    }
+    if (filter.createdBy) {
+      conditions.push(eq(experiments.createdBy, filter.createdBy));
+    }
+
+    const rows = await db
+      .select()
+      .from(experiments)
+      .where(conditions.length > 0 ? and(...conditions) : sql`true`)
+      .orderBy(desc(experiments.createdAt));
+
+    return rows.map(toExperiment);
+  }
+
+  async getExperiment(id: string): Promise<Experiment | null> {
+    const [row] = await db.select().from(experiments).where(eq(experiments.id, id));
+    return row ? toExperiment(row) : null;
+  }
+
+  async updateExperiment(id: string, input: Partial<ExperimentInput>): Promise<Experiment | null> {
+    const existing = await this.getExperiment(id);
+    if (!existing) {
+      return null;
+    }
+
+    // Changing assignment inputs mid-flight would reshuffle units between variants
+    if (existing.status !== 'draft' && (input.variants !== undefined || input.unit !== undefined || input.key !== undefined)) {
+      throw new ExperimentValidationError('key, unit and variants can only be changed while the experiment is a draft');
+    }
+    this.validateExperiment(input);
+
+    const updates: Partial<ExperimentRow> = { updatedAt: new Date() };
+    if (input.name !== undefined) updates.name = input.name;
+    if (input.description !== undefined) updates.description = input.description;
+    if (input.surface !== undefined) updates.surface = input.surface;
+    if (input.courseId !== undefined) updates.courseId = input.courseId || null;
+    if (input.key !== undefined) updates.key = input.key;
+    if (input.unit !== undefined) updates.unit = input.unit;
+    if (input.variants !== undefined) updates.variants = JSON.stringify(input.variants);
+    if (input.status !== undefined && input.status !== existing.status) {
+      updates.status = input.status;
+      if (input.status === 'running' && !existing.startedAt) {
+        updates.startedAt = new Date();
+      }
+      if (input.status === 'completed') {
+        updates.endedAt = new Date();
+      }
+    }
+
+    const [row] = await db
+      .update(experiments)
+      .set(updates)
+      .where(eq(experiments.id, id))
+      .returning();
+
+    await this.invalidateActive();
+    return toExperiment(row);
+  }
+
+  async deleteExperiment(id: string): Promise<boolean> {
+    const existing = await this.getExperiment(id);
+    if (!existing) {
+      return false;
+    }
+
+    await db.delete(experimentMetrics).where(eq(experimentMetrics.experimentId, id));
+    await db.delete(experimentExposures).where(eq(experimentExposures.experimentId, id));
+    await db.delete(experiments).where(eq(experiments.id, id));
+
+    await this.invalidateActive();
+    return true;
+  }
+
+  // Running experiments that apply to a course (or every course), cached briefly
+  async getActiveExperiments(courseId?: string): Promise<Experiment[]> {
+    const cacheKey = `experiments:active:${courseId || 'all'}`;
+    const cached = await redisClient.getCachedAnalyticsData(cacheKey);
+    if (cached) {
+      return cached;
+    }
+
+    const rows = await db
+      .select()
+      .from(experiments)
+      .where(
+        and(
+          eq(experiments.status, 'running'),
+          courseId ? or(isNull(experiments.courseId), eq(experiments.courseId, courseId)) : sql`true`
+        )
+      );
+
+    const result = rows.map(toExperiment);
+    await redisClient.cacheAnalyticsData(cacheKey, result, ACTIVE_CACHE_TTL);
+    return result;
+  }
+
+  // Assign a unit to a variant of a running experiment
+  async assign(experimentKey: string, unitId: string): Promise<{ experiment: Experiment; variant: ExperimentVariant } | null> {
+    const [row] = await db.select().from(experiments).where(eq(experiments.key, experimentKey));
+    if (!row || row.status !== 'running') {
+      return null;
+    }
+
+    const experiment = toExperiment(row);
+    return { experiment, variant: assignVariant(experiment.key, unitId, experiment.variants) };
+  }
+
+  // Record exposures, keeping only the first per unit and experiment
+  async logExposures(exposures: ExposureInput[]): Promise<number> {
+    let recorded = 0;
+
+    for (const exposure of exposures) {
+      const [experiment] = await db
+        .select({ id: experiments.id })
+        .from(experiments)
+        .where(eq(experiments.key, exposure.experimentKey));
+      if (!experiment) {
+        continue;
+      }
+
+      const dedupeKey = `experiments:exposed:${experiment.id}:${exposure.unitId}`;
+      const first = await redisClient
+        .getClient()
+        .set(dedupeKey, exposure.variantKey, { NX: true, EX: EXPOSURE_DEDUPE_TTL });
+      if (!first) {
+        continue;
+      }
+
+      await db.insert(experimentExposures).values({
+        experimentId: experiment.id,
+        variantKey: exposure.variantKey,
+        unitId: exposure.unitId,
+        courseId: exposure.courseId || null,
+      });
+      recorded++;
+    }
+
+    return recorded;
+  }
+
+  // Attribute an outcome to the variant the unit was assigned
+  async recordMetric(input: MetricInput): Promise<boolean> {
+    const assignment = await this.assign(input.experimentKey, input.unitId);
+    if (!assignment) {
+      return false;
+    }
+
+    await db.insert(experimentMetrics).values({
+      experimentId: assignment.experiment.id,
+      variantKey: assignment.variant.key,
+      unitId: input.unitId,
+      metric: input.metric,
+      value: String(input.value ?? 1),
+    });
+    return true;
+  }
+
+  // Per-variant exposures and metric totals; conversion is units with the
+  // metric divided by exposed units.
+  async getResults(id: string): Promise<any | null> {
+    const experiment = await this.getExperiment(id);
+    if (!experiment) {
+      return null;
+    }
+
+    const exposures = await db
+      .select({
+        variantKey: experimentExposures.variantKey,
+        units: sql<number>`count(distinct ${experimentExposures.unitId})::int`,
+      })
+      .from(experimentExposures)
+      .where(eq(experimentExposures.experimentId, id))
+      .groupBy(experimentExposures.variantKey);
+
+    const metrics = await db
+      .select({
+        variantKey: experimentMetrics.variantKey,
+        metric: experimentMetrics.metric,
+        events: sql<number>`count(*)::int`,
+        units: sql<number>`count(distinct ${experimentMetrics.unitId})::int`,
+        total: sql<string>`coalesce(sum(${experimentMetrics.value}), 0)`,
+      })
+      .from(experimentMetrics)
+      .where(eq(experimentMetrics.experimentId, id))
+      .groupBy(experimentMetrics.variantKey, experimentMetrics.metric);
+
+    const variants = experiment.variants.map(variant => {
+      const exposed = exposures.find(e => e.variantKey === variant.key)?.units || 0;
+      const variantMetrics = metrics
+        .filter(m => m.variantKey === variant.key)
+        .reduce((acc, m) => {
+          acc[m.metric] = {
+            events: m.events,
+            units: m.units,
+            total: parseFloat(m.total),
+            conversionRate: exposed > 0 ? (m.units / exposed).toFixed(4) : '0',
+          };
+          return acc;
+        }, {} as Record<string, any>);
+
+      return { key: variant.key, weight: variant.weight, exposedUnits: exposed, metrics: variantMetrics };
+    });
+
+    return {
+      experiment: {
+        id: experiment.id,
+        key: experiment.key,
+        name: experiment.name,
+        status: experiment.status,
+        startedAt: experiment.startedAt,
+        endedAt: experiment.endedAt,
+      },
+      variants,
+    };
+  }
+
+  private async invalidateActive(): Promise<void> {
+    const client = redisClient.getClient();
+    const keys = await client.keys('experiments:active:*');
+    if (keys.length > 0) {
+      await client.del(keys);
+    }
+  }
+}
+
+export const experimentService = new ExperimentService();
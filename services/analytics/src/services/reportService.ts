@@ -0,0 +1,390 @@
+import { eq, and, gte, lte, desc, sql, InferModel } from 'drizzle-orm';
+import { createHmac, randomBytes } from 'crypto';
+import { addDays, addMonths, subDays } from 'date-fns';
+import { db } from '../config/database';
+import { redisClient } from '../config/redis';
+import { config } from '../config/config';
+import { userEvents, reportSubscriptions, reportRuns } from '../models/schema';
+
+export type ReportType = 'weekly_engagement' | 'pending_grading';
+export type ReportFrequency = 'daily' | 'weekly' | 'monthly';
+export type ReportChannel = 'email' | 'webhook';
+
+export const REPORT_TYPES: ReportType[] = ['weekly_engagement', 'pending_grading'];
+export const REPORT_FREQUENCIES: ReportFrequency[] = ['daily', 'weekly', 'monthly'];
+export const REPORT_CHANNELS: ReportChannel[] = ['email', 'webhook'];
+
+export type ReportSubscription = InferModel<typeof reportSubscriptions>;
+
+export interface ReportOwner {
+  id: string;
+  role: string;
+}
+
+export interface SubscriptionInput {
+  reportType: ReportType;
+  frequency: ReportFrequency;
+  courseId?: string | null;
+  channel: ReportChannel;
+  destination: string;
+  isActive?: boolean;
+}
+
+export class ReportValidationError extends Error {}
+
+const SCHEDULER_LOCK_KEY = 'analytics:reports:scheduler-lock';
+
+// Compute when a subscription should next run after `from`
+export function computeNextRun(frequency: ReportFrequency, from: Date = new Date()): Date {
+  switch (frequency) {
+    case 'daily':
+      return addDays(from, 1);
+    case 'weekly':
+      return addDays(from, 7);
+    case 'monthly':
+      return addMonths(from, 1);
+  }
+}
+
+class ReportService {
+
+  // Validate a subscription definition for the given owner
+  validateSubscription(input: Partial<SubscriptionInput>, owner: ReportOwner): void {
+    if (input.reportType !== undefined && !REPORT_TYPES.includes(input.reportType)) {
+      throw new ReportValidationError(`reportType must be one of: ${REPORT_TYPES.join(', ')}`);
+    }
+    if (input.frequency !== undefined && !REPORT_FREQUENCIES.includes(input.frequency)) {
+      throw new ReportValidationError(`frequency must be one of: ${REPORT_FREQUENCIES.join(', ')}`);
+    }
+    if (input.channel !== undefined && !REPORT_CHANNELS.includes(input.channel)) {
+      throw new ReportValidationError(`channel must be one of: ${REPORT_CHANNELS.join(', ')}`);
+    }
+
+    if (input.channel === 'webhook' && input.destination !== undefined) {
+      let url: URL;
+      try {
+        url = new URL(input.destination);
+      } catch {
+        throw new ReportValidationError('destination must be a valid webhook URL');
+      }
+      if (url.protocol !== 'https:' && config.NODE_ENV === 'production') {
+        throw new ReportValidationError('webhook destinations must use https');
+      }
+    }
+    if (input.channel === 'email' && input.destination !== undefined && !/^[^\s@]+@[^\s@]+$/.test(input.destination)) {
+      throw new ReportValidationError('destination must be a valid email address');
+    }
+
+    // Instructors only see their own courses; platform-wide engagement is admin-only
+    if (owner.role !== 'admin' && input.reportType === 'weekly_engagement' && !input.courseId) {
+      throw new ReportValidationError('courseId is required for engagement reports');
+    }
+  }
+
+  async createSubscription(input: SubscriptionInput, owner: ReportOwner): Promise<ReportSubscription> {
+    this.validateSubscription(input, owner);
+
+    const [subscription] = await db
+      .insert(reportSubscriptions)
+      .values({
+        ownerId: owner.id,
+        ownerRole: owner.role,
+        reportType: input.reportType,
+        frequency: input.frequency,
+        courseId: input.courseId || null,
+        channel: input.channel,
+        destination: input.destination,
+        webhookSecret: input.channel === 'webhook' ? randomBytes(32).toString('hex') : null,
+        isActive: input.isActive ?? true,
+        nextRunAt: computeNextRun(input.frequency),
+      })
+      .returning();
+
+    return subscription;
+  }
+
+  // Admins see every subscription, everyone else only their own
+  async listSubscriptions(owner: ReportOwner): Promise<ReportSubscription[]> {
+    const query = db.select().from(reportSubscriptions);
+    const rows = owner.role === 'admin'
+      ? await query.orderBy(desc(reportSubscriptions.createdAt))
+      : await query.where(eq(reportSubscriptions.ownerId, owner.id)).orderBy(desc(reportSubscriptions.createdAt));
+
+    return rows;
+  }
+
+  async getSubscription(id: string, owner: ReportOwner): Promise<ReportSubscription | null> {
+    const [subscription] = await db
+      .select()
+      .from(reportSubscriptions)
+      .where(eq(reportSubscriptions.id, id));
+
+    if (!subscription || (owner.role !== 'admin' && subscription.ownerId !== owner.id)) {
+      return null;
+    }
+    return subscription;
+  }
+
+  async updateSubscription(
+    id: string,
+    input: Partial<SubscriptionInput>,
+    owner: ReportOwner
+  ): Promise<ReportSubscription | null> {
+    const existing = await this.getSubscription(id, owner);
+    if (!existing) {
+      return null;
+    }
+
+    const merged: SubscriptionInput = {
+      reportType: input.reportType ?? existing.reportType,
+      frequency: input.frequency ?? existing.frequency,
+      courseId: input.courseId !== undefined ? input.courseId : existing.courseId,
+      channel: input.channel ?? existing.channel,
+      destination: input.destination ?? existing.destination,
+      isActive: input.isActive ?? existing.isActive,
+    };
+    this.validateSubscription(merged, owner);
+
+    const [subscription] = await db
+      .update(reportSubscriptions)
+      .set({
+        ...merged,
+        courseId: merged.courseId || null,
+        webhookSecret: merged.channel === 'webhook'
+          ? existing.webhookSecret || randomBytes(32).toString('hex')
+          : null,
+        nextRunAt: merged.frequency !== existing.frequency ? computeNextRun(merged.frequency) : existing.nextRunAt,
+        updatedAt: new Date(),
+      })
+      .where(eq(reportSubscriptions.id, id))
+      .returning();
+
+    return subscription;
+  }
+
+  async deleteSubscription(id: string, owner: ReportOwner): Promise<boolean> {
+    const existing = await this.getSubscription(id, owner);
+    if (!existing) {
+      return false;
+    }
+
+    await db.delete(reportRuns).where(eq(reportRuns.subscriptionId, id));
+    await db.delete(reportSubscriptions).where(eq(reportSubscriptions.id, id));
+    return true;
+  }
+
+  async listRuns(subscriptionId: string, limit: number = 20): Promise<any[]> {
+    return db
+      .select()
+      .from(reportRuns)
+      .where(eq(reportRuns.subscriptionId, subscriptionId))
+      .orderBy(desc(reportRuns.startedAt))
+      .limit(limit);
+  }
+
+  // Generate and deliver one report, recording the run
+  async runSubscription(subscription: ReportSubscription, trigger: 'schedule' | 'manual'): Promise<any> {
+    const [run] = await db
+      .insert(reportRuns)
+      .values({ subscriptionId: subscription.id, trigger, status: 'running' })
+      .returning();
+
+    try {
+      const report = await this.generateReport(subscription);
+      await this.deliverReport(subscription, report);
+
+      const [completed] = await db
+        .update(reportRuns)
+        .set({ status: 'delivered', report: JSON.stringify(report), completedAt: new Date() })
+        .where(eq(reportRuns.id, run.id))
+        .returning();
+      return completed;
+    } catch (error) {
+      const message = error instanceof Error ? error.message : 'Unknown error';
+      const [failed] = await db
+        .update(reportRuns)
+        .set({ status: 'failed', error: message, completedAt: new Date() })
+        .where(eq(reportRuns.id, run.id))
+        .returning();
+      return failed;
+    } finally {
+      // Manual runs don't shift the schedule
+      if (trigger === 'schedule') {
+        await db
+          .update(reportSubscriptions)
+          .set({ lastRunAt: new Date(), nextRunAt: computeNextRun(subscription.frequency) })
+          .where(eq(reportSubscriptions.id, subscription.id));
+      }
+    }
+  }
+
+  // Run every active subscription that is due. A Redis lock keeps multiple
+  // analytics instances from sending the same report twice.
+  async runDueSubscriptions(): Promise<number> {
+    const lockTtl = Math.max(config.REPORT_SCHEDULER_INTERVAL_MS, 30000);
+    const acquired = await redisClient
+      .getClient()
+      .set(SCHEDULER_LOCK_KEY, process.pid.toString(), { NX: true, PX: lockTtl });
+    if (!acquired) {
+      return 0;
+    }
+
+    try {
+      const due = await db
+        .select()
+        .from(reportSubscriptions)
+        .where(
+          and(
+            eq(reportSubscriptions.isActive, true),
+            lte(reportSubscriptions.nextRunAt, new Date())
+          )
+        )
+        .limit(100);
+
+      for (const subscription of due) {
+        await this.runSubscription(subscription, 'schedule');
+      }
+
+      return due.length;
+    } finally {
+      await redisClient.getClient().del(SCHEDULER_LOCK_KEY);
+    }
+  }
+
+  private async generateReport(subscription: ReportSubscription): Promise<Record<string, any>> {
+    const base = {
+      subscriptionId: subscription.id,
+      reportType: subscription.reportType,
+      courseId: subscription.courseId,
+      generatedAt: new Date().toISOString(),
+    };
+
+    switch (subscription.reportType) {
+      case 'weekly_engagement':
+        return { ...base, ...(await this.generateEngagementReport(subscription.courseId)) };
+      case 'pending_grading':
+        return { ...base, ...(await this.generatePendingGradingReport(subscription)) };
+      default:
+        throw new Error(`Unsupported report type: ${subscription.reportType}`);
+    }
+  }
+
+  private async generateEngagementReport(courseId: string | null): Promise<Record<string, any>> {
+    const periodEnd = new Date();
+    const periodStart = subDays(periodEnd, 7);
+
+    const rows = await db
+      .select({
+        eventType: userEvents.eventType,
+        events: sql<number>`count(*)::int`,
+        users: sql<number>`count(distinct ${userEvents.userId})::int`,
+      })
+      .from(userEvents)
+      .where(
+        and(
+          gte(userEvents.createdAt, periodStart),
+          courseId ? eq(userEvents.courseId, courseId) : sql`true`
+        )
+      )
+      .groupBy(userEvents.eventType);
+
+    const [totals] = await db
+      .select({ activeUsers: sql<number>`count(distinct ${userEvents.userId})::int` })
+      .from(userEvents)
+      .where(
+        and(
+          gte(userEvents.createdAt, periodStart),
+          courseId ? eq(userEvents.courseId, courseId) : sql`true`
+        )
+      );
+
+    return {
+      periodStart: periodStart.toISOString(),
+      periodEnd: periodEnd.toISOString(),
+      activeUsers: totals?.activeUsers || 0,
+      eventsByType: rows.reduce((acc, row) => {
+        acc[row.eventType] = { events: row.events, users: row.users };
+        return acc;
+      }, {} as Record<string, { events: number; users: number }>),
+    };
+  }
+
+  private async generatePendingGradingReport(subscription: ReportSubscription): Promise<Record<string, any>> {
+    const params = new URLSearchParams();
+    if (subscription.courseId) {
+      params.set('courseId', subscription.courseId);
+    }
+    // Instructors get the grading queue for assessments they created
+    if (subscription.ownerRole !== 'admin') {
+      params.set('createdBy', subscription.ownerId);
+    }
+
+    const response = await fetch(
+      `${config.ASSESSMENT_SERVICE_URL}/api/v1/internal/reports/pending-grading?${params.toString()}`,
+      {
+        headers: { 'X-Service-Key': config.INTERNAL_SERVICE_KEY },
+        signal: AbortSignal.timeout(config.REPORT_DELIVERY_TIMEOUT_MS),
+      }
+    );
+    if (!response.ok) {
+      throw new Error(`Assessment service returned ${response.status}`);
+    }
+
+    const body = await response.json() as { data: Array<{ pending: number }> | null };
+    const assessments = body.data || [];
+
+    return {
+      totalPending: assessments.reduce((sum, item) => sum + item.pending, 0),
+      assessments,
+    };
+  }
+
+  private async deliverReport(subscription: ReportSubscription, report: Record<string, any>): Promise<void> {
+    const title = `${subscription.reportType.replace(/_/g, ' ')} report`;
+
+    if (subscription.channel === 'email') {
+      const response = await fetch(`${config.NOTIFICATION_SERVICE_URL}/api/v1/notifications/send`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json', 'X-Service-Key': config.INTERNAL_SERVICE_KEY },
+        body: JSON.stringify({
+          recipientId: subscription.ownerId,
+          type: 'email',
+          template: 'scheduled_report',
+          subject: title.charAt(0).toUpperCase() + title.slice(1),
+          content: JSON.stringify(report, null, 2),
+          metadata: { email: subscription.destination, report },
+          priority: 'low',
+        }),
+        signal: AbortSignal.timeout(config.REPORT_DELIVERY_TIMEOUT_MS),
+      });
+      if (!response.ok) {
+        throw new Error(`Notification service returned ${response.status}`);
+      }
+      return;
+    }
+
+    // Webhooks are signed so receivers can verify the sender
+    const payload = JSON.stringify(report);
+    const timestamp = Math.floor(Date.now() / 1000).toString();
+    const signature = createHmac('sha256', subscription.webhookSecret || '')
+      .update(`${timestamp}.${payload}`)
+      .digest('hex');
+
+    const response = await fetch(subscription.destination, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+        'X-Modex-Report': subscription.reportType,
+        'X-Modex-Timestamp': timestamp,
+        'X-Modex-Signature': `sha256=${signature}`,
+      },
+      body: payload,
+      signal: AbortSignal.timeout(config.REPORT_DELIVERY_TIMEOUT_MS),
+    });
+    if (!response.ok) {
+      throw new Error(`Webhook returned ${response.status}`);
+    }
+  }
+}
+
+export const reportService = new ReportService();
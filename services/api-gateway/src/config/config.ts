@@ -10,6 +10,12 @@ export const config = {
   JWT_SECRET: process.env.JWT_SECRET || 'your-secret-key',
   JWT_EXPIRES_IN: process.env.JWT_EXPIRES_IN || '24h',
   
+  // SSO claim mapping (IdP groups/departments -> Modex roles and organizations)
+  SSO: {
+    CLAIM_MAPPING: process.env.SSO_CLAIM_MAPPING || '',
+    CLAIM_MAPPING_FILE: process.env.SSO_CLAIM_MAPPING_FILE || ''
+  },
+  
   // Redis Configuration
   REDIS_URL: process.env.REDIS_URL || 'redis://localhost:6379',
   
@@ -19,6 +25,30 @@ export const config = {
   // Rate Limiting
   RATE_LIMIT_WINDOW: parseInt(process.env.RATE_LIMIT_WINDOW || '900000'), // 15 minutes
   RATE_LIMIT_MAX: parseInt(process.env.RATE_LIMIT_MAX || '100'),
+  RATE_LIMIT_TIERS: {
+    anonymous: parseInt(process.env.RATE_LIMIT_MAX_ANONYMOUS || process.env.RATE_LIMIT_MAX || '100'),
+    authenticated: parseInt(process.env.RATE_LIMIT_MAX_AUTHENTICATED || '1000'),
+    service: parseInt(process.env.RATE_LIMIT_MAX_SERVICE || '10000')
+  },
+  // Per-route overrides and per-API-key limits (inline JSON)
+  RATE_LIMIT_ROUTES: process.env.RATE_LIMIT_ROUTES || '',
+  RATE_LIMIT_API_KEYS: process.env.RATE_LIMIT_API_KEYS || '',
+
+  // Tenant quotas: plan limits (inline JSON merged over the built-in plans)
+  QUOTAS: {
+    PLANS: process.env.QUOTA_PLANS || '',
+    DEFAULT_PLAN: process.env.QUOTA_DEFAULT_PLAN || 'free',
+    EXAM_SESSION_TTL: parseInt(process.env.QUOTA_EXAM_SESSION_TTL || '10800000'), // 3 hours
+    // Percentages of a quota at which organizations are warned
+    WARNING_THRESHOLDS: (process.env.QUOTA_WARNING_THRESHOLDS || '80,95').split(',').map((t) => parseInt(t))
+  },
+
+  // Response compression: bodies smaller than the threshold are sent as-is,
+  // and Brotli quality is kept low since responses are compressed per request
+  COMPRESSION: {
+    THRESHOLD: parseInt(process.env.COMPRESSION_THRESHOLD || '1024'),
+    BROTLI_QUALITY: parseInt(process.env.COMPRESSION_BROTLI_QUALITY || '4')
+  },
   
   // Service Discovery
   SERVICES: {
@@ -29,6 +59,7 @@ export const config = {
     ASSESSMENT: process.env.ASSESSMENT_URL || 'http://localhost:3005',
     PAYMENT: process.env.PAYMENT_URL || 'http://localhost:3006',
     ANALYTICS: process.env.ANALYTICS_URL || 'http://localhost:3007',
-    NOTIFICATION: process.env.NOTIFICATION_URL || 'http://localhost:3008'
+    NOTIFICATION: process.env.NOTIFICATION_URL || 'http://localhost:3008',
+    EVENT_BUS: process.env.EVENT_BUS_URL || 'http://localhost:3009'
   }
 };
@@ -0,0 +1,53 @@
+import { config } from './config';
+import { logger } from '../utils/logger';
+
+export type QuotaName = 'requestsPerDay' | 'uploadsPerDay' | 'storageBytes' | 'concurrentExamTakers';
+
+// Limits for one organization; 0 means unlimited
+export type QuotaLimits = Record<QuotaName, number>;
+
+export const QUOTA_NAMES: QuotaName[] = ['requestsPerDay', 'uploadsPerDay', 'storageBytes', 'concurrentExamTakers'];
+
+const GB = 1024 * 1024 * 1024;
+
+// Built-in pricing plans; QUOTA_PLANS can change these or add new ones
+const DEFAULT_PLANS: Record<string, QuotaLimits> = {
+  free: { requestsPerDay: 10000, uploadsPerDay: 50, storageBytes: 5 * GB, concurrentExamTakers: 50 },
+  pro: { requestsPerDay: 250000, uploadsPerDay: 2000, storageBytes: 250 * GB, concurrentExamTakers: 1000 },
+  enterprise: { requestsPerDay: 0, uploadsPerDay: 0, storageBytes: 0, concurrentExamTakers: 0 }
+};
+
+export const isQuotaLimit = (value: unknown): value is number =>
+  typeof value === 'number' && Number.isInteger(value) && value >= 0;
+
+// Load the plans, merging QUOTA_PLANS (inline JSON) over the built-in ones
+export const loadQuotaPlans = (): Record<string, QuotaLimits> => {
+  const plans: Record<string, QuotaLimits> = { ...DEFAULT_PLANS };
+  if (!config.QUOTAS.PLANS) {
+    return plans;
+  }
+
+  try {
+    const parsed = JSON.parse(config.QUOTAS.PLANS);
+    for (const [name, limits] of Object.entries<Partial<QuotaLimits>>(parsed)) {
+      const merged = { ...(plans[name] || DEFAULT_PLANS.free), ...limits };
+      if (!QUOTA_NAMES.every((quota) => isQuotaLimit(merged[quota]))) {
+        logger.warn('Ignoring invalid quota plan', { plan: name });
+        continue;
+      }
+      plans[name] = merged;
+    }
+  } catch (error) {
+    logger.error('Invalid QUOTA_PLANS JSON:', error);
+  }
+  return plans;
+};
+
+export const quotaPlans = loadQuotaPlans();
+
+export const defaultPlan = quotaPlans[config.QUOTAS.DEFAULT_PLAN] ? config.QUOTAS.DEFAULT_PLAN : 'free';
+
+// Warning thresholds as percentages, highest first
+export const warningThresholds = config.QUOTAS.WARNING_THRESHOLDS
+  .filter((threshold) => threshold > 0 && threshold < 100)
+  .sort((a, b) => b - a);
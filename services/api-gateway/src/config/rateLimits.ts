@@ -0,0 +1,116 @@
+import { config } from './config';
+import { logger } from '../utils/logger';
+
+export type CallerTier = 'anonymous' | 'authenticated' | 'service';
+
+export type TierLimits = Record<CallerTier, number>;
+
+// A stricter (or looser) limit for requests whose path starts with pathPrefix.
+// Matching requests are counted in their own bucket instead of the default one.
+export interface RouteLimitRule {
+  name: string;
+  pathPrefix: string;
+  methods?: string[];
+  windowMs: number;
+  limits: TierLimits;
+}
+
+// A service caller identified by its X-API-Key; max overrides the service tier
+export interface ApiKeyRule {
+  name: string;
+  key: string;
+  max?: number;
+}
+
+export interface RateLimitConfig {
+  windowMs: number;
+  limits: TierLimits;
+  routes: RouteLimitRule[];
+  apiKeys: ApiKeyRule[];
+}
+
+const TIERS: CallerTier[] = ['anonymous', 'authenticated', 'service'];
+
+// Uploads and login attempts get tighter limits unless RATE_LIMIT_ROUTES says otherwise
+const DEFAULT_ROUTES: RouteLimitRule[] = [
+  {
+    name: 'uploads',
+    pathPrefix: '/api/content',
+    methods: ['POST', 'PUT'],
+    windowMs: 60 * 60 * 1000,
+    limits: { anonymous: 5, authenticated: 30, service: 500 }
+  },
+  {
+    name: 'auth',
+    pathPrefix: '/api/auth',
+    methods: ['POST'],
+    windowMs: 15 * 60 * 1000,
+    limits: { anonymous: 20, authenticated: 20, service: 1000 }
+  }
+];
+
+const parseJson = (raw: string, name: string): unknown => {
+  if (!raw) {
+    return undefined;
+  }
+  try {
+    return JSON.parse(raw);
+  } catch (error) {
+    logger.error(`Invalid ${name} JSON:`, error);
+    return undefined;
+  }
+};
+
+const isTierLimits = (limits: any): limits is TierLimits =>
+  !!limits && TIERS.every((tier) => Number.isInteger(limits[tier]) && limits[tier] >= 0);
+
+const loadRoutes = (): RouteLimitRule[] => {
+  const parsed = parseJson(config.RATE_LIMIT_ROUTES, 'RATE_LIMIT_ROUTES');
+  if (!Array.isArray(parsed)) {
+    return DEFAULT_ROUTES;
+  }
+
+  const rules = parsed.map((rule: any): RouteLimitRule => ({
+    ...rule,
+    methods: Array.isArray(rule.methods) ? rule.methods.map((method: string) => method.toUpperCase()) : undefined,
+    windowMs: rule.windowMs ?? config.RATE_LIMIT_WINDOW
+  }));
+
+  const invalid = rules.filter((rule) =>
+    !rule.name || !rule.pathPrefix?.startsWith('/') || !(rule.windowMs > 0) || !isTierLimits(rule.limits)
+  );
+  if (invalid.length > 0) {
+    logger.warn('Ignoring invalid rate limit route rules', { invalid: invalid.map((rule) => rule.name) });
+  }
+
+  // Longest prefix first so the most specific rule wins
+  return rules
+    .filter((rule) => !invalid.includes(rule))
+    .sort((a, b) => b.pathPrefix.length - a.pathPrefix.length);
+};
+
+const loadApiKeys = (): ApiKeyRule[] => {
+  const parsed = parseJson(config.RATE_LIMIT_API_KEYS, 'RATE_LIMIT_API_KEYS');
+  if (!Array.isArray(parsed)) {
+    return [];
+  }
+
+  const keys = parsed as ApiKeyRule[];
+  const invalid = keys.filter((rule) =>
+    !rule.name || !rule.key || (rule.max !== undefined && !(Number.isInteger(rule.max) && rule.max >= 0))
+  );
+  if (invalid.length > 0) {
+    // Never log the keys themselves
+    logger.warn('Ignoring invalid rate limit API keys', { invalid: invalid.map((rule) => rule.name) });
+  }
+  return keys.filter((rule) => !invalid.includes(rule));
+};
+
+export const loadRateLimitConfig = (): RateLimitConfig => ({
+  windowMs: config.RATE_LIMIT_WINDOW,
+  limits: config.RATE_LIMIT_TIERS,
+  routes: loadRoutes(),
+  apiKeys: loadApiKeys()
+});
+
+export const rateLimitConfig = loadRateLimitConfig();
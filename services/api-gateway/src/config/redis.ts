@@ -0,0 +1,7 @@
+import { createClient } from 'redis';
+import { config } from './config';
+import { logger } from '../utils/logger';
+
+// Shared Redis client for rate limiting and tenant quotas
+export const redisClient = createClient({ url: config.REDIS_URL });
+redisClient.connect().catch((err) => logger.error('Redis connection error:', err));
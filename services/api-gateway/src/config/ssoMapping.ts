@@ -0,0 +1,111 @@
+import fs from 'fs';
+import { config } from './config';
+import { logger } from '../utils/logger';
+
+export type PlatformRole = 'student' | 'instructor' | 'admin';
+
+// A single rule matching an IdP claim value to a Modex role and/or organization
+export interface SsoMappingRule {
+  claim: string;
+  value: string;
+  role?: PlatformRole;
+  organizationId?: string;
+}
+
+export interface SsoMappingConfig {
+  rules: SsoMappingRule[];
+  defaultRole: PlatformRole;
+}
+
+export interface MappedIdentity {
+  role: PlatformRole;
+  organizationId?: string;
+  matchedRules: number;
+}
+
+// Higher rank wins when several rules match the same user
+const ROLE_RANK: Record<PlatformRole, number> = {
+  student: 0,
+  instructor: 1,
+  admin: 2
+};
+
+const EMPTY_MAPPING: SsoMappingConfig = { rules: [], defaultRole: 'student' };
+
+const isPlatformRole = (role: unknown): role is PlatformRole =>
+  typeof role === 'string' && role in ROLE_RANK;
+
+// Load the mapping from SSO_CLAIM_MAPPING (inline JSON) or SSO_CLAIM_MAPPING_FILE.
+// The Go services apply the same configuration through shared/go/jwt, so
+// keep the two in step.
+export const loadSsoMapping = (): SsoMappingConfig => {
+  let raw = config.SSO.CLAIM_MAPPING;
+
+  if (!raw && config.SSO.CLAIM_MAPPING_FILE) {
+    try {
+      raw = fs.readFileSync(config.SSO.CLAIM_MAPPING_FILE, 'utf8');
+    } catch (error) {
+      logger.error('Failed to read SSO claim mapping file:', error);
+      return EMPTY_MAPPING;
+    }
+  }
+
+  if (!raw) {
+    return EMPTY_MAPPING;
+  }
+
+  try {
+    const parsed = JSON.parse(raw);
+    const rules: SsoMappingRule[] = Array.isArray(parsed.rules) ? parsed.rules : [];
+
+    const invalid = rules.filter((rule) =>
+      !rule.claim || !rule.value || (rule.role !== undefined && !isPlatformRole(rule.role))
+    );
+    if (invalid.length > 0) {
+      logger.warn('Ignoring invalid SSO claim mapping rules', { invalid });
+    }
+
+    return {
+      rules: rules.filter((rule) => !invalid.includes(rule)),
+      defaultRole: isPlatformRole(parsed.defaultRole) ? parsed.defaultRole : 'student'
+    };
+  } catch (error) {
+    logger.error('Invalid SSO claim mapping JSON:', error);
+    return EMPTY_MAPPING;
+  }
+};
+
+const claimValues = (claims: Record<string, any>, claim: string): string[] => {
+  const value = claims[claim];
+  if (Array.isArray(value)) {
+    return value.map(String);
+  }
+  return value === undefined || value === null ? [] : [String(value)];
+};
+
+// Resolve the Modex role and organization for a set of SSO/OIDC claims
+export const mapSsoClaims = (claims: Record<string, any>, mapping: SsoMappingConfig): MappedIdentity => {
+  let role = mapping.defaultRole;
+  let organizationId: string | undefined;
+  let matchedRules = 0;
+
+  for (const rule of mapping.rules) {
+    const values = claimValues(claims, rule.claim);
+    if (!values.some((value) => value.toLowerCase() === rule.value.toLowerCase())) {
+      continue;
+    }
+
+    matchedRules++;
+    if (rule.role && ROLE_RANK[rule.role] > ROLE_RANK[role]) {
+      role = rule.role;
+    }
+    // The first matching organization rule wins so ordering in the config is meaningful
+    if (rule.organizationId && !organizationId) {
+      organizationId = rule.organizationId;
+    }
+  }
+
+  return { role, organizationId, matchedRules };
+};
+
+export const ssoMapping = loadSsoMapping();
@@ -3,11 +3,13 @@ import cors from 'cors';
 import helmet from 'helmet';
 import compression from 'compression';
 import morgan from 'morgan';
+import zlib from 'zlib';
 import { config } from './config/config';
 import { logger } from './utils/logger';
 import { setupRoutes } from './routes';
 import { setupMiddleware } from './middleware';
 import { createPrometheusMetrics } from './middleware/metrics';
+import { startStorageTracking } from './services/storageUsage';
 
 const app = express();
 
@@ -15,9 +17,17 @@ const app = express();
 app.use(helmet());
 app.use(cors({
   origin: config.CORS_ORIGINS,
-  credentials: true
+  credentials: true,
+  // Lets browser clients read their remaining rate limit and quota usage
+  exposedHeaders: ['X-RateLimit-Limit', 'X-RateLimit-Remaining', 'X-RateLimit-Reset', 'Retry-After', 'X-Quota-Usage']
+}));
+// Negotiates br or gzip; responses a service already compressed pass through
+app.use(compression({
+  threshold: config.COMPRESSION.THRESHOLD,
+  brotli: {
+    params: { [zlib.constants.BROTLI_PARAM_QUALITY]: config.COMPRESSION.BROTLI_QUALITY }
+  }
 }));
-app.use(compression());
 
 // Logging
 app.use(morgan('combined', { stream: { write: (message: string) => logger.info(message.trim()) } }));
@@ -55,4 +65,6 @@ app.listen(PORT, () => {
   logger.info(`Environment: ${config.NODE_ENV}`);
 });
 
+startStorageTracking().catch((error) => logger.error('Failed to start storage tracking:', error));
+
 export default app;
\ No newline at end of file
@@ -1,13 +1,51 @@
 import express from 'express';
 import jwt from 'jsonwebtoken';
 import { config } from '../config/config';
+import { mapSsoClaims, ssoMapping } from '../config/ssoMapping';
 import { logger } from '../utils/logger';
 
 interface AuthenticatedRequest extends express.Request {
   user?: any;
 }
 
+// Identity headers forwarded to downstream services; only the gateway may set them
+const IDENTITY_HEADERS = ['x-user-id', 'x-user-role', 'x-organization-id'];
+
+const stripIdentityHeaders = (req: AuthenticatedRequest): void => {
+  IDENTITY_HEADERS.forEach((header) => delete req.headers[header]);
+};
+
+// Attach the verified user, mapping SSO claims to a Modex role and organization
+// for enterprise logins (tokens carrying an `idp` claim).
+const attachIdentity = (req: AuthenticatedRequest, decoded: string | jwt.JwtPayload): void => {
+  const user: Record<string, any> = typeof decoded === 'string' ? { sub: decoded } : { ...decoded };
+
+  if (user.idp) {
+    const mapped = mapSsoClaims(user, ssoMapping);
+    user.role = mapped.role;
+    if (mapped.organizationId) {
+      user.organizationId = mapped.organizationId;
+    }
+    logger.debug('Mapped SSO claims', { idp: user.idp, role: mapped.role, matchedRules: mapped.matchedRules });
+  }
+
+  req.user = user;
+
+  const userId = user.sub || user.userId || user.id;
+  if (userId) {
+    req.headers['x-user-id'] = String(userId);
+  }
+  if (user.role) {
+    req.headers['x-user-role'] = String(user.role);
+  }
+  if (user.organizationId) {
+    req.headers['x-organization-id'] = String(user.organizationId);
+  }
+};
+
 export const authenticateToken = (req: AuthenticatedRequest, res: express.Response, next: express.NextFunction): void => {
+  stripIdentityHeaders(req);
+
   const authHeader = req.headers['authorization'];
   const token = authHeader && authHeader.split(' ')[1];
 
@@ -21,7 +59,7 @@ export const authenticateToken = (req: AuthenticatedRequest, res: express.Respon
 
   try {
     const decoded = jwt.verify(token, config.JWT_SECRET);
-    req.user = decoded;
+    attachIdentity(req, decoded);
     next();
   } catch (error) {
     logger.warn('Invalid token attempt', { 
@@ -37,6 +75,8 @@ export const authenticateToken = (req: AuthenticatedRequest, res: express.Respon
 };
 
 export const optionalAuth = (req: AuthenticatedRequest, res: express.Response, next: express.NextFunction): void => {
+  stripIdentityHeaders(req);
+
   const authHeader = req.headers['authorization'];
   const token = authHeader && authHeader.split(' ')[1];
 
@@ -47,7 +87,7 @@ export const optionalAuth = (req: AuthenticatedRequest, res: express.Response, n
 
   try {
     const decoded = jwt.verify(token, config.JWT_SECRET);
-    req.user = decoded;
+    attachIdentity(req, decoded);
   } catch (error) {
     // For optional auth, we don't fail on invalid tokens
     logger.debug('Optional auth failed', { error });
@@ -55,3 +95,15 @@ export const optionalAuth = (req: AuthenticatedRequest, res: express.Response, n
   
   next();
 };
+
+// Allows only platform admins through; must run after authenticateToken
+export const requireAdmin = (req: AuthenticatedRequest, res: express.Response, next: express.NextFunction): void => {
+  if (req.user?.role !== 'admin') {
+    res.status(403).json({
+      error: 'Admin access required.',
+      requestId: req.headers['x-request-id']
+    });
+    return;
+  }
+  next();
+};
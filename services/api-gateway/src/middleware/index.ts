@@ -1,13 +1,6 @@
 import express from 'express';
-import rateLimit from 'express-rate-limit';
-import { createClient } from 'redis';
 import { v4 as uuidv4 } from 'uuid';
-import { config } from '../config/config';
-import { logger } from '../utils/logger';
-
-// Redis client for rate limiting
-const redisClient = createClient({ url: config.REDIS_URL });
-redisClient.connect().catch((err) => logger.error('Redis connection error:', err));
+import { createRateLimiter } from './rateLimit';
 
 // Request ID middleware
 export const requestId = (req: express.Request, res: express.Response, next: express.NextFunction) => {
@@ -17,16 +10,7 @@ export const requestId = (req: express.Request, res: express.Response, next: exp
 };
 
 // Rate limiting middleware
-export const rateLimiter = rateLimit({
-  windowMs: config.RATE_LIMIT_WINDOW,
-  max: config.RATE_LIMIT_MAX,
-  message: {
-    error: 'Too many requests from this IP, please try again later.',
-    retryAfter: Math.ceil(config.RATE_LIMIT_WINDOW / 1000)
-  },
-  standardHeaders: true,
-  legacyHeaders: false,
-});
+export const rateLimiter = createRateLimiter();
 
 // JSON parsing middleware
 export const jsonParser = express.json({ limit: '10mb' });
@@ -0,0 +1,136 @@
+import express from 'express';
+import { redisClient } from '../config/redis';
+import { QuotaLimits, QuotaName } from '../config/quotas';
+import { nextReset, quotaService } from '../services/quotaService';
+import { logger } from '../utils/logger';
+
+// Matched against the path below /api/assessments
+const EXAM_START = /\/assessments\/[^/]+\/start$/;
+const EXAM_SUBMIT = /\/assessments\/submissions\/[^/]+\/submit$/;
+
+// Only POSTs create content; PUTs to /api/content update metadata
+const isUpload = (req: express.Request): boolean =>
+  req.baseUrl === '/api/content' && req.method === 'POST';
+
+// X-Quota-Usage lists usage of each limited quota, e.g.
+// `requestsPerDay;used=8200;limit=10000, storageBytes;used=1024;limit=5368709120`,
+// so UIs can warn before requests start failing
+const formatUsage = (usage: Partial<Record<QuotaName, number>>, limits: QuotaLimits): string =>
+  (Object.entries(usage) as [QuotaName, number][])
+    .filter(([quota]) => limits[quota] > 0)
+    .map(([quota, used]) => `${quota};used=${used};limit=${limits[quota]}`)
+    .join(', ');
+
+const warn = (organizationId: string, quota: QuotaName, limit: number, used: number): void => {
+  quotaService.checkWarning(organizationId, quota, limit, used)
+    .catch((error) => logger.error('Quota warning check failed:', error));
+};
+
+const rejectOverQuota = (req: express.Request, res: express.Response, quota: QuotaName, limit: number, daily: boolean): void => {
+  const body: Record<string, any> = {
+    error: 'Organization quota exceeded',
+    quota,
+    limit,
+    requestId: req.headers['x-request-id']
+  };
+  if (daily) {
+    const reset = nextReset();
+    body.resetsAt = reset.toISOString();
+    res.set('Retry-After', String(Math.ceil((reset.getTime() - Date.now()) / 1000)));
+  }
+  res.status(429).json(body);
+};
+
+// Enforces the caller's organization quotas: requests and uploads per day,
+// storage and concurrent exam takers. Runs after authentication, since the
+// organization comes from the verified token. Requests rejected here don't
+// count against requestsPerDay, failed uploads don't count against
+// uploadsPerDay, and requests are let through if Redis is down. Storage isn't
+// counted here: the figure comes from what the content service reports storing
+// (see storageUsage.ts), and uploads are refused once it would exceed the
+// limit. Usage nearing a limit triggers a warning event and is reported in
+// X-Quota-Usage.
+export const enforceTenantQuotas = async (req: express.Request, res: express.Response, next: express.NextFunction) => {
+  const organizationId = req.headers['x-organization-id'] as string | undefined;
+  if (!organizationId || !redisClient.isReady) {
+    return next();
+  }
+
+  try {
+    const limits = await quotaService.getLimits(organizationId);
+
+    const requests = await quotaService.incrementDaily(organizationId, 'requests');
+    const releaseRequest = () => quotaService.incrementDaily(organizationId, 'requests', -1);
+    if (limits.requestsPerDay > 0 && requests > limits.requestsPerDay) {
+      await releaseRequest();
+      await quotaService.recordOverage(organizationId, 'requestsPerDay', limits.requestsPerDay, requests);
+      return rejectOverQuota(req, res, 'requestsPerDay', limits.requestsPerDay, true);
+    }
+    warn(organizationId, 'requestsPerDay', limits.requestsPerDay, requests);
+    const usage: Partial<Record<QuotaName, number>> = { requestsPerDay: requests };
+
+    if (isUpload(req)) {
+      const uploads = await quotaService.incrementDaily(organizationId, 'uploads');
+      const storage = await quotaService.getStorage(organizationId);
+      // The declared size only screens out uploads that can't fit; chunked
+      // uploads declare none and are held to the stored total alone
+      const declared = parseInt(req.headers['content-length'] || '0') || 0;
+
+      const release = () => quotaService.incrementDaily(organizationId, 'uploads', -1);
+
+      if (limits.uploadsPerDay > 0 && uploads > limits.uploadsPerDay) {
+        await Promise.all([release(), releaseRequest()]);
+        await quotaService.recordOverage(organizationId, 'uploadsPerDay', limits.uploadsPerDay, uploads);
+        return rejectOverQuota(req, res, 'uploadsPerDay', limits.uploadsPerDay, true);
+      }
+      if (limits.storageBytes > 0 && (storage >= limits.storageBytes || storage + declared > limits.storageBytes)) {
+        await Promise.all([release(), releaseRequest()]);
+        await quotaService.recordOverage(organizationId, 'storageBytes', limits.storageBytes, storage + declared);
+        return rejectOverQuota(req, res, 'storageBytes', limits.storageBytes, false);
+      }
+      warn(organizationId, 'uploadsPerDay', limits.uploadsPerDay, uploads);
+      warn(organizationId, 'storageBytes', limits.storageBytes, storage);
+      usage.uploadsPerDay = uploads;
+      usage.storageBytes = storage;
+
+      res.on('finish', () => {
+        if (res.statusCode >= 400) {
+          release().catch((error) => logger.error('Failed to release upload quota:', error));
+        }
+      });
+    } else if (req.method === 'GET') {
+      usage.storageBytes = await quotaService.getStorage(organizationId);
+    }
+
+    const usageHeader = formatUsage(usage, limits);
+    if (usageHeader) {
+      res.set('X-Quota-Usage', usageHeader);
+    }
+
+    const userId = req.headers['x-user-id'] as string | undefined;
+    if (userId && req.baseUrl === '/api/assessments' && req.method === 'POST') {
+      if (EXAM_START.test(req.path)) {
+        if (!(await quotaService.startExam(organizationId, userId, limits.concurrentExamTakers))) {
+          await releaseRequest();
+          await quotaService.recordOverage(organizationId, 'concurrentExamTakers', limits.concurrentExamTakers, limits.concurrentExamTakers + 1);
+          return rejectOverQuota(req, res, 'concurrentExamTakers', limits.concurrentExamTakers, false);
+        }
+        res.on('finish', () => {
+          if (res.statusCode >= 400) {
+            quotaService.finishExam(organizationId, userId).catch((error) => logger.error('Failed to release exam seat:', error));
+          }
+        });
+      } else if (EXAM_SUBMIT.test(req.path)) {
+        res.on('finish', () => {
+          if (res.statusCode < 400) {
+            quotaService.finishExam(organizationId, userId).catch((error) => logger.error('Failed to release exam seat:', error));
+          }
+        });
+      }
+    }
+  } catch (error) {
+    logger.error('Quota check failed:', error);
+  }
+
+  next();
+};
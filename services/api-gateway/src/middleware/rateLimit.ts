@@ -0,0 +1,153 @@
+import express from 'express';
+import jwt from 'jsonwebtoken';
+import { createHash } from 'crypto';
+import { config } from '../config/config';
+import { ApiKeyRule, CallerTier, RateLimitConfig, rateLimitConfig } from '../config/rateLimits';
+import { redisClient } from '../config/redis';
+import { logger } from '../utils/logger';
+
+// Fixed-window counter: increments the bucket, starting its window on the
+// first hit, and returns the count and milliseconds left in the window
+const INCREMENT_SCRIPT = `
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+  ttl = tonumber(ARGV[1])
+end
+return { count, ttl }
+`;
+
+// Per-instance fixed windows used while Redis is unavailable. Each gateway
+// replica then enforces the limits on its own, which is looser than the shared
+// count but never lets traffic through unlimited.
+class MemoryWindows {
+  private buckets = new Map<string, { count: number; resetAt: number }>();
+
+  constructor(sweepIntervalMs: number = 60 * 1000) {
+    setInterval(() => this.sweep(), sweepIntervalMs).unref();
+  }
+
+  increment(bucket: string, windowMs: number): [number, number] {
+    const now = Date.now();
+    let entry = this.buckets.get(bucket);
+    if (!entry || entry.resetAt <= now) {
+      entry = { count: 0, resetAt: now + windowMs };
+      this.buckets.set(bucket, entry);
+    }
+    entry.count++;
+    return [entry.count, entry.resetAt - now];
+  }
+
+  private sweep(): void {
+    const now = Date.now();
+    for (const [bucket, entry] of this.buckets) {
+      if (entry.resetAt <= now) {
+        this.buckets.delete(bucket);
+      }
+    }
+  }
+}
+
+interface Caller {
+  tier: CallerTier;
+  id: string;
+  apiKey?: ApiKeyRule;
+}
+
+const hashKey = (key: string): string => createHash('sha256').update(key).digest('hex');
+
+// Works out who is calling: a known X-API-Key is a service caller, a valid
+// bearer token an authenticated user, and anyone else is limited by IP
+const identifyCaller = (req: express.Request, apiKeys: Map<string, ApiKeyRule>): Caller => {
+  const apiKey = req.get('x-api-key');
+  if (apiKey) {
+    const rule = apiKeys.get(hashKey(apiKey));
+    if (rule) {
+      return { tier: 'service', id: `key:${rule.name}`, apiKey: rule };
+    }
+  }
+
+  const authHeader = req.headers['authorization'];
+  const token = authHeader && authHeader.split(' ')[1];
+  if (token) {
+    try {
+      const decoded = jwt.verify(token, config.JWT_SECRET);
+      const userId = typeof decoded === 'string' ? decoded : decoded.sub || decoded.userId || decoded.id;
+      if (userId) {
+        return { tier: 'authenticated', id: `user:${userId}` };
+      }
+    } catch {
+      // Invalid tokens are rejected by auth later; count them as anonymous
+    }
+  }
+
+  return { tier: 'anonymous', id: `ip:${req.ip}` };
+};
+
+// Limits requests per caller with separate quotas for anonymous,
+// authenticated and service-key callers, plus per-route overrides. Every
+// response carries X-RateLimit-Limit/Remaining/Reset; rejected ones also get
+// Retry-After. While Redis is unavailable each instance counts in memory.
+export const createRateLimiter = (limits: RateLimitConfig = rateLimitConfig): express.RequestHandler => {
+  const apiKeys = new Map(limits.apiKeys.map((rule) => [hashKey(rule.key), rule]));
+  const fallback = new MemoryWindows();
+
+  return async (req: express.Request, res: express.Response, next: express.NextFunction) => {
+    const caller = identifyCaller(req, apiKeys);
+    const route = limits.routes.find((rule) =>
+      req.path.startsWith(rule.pathPrefix) && (!rule.methods || rule.methods.includes(req.method))
+    );
+
+    const windowMs = route ? route.windowMs : limits.windowMs;
+    let max = route ? route.limits[caller.tier] : limits.limits[caller.tier];
+    if (!route && caller.apiKey?.max !== undefined) {
+      max = caller.apiKey.max;
+    }
+
+    const bucket = `ratelimit:${route ? route.name : 'default'}:${caller.id}`;
+    let count: number;
+    let ttl: number;
+    if (!redisClient.isReady) {
+      [count, ttl] = fallback.increment(bucket, windowMs);
+    } else {
+      try {
+        [count, ttl] = (await redisClient.eval(INCREMENT_SCRIPT, {
+          keys: [bucket],
+          arguments: [String(windowMs)]
+        })) as [number, number];
+      } catch (error) {
+        logger.error('Rate limit check failed, counting in memory:', error);
+        [count, ttl] = fallback.increment(bucket, windowMs);
+      }
+    }
+
+    const resetSeconds = Math.ceil(ttl / 1000);
+    res.set({
+      'X-RateLimit-Limit': String(max),
+      'X-RateLimit-Remaining': String(Math.max(max - count, 0)),
+      'X-RateLimit-Reset': String(Math.ceil((Date.now() + ttl) / 1000))
+    });
+
+    if (count > max) {
+      res.set('Retry-After', String(resetSeconds));
+      logger.warn('Rate limit exceeded', {
+        requestId: req.headers['x-request-id'],
+        tier: caller.tier,
+        route: route?.name || 'default',
+        path: req.path
+      });
+      res.status(429).json({
+        error: 'Too many requests, please try again later.',
+        retryAfter: resetSeconds,
+        requestId: req.headers['x-request-id']
+      });
+      return;
+    }
+
+    next();
+  };
+};
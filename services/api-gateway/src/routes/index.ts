@@ -1,8 +1,11 @@
 import express from 'express';
 import { createProxyMiddleware } from 'http-proxy-middleware';
 import { config } from '../config/config';
-import { authenticateToken, optionalAuth } from '../middleware/auth';
+import { authenticateToken, optionalAuth, requireAdmin } from '../middleware/auth';
 import { metricsHandler } from '../middleware/metrics';
+import { enforceTenantQuotas } from '../middleware/quota';
+import { quotaService } from '../services/quotaService';
+import { createQuotaRouter } from './quotas';
 import { logger } from '../utils/logger';
 
 interface AuthenticatedRequest extends express.Request {
@@ -16,7 +19,7 @@ export const setupRoutes = (app: express.Application) => {
   // Service proxy routes with authentication
   
   // User Management Service - Authentication required
-  app.use('/api/users', authenticateToken as any, createProxyMiddleware({
+  app.use('/api/users', authenticateToken as any, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.USER_MANAGEMENT,
     changeOrigin: true,
     pathRewrite: { '^/api/users': '' },
@@ -38,7 +41,7 @@ export const setupRoutes = (app: express.Application) => {
   }));
 
   // Content Delivery Service - Optional authentication
-  app.use('/api/content', optionalAuth as any, createProxyMiddleware({
+  app.use('/api/content', optionalAuth as any, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.CONTENT_DELIVERY,
     changeOrigin: true,
     pathRewrite: { '^/api/content': '' },
@@ -55,7 +58,7 @@ export const setupRoutes = (app: express.Application) => {
     } else {
       authenticateToken(req, res, next);
     }
-  }, createProxyMiddleware({
+  }, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.COURSE_MANAGEMENT,
     changeOrigin: true,
     pathRewrite: { '^/api/courses': '' },
@@ -66,7 +69,7 @@ export const setupRoutes = (app: express.Application) => {
   }));
 
   // Enrollment Service - Authentication required
-  app.use('/api/enrollments', authenticateToken as any, createProxyMiddleware({
+  app.use('/api/enrollments', authenticateToken as any, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.ENROLLMENT,
     changeOrigin: true,
     pathRewrite: { '^/api/enrollments': '' },
@@ -77,7 +80,7 @@ export const setupRoutes = (app: express.Application) => {
   }));
 
   // Assessment Service - Authentication required
-  app.use('/api/assessments', authenticateToken as any, createProxyMiddleware({
+  app.use('/api/assessments', authenticateToken as any, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.ASSESSMENT,
     changeOrigin: true,
     pathRewrite: { '^/api/assessments': '' },
@@ -88,7 +91,7 @@ export const setupRoutes = (app: express.Application) => {
   }));
 
   // Payment Service - Authentication required
-  app.use('/api/payments', authenticateToken as any, createProxyMiddleware({
+  app.use('/api/payments', authenticateToken as any, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.PAYMENT,
     changeOrigin: true,
     pathRewrite: { '^/api/payments': '' },
@@ -99,7 +102,7 @@ export const setupRoutes = (app: express.Application) => {
   }));
 
   // Analytics Service - Authentication required (admin/instructor only)
-  app.use('/api/analytics', authenticateToken as any, createProxyMiddleware({
+  app.use('/api/analytics', authenticateToken as any, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.ANALYTICS,
     changeOrigin: true,
     pathRewrite: { '^/api/analytics': '' },
@@ -110,7 +113,7 @@ export const setupRoutes = (app: express.Application) => {
   }));
 
   // Notification Service - Authentication required
-  app.use('/api/notifications', authenticateToken as any, createProxyMiddleware({
+  app.use('/api/notifications', authenticateToken as any, enforceTenantQuotas, createProxyMiddleware({
     target: config.SERVICES.NOTIFICATION,
     changeOrigin: true,
     pathRewrite: { '^/api/notifications': '' },
@@ -120,6 +123,20 @@ export const setupRoutes = (app: express.Application) => {
     }
   }));
 
+  // Event Bus webhook subscriptions - Authentication required
+  app.use('/api/webhooks', authenticateToken as any, enforceTenantQuotas, createProxyMiddleware({
+    target: config.SERVICES.EVENT_BUS,
+    changeOrigin: true,
+    pathRewrite: { '^/api/webhooks': '/api/v1/webhooks' },
+    onError: (err: any, req: any, res: any) => {
+      logger.error('Event Bus proxy error:', err);
+      res.status(503).json({ error: 'Event Bus unavailable' });
+    }
+  }));
+
+  // Tenant quota administration - platform admins only
+  app.use('/api/admin/quotas', authenticateToken as any, requireAdmin as any, createQuotaRouter(quotaService));
+
   // API documentation
   app.get('/api', (req, res) => {
     res.json({
@@ -134,7 +151,9 @@ export const setupRoutes = (app: express.Application) => {
         '/api/assessments': 'Assessment endpoints',
         '/api/payments': 'Payment endpoints',
         '/api/analytics': 'Analytics endpoints',
-        '/api/notifications': 'Notification endpoints'
+        '/api/notifications': 'Notification endpoints',
+        '/api/webhooks': 'Webhook subscription endpoints',
+        '/api/admin/quotas': 'Organization quota administration'
       }
     });
   });
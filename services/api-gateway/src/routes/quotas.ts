@@ -0,0 +1,65 @@
+import express from 'express';
+import { QUOTA_NAMES, QuotaName, isQuotaLimit, quotaPlans } from '../config/quotas';
+import { QuotaService } from '../services/quotaService';
+
+// Admin API for viewing organizations' usage and adjusting their plan or limits
+export const createQuotaRouter = (quotas: QuotaService): express.Router => {
+  const router = express.Router();
+
+  router.get('/plans', (req, res) => {
+    res.json({ plans: quotaPlans });
+  });
+
+  router.get('/:orgId', async (req, res, next) => {
+    try {
+      res.json(await quotas.getQuotas(req.params.orgId));
+    } catch (error) {
+      next(error);
+    }
+  });
+
+  // Body: { plan?: string, limits?: { [quota]: number | null } }; null clears an override
+  router.put('/:orgId', async (req, res, next) => {
+    try {
+      const { plan, limits } = req.body || {};
+
+      if (plan !== undefined && (typeof plan !== 'string' || !quotaPlans[plan])) {
+        return res.status(400).json({ error: `plan must be one of ${Object.keys(quotaPlans).join(', ')}` });
+      }
+      if (limits !== undefined) {
+        if (!limits || typeof limits !== 'object' || Array.isArray(limits)) {
+          return res.status(400).json({ error: 'limits must be an object' });
+        }
+        const invalid = Object.entries(limits).filter(([quota, limit]) =>
+          !QUOTA_NAMES.includes(quota as QuotaName) || (limit !== null && !isQuotaLimit(limit))
+        );
+        if (invalid.length > 0) {
+          return res.status(400).json({
+            error: `limits must map ${QUOTA_NAMES.join(', ')} to non-negative integers or null`
+          });
+        }
+      }
+
+      res.json(await quotas.updateQuotas(req.params.orgId, plan, limits));
+    } catch (error) {
+      next(error);
+    }
+  });
+
+  // Lets the storage figure be reconciled with what the organization actually stores
+  router.put('/:orgId/usage/storage', async (req, res, next) => {
+    try {
+      const bytes = req.body?.bytes;
+      if (!isQuotaLimit(bytes)) {
+        return res.status(400).json({ error: 'bytes must be a non-negative integer' });
+      }
+
+      await quotas.setStorage(req.params.orgId, bytes);
+      res.json(await quotas.getQuotas(req.params.orgId));
+    } catch (error) {
+      next(error);
+    }
+  });
+
+  return router;
+};
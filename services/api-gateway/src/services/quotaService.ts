@@ -0,0 +1,249 @@
+import { v4 as uuidv4 } from 'uuid';
+import { config } from '../config/config';
+import { QUOTA_NAMES, QuotaLimits, QuotaName, defaultPlan, quotaPlans, warningThresholds } from '../config/quotas';
+import { redisClient } from '../config/redis';
+import { logger } from '../utils/logger';
+
+// Daily counters are kept a day past their date so yesterday's usage can still be read
+const DAILY_COUNTER_TTL_SECONDS = 2 * 24 * 60 * 60;
+
+// Overage and warning events share the Redis channels the Go services publish to
+const SYSTEM_EVENTS_CHANNEL = 'system-events';
+
+// Storage is counted per stored object so a repeated event can't count it twice.
+// KEYS: content sizes hash, storage counter; ARGV: content ID, bytes
+const ADD_CONTENT_SCRIPT = `
+if redis.call('HSETNX', KEYS[1], ARGV[1], ARGV[2]) == 1 then
+  return redis.call('INCRBY', KEYS[2], ARGV[2])
+end
+return tonumber(redis.call('GET', KEYS[2]) or '0')
+`;
+
+// KEYS: content sizes hash, storage counter; ARGV: content ID
+const REMOVE_CONTENT_SCRIPT = `
+local bytes = redis.call('HGET', KEYS[1], ARGV[1])
+if bytes then
+  redis.call('HDEL', KEYS[1], ARGV[1])
+  return redis.call('INCRBY', KEYS[2], -tonumber(bytes))
+end
+return tonumber(redis.call('GET', KEYS[2]) or '0')
+`;
+
+export interface QuotaUsage {
+  requestsPerDay: number;
+  uploadsPerDay: number;
+  storageBytes: number;
+  concurrentExamTakers: number;
+}
+
+export interface OrganizationQuotas {
+  organizationId: string;
+  plan: string;
+  limits: QuotaLimits;
+  overrides: Partial<QuotaLimits>;
+  usage: QuotaUsage;
+  resetsAt: string;
+}
+
+const today = (): string => new Date().toISOString().slice(0, 10);
+
+// Start of the next UTC day, when the daily counters reset
+export const nextReset = (): Date => {
+  const reset = new Date();
+  reset.setUTCHours(24, 0, 0, 0);
+  return reset;
+};
+
+const key = (organizationId: string, ...parts: string[]): string =>
+  ['quota', organizationId, ...parts].join(':');
+
+// Tracks each organization's usage against its plan in Redis. An
+// organization's plan and any per-organization overrides are stored there
+// too, so admins can change them without a deploy.
+export class QuotaService {
+  async getPlan(organizationId: string): Promise<string> {
+    const plan = await redisClient.get(key(organizationId, 'plan'));
+    return plan && quotaPlans[plan] ? plan : defaultPlan;
+  }
+
+  async getOverrides(organizationId: string): Promise<Partial<QuotaLimits>> {
+    const raw = await redisClient.hGetAll(key(organizationId, 'overrides'));
+    const overrides: Partial<QuotaLimits> = {};
+    for (const quota of QUOTA_NAMES) {
+      if (raw[quota] !== undefined) {
+        overrides[quota] = parseInt(raw[quota]);
+      }
+    }
+    return overrides;
+  }
+
+  async getLimits(organizationId: string): Promise<QuotaLimits> {
+    const [plan, overrides] = await Promise.all([this.getPlan(organizationId), this.getOverrides(organizationId)]);
+    return { ...quotaPlans[plan], ...overrides };
+  }
+
+  async getQuotas(organizationId: string): Promise<OrganizationQuotas> {
+    const [plan, overrides, usage] = await Promise.all([
+      this.getPlan(organizationId),
+      this.getOverrides(organizationId),
+      this.getUsage(organizationId)
+    ]);
+
+    return {
+      organizationId,
+      plan,
+      limits: { ...quotaPlans[plan], ...overrides },
+      overrides,
+      usage,
+      resetsAt: nextReset().toISOString()
+    };
+  }
+
+  async getUsage(organizationId: string): Promise<QuotaUsage> {
+    const date = today();
+    await redisClient.zRemRangeByScore(key(organizationId, 'exam-takers'), '-inf', Date.now());
+
+    const [requests, uploads, storage, examTakers] = await Promise.all([
+      redisClient.get(key(organizationId, 'requests', date)),
+      redisClient.get(key(organizationId, 'uploads', date)),
+      redisClient.get(key(organizationId, 'storage')),
+      redisClient.zCard(key(organizationId, 'exam-takers'))
+    ]);
+
+    return {
+      requestsPerDay: parseInt(requests || '0'),
+      uploadsPerDay: parseInt(uploads || '0'),
+      storageBytes: parseInt(storage || '0'),
+      concurrentExamTakers: examTakers
+    };
+  }
+
+  // Changes the organization's plan and/or overrides; a null override clears it
+  async updateQuotas(
+    organizationId: string,
+    plan: string | undefined,
+    overrides: Partial<Record<QuotaName, number | null>> | undefined
+  ): Promise<OrganizationQuotas> {
+    if (plan) {
+      await redisClient.set(key(organizationId, 'plan'), plan);
+    }
+
+    for (const [quota, limit] of Object.entries(overrides || {})) {
+      if (limit === null) {
+        await redisClient.hDel(key(organizationId, 'overrides'), quota);
+      } else {
+        await redisClient.hSet(key(organizationId, 'overrides'), quota, String(limit));
+      }
+    }
+
+    logger.info('Organization quotas updated', { organizationId, plan, overrides });
+    return this.getQuotas(organizationId);
+  }
+
+  // Adds amount to today's requests or uploads counter and returns the new total
+  async incrementDaily(organizationId: string, quota: 'requests' | 'uploads', amount: number = 1): Promise<number> {
+    const counter = key(organizationId, quota, today());
+    const [total] = await redisClient.multi()
+      .incrBy(counter, amount)
+      .expire(counter, DAILY_COUNTER_TTL_SECONDS)
+      .exec();
+    return Number(total);
+  }
+
+  // Counts a stored object against the organization's storage and returns the new total
+  async addContent(organizationId: string, contentId: string, bytes: number): Promise<number> {
+    return Number(await redisClient.eval(ADD_CONTENT_SCRIPT, {
+      keys: [key(organizationId, 'content'), key(organizationId, 'storage')],
+      arguments: [contentId, String(bytes)]
+    }));
+  }
+
+  // Stops counting a deleted object and returns the new total
+  async removeContent(organizationId: string, contentId: string): Promise<number> {
+    return Number(await redisClient.eval(REMOVE_CONTENT_SCRIPT, {
+      keys: [key(organizationId, 'content'), key(organizationId, 'storage')],
+      arguments: [contentId]
+    }));
+  }
+
+  async getStorage(organizationId: string): Promise<number> {
+    return parseInt((await redisClient.get(key(organizationId, 'storage'))) || '0');
+  }
+
+  // Replaces the storage figure, for reconciling against what is actually stored
+  async setStorage(organizationId: string, bytes: number): Promise<void> {
+    await redisClient.set(key(organizationId, 'storage'), String(bytes));
+  }
+
+  // Admits a student to an exam unless the organization is at its limit of
+  // concurrent takers. A student already taking an exam doesn't count twice.
+  async startExam(organizationId: string, userId: string, limit: number): Promise<boolean> {
+    const takers = key(organizationId, 'exam-takers');
+    await redisClient.zRemRangeByScore(takers, '-inf', Date.now());
+
+    if (limit > 0 && (await redisClient.zScore(takers, userId)) === null &&
+        (await redisClient.zCard(takers)) >= limit) {
+      return false;
+    }
+
+    await redisClient.zAdd(takers, { score: Date.now() + config.QUOTAS.EXAM_SESSION_TTL, value: userId });
+    return true;
+  }
+
+  async finishExam(organizationId: string, userId: string): Promise<void> {
+    await redisClient.zRem(key(organizationId, 'exam-takers'), userId);
+  }
+
+  // Publishes QUOTA_EXCEEDED the first time a quota is exceeded each day
+  async recordOverage(organizationId: string, quota: QuotaName, limit: number, used: number): Promise<void> {
+    const marker = key(organizationId, 'exceeded', quota, today());
+    const first = await redisClient.set(marker, '1', { NX: true, EX: DAILY_COUNTER_TTL_SECONDS });
+    if (!first) {
+      return;
+    }
+
+    const plan = await this.getPlan(organizationId);
+    logger.warn('Organization quota exceeded', { organizationId, quota, limit, used, plan });
+    await this.publish('QUOTA_EXCEEDED', organizationId, { organizationId, quota, limit, used, plan });
+  }
+
+  // Publishes QUOTA_WARNING the first time each day usage reaches one of the
+  // warning thresholds, so organizations hear before requests start failing.
+  // Usage over the limit is left to recordOverage.
+  async checkWarning(organizationId: string, quota: QuotaName, limit: number, used: number): Promise<void> {
+    if (limit <= 0 || used > limit) {
+      return;
+    }
+
+    const percent = (used / limit) * 100;
+    const threshold = warningThresholds.find((t) => percent >= t);
+    if (threshold === undefined) {
+      return;
+    }
+
+    const marker = key(organizationId, 'warned', quota, String(threshold), today());
+    const first = await redisClient.set(marker, '1', { NX: true, EX: DAILY_COUNTER_TTL_SECONDS });
+    if (!first) {
+      return;
+    }
+
+    const plan = await this.getPlan(organizationId);
+    logger.info('Organization quota warning', { organizationId, quota, threshold, limit, used, plan });
+    await this.publish('QUOTA_WARNING', organizationId, { organizationId, quota, threshold, limit, used, plan });
+  }
+
+  private async publish(eventType: string, organizationId: string, data: Record<string, any>): Promise<void> {
+    await redisClient.publish(SYSTEM_EVENTS_CHANNEL, JSON.stringify({
+      id: uuidv4(),
+      aggregateId: organizationId,
+      aggregateType: 'Organization',
+      eventType,
+      version: 1,
+      timestamp: new Date().toISOString(),
+      data,
+      metadata: { source: 'api-gateway' }
+    }));
+  }
+}
+
+export const quotaService = new QuotaService();
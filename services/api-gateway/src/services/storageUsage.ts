@@ -0,0 +1,49 @@
+import { redisClient } from '../config/redis';
+import { logger } from '../utils/logger';
+import { quotaService } from './quotaService';
+
+// Content services publish their events to the Redis channel of their topic
+const CONTENT_EVENTS_CHANNEL = 'content-events';
+
+// Takes the organization from the event data, falling back to its metadata
+const eventOrganization = (event: any): string | undefined =>
+  event?.data?.organizationId || event?.metadata?.organizationId;
+
+const handleContentEvent = async (message: string): Promise<void> => {
+  const event = JSON.parse(message);
+  const organizationId = eventOrganization(event);
+  const contentId = event?.data?.contentId;
+  if (!organizationId || !contentId) {
+    return;
+  }
+
+  switch (event.eventType) {
+    case 'CONTENT_UPLOADED': {
+      const bytes = Number(event.data.fileSize);
+      if (!Number.isInteger(bytes) || bytes < 0) {
+        logger.warn('Ignoring upload with invalid size', { organizationId, contentId, fileSize: event.data.fileSize });
+        return;
+      }
+      await quotaService.addContent(organizationId, contentId, bytes);
+      break;
+    }
+    case 'CONTENT_DELETED':
+      await quotaService.removeContent(organizationId, contentId);
+      break;
+  }
+};
+
+// Keeps each organization's storage figure in step with what the content
+// service actually stores: objects are counted when CONTENT_UPLOADED reports
+// their size and uncounted on CONTENT_DELETED. Admins can still reconcile the
+// figure through PUT /api/admin/quotas/:orgId/usage/storage.
+export const startStorageTracking = async (): Promise<void> => {
+  const subscriber = redisClient.duplicate();
+  subscriber.on('error', (error) => logger.error('Storage tracking Redis error:', error));
+  await subscriber.connect();
+
+  await subscriber.subscribe(CONTENT_EVENTS_CHANNEL, (message) => {
+    handleContentEvent(message).catch((error) => logger.error('Failed to track content storage:', error));
+  });
+  logger.info('Tracking organization storage from content events');
+};
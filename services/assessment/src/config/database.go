@@ -38,12 +38,25 @@ func InitDatabase() error {
 }
 
 func MigrateDatabase() error {
+	if err := renumberSubmissionAttempts(); err != nil {
+		return fmt.Errorf("failed to renumber submission attempts: %w", err)
+	}
+
 	err := DB.AutoMigrate(
 		&models.Assessment{},
 		&models.Question{},
 		&models.QuestionOption{},
 		&models.Submission{},
 		&models.SubmissionAnswer{},
+		&models.QuestionBank{},
+		&models.BankQuestion{},
+		&models.BankListing{},
+		&models.BankImport{},
+		&models.BankQuestionUsage{},
+		&models.GradingScale{},
+		&models.ExportJob{},
+		&models.AvailabilityOverride{},
+		&models.AssessmentPool{},
 	)
 
 	if err != nil {
@@ -54,6 +67,34 @@ func MigrateDatabase() error {
 	return nil
 }
 
+// renumberSubmissionAttempts numbers each student's attempts at an assessment
+// 1, 2, 3... in the order they were started. Attempts started concurrently
+// before idx_submission_attempt existed can share a number, which would stop
+// the index from being created. It only runs until the index exists.
+func renumberSubmissionAttempts() error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Submission{}) || migrator.HasIndex(&models.Submission{}, "idx_submission_attempt") {
+		return nil
+	}
+
+	result := DB.Exec(`
+		UPDATE submissions SET attempt_number = numbered.attempt
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (
+				PARTITION BY assessment_id, student_id ORDER BY started_at, created_at, id
+			) AS attempt
+			FROM submissions
+		) numbered
+		WHERE submissions.id = numbered.id AND submissions.attempt_number <> numbered.attempt`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Renumbered %d submission attempts before adding idx_submission_attempt", result.RowsAffected)
+	}
+	return nil
+}
+
 func CloseDatabase() {
 	if DB != nil {
 		if sqlDB, err := DB.DB(); err == nil {
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -11,23 +13,46 @@ import (
 
 type AssessmentHandler struct {
 	assessmentService *services.AssessmentService
+	gradingService    *services.GradingScaleService
+	previewService    *services.QuestionPreviewService
+	answerKeyService  *services.AnswerKeyService
+	availability      *services.AvailabilityService
+	bankService       *services.QuestionBankService
+	policy            *services.AccessPolicy
 }
 
 func NewAssessmentHandler() *AssessmentHandler {
 	return &AssessmentHandler{
 		assessmentService: services.NewAssessmentService(),
+		gradingService:    services.NewGradingScaleService(),
+		previewService:    services.NewQuestionPreviewService(),
+		answerKeyService:  services.NewAnswerKeyService(),
+		availability:      services.NewAvailabilityService(),
+		bankService:       services.NewQuestionBankService(),
+		policy:            services.NewAccessPolicy(),
 	}
 }
 
-// CreateAssessment creates a new assessment
+// CreateAssessment creates a new assessment owned by the caller
 func (h *AssessmentHandler) CreateAssessment(c *gin.Context) {
+	caller, ok := requestCaller(c)
+	if !ok {
+		return
+	}
+
 	var assessment models.Assessment
 	if err := c.ShouldBindJSON(&assessment); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	assessment.CreatedBy = caller.UserID
+	assessment.OrganizationID = requestOrganization(c)
 
 	if err := h.assessmentService.CreateAssessment(&assessment); err != nil {
+		if errors.Is(err, services.ErrInvalidQuestion) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -43,12 +68,33 @@ func (h *AssessmentHandler) GetAssessment(c *gin.Context) {
 		return
 	}
 
+	caller, ok := requestCaller(c)
+	if !ok {
+		return
+	}
+
 	assessment, err := h.assessmentService.GetAssessmentByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
 		return
 	}
 
+	// Questions carry the answer key and submissions are everyone's work, so
+	// both are only for those who grade them. Students get their questions
+	// from their attempt's take payload.
+	staff, err := h.policy.IsStaff(c.Request.Context(), caller, assessment, services.CoursePermissionGradeSubmissions)
+	if err != nil || !staff {
+		questionCount, err := h.assessmentService.PaperLength(assessment)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		assessment.Questions = nil
+		assessment.Submissions = nil
+		c.JSON(http.StatusOK, gin.H{"data": assessment, "questionCount": questionCount})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"data": assessment})
 }
 
@@ -77,14 +123,30 @@ func (h *AssessmentHandler) UpdateAssessment(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.assessmentService.GetAssessmentByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+		return
+	}
+	if !authorizeAssessment(c, h.policy, existing, services.CoursePermissionManageContent) {
+		return
+	}
+
 	var assessment models.Assessment
 	if err := c.ShouldBindJSON(&assessment); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Ownership doesn't change through an update
 	assessment.ID = id
+	assessment.CreatedBy = existing.CreatedBy
+	assessment.OrganizationID = existing.OrganizationID
 	if err := h.assessmentService.UpdateAssessment(&assessment); err != nil {
+		if errors.Is(err, services.ErrInvalidQuestion) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -100,6 +162,15 @@ func (h *AssessmentHandler) DeleteAssessment(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.assessmentService.GetAssessmentByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+		return
+	}
+	if !authorizeAssessment(c, h.policy, existing, services.CoursePermissionManageContent) {
+		return
+	}
+
 	if err := h.assessmentService.DeleteAssessment(id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -108,6 +179,40 @@ func (h *AssessmentHandler) DeleteAssessment(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// DeleteQuestion moves a question to the trash
+func (h *AssessmentHandler) DeleteQuestion(c *gin.Context) {
+	assessmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assessment ID"})
+		return
+	}
+	questionID, err := uuid.Parse(c.Param("questionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(assessmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+		return
+	}
+	if !authorizeAssessment(c, h.policy, assessment, services.CoursePermissionManageContent) {
+		return
+	}
+
+	if err := h.assessmentService.DeleteQuestion(assessmentID, questionID); err != nil {
+		if errors.Is(err, services.ErrQuestionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
 // StartAssessment creates a new submission for a student
 func (h *AssessmentHandler) StartAssessment(c *gin.Context) {
 	assessmentID, err := uuid.Parse(c.Param("id"))
@@ -116,30 +221,165 @@ func (h *AssessmentHandler) StartAssessment(c *gin.Context) {
 		return
 	}
 
+	caller, ok := requestCaller(c)
+	if !ok {
+		return
+	}
+
 	var req struct {
-		StudentID uuid.UUID `json:"studentId" binding:"required"`
+		StudentID     uuid.UUID `json:"studentId" binding:"required"`
+		OverrideToken string    `json:"overrideToken"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.StudentID != caller.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Students can only start their own attempts"})
+		return
+	}
 
-	// Check existing attempts
-	existing, _ := h.assessmentService.GetStudentSubmissions(req.StudentID, assessmentID)
-	
-	submission := models.Submission{
-		AssessmentID:  assessmentID,
-		StudentID:     req.StudentID,
-		AttemptNumber: len(existing) + 1,
-		Status:        models.SubmissionStatusInProgress,
+	submission, err := h.assessmentService.StartSubmission(assessmentID, req.StudentID, req.OverrideToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAssessmentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+		case errors.Is(err, services.ErrAttemptsExhausted):
+			c.JSON(http.StatusConflict, gin.H{"error": "No attempts remaining for this assessment", "detail": err.Error()})
+		case errors.Is(err, services.ErrAssessmentNotOpen):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Assessment is not open yet"})
+		case errors.Is(err, services.ErrAssessmentClosed):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Assessment is closed"})
+		case errors.Is(err, services.ErrInvalidOverride):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Override token is invalid, expired or already used"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
 	}
 
-	if err := h.assessmentService.CreateSubmission(&submission); err != nil {
+	questions, err := h.assessmentService.AttemptQuestions(submission)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"data": submission})
+	// serverTime lets clients correct for clock skew when counting down to
+	// the deadline
+	c.JSON(http.StatusCreated, gin.H{"data": submission, "questions": questions, "serverTime": time.Now().UTC()})
+}
+
+// TakeAssessment returns the payload for taking a submission's attempt:
+// assessment details, timing, and questions and options in the attempt's
+// order, without correct answers or explanations
+func (h *AssessmentHandler) TakeAssessment(c *gin.Context) {
+	submissionID, err := uuid.Parse(c.Param("submissionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+
+	caller, ok := requestCaller(c)
+	if !ok {
+		return
+	}
+
+	submission, err := h.assessmentService.GetSubmission(submissionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return
+	}
+	if submission.StudentID != caller.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Students can only take their own attempts"})
+		return
+	}
+
+	paper, err := h.assessmentService.TakePaper(submission)
+	if err != nil {
+		if errors.Is(err, services.ErrAssessmentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"data": paper})
+}
+
+// GetAttemptQuestions returns a submission's questions in the order its
+// attempt shows them, for resuming an attempt
+func (h *AssessmentHandler) GetAttemptQuestions(c *gin.Context) {
+	submissionID, err := uuid.Parse(c.Param("submissionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+
+	submission, err := h.assessmentService.GetSubmission(submissionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return
+	}
+	if !authorizeSubmissions(c, h.policy, submission.AssessmentID, submission.StudentID) {
+		return
+	}
+
+	questions, err := h.assessmentService.AttemptQuestions(submission)
+	if err != nil {
+		if errors.Is(err, services.ErrAssessmentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": questions})
+}
+
+// IssueAvailabilityOverride gives a student a single-use token to start an
+// attempt outside the assessment's availability window
+func (h *AssessmentHandler) IssueAvailabilityOverride(c *gin.Context) {
+	assessmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assessment ID"})
+		return
+	}
+
+	var req struct {
+		StudentID  uuid.UUID `json:"studentId" binding:"required"`
+		ValidUntil time.Time `json:"validUntil" binding:"required"`
+		Reason     string    `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(assessmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+		return
+	}
+	if !authorizeAssessment(c, h.policy, assessment, services.CoursePermissionManageContent) {
+		return
+	}
+	caller, _ := requestCaller(c)
+
+	override, token, err := h.availability.IssueOverride(assessmentID, req.StudentID, caller.UserID, req.ValidUntil, req.Reason)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidOverride) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The token is only ever shown here
+	c.JSON(http.StatusCreated, gin.H{"data": override, "token": token})
 }
 
 // SubmitAssessment submits answers for an assessment
@@ -150,6 +390,11 @@ func (h *AssessmentHandler) SubmitAssessment(c *gin.Context) {
 		return
 	}
 
+	caller, ok := requestCaller(c)
+	if !ok {
+		return
+	}
+
 	var req struct {
 		Answers []models.SubmissionAnswer `json:"answers" binding:"required"`
 	}
@@ -158,8 +403,32 @@ func (h *AssessmentHandler) SubmitAssessment(c *gin.Context) {
 		return
 	}
 
-	if err := h.assessmentService.SubmitAssessment(submissionID, req.Answers); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	submission, err := h.assessmentService.GetSubmission(submissionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return
+	}
+	if submission.StudentID != caller.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Students can only submit their own attempts"})
+		return
+	}
+
+	submitted, err := h.assessmentService.SubmitAssessment(submissionID, req.Answers)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSubmissionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		case errors.Is(err, services.ErrSubmissionClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": "Submission has already been submitted"})
+		case errors.Is(err, services.ErrAssessmentClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": "Assessment closed before this attempt was submitted", "closesAt": submission.ClosesAt})
+		case errors.Is(err, services.ErrSubmissionPastDeadline):
+			c.JSON(http.StatusConflict, gin.H{"error": "Time limit for this attempt has passed", "deadline": submission.Deadline})
+		case errors.Is(err, services.ErrInvalidAnswer):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -168,7 +437,14 @@ func (h *AssessmentHandler) SubmitAssessment(c *gin.Context) {
 		h.assessmentService.GradeSubmission(submissionID)
 	}()
 
-	c.JSON(http.StatusOK, gin.H{"message": "Assessment submitted successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Assessment submitted successfully",
+		"data": gin.H{
+			"submittedAt": submitted.SubmittedAt,
+			"timeSpent":   submitted.TimeSpent,
+			"late":        submitted.Late,
+		},
+	})
 }
 
 // GetSubmission retrieves a submission by ID
@@ -184,6 +460,16 @@ func (h *AssessmentHandler) GetSubmission(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
 		return
 	}
+	if !authorizeSubmissions(c, h.policy, submission.AssessmentID, submission.StudentID) {
+		return
+	}
+
+	graded := []models.Submission{*submission}
+	if err := h.gradingService.GradeSubmissions(submission.AssessmentID, requestOrganization(c), graded); err != nil && !errors.Is(err, services.ErrAssessmentNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	submission = &graded[0]
 
 	c.JSON(http.StatusOK, gin.H{"data": submission})
 }
@@ -202,11 +488,212 @@ func (h *AssessmentHandler) GetStudentSubmissions(c *gin.Context) {
 		return
 	}
 
+	if !authorizeSubmissions(c, h.policy, assessmentID, studentID) {
+		return
+	}
+
 	submissions, err := h.assessmentService.GetStudentSubmissions(studentID, assessmentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := h.gradingService.GradeSubmissions(assessmentID, requestOrganization(c), submissions); err != nil && !errors.Is(err, services.ErrAssessmentNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"data": submissions})
-}
\ No newline at end of file
+}
+
+type previewQuestionRequest struct {
+	Question models.Question `json:"question" binding:"required"`
+	Shuffle  *bool           `json:"shuffle"` // defaults to true
+	Seed     *int64          `json:"seed"`
+}
+
+// PreviewQuestion renders a question as students will see it without saving it
+func (h *AssessmentHandler) PreviewQuestion(c *gin.Context) {
+	var req previewQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	shuffle := req.Shuffle == nil || *req.Shuffle
+	preview, err := h.previewService.Preview(&req.Question, shuffle, req.Seed)
+	if errors.Is(err, services.ErrInvalidQuestion) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported question type"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preview})
+}
+
+// GetAnswerKey exports an assessment's answer key for offline review. Only
+// the assessment's instructor may export it, and each export is watermarked.
+func (h *AssessmentHandler) GetAnswerKey(c *gin.Context) {
+	id, ok := uuidParam(c, "id", "Invalid assessment ID")
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return
+	}
+
+	key, err := h.answerKeyService.Export(id, userID)
+	if err != nil {
+		respondAnswerKeyError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"data": key})
+}
+
+type importAnswerKeyRequest struct {
+	Questions []services.AnswerKeyUpdate `json:"questions" binding:"required,dive"`
+}
+
+// ImportAnswerKey applies a reviewed answer key. The response says whether
+// graded submissions need a regrade to reflect it.
+func (h *AssessmentHandler) ImportAnswerKey(c *gin.Context) {
+	id, ok := uuidParam(c, "id", "Invalid assessment ID")
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return
+	}
+
+	var req importAnswerKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.answerKeyService.Import(id, userID, req.Questions)
+	if err != nil {
+		respondAnswerKeyError(c, err)
+		return
+	}
+
+	response := gin.H{"data": result}
+	if result.RegradeRequired {
+		response["regrade"] = gin.H{
+			"message": "Graded submissions were scored against the old key",
+			"href":    "/api/v1/assessments/" + id.String() + "/regrade",
+			"method":  http.MethodPost,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RegradeAssessment rescores graded submissions against the current answer key
+func (h *AssessmentHandler) RegradeAssessment(c *gin.Context) {
+	id, ok := uuidParam(c, "id", "Invalid assessment ID")
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return
+	}
+
+	result, err := h.answerKeyService.Regrade(id, userID)
+	if err != nil {
+		respondAnswerKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetQuestionPools lists the bank pools an assessment draws from
+func (h *AssessmentHandler) GetQuestionPools(c *gin.Context) {
+	id, ok := uuidParam(c, "id", "Invalid assessment ID")
+	if !ok {
+		return
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+		return
+	}
+	if !authorizeAssessment(c, h.policy, assessment, services.CoursePermissionManageContent) {
+		return
+	}
+
+	pools, err := h.bankService.GetPools(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": pools})
+}
+
+// SetQuestionPools replaces the bank pools an assessment draws from at each
+// attempt's start
+func (h *AssessmentHandler) SetQuestionPools(c *gin.Context) {
+	id, ok := uuidParam(c, "id", "Invalid assessment ID")
+	if !ok {
+		return
+	}
+	userID, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Pools []models.AssessmentPool `json:"pools" binding:"dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	assessment, err := h.assessmentService.GetAssessmentByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+		return
+	}
+	if !authorizeAssessment(c, h.policy, assessment, services.CoursePermissionManageContent) {
+		return
+	}
+
+	pools, err := h.bankService.SetPools(id, orgID, userID, req.Pools)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPool), errors.Is(err, services.ErrPoolTooSmall):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			respondBankError(c, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": pools})
+}
+
+func respondAnswerKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrAssessmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+	case errors.Is(err, services.ErrNotAssessmentOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the assessment's instructor can manage its answer key"})
+	case errors.Is(err, services.ErrInvalidAnswerKey):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"github.com/modex/assessment/src/services"
+)
+
+// requestCaller reads the authenticated caller, writing a 401 when missing
+func requestCaller(c *gin.Context) (services.Caller, bool) {
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return services.Caller{}, false
+	}
+	return services.Caller{UserID: userID, Role: c.GetHeader("X-User-Role")}, true
+}
+
+// authorizeAssessment checks the caller is staff on assessment with perm,
+// writing the error response and returning false otherwise
+func authorizeAssessment(c *gin.Context, policy *services.AccessPolicy, assessment *models.Assessment, perm string) bool {
+	caller, ok := requestCaller(c)
+	if !ok {
+		return false
+	}
+
+	allowed, err := policy.IsStaff(c.Request.Context(), caller, assessment, perm)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not permitted for this assessment"})
+		return false
+	}
+	return true
+}
+
+// authorizeSubmissions checks the caller may read studentID's submissions to
+// assessmentID, writing the error response and returning false otherwise
+func authorizeSubmissions(c *gin.Context, policy *services.AccessPolicy, assessmentID, studentID uuid.UUID) bool {
+	caller, ok := requestCaller(c)
+	if !ok {
+		return false
+	}
+
+	allowed, err := policy.CanReadSubmissions(c.Request.Context(), caller, assessmentID, studentID)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not permitted to view these submissions"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"github.com/modex/assessment/src/services"
+)
+
+type ExportHandler struct {
+	exportService *services.ExportJobService
+}
+
+func NewExportHandler() *ExportHandler {
+	return &ExportHandler{
+		exportService: services.NewExportJobService(),
+	}
+}
+
+// CreateGradebookExport starts building a course's gradebook CSV in the
+// background, for courses too large to download in one request
+func (h *ExportHandler) CreateGradebookExport(c *gin.Context) {
+	courseID, ok := uuidParam(c, "courseId", "Invalid course ID")
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return
+	}
+
+	params := map[string]interface{}{"courseId": courseID.String()}
+	if orgID := requestOrganization(c); orgID != nil {
+		params["organizationId"] = orgID.String()
+	}
+
+	job, err := h.exportService.Create(services.ExportKindGradebook, userID, params)
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"data": job})
+}
+
+// GetExport reports a job's progress, with a download link once it's done
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+
+	data := gin.H{"export": job}
+	if job.Status == models.ExportSucceeded {
+		link, expiresAt, err := h.exportService.DownloadURL(job)
+		if err != nil {
+			respondExportError(c, err)
+			return
+		}
+		data["downloadUrl"] = link
+		data["downloadUrlExpiresAt"] = expiresAt
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": data})
+}
+
+// DownloadExport redirects to a fresh link to the job's file
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+
+	link, _, err := h.exportService.DownloadURL(job)
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, link)
+}
+
+func (h *ExportHandler) loadJob(c *gin.Context) (*models.ExportJob, bool) {
+	jobID, ok := uuidParam(c, "jobId", "Invalid export ID")
+	if !ok {
+		return nil, false
+	}
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return nil, false
+	}
+
+	job, err := h.exportService.Get(jobID, userID, c.GetHeader("X-User-Role") == "admin")
+	if err != nil {
+		respondExportError(c, err)
+		return nil, false
+	}
+	return job, true
+}
+
+func respondExportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrExportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+	case errors.Is(err, services.ErrExportNotReady):
+		c.JSON(http.StatusConflict, gin.H{"error": "Export is not ready"})
+	case errors.Is(err, services.ErrExportExpired):
+		c.JSON(http.StatusGone, gin.H{"error": "Export has expired"})
+	case errors.Is(err, services.ErrObjectStoreNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Exports are not available"})
+	default:
+		respondGradingError(c, err)
+	}
+}
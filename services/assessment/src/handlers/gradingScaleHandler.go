@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"github.com/modex/assessment/src/services"
+)
+
+type GradingScaleHandler struct {
+	gradingService *services.GradingScaleService
+}
+
+func NewGradingScaleHandler() *GradingScaleHandler {
+	return &GradingScaleHandler{
+		gradingService: services.NewGradingScaleService(),
+	}
+}
+
+// GetPresets lists the built-in grading scales
+func (h *GradingScaleHandler) GetPresets(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"data": services.GradingScalePresets})
+}
+
+// GetCourseScale returns the scale a course's scores are presented on, which
+// may be inherited from its organization
+func (h *GradingScaleHandler) GetCourseScale(c *gin.Context) {
+	courseID, ok := uuidParam(c, "courseId", "Invalid course ID")
+	if !ok {
+		return
+	}
+
+	scale, err := h.gradingService.Resolve(courseID, requestOrganization(c))
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": scale})
+}
+
+// SetCourseScale sets a course's own grading scale
+func (h *GradingScaleHandler) SetCourseScale(c *gin.Context) {
+	courseID, ok := uuidParam(c, "courseId", "Invalid course ID")
+	if !ok {
+		return
+	}
+
+	scale, ok := bindGradingScale(c)
+	if !ok {
+		return
+	}
+	scale.OrganizationID = requestOrganization(c)
+
+	if err := h.gradingService.SetCourseScale(courseID, scale); err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": scale})
+}
+
+// DeleteCourseScale reverts a course to its organization's default scale
+func (h *GradingScaleHandler) DeleteCourseScale(c *gin.Context) {
+	courseID, ok := uuidParam(c, "courseId", "Invalid course ID")
+	if !ok {
+		return
+	}
+
+	if err := h.gradingService.DeleteCourseScale(courseID); err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Grading scale removed"})
+}
+
+// GetOrganizationScale returns the caller's organization default scale
+func (h *GradingScaleHandler) GetOrganizationScale(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+
+	scale, err := h.gradingService.GetOrganizationScale(orgID)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": scale})
+}
+
+// SetOrganizationScale sets the default scale for the caller's organization
+func (h *GradingScaleHandler) SetOrganizationScale(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+
+	scale, ok := bindGradingScale(c)
+	if !ok {
+		return
+	}
+
+	if err := h.gradingService.SetOrganizationScale(orgID, scale); err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": scale})
+}
+
+// GetGradebook returns each student's grades for a course. ?format=csv
+// downloads it as a spreadsheet.
+func (h *GradingScaleHandler) GetGradebook(c *gin.Context) {
+	courseID, ok := uuidParam(c, "courseId", "Invalid course ID")
+	if !ok {
+		return
+	}
+
+	book, err := h.gradingService.Gradebook(courseID, requestOrganization(c))
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		writeGradebookCSV(c, book)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": book})
+}
+
+// FormatGrade presents a percentage on a course's scale, for services that
+// show grades outside the assessment service such as certificates
+func (h *GradingScaleHandler) FormatGrade(c *gin.Context) {
+	courseID, ok := uuidParam(c, "courseId", "Invalid course ID")
+	if !ok {
+		return
+	}
+
+	percentage, err := strconv.ParseFloat(c.Query("percentage"), 64)
+	if err != nil || percentage < 0 || percentage > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "percentage must be a number between 0 and 100"})
+		return
+	}
+	passingScore := 0.0
+	if raw := c.Query("passingScore"); raw != "" {
+		if passingScore, err = strconv.ParseFloat(raw, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid passingScore"})
+			return
+		}
+	}
+	orgID, ok := optionalUUIDQuery(c, "organizationId")
+	if !ok {
+		return
+	}
+
+	scale, err := h.gradingService.Resolve(courseID, orgID)
+	if err != nil {
+		respondGradingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": services.ApplyGradingScale(scale, percentage, passingScore)})
+}
+
+// GetStudentResults returns a student's best graded result at each assessment
+// in ?assessmentIds= (comma-separated), for course-management's lesson unlocks
+func (h *GradingScaleHandler) GetStudentResults(c *gin.Context) {
+	studentID, ok := uuidParam(c, "studentId", "Invalid student ID")
+	if !ok {
+		return
+	}
+
+	var assessmentIDs []uuid.UUID
+	for _, raw := range strings.Split(c.Query("assessmentIds"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assessment ID"})
+			return
+		}
+		assessmentIDs = append(assessmentIDs, id)
+	}
+
+	results, err := h.gradingService.BestResults(studentID, assessmentIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+func writeGradebookCSV(c *gin.Context, book *services.Gradebook) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, services.GradebookFileName(book.CourseID)))
+	services.WriteGradebookCSV(c.Writer, book)
+}
+
+func bindGradingScale(c *gin.Context) (*models.GradingScale, bool) {
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return nil, false
+	}
+
+	var req struct {
+		Preset      string             `json:"preset"`
+		Name        string             `json:"name"`
+		Type        string             `json:"type"`
+		Bands       []models.GradeBand `json:"bands"`
+		PassPercent float64            `json:"passPercent"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	scale := &models.GradingScale{
+		Name:        req.Name,
+		Type:        models.GradingScaleType(req.Type),
+		Bands:       req.Bands,
+		PassPercent: req.PassPercent,
+	}
+	if req.Preset != "" {
+		preset, ok := services.GradingScalePresets[req.Preset]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown grading scale preset"})
+			return nil, false
+		}
+		scale.Name = preset.Name
+		scale.Type = preset.Type
+		scale.Bands = append([]models.GradeBand(nil), preset.Bands...)
+		if req.Name != "" {
+			scale.Name = req.Name
+		}
+	}
+	scale.CreatedBy = userID
+	return scale, true
+}
+
+// requestOrganization returns the caller's organization when the gateway sent one
+func requestOrganization(c *gin.Context) *uuid.UUID {
+	orgID, err := uuid.Parse(c.GetHeader("X-Organization-ID"))
+	if err != nil {
+		return nil
+	}
+	return &orgID
+}
+
+func respondGradingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrGradingScaleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Grading scale not found"})
+	case errors.Is(err, services.ErrInvalidGradingScale):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/services"
+)
+
+type IDResolutionHandler struct {
+	resolver *services.IDResolutionService
+}
+
+func NewIDResolutionHandler() *IDResolutionHandler {
+	return &IDResolutionHandler{
+		resolver: services.NewIDResolutionService(),
+	}
+}
+
+// ResolveID lists the assessment records stored under an ID, for support tooling
+func (h *IDResolutionHandler) ResolveID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	matches, err := h.resolver.Resolve(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": matches})
+}
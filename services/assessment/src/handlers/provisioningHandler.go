@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/services"
+)
+
+// Provisioning event types forwarded by the identity layer's SCIM endpoints
+const (
+	ProvisioningEventUserProvisioned   = "user.provisioned"
+	ProvisioningEventUserDeprovisioned = "user.deprovisioned"
+)
+
+type ProvisioningHandler struct {
+	assessmentService *services.AssessmentService
+}
+
+func NewProvisioningHandler() *ProvisioningHandler {
+	return &ProvisioningHandler{
+		assessmentService: services.NewAssessmentService(),
+	}
+}
+
+// HandleProvisioningEvent applies a SCIM provisioning or deprovisioning event.
+// Deprovisioned instructors have their assessments reassigned to the successor.
+func (h *ProvisioningHandler) HandleProvisioningEvent(c *gin.Context) {
+	var req struct {
+		Event       string `json:"event" binding:"required"`
+		UserID      string `json:"userId" binding:"required"`
+		SuccessorID string `json:"successorId"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	switch req.Event {
+	case ProvisioningEventUserProvisioned:
+		c.JSON(http.StatusOK, gin.H{"message": "Provisioning acknowledged"})
+
+	case ProvisioningEventUserDeprovisioned:
+		successorID, err := uuid.Parse(req.SuccessorID)
+		if err != nil || successorID == userID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A valid successorId is required to deprovision a user"})
+			return
+		}
+
+		reassigned, err := h.assessmentService.ReassignAssessments(userID, successorID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{"reassignedAssessments": reassigned}})
+
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported provisioning event"})
+	}
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"github.com/modex/assessment/src/services"
+)
+
+type QuestionBankHandler struct {
+	bankService *services.QuestionBankService
+}
+
+func NewQuestionBankHandler() *QuestionBankHandler {
+	return &QuestionBankHandler{
+		bankService: services.NewQuestionBankService(),
+	}
+}
+
+// CreateBank creates a question bank owned by the caller's organization
+func (h *QuestionBankHandler) CreateBank(c *gin.Context) {
+	userID, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bank := models.QuestionBank{
+		OrganizationID: orgID,
+		Name:           req.Name,
+		Description:    req.Description,
+		CreatedBy:      userID,
+	}
+	if err := h.bankService.CreateBank(&bank); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": bank})
+}
+
+// ListBanks lists the organization's own and imported banks
+func (h *QuestionBankHandler) ListBanks(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+
+	owned, imports, err := h.bankService.ListBanks(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"owned": owned, "imported": imports}})
+}
+
+// GetBank returns a bank and its questions, with license details for imported banks
+func (h *QuestionBankHandler) GetBank(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	bankID, ok := uuidParam(c, "id", "Invalid question bank ID")
+	if !ok {
+		return
+	}
+
+	access, err := h.bankService.GetBank(bankID, orgID)
+	if err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": access})
+}
+
+// AddQuestion adds a question to an owned bank
+func (h *QuestionBankHandler) AddQuestion(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	bankID, ok := uuidParam(c, "id", "Invalid question bank ID")
+	if !ok {
+		return
+	}
+
+	var question models.BankQuestion
+	if err := c.ShouldBindJSON(&question); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if question.Type == "" || question.Question == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Question type and text are required"})
+		return
+	}
+
+	if err := h.bankService.AddQuestion(bankID, orgID, &question); err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": question})
+}
+
+// PublishBank lists an owned bank in the shared marketplace
+func (h *QuestionBankHandler) PublishBank(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	bankID, ok := uuidParam(c, "id", "Invalid question bank ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Title        string             `json:"title" binding:"required"`
+		Description  string             `json:"description"`
+		License      models.LicenseType `json:"license" binding:"required"`
+		LicenseTerms string             `json:"licenseTerms"`
+		Attribution  string             `json:"attribution"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	listing := models.BankListing{
+		Title:        req.Title,
+		Description:  req.Description,
+		License:      req.License,
+		LicenseTerms: req.LicenseTerms,
+		Attribution:  req.Attribution,
+	}
+	if err := services.ValidateListing(&listing); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.bankService.PublishBank(bankID, orgID, &listing); err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": listing})
+}
+
+// RevokeListing withdraws an owned bank from the marketplace
+func (h *QuestionBankHandler) RevokeListing(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	bankID, ok := uuidParam(c, "id", "Invalid question bank ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.bankService.RevokeListing(bankID, orgID, req.Reason)
+	if err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// GetUsage reports which organizations use an owned bank's questions
+func (h *QuestionBankHandler) GetUsage(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	bankID, ok := uuidParam(c, "id", "Invalid question bank ID")
+	if !ok {
+		return
+	}
+
+	usage, err := h.bankService.GetUsage(bankID, orgID)
+	if err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": usage})
+}
+
+// UseQuestion copies a bank question into an assessment
+func (h *QuestionBankHandler) UseQuestion(c *gin.Context) {
+	userID, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	bankID, ok := uuidParam(c, "id", "Invalid question bank ID")
+	if !ok {
+		return
+	}
+	questionID, ok := uuidParam(c, "questionId", "Invalid question ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AssessmentID uuid.UUID `json:"assessmentId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	question, err := h.bankService.UseQuestion(bankID, questionID, orgID, userID, req.AssessmentID)
+	if err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": question})
+}
+
+// ListMarketplace lists banks shared by other organizations
+func (h *QuestionBankHandler) ListMarketplace(c *gin.Context) {
+	listings, err := h.bankService.ListMarketplace(models.LicenseType(c.Query("license")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": listings})
+}
+
+// ImportListing imports a marketplace bank by reference after accepting its license
+func (h *QuestionBankHandler) ImportListing(c *gin.Context) {
+	userID, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	listingID, ok := uuidParam(c, "listingId", "Invalid listing ID")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		AcceptLicense models.LicenseType `json:"acceptLicense" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	imp, err := h.bankService.ImportListing(listingID, orgID, userID, req.AcceptLicense)
+	if err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": imp})
+}
+
+// RemoveImport drops an imported bank from the organization
+func (h *QuestionBankHandler) RemoveImport(c *gin.Context) {
+	_, orgID, ok := requestIdentity(c)
+	if !ok {
+		return
+	}
+	importID, ok := uuidParam(c, "importId", "Invalid import ID")
+	if !ok {
+		return
+	}
+
+	if err := h.bankService.RemoveImport(importID, orgID); err != nil {
+		respondBankError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// requestIdentity reads the caller's user and organization from the headers
+// set by the API gateway, writing a 401 when either is missing.
+func requestIdentity(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.GetHeader("X-User-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	orgID, err := uuid.Parse(c.GetHeader("X-Organization-ID"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid organization"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, orgID, true
+}
+
+func uuidParam(c *gin.Context, name, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func respondBankError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrBankNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question bank not found"})
+	case errors.Is(err, services.ErrQuestionNotInBank):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found in bank"})
+	case errors.Is(err, services.ErrListingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Listing not found"})
+	case errors.Is(err, services.ErrImportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+	case errors.Is(err, services.ErrAssessmentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Assessment not found"})
+	case errors.Is(err, services.ErrBankAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Question bank not accessible to your organization"})
+	case errors.Is(err, services.ErrAlreadyListed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Question bank is already listed"})
+	case errors.Is(err, services.ErrOwnListing):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot import your own organization's bank"})
+	case errors.Is(err, services.ErrLicenseNotAccepted):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The listing's license must be accepted to import"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
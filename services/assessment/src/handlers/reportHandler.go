@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/services"
+)
+
+type ReportHandler struct {
+	assessmentService *services.AssessmentService
+}
+
+func NewReportHandler() *ReportHandler {
+	return &ReportHandler{
+		assessmentService: services.NewAssessmentService(),
+	}
+}
+
+// GetPendingGrading returns submissions awaiting grading for the analytics
+// service's scheduled reports. Filters: courseId, createdBy.
+func (h *ReportHandler) GetPendingGrading(c *gin.Context) {
+	courseID, ok := optionalUUIDQuery(c, "courseId")
+	if !ok {
+		return
+	}
+	createdBy, ok := optionalUUIDQuery(c, "createdBy")
+	if !ok {
+		return
+	}
+
+	items, err := h.assessmentService.GetPendingGrading(courseID, createdBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// optionalUUIDQuery parses an optional UUID query parameter, writing a 400 when it is malformed
+func optionalUUIDQuery(c *gin.Context, name string) (*uuid.UUID, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return nil, false
+	}
+	return &id, true
+}
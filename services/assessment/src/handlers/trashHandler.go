@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/services"
+)
+
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler() *TrashHandler {
+	return &TrashHandler{
+		trashService: services.NewTrashService(),
+	}
+}
+
+// ListTrash lists a course's deleted assessments and questions
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Query("courseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID"})
+		return
+	}
+
+	items, err := h.trashService.ListTrash(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// RestoreAssessment restores a deleted assessment and its questions
+func (h *TrashHandler) RestoreAssessment(c *gin.Context) {
+	id, ok := uuidParam(c, "id", "Invalid assessment ID")
+	if !ok {
+		return
+	}
+
+	assessment, err := h.trashService.RestoreAssessment(id)
+	if err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": assessment})
+}
+
+// RestoreQuestion restores a deleted question and its options
+func (h *TrashHandler) RestoreQuestion(c *gin.Context) {
+	id, ok := uuidParam(c, "id", "Invalid question ID")
+	if !ok {
+		return
+	}
+
+	question, err := h.trashService.RestoreQuestion(id)
+	if err != nil {
+		respondTrashError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": question})
+}
+
+// PurgeTrash permanently removes items past the retention window
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	assessments, questions, err := h.trashService.PurgeExpired()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"purgedAssessments": assessments,
+		"purgedQuestions":   questions,
+	}})
+}
+
+func respondTrashError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotInTrash):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found in trash"})
+	case errors.Is(err, services.ErrAssessmentInTrash):
+		c.JSON(http.StatusConflict, gin.H{"error": "Restore the question's assessment first"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/routes"
+	"github.com/modex/assessment/src/services"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	if err := config.InitDatabase(); err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+	defer config.CloseDatabase()
+
+	if err := config.InitRedis(); err != nil {
+		log.Fatal("Failed to initialize Redis:", err)
+	}
+	defer config.CloseRedis()
+
+	if err := config.MigrateDatabase(); err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	if os.Getenv("ENV") == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+	routes.SetupRoutes(router)
+
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	// Submissions whose code couldn't be run are graded again once it can be
+	go services.NewAssessmentService().RunGradingSweeper(jobsCtx)
+	// Items past the retention window are purged hourly
+	go services.NewTrashService().RunPurger(jobsCtx)
+	// Expired export files are deleted every 15 minutes
+	go services.NewExportJobService().RunCleaner(jobsCtx)
+	// Erasure requests from course-management arrive over the event channel
+	go services.NewPrivacyService().ListenForErasures(jobsCtx)
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "3004"
+	}
+
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("Starting assessment service on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+	stopJobs()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatal("Server forced to shutdown:", err)
+	}
+
+	log.Println("Server exited")
+}
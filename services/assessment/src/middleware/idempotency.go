@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/shared/idempotency"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultIdempotencyTTL is how long a stored response is replayed for
+const DefaultIdempotencyTTL = idempotency.DefaultTTL
+
+// Idempotency honors the Idempotency-Key header on POST requests. The first
+// response for a key is stored in Redis for ttl and replayed verbatim for any
+// retry carrying the same key, so network retries don't create duplicates.
+func Idempotency(ttl time.Duration) gin.HandlerFunc {
+	return idempotency.Middleware(idempotency.Options{
+		Client: func() *redis.Client { return config.RedisClient },
+		TTL:    ttl,
+		OnStoreError: func(message string, err error) {
+			log.Printf("%s: %v", message, err)
+		},
+	})
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-contrib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/modex/shared/jwt"
+	"github.com/ulule/limiter/v3"
+	limitergin "github.com/ulule/limiter/v3/drivers/middleware/gin"
+	"github.com/ulule/limiter/v3/drivers/store/memory"
+)
+
+// ServiceAuthRequired guards internal endpoints that are only called by
+// other platform services. Callers must present the shared INTERNAL_SERVICE_KEY.
+func ServiceAuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := os.Getenv("INTERNAL_SERVICE_KEY")
+		if expected == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Internal service key not configured"})
+			c.Abort()
+			return
+		}
+
+		key := c.GetHeader("X-Service-Key")
+		if subtle.ConstantTimeCompare([]byte(key), []byte(expected)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid service key"})
+			c.Abort()
+			return
+		}
+
+		c.Set("service_caller", true)
+		c.Next()
+	}
+}
+
+func CORS() gin.HandlerFunc {
+	return cors.New(cors.Config{
+		AllowOrigins:     []string{"*"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Idempotency-Key"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "Idempotent-Replayed"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	})
+}
+
+func RequestID() gin.HandlerFunc {
+	return requestid.New()
+}
+
+func RateLimit() gin.HandlerFunc {
+	rate, _ := limiter.NewRateFromFormatted("100-M")
+	store := memory.NewStore()
+	instance := limiter.New(store, rate)
+
+	return limitergin.NewMiddleware(instance)
+}
+
+// Identity headers handlers read the caller from. AuthRequired replaces
+// whatever the client sent with the verified token's claims.
+var identityHeaders = []string{"X-User-ID", "X-User-Role", "X-Organization-ID"}
+
+// AuthRequired verifies the bearer token against JWT_SECRET, the secret the
+// API gateway signs user tokens with, and exposes the caller as user_id,
+// user_role and organization_id.
+func AuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, header := range identityHeaders {
+			c.Request.Header.Del(header)
+		}
+
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Authentication not configured"})
+			c.Abort()
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Abort()
+			return
+		}
+		token, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+			c.Abort()
+			return
+		}
+
+		claims, err := jwt.Verify(token, secret)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.User())
+		c.Set("user_role", claims.Role)
+		c.Set("organization_id", claims.OrganizationID)
+		c.Request.Header.Set("X-User-ID", claims.User())
+		if claims.Role != "" {
+			c.Request.Header.Set("X-User-Role", claims.Role)
+		}
+		if claims.OrganizationID != "" {
+			c.Request.Header.Set("X-Organization-ID", claims.OrganizationID)
+		}
+		c.Next()
+	}
+}
+
+// RoleRequired lets through callers whose token carries one of roles. It
+// must run after AuthRequired.
+func RoleRequired(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("user_role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient role"})
+		c.Abort()
+	}
+}
+
+// InstructorRequired lets through instructors and admins
+func InstructorRequired() gin.HandlerFunc {
+	return RoleRequired("instructor", "admin")
+}
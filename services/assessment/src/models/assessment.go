@@ -29,6 +29,7 @@ type Assessment struct {
 	// Scheduling
 	AvailableFrom *time.Time     `gorm:"type:timestamp" json:"availableFrom"`
 	AvailableTo   *time.Time     `gorm:"type:timestamp" json:"availableTo"`
+	LateMinutes   int            `gorm:"type:integer;default:0" json:"lateMinutes"` // attempts started before AvailableTo may be submitted this long after it
 	
 	// Grading settings
 	ShowCorrectAnswers bool       `gorm:"default:true" json:"showCorrectAnswers"`
@@ -42,6 +43,7 @@ type Assessment struct {
 	
 	// Metadata
 	CreatedBy uuid.UUID      `gorm:"type:uuid;not null" json:"createdBy"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index" json:"organizationId,omitempty"` // tenant its events are delivered to
 	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
@@ -60,6 +62,9 @@ type Question struct {
 	// Question configuration
 	Required     bool           `gorm:"default:true" json:"required"`
 	MediaURL     string         `gorm:"type:varchar(500)" json:"mediaUrl"`
+	SourceBankQuestionID *uuid.UUID `gorm:"type:uuid;index" json:"sourceBankQuestionId,omitempty"` // set when copied from a question bank
+	PoolID       *uuid.UUID     `gorm:"type:uuid;index" json:"poolId,omitempty"` // set on copies drawn by a pool, which only appear on attempts that drew them
+	Code         *CodeQuestion  `gorm:"type:jsonb;serializer:json" json:"code,omitempty"` // languages and test cases of code questions
 	
 	// Relationships
 	Options []QuestionOption `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"options"`
@@ -84,9 +89,9 @@ type QuestionOption struct {
 // Submission represents a student's submission
 type Submission struct {
 	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	AssessmentID uuid.UUID      `gorm:"type:uuid;not null;index" json:"assessmentId"`
-	StudentID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"studentId"`
-	AttemptNumber int           `gorm:"type:integer;not null;default:1" json:"attemptNumber"`
+	AssessmentID uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_submission_attempt" json:"assessmentId"`
+	StudentID    uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_submission_attempt" json:"studentId"`
+	AttemptNumber int           `gorm:"type:integer;not null;default:1;uniqueIndex:idx_submission_attempt" json:"attemptNumber"`
 	
 	// Submission data
 	Status       SubmissionStatus `gorm:"type:varchar(20);default:'in_progress'" json:"status"`
@@ -98,10 +103,25 @@ type Submission struct {
 	StartedAt    time.Time       `gorm:"type:timestamp;default:current_timestamp" json:"startedAt"`
 	SubmittedAt  *time.Time      `gorm:"type:timestamp" json:"submittedAt"`
 	TimeSpent    int             `gorm:"type:integer;default:0" json:"timeSpent"` // in seconds
+	Deadline     *time.Time      `gorm:"type:timestamp" json:"deadline"` // StartedAt plus the time limit, nil when untimed
+	Late         bool            `gorm:"default:false" json:"late"` // submitted after Deadline and its grace period
+	ClosesAt     *time.Time      `gorm:"type:timestamp" json:"closesAt"` // no submissions accepted after this, nil when the assessment never closes
+	
+	// Grading retries while the code runner is unavailable
+	GradingAttempts int          `gorm:"type:integer;not null;default:0" json:"-"`
+	NextGradingAt   *time.Time   `gorm:"type:timestamp;index" json:"-"`
+	
+	// Question IDs in the order this attempt shows them, fixed when it starts
+	QuestionOrder []uuid.UUID    `gorm:"type:jsonb;serializer:json" json:"questionOrder,omitempty"`
+	// Option IDs per question, for assessments that shuffle options
+	OptionOrder  map[uuid.UUID][]uuid.UUID `gorm:"type:jsonb;serializer:json" json:"optionOrder,omitempty"`
 	
 	// Relationships
 	Answers []SubmissionAnswer `gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE" json:"answers"`
 	
+	// Presentation of Score on the course's grading scale, filled in when read
+	Grade *Grade `gorm:"-" json:"grade,omitempty"`
+	
 	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
 }
@@ -112,7 +132,9 @@ type SubmissionAnswer struct {
 	SubmissionID uuid.UUID      `gorm:"type:uuid;not null;index" json:"submissionId"`
 	QuestionID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"questionId"`
 	SelectedOptions []uuid.UUID `gorm:"type:uuid[];serializer:json" json:"selectedOptions"` // For multiple choice
-	TextAnswer   string         `gorm:"type:text" json:"textAnswer"` // For text/essay questions
+	TextAnswer   string         `gorm:"type:text" json:"textAnswer"` // For text/essay questions, and the source of code questions
+	Language     string         `gorm:"type:varchar(30)" json:"language,omitempty"` // For code questions
+	CodeResults  []CodeTestResult `gorm:"type:jsonb;serializer:json" json:"codeResults,omitempty"` // per test case, filled in when graded
 	IsCorrect    *bool          `json:"isCorrect"`
 	PointsEarned *float64       `gorm:"type:decimal(5,2)" json:"pointsEarned"`
 	
@@ -151,6 +173,7 @@ const (
 	QuestionTypeText           QuestionType = "text"
 	QuestionTypeEssay          QuestionType = "essay"
 	QuestionTypeTrueFalse      QuestionType = "true_false"
+	QuestionTypeCode           QuestionType = "code"
 )
 
 type SubmissionStatus string
@@ -158,6 +181,7 @@ const (
 	SubmissionStatusInProgress SubmissionStatus = "in_progress"
 	SubmissionStatusSubmitted  SubmissionStatus = "submitted"
 	SubmissionStatusGraded     SubmissionStatus = "graded"
+	SubmissionStatusGradingPending SubmissionStatus = "grading_pending" // code answers wait for the runner
 	SubmissionStatusReviewing  SubmissionStatus = "reviewing"
 )
 
@@ -167,3 +191,13 @@ func (Question) TableName() string { return "questions" }
 func (QuestionOption) TableName() string { return "question_options" }
 func (Submission) TableName() string { return "submissions" }
 func (SubmissionAnswer) TableName() string { return "submission_answers" }
+
+// ClosesAt is when in-progress attempts stop being accepted: AvailableTo
+// plus the late window, or nil when the assessment never closes
+func (a *Assessment) ClosesAt() *time.Time {
+	if a.AvailableTo == nil {
+		return nil
+	}
+	closes := a.AvailableTo.Add(time.Duration(a.LateMinutes) * time.Minute)
+	return &closes
+}
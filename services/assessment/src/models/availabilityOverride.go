@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AvailabilityOverride lets one student start an attempt outside an
+// assessment's availability window, for accommodations and make-ups. The
+// instructor hands the student a single-use token; only its hash is stored.
+type AvailabilityOverride struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AssessmentID uuid.UUID  `gorm:"type:uuid;not null;index" json:"assessmentId"`
+	StudentID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"studentId"`
+	TokenHash    string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ValidUntil   time.Time  `gorm:"type:timestamp;not null" json:"validUntil"` // the attempt closes here instead of AvailableTo
+	Reason       string     `gorm:"type:text" json:"reason,omitempty"`
+	IssuedBy     uuid.UUID  `gorm:"type:uuid;not null" json:"issuedBy"`
+	UsedAt       *time.Time `gorm:"type:timestamp" json:"usedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+}
+
+func (AvailabilityOverride) TableName() string { return "availability_overrides" }
@@ -0,0 +1,34 @@
+package models
+
+// CodeQuestion configures a code question: the languages students may answer
+// in and the test cases their program is run against. Each test case feeds
+// Input on stdin and passes when stdout matches ExpectedOutput.
+type CodeQuestion struct {
+	Languages     []string          `json:"languages"`
+	StarterCode   map[string]string `json:"starterCode,omitempty"` // by language
+	TestCases     []CodeTestCase    `json:"testCases"`
+	TimeLimitMs   int               `json:"timeLimitMs,omitempty"`   // CPU time per test case, 0 uses the runner default
+	MemoryLimitKB int               `json:"memoryLimitKb,omitempty"` // per test case, 0 uses the runner default
+}
+
+// CodeTestCase is one run of a student's program. Hidden test cases are
+// graded but never shown to students, not even their output.
+type CodeTestCase struct {
+	Name           string  `json:"name"`
+	Input          string  `json:"input"`
+	ExpectedOutput string  `json:"expectedOutput"`
+	Hidden         bool    `json:"hidden"`
+	Weight         float64 `json:"weight,omitempty"` // share of the question's points, 0 counts as 1
+}
+
+// CodeTestResult is the outcome of one test case against a student's answer
+type CodeTestResult struct {
+	Name     string `json:"name"`
+	Hidden   bool   `json:"hidden"`
+	Passed   bool   `json:"passed"`
+	Status   string `json:"status"`           // the runner's verdict, e.g. Accepted or Time Limit Exceeded
+	Stdout   string `json:"stdout,omitempty"` // left out for hidden test cases
+	Stderr   string `json:"stderr,omitempty"` // compile or runtime errors, left out for hidden test cases
+	TimeMs   int    `json:"timeMs"`
+	MemoryKB int    `json:"memoryKb"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GradingScaleType controls how a percentage score is presented
+type GradingScaleType string
+
+const (
+	GradingScaleLetter     GradingScaleType = "letter"     // A–F style bands
+	GradingScalePassFail   GradingScaleType = "pass_fail"  // pass at or above PassPercent
+	GradingScalePercentage GradingScaleType = "percentage" // the raw percentage
+	GradingScaleGPA        GradingScaleType = "gpa"        // bands carrying grade points
+)
+
+// GradingScale maps percentage scores to grades. A scale belongs either to a
+// course or, as the default for its courses, to an organization.
+type GradingScale struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID *uuid.UUID       `gorm:"type:uuid;uniqueIndex:idx_grading_scale_org,where:course_id IS NULL" json:"organizationId,omitempty"`
+	CourseID       *uuid.UUID       `gorm:"type:uuid;uniqueIndex" json:"courseId,omitempty"`
+	Name           string           `gorm:"type:varchar(100);not null" json:"name"`
+	Type           GradingScaleType `gorm:"type:varchar(20);not null" json:"type"`
+	Bands          []GradeBand      `gorm:"type:jsonb;serializer:json" json:"bands,omitempty"` // letter and gpa scales, highest first
+	PassPercent    float64          `gorm:"type:decimal(5,2);default:0" json:"passPercent"`    // 0 = use the assessment's passing score
+
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
+}
+
+// GradeBand is awarded for percentages at or above MinPercent
+type GradeBand struct {
+	Label      string   `json:"label"`
+	MinPercent float64  `json:"minPercent"`
+	GPAPoints  *float64 `json:"gpaPoints,omitempty"`
+	Passing    bool     `json:"passing"`
+}
+
+// Grade is a score presented on a grading scale
+type Grade struct {
+	Scale      GradingScaleType `json:"scale"`
+	Percentage float64          `json:"percentage"`
+	Label      string           `json:"label"`
+	GPAPoints  *float64         `json:"gpaPoints,omitempty"`
+	Passed     bool             `json:"passed"`
+}
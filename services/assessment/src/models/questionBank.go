@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// QuestionBank is a reusable pool of questions owned by an organization
+type QuestionBank struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index" json:"organizationId"`
+	Name           string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description    string    `gorm:"type:text" json:"description"`
+
+	// Relationships
+	Questions []BankQuestion `gorm:"foreignKey:BankID;constraint:OnDelete:CASCADE" json:"questions,omitempty"`
+
+	// Metadata
+	CreatedBy uuid.UUID      `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+}
+
+// BankQuestion is a question held in a bank. Its options are stored inline
+// since they are only ever read and copied together with the question.
+type BankQuestion struct {
+	ID          uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BankID      uuid.UUID            `gorm:"type:uuid;not null;index" json:"bankId"`
+	Type        QuestionType         `gorm:"type:varchar(20);not null" json:"type"`
+	Question    string               `gorm:"type:text;not null" json:"question"`
+	Explanation string               `gorm:"type:text" json:"explanation"`
+	Points      float64              `gorm:"type:decimal(5,2);default:1.00" json:"points"`
+	MediaURL    string               `gorm:"type:varchar(500)" json:"mediaUrl"`
+	Options     []BankQuestionOption `gorm:"type:jsonb;serializer:json" json:"options"`
+	Tags        []string             `gorm:"type:jsonb;serializer:json" json:"tags,omitempty"`
+	Code        *CodeQuestion        `gorm:"type:jsonb;serializer:json" json:"code,omitempty"`
+
+	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+}
+
+// BankQuestionOption is an answer option of a bank question
+type BankQuestionOption struct {
+	Text      string `json:"text"`
+	IsCorrect bool   `json:"isCorrect"`
+}
+
+// BankListing publishes a question bank to the shared marketplace under a license
+type BankListing struct {
+	ID               uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BankID           uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex" json:"bankId"`
+	PublisherOrgID   uuid.UUID     `gorm:"type:uuid;not null;index" json:"publisherOrgId"`
+	Title            string        `gorm:"type:varchar(255);not null" json:"title"`
+	Description      string        `gorm:"type:text" json:"description"`
+	License          LicenseType   `gorm:"type:varchar(30);not null" json:"license"`
+	LicenseTerms     string        `gorm:"type:text" json:"licenseTerms"`
+	Attribution      string        `gorm:"type:varchar(500)" json:"attribution"`
+	Status           ListingStatus `gorm:"type:varchar(20);default:'active';index" json:"status"`
+	PublishedAt      time.Time     `gorm:"type:timestamp;default:current_timestamp" json:"publishedAt"`
+	RevokedAt        *time.Time    `gorm:"type:timestamp" json:"revokedAt,omitempty"`
+	RevocationReason string        `gorm:"type:text" json:"revocationReason,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
+}
+
+// BankImport grants an organization read access to a listed bank. Imports are
+// by reference: the importer sees the publisher's current questions rather
+// than a copy, and loses access if the listing is revoked.
+type BankImport struct {
+	ID              uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ListingID       uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_bank_import_listing_org" json:"listingId"`
+	BankID          uuid.UUID    `gorm:"type:uuid;not null;index" json:"bankId"`
+	OrganizationID  uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_bank_import_listing_org" json:"organizationId"`
+	ImportedBy      uuid.UUID    `gorm:"type:uuid;not null" json:"importedBy"`
+	AcceptedLicense LicenseType  `gorm:"type:varchar(30);not null" json:"acceptedLicense"`
+	Status          ImportStatus `gorm:"type:varchar(20);default:'active'" json:"status"`
+	RevokedAt       *time.Time   `gorm:"type:timestamp" json:"revokedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
+}
+
+// BankQuestionUsage records a bank question being placed in an assessment
+type BankQuestionUsage struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	BankID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"bankId"`
+	BankQuestionID uuid.UUID  `gorm:"type:uuid;not null;index" json:"bankQuestionId"`
+	ImportID       *uuid.UUID `gorm:"type:uuid;index" json:"importId,omitempty"` // nil when used by the owning organization
+	OrganizationID uuid.UUID  `gorm:"type:uuid;not null;index" json:"organizationId"`
+	AssessmentID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"assessmentId"`
+	QuestionID     uuid.UUID  `gorm:"type:uuid;not null" json:"questionId"`
+	UsedBy         uuid.UUID  `gorm:"type:uuid;not null" json:"usedBy"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+}
+
+// AssessmentPool draws Count random questions from a bank into every attempt
+// at an assessment, so each student gets different questions of the same
+// number and weight. Only questions carrying one of Tags are drawn when Tags
+// is set.
+type AssessmentPool struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	AssessmentID   uuid.UUID `gorm:"type:uuid;not null;index" json:"assessmentId"`
+	BankID         uuid.UUID `gorm:"type:uuid;not null;index" json:"bankId" binding:"required"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null" json:"organizationId"` // draws as this organization, so revoked imports stop drawing
+	Tags           []string  `gorm:"type:jsonb;serializer:json" json:"tags,omitempty"`
+	Count          int       `gorm:"type:integer;not null" json:"count" binding:"required,min=1"`
+	Points         float64   `gorm:"type:decimal(5,2);default:0" json:"points"` // per drawn question, 0 keeps the bank's points
+	OrderIndex     int       `gorm:"type:integer;not null" json:"orderIndex"`
+	CreatedBy      uuid.UUID `gorm:"type:uuid;not null" json:"createdBy"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
+}
+
+type LicenseType string
+
+const (
+	LicenseCCBY          LicenseType = "cc-by"
+	LicenseCCBYSA        LicenseType = "cc-by-sa"
+	LicenseCCBYNC        LicenseType = "cc-by-nc"
+	LicenseInstitutional LicenseType = "institutional" // partner institutions, no redistribution
+	LicenseProprietary   LicenseType = "proprietary"   // custom terms in LicenseTerms
+)
+
+type ListingStatus string
+
+const (
+	ListingStatusActive  ListingStatus = "active"
+	ListingStatusRevoked ListingStatus = "revoked"
+)
+
+type ImportStatus string
+
+const (
+	ImportStatusActive  ImportStatus = "active"
+	ImportStatusRevoked ImportStatus = "revoked" // publisher revoked the listing
+	ImportStatusRemoved ImportStatus = "removed" // importer dropped the bank
+)
+
+// Table names
+func (QuestionBank) TableName() string      { return "question_banks" }
+func (BankQuestion) TableName() string      { return "bank_questions" }
+func (BankListing) TableName() string       { return "bank_listings" }
+func (BankImport) TableName() string        { return "bank_imports" }
+func (BankQuestionUsage) TableName() string { return "bank_question_usages" }
+func (AssessmentPool) TableName() string    { return "assessment_pools" }
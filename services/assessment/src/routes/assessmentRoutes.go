@@ -3,18 +3,17 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
 )
 
 func SetupAssessmentRoutes(router *gin.RouterGroup) {
 	assessmentHandler := handlers.NewAssessmentHandler()
 
 	assessments := router.Group("/assessments")
+	assessments.Use(middleware.AuthRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
 	{
-		assessments.POST("", assessmentHandler.CreateAssessment)
 		assessments.GET("/:id", assessmentHandler.GetAssessment)
-		assessments.PUT("/:id", assessmentHandler.UpdateAssessment)
-		assessments.DELETE("/:id", assessmentHandler.DeleteAssessment)
-		
+
 		// Course assessments
 		assessments.GET("/course/:courseId", assessmentHandler.GetCourseAssessments)
 		
@@ -22,6 +21,36 @@ func SetupAssessmentRoutes(router *gin.RouterGroup) {
 		assessments.POST("/:id/start", assessmentHandler.StartAssessment)
 		assessments.POST("/submissions/:submissionId/submit", assessmentHandler.SubmitAssessment)
 		assessments.GET("/submissions/:submissionId", assessmentHandler.GetSubmission)
+		assessments.GET("/submissions/:submissionId/questions", assessmentHandler.GetAttemptQuestions)
+		assessments.GET("/submissions/:submissionId/take", assessmentHandler.TakeAssessment)
 		assessments.GET("/student/:studentId/assessment/:assessmentId/submissions", assessmentHandler.GetStudentSubmissions)
 	}
+
+	instructor := assessments.Group("")
+	instructor.Use(middleware.InstructorRequired())
+	{
+		instructor.POST("", assessmentHandler.CreateAssessment)
+		instructor.PUT("/:id", assessmentHandler.UpdateAssessment)
+		instructor.DELETE("/:id", assessmentHandler.DeleteAssessment)
+		instructor.DELETE("/:id/questions/:questionId", assessmentHandler.DeleteQuestion)
+
+		// Answer keys for offline review
+		instructor.GET("/:id/answer-key", assessmentHandler.GetAnswerKey)
+		instructor.PUT("/:id/answer-key", assessmentHandler.ImportAnswerKey)
+		instructor.POST("/:id/regrade", assessmentHandler.RegradeAssessment)
+
+		// Random draws from question banks
+		instructor.GET("/:id/pools", assessmentHandler.GetQuestionPools)
+		instructor.PUT("/:id/pools", assessmentHandler.SetQuestionPools)
+
+		// Accommodations outside the availability window
+		instructor.POST("/:id/overrides", assessmentHandler.IssueAvailabilityOverride)
+	}
+
+	// Previews persist nothing, so they skip idempotency
+	questions := router.Group("/questions")
+	questions.Use(middleware.AuthRequired(), middleware.InstructorRequired())
+	{
+		questions.POST("/preview", assessmentHandler.PreviewQuestion)
+	}
 }
\ No newline at end of file
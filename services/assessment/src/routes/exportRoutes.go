@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupExportRoutes(router *gin.RouterGroup) {
+	exportHandler := handlers.NewExportHandler()
+
+	exports := router.Group("/exports")
+	exports.Use(middleware.AuthRequired())
+	{
+		exports.GET("/:jobId", exportHandler.GetExport)
+		exports.GET("/:jobId/download", exportHandler.DownloadExport)
+	}
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupGradingRoutes(router *gin.RouterGroup) {
+	gradingHandler := handlers.NewGradingScaleHandler()
+	exportHandler := handlers.NewExportHandler()
+
+	grading := router.Group("/grading")
+	grading.Use(middleware.AuthRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
+	{
+		grading.GET("/scales/presets", gradingHandler.GetPresets)
+		grading.GET("/organization/scale", gradingHandler.GetOrganizationScale)
+		grading.GET("/courses/:courseId/scale", gradingHandler.GetCourseScale)
+	}
+
+	instructor := grading.Group("")
+	instructor.Use(middleware.InstructorRequired())
+	{
+		instructor.PUT("/organization/scale", gradingHandler.SetOrganizationScale)
+		instructor.PUT("/courses/:courseId/scale", gradingHandler.SetCourseScale)
+		instructor.DELETE("/courses/:courseId/scale", gradingHandler.DeleteCourseScale)
+		instructor.GET("/courses/:courseId/gradebook", gradingHandler.GetGradebook)
+		instructor.POST("/courses/:courseId/gradebook/exports", exportHandler.CreateGradebookExport)
+	}
+
+	internal := router.Group("/internal/grading")
+	internal.Use(middleware.ServiceAuthRequired())
+	{
+		internal.GET("/courses/:courseId/grade", gradingHandler.FormatGrade)
+		internal.GET("/students/:studentId/results", gradingHandler.GetStudentResults)
+	}
+}
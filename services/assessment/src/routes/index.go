@@ -0,0 +1,90 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupRoutes(router *gin.Engine) {
+	router.Use(middleware.CORS())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RateLimit())
+
+	setupHealthRoutes(router)
+
+	api := router.Group("/api/v1")
+	{
+		SetupAssessmentRoutes(api)
+		SetupTrashRoutes(api)
+		SetupQuestionBankRoutes(api)
+		SetupGradingRoutes(api)
+		SetupExportRoutes(api)
+		SetupReportRoutes(api)
+		SetupPrivacyRoutes(api)
+		SetupProvisioningRoutes(api)
+		SetupSupportRoutes(api)
+	}
+}
+
+var startTime = time.Now()
+
+func setupHealthRoutes(router *gin.Engine) {
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "healthy",
+			"service":   "assessment",
+			"timestamp": time.Now().UTC(),
+		})
+	})
+	router.GET("/health/live", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "alive",
+			"service":   "assessment",
+			"timestamp": time.Now().UTC(),
+			"uptime":    time.Since(startTime),
+		})
+	})
+	router.GET("/health/ready", readinessCheck)
+}
+
+// readinessCheck pings the database and Redis; both are required
+func readinessCheck(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	checks := gin.H{}
+	ready := true
+
+	if config.DB == nil {
+		checks["database"], ready = "not initialized", false
+	} else if sqlDB, err := config.DB.DB(); err != nil {
+		checks["database"], ready = err.Error(), false
+	} else if err := sqlDB.PingContext(ctx); err != nil {
+		checks["database"], ready = err.Error(), false
+	} else {
+		checks["database"] = "up"
+	}
+
+	if config.RedisClient == nil {
+		checks["redis"], ready = "not initialized", false
+	} else if err := config.RedisClient.Ping(ctx).Err(); err != nil {
+		checks["redis"], ready = err.Error(), false
+	} else {
+		checks["redis"] = "up"
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not ready", http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{
+		"status":    status,
+		"checks":    checks,
+		"timestamp": time.Now().UTC(),
+	})
+}
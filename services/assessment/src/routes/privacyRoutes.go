@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupPrivacyRoutes(router *gin.RouterGroup) {
+	privacyHandler := handlers.NewPrivacyHandler()
+
+	privacy := router.Group("/internal/privacy")
+	privacy.Use(middleware.ServiceAuthRequired())
+	{
+		privacy.GET("/users/:userId/export", privacyHandler.ExportUserData)
+		privacy.DELETE("/users/:userId", privacyHandler.EraseUserData)
+	}
+}
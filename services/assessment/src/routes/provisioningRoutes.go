@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupProvisioningRoutes(router *gin.RouterGroup) {
+	provisioningHandler := handlers.NewProvisioningHandler()
+
+	provisioning := router.Group("/internal/provisioning")
+	provisioning.Use(middleware.ServiceAuthRequired())
+	{
+		provisioning.POST("/events", provisioningHandler.HandleProvisioningEvent)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupQuestionBankRoutes(router *gin.RouterGroup) {
+	bankHandler := handlers.NewQuestionBankHandler()
+
+	banks := router.Group("/question-banks")
+	banks.Use(middleware.AuthRequired(), middleware.InstructorRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
+	{
+		banks.POST("", bankHandler.CreateBank)
+		banks.GET("", bankHandler.ListBanks)
+
+		// Shared marketplace
+		banks.GET("/marketplace", bankHandler.ListMarketplace)
+		banks.POST("/marketplace/:listingId/import", bankHandler.ImportListing)
+		banks.DELETE("/imports/:importId", bankHandler.RemoveImport)
+
+		banks.GET("/:id", bankHandler.GetBank)
+		banks.POST("/:id/questions", bankHandler.AddQuestion)
+		banks.POST("/:id/questions/:questionId/use", bankHandler.UseQuestion)
+		banks.POST("/:id/publish", bankHandler.PublishBank)
+		banks.POST("/:id/revoke", bankHandler.RevokeListing)
+		banks.GET("/:id/usage", bankHandler.GetUsage)
+	}
+}
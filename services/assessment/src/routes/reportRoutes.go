@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupReportRoutes(router *gin.RouterGroup) {
+	reportHandler := handlers.NewReportHandler()
+
+	reports := router.Group("/internal/reports")
+	reports.Use(middleware.ServiceAuthRequired())
+	{
+		reports.GET("/pending-grading", reportHandler.GetPendingGrading)
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupSupportRoutes(router *gin.RouterGroup) {
+	idResolutionHandler := handlers.NewIDResolutionHandler()
+
+	// Called by course-management's support lookup
+	support := router.Group("/internal/resolve")
+	support.Use(middleware.ServiceAuthRequired())
+	{
+		support.GET("/:id", idResolutionHandler.ResolveID)
+	}
+}
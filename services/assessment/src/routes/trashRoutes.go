@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/assessment/src/handlers"
+	"github.com/modex/assessment/src/middleware"
+)
+
+func SetupTrashRoutes(router *gin.RouterGroup) {
+	trashHandler := handlers.NewTrashHandler()
+
+	trash := router.Group("/assessments/trash")
+	trash.Use(middleware.AuthRequired(), middleware.InstructorRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
+	{
+		trash.GET("", trashHandler.ListTrash)
+		trash.POST("/assessments/:id/restore", trashHandler.RestoreAssessment)
+		trash.POST("/questions/:id/restore", trashHandler.RestoreQuestion)
+	}
+
+	internal := router.Group("/internal/trash")
+	internal.Use(middleware.ServiceAuthRequired())
+	{
+		internal.POST("/purge", trashHandler.PurgeTrash)
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+// Caller is the authenticated user making a request
+type Caller struct {
+	UserID uuid.UUID
+	Role   string
+}
+
+// IsAdmin reports whether the caller is a platform admin
+func (c Caller) IsAdmin() bool {
+	return c.Role == "admin"
+}
+
+// AccessPolicy decides who may manage an assessment and read its
+// submissions: admins, the instructor who created it, and course staff
+// holding the matching course permission. Students only reach their own
+// submissions.
+type AccessPolicy struct {
+	db      *gorm.DB
+	courses *CourseClient
+}
+
+func NewAccessPolicy() *AccessPolicy {
+	return &AccessPolicy{
+		db:      config.DB,
+		courses: NewCourseClient(),
+	}
+}
+
+// IsStaff reports whether caller may act on assessment with the course
+// permission perm. Course-management is only asked when the caller is
+// neither an admin nor the assessment's creator.
+func (p *AccessPolicy) IsStaff(ctx context.Context, caller Caller, assessment *models.Assessment, perm string) (bool, error) {
+	if caller.IsAdmin() || assessment.CreatedBy == caller.UserID {
+		return true, nil
+	}
+	if caller.Role != "instructor" {
+		return false, nil
+	}
+	return p.courses.HasPermission(ctx, assessment.CourseID, caller.UserID, perm)
+}
+
+// CanReadSubmissions reports whether caller may read studentID's submissions
+// to assessmentID
+func (p *AccessPolicy) CanReadSubmissions(ctx context.Context, caller Caller, assessmentID, studentID uuid.UUID) (bool, error) {
+	if studentID == caller.UserID || caller.IsAdmin() {
+		return true, nil
+	}
+
+	var assessment models.Assessment
+	err := p.db.Unscoped().Select("id", "course_id", "created_by").First(&assessment, "id = ?", assessmentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get assessment: %w", err)
+	}
+	return p.IsStaff(ctx, caller, &assessment, CoursePermissionGradeSubmissions)
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotAssessmentOwner = errors.New("only the assessment's instructor can manage its answer key")
+	ErrInvalidAnswerKey   = errors.New("invalid answer key")
+)
+
+// AnswerKey is an assessment's correct answers and points, exported for
+// offline review and imported back once reviewed
+type AnswerKey struct {
+	AssessmentID uuid.UUID           `json:"assessmentId"`
+	Title        string              `json:"title"`
+	Questions    []AnswerKeyQuestion `json:"questions"`
+	Watermark    *AnswerKeyWatermark `json:"watermark,omitempty"`
+}
+
+type AnswerKeyQuestion struct {
+	QuestionID uuid.UUID           `json:"questionId"`
+	OrderIndex int                 `json:"orderIndex"`
+	Type       models.QuestionType `json:"type"`
+	Question   string              `json:"question"`
+	Points     float64             `json:"points"`
+	Options    []AnswerKeyOption   `json:"options,omitempty"`
+}
+
+type AnswerKeyOption struct {
+	OptionID  uuid.UUID `json:"optionId"`
+	Text      string    `json:"text"`
+	IsCorrect bool      `json:"isCorrect"`
+}
+
+// AnswerKeyWatermark identifies who exported a key, so a leaked copy can be
+// traced back to its export
+type AnswerKeyWatermark struct {
+	ExportedBy  uuid.UUID `json:"exportedBy"`
+	ExportedAt  time.Time `json:"exportedAt"`
+	Fingerprint string    `json:"fingerprint"`
+	Notice      string    `json:"notice"`
+}
+
+// AnswerKeyUpdate is a reviewed question coming back in an import. Points and
+// CorrectOptionIDs are left alone when omitted.
+type AnswerKeyUpdate struct {
+	QuestionID       uuid.UUID    `json:"questionId" binding:"required"`
+	Points           *float64     `json:"points"`
+	CorrectOptionIDs *[]uuid.UUID `json:"correctOptionIds"`
+}
+
+// AnswerKeyImportResult reports what an import changed and whether existing
+// grades are now stale
+type AnswerKeyImportResult struct {
+	QuestionsUpdated    int   `json:"questionsUpdated"`
+	RegradeRequired     bool  `json:"regradeRequired"`
+	AffectedSubmissions int64 `json:"affectedSubmissions"`
+}
+
+// RegradeResult reports a regrade run
+type RegradeResult struct {
+	Regraded int `json:"regraded"`
+}
+
+type AnswerKeyService struct {
+	db         *gorm.DB
+	cache      *CacheService
+	assessment *AssessmentService
+}
+
+func NewAnswerKeyService() *AnswerKeyService {
+	return &AnswerKeyService{
+		db:         config.DB,
+		cache:      NewCacheService(),
+		assessment: NewAssessmentService(),
+	}
+}
+
+// Export returns the answer key for an assessment owned by userID, stamped
+// with a watermark for userID
+func (s *AnswerKeyService) Export(assessmentID, userID uuid.UUID) (*AnswerKey, error) {
+	assessment, err := s.ownedAssessment(assessmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &AnswerKey{AssessmentID: assessment.ID, Title: assessment.Title, Questions: []AnswerKeyQuestion{}}
+	for _, question := range assessment.Questions {
+		entry := AnswerKeyQuestion{
+			QuestionID: question.ID,
+			OrderIndex: question.OrderIndex,
+			Type:       question.Type,
+			Question:   question.Question,
+			Points:     question.Points,
+		}
+		for _, option := range question.Options {
+			entry.Options = append(entry.Options, AnswerKeyOption{
+				OptionID:  option.ID,
+				Text:      option.Text,
+				IsCorrect: option.IsCorrect,
+			})
+		}
+		key.Questions = append(key.Questions, entry)
+	}
+
+	exportedAt := time.Now().UTC()
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", assessment.ID, userID, exportedAt.UnixNano())))
+	key.Watermark = &AnswerKeyWatermark{
+		ExportedBy:  userID,
+		ExportedAt:  exportedAt,
+		Fingerprint: hex.EncodeToString(sum[:8]),
+		Notice:      fmt.Sprintf("CONFIDENTIAL answer key exported by %s at %s. Do not distribute.", userID, exportedAt.Format(time.RFC3339)),
+	}
+	log.Printf("Answer key for assessment %s exported by %s (fingerprint %s)", assessment.ID, userID, key.Watermark.Fingerprint)
+
+	return key, nil
+}
+
+// Import applies reviewed points and correct options to an assessment owned
+// by userID. Every update is validated before any is written, so a bad row
+// leaves the key untouched.
+func (s *AnswerKeyService) Import(assessmentID, userID uuid.UUID, updates []AnswerKeyUpdate) (*AnswerKeyImportResult, error) {
+	assessment, err := s.ownedAssessment(assessmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make(map[uuid.UUID]models.Question, len(assessment.Questions))
+	for _, question := range assessment.Questions {
+		questions[question.ID] = question
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, update := range updates {
+		if err := validateAnswerKeyUpdate(questions, update); err != nil {
+			return nil, err
+		}
+		if seen[update.QuestionID] {
+			return nil, fmt.Errorf("%w: question %s appears more than once", ErrInvalidAnswerKey, update.QuestionID)
+		}
+		seen[update.QuestionID] = true
+	}
+
+	var changed []uuid.UUID
+	pointsChanged := false
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, update := range updates {
+			question := questions[update.QuestionID]
+			questionChanged := false
+
+			if update.Points != nil && *update.Points != question.Points {
+				if err := tx.Model(&models.Question{}).Where("id = ?", question.ID).
+					Update("points", *update.Points).Error; err != nil {
+					return fmt.Errorf("failed to update points: %w", err)
+				}
+				questionChanged = true
+				pointsChanged = true
+			}
+
+			if update.CorrectOptionIDs != nil {
+				correct := map[uuid.UUID]bool{}
+				for _, id := range *update.CorrectOptionIDs {
+					correct[id] = true
+				}
+				for _, option := range question.Options {
+					if option.IsCorrect == correct[option.ID] {
+						continue
+					}
+					if err := tx.Model(&models.QuestionOption{}).Where("id = ?", option.ID).
+						Update("is_correct", correct[option.ID]).Error; err != nil {
+						return fmt.Errorf("failed to update options: %w", err)
+					}
+					questionChanged = true
+				}
+			}
+
+			if questionChanged {
+				changed = append(changed, question.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AnswerKeyImportResult{QuestionsUpdated: len(changed)}
+	if len(changed) == 0 {
+		return result, nil
+	}
+	s.cache.Delete(fmt.Sprintf("assessment:%s", assessmentID))
+
+	// New points change every graded submission's maximum score; new correct
+	// options only affect submissions that answered those questions
+	affected := s.db.Model(&models.Submission{}).
+		Where("assessment_id = ? AND status = ?", assessmentID, models.SubmissionStatusGraded)
+	if !pointsChanged {
+		affected = affected.Where("id IN (?)", s.db.Model(&models.SubmissionAnswer{}).Select("submission_id").Where("question_id IN ?", changed))
+	}
+	if err := affected.Count(&result.AffectedSubmissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to count affected submissions: %w", err)
+	}
+	result.RegradeRequired = result.AffectedSubmissions > 0
+
+	return result, nil
+}
+
+// Regrade rescores an owned assessment's graded submissions against the
+// current key. Choice and true/false answers are regraded; manually graded
+// text and essay answers, and code answers graded by running them, keep the
+// points they were given.
+func (s *AnswerKeyService) Regrade(assessmentID, userID uuid.UUID) (*RegradeResult, error) {
+	assessment, err := s.ownedAssessment(assessmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var submissions []models.Submission
+	if err := s.db.Preload("Answers").
+		Where("assessment_id = ? AND status = ?", assessmentID, models.SubmissionStatusGraded).
+		Find(&submissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get graded submissions: %w", err)
+	}
+
+	result := &RegradeResult{}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for _, submission := range submissions {
+			// Attempts drawing from pools each have their own questions
+			questions := map[uuid.UUID]models.Question{}
+			maxScore := 0.0
+			for _, question := range paperQuestions(assessment, &submission) {
+				questions[question.ID] = question
+				maxScore += question.Points
+			}
+
+			total := 0.0
+			for _, answer := range submission.Answers {
+				question, ok := questions[answer.QuestionID]
+				if !ok {
+					continue
+				}
+				if question.Type == models.QuestionTypeText || question.Type == models.QuestionTypeEssay || question.Type == models.QuestionTypeCode {
+					if answer.PointsEarned != nil {
+						total += *answer.PointsEarned
+					}
+					continue
+				}
+
+				points := s.assessment.gradeAnswer(answer, question)
+				total += points
+				if err := tx.Model(&answer).Updates(map[string]interface{}{
+					"points_earned": points,
+					"is_correct":    points == question.Points,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to regrade answer: %w", err)
+				}
+			}
+
+			if err := tx.Model(&submission).Updates(map[string]interface{}{
+				"score":     total,
+				"max_score": maxScore,
+				"passed":    total >= maxScore*assessment.PassingScore/100,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to regrade submission: %w", err)
+			}
+			result.Regraded++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Regraded %d submissions for assessment %s after answer key change", result.Regraded, assessmentID)
+	return result, nil
+}
+
+func (s *AnswerKeyService) ownedAssessment(assessmentID, userID uuid.UUID) (*models.Assessment, error) {
+	var assessment models.Assessment
+	err := s.db.Preload("Questions", func(db *gorm.DB) *gorm.DB { return db.Order("order_index ASC") }).
+		Preload("Questions.Options", func(db *gorm.DB) *gorm.DB { return db.Order("order_index ASC") }).
+		First(&assessment, "id = ?", assessmentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAssessmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assessment: %w", err)
+	}
+	if assessment.CreatedBy != userID {
+		return nil, ErrNotAssessmentOwner
+	}
+	return &assessment, nil
+}
+
+func validateAnswerKeyUpdate(questions map[uuid.UUID]models.Question, update AnswerKeyUpdate) error {
+	question, ok := questions[update.QuestionID]
+	if !ok {
+		return fmt.Errorf("%w: question %s is not in this assessment", ErrInvalidAnswerKey, update.QuestionID)
+	}
+	if update.Points != nil && *update.Points <= 0 {
+		return fmt.Errorf("%w: question %s points must be positive", ErrInvalidAnswerKey, update.QuestionID)
+	}
+	if update.CorrectOptionIDs == nil {
+		return nil
+	}
+
+	correct := *update.CorrectOptionIDs
+	switch question.Type {
+	case models.QuestionTypeSingleChoice, models.QuestionTypeTrueFalse:
+		if len(correct) != 1 {
+			return fmt.Errorf("%w: question %s needs exactly one correct option", ErrInvalidAnswerKey, update.QuestionID)
+		}
+	case models.QuestionTypeMultipleChoice:
+		if len(correct) == 0 {
+			return fmt.Errorf("%w: question %s needs at least one correct option", ErrInvalidAnswerKey, update.QuestionID)
+		}
+	default:
+		return fmt.Errorf("%w: question %s is graded manually and has no correct options", ErrInvalidAnswerKey, update.QuestionID)
+	}
+
+	options := map[uuid.UUID]bool{}
+	for _, option := range question.Options {
+		options[option.ID] = true
+	}
+	for _, id := range correct {
+		if !options[id] {
+			return fmt.Errorf("%w: option %s does not belong to question %s", ErrInvalidAnswerKey, id, update.QuestionID)
+		}
+	}
+	return nil
+}
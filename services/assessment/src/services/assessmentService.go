@@ -3,7 +3,11 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,20 +16,52 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrSubmissionNotFound     = errors.New("submission not found")
+	ErrSubmissionClosed       = errors.New("submission has already been submitted")
+	ErrSubmissionPastDeadline = errors.New("time limit for this attempt has passed")
+	ErrAttemptsExhausted      = errors.New("no attempts remaining")
+	ErrInvalidAnswer          = errors.New("invalid answer")
+)
+
+// startAttemptRetries bounds how often StartSubmission retries after losing a
+// race for the next attempt number
+const startAttemptRetries = 3
+
+// defaultSubmitGrace absorbs network latency between a client's timer running
+// out and its submission arriving
+const defaultSubmitGrace = 30 * time.Second
+
 type AssessmentService struct {
 	db    *gorm.DB
 	cache *CacheService
+	// grace is how long after an attempt's deadline a submission is still on
+	// time. Past it, submissions are rejected when rejectLate is set and
+	// flagged late otherwise.
+	grace        time.Duration
+	rejectLate   bool
+	availability *AvailabilityService
+	banks        *QuestionBankService
+	runner       CodeRunner
 }
 
 func NewAssessmentService() *AssessmentService {
 	return &AssessmentService{
-		db:    config.DB,
-		cache: NewCacheService(),
+		db:           config.DB,
+		cache:        NewCacheService(),
+		grace:        durationFromEnv("ASSESSMENT_SUBMIT_GRACE", defaultSubmitGrace),
+		rejectLate:   os.Getenv("ASSESSMENT_LATE_SUBMISSIONS") == "reject",
+		availability: NewAvailabilityService(),
+		banks:        NewQuestionBankService(),
+		runner:       NewCodeRunner(),
 	}
 }
 
 // Assessment CRUD Operations
 func (s *AssessmentService) CreateAssessment(assessment *models.Assessment) error {
+	if err := validateQuestions(assessment.Questions); err != nil {
+		return err
+	}
 	if err := s.db.Create(assessment).Error; err != nil {
 		return fmt.Errorf("failed to create assessment: %w", err)
 	}
@@ -82,6 +118,9 @@ func (s *AssessmentService) GetAssessmentsByCourse(courseID uuid.UUID) ([]models
 }
 
 func (s *AssessmentService) UpdateAssessment(assessment *models.Assessment) error {
+	if err := validateQuestions(assessment.Questions); err != nil {
+		return err
+	}
 	if err := s.db.Save(assessment).Error; err != nil {
 		return fmt.Errorf("failed to update assessment: %w", err)
 	}
@@ -102,6 +141,54 @@ func (s *AssessmentService) DeleteAssessment(id uuid.UUID) error {
 	return nil
 }
 
+// ReassignAssessments hands every assessment created by fromID over to toID,
+// so a deprovisioned instructor's grading queue moves to their successor.
+func (s *AssessmentService) ReassignAssessments(fromID, toID uuid.UUID) (int64, error) {
+	result := s.db.Model(&models.Assessment{}).Where("created_by = ?", fromID).
+		Update("created_by", toID)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to reassign assessments: %w", result.Error)
+	}
+
+	// Invalidate cache
+	s.cache.DeletePattern("assessment:*")
+	return result.RowsAffected, nil
+}
+
+// PendingGradingItem counts submissions awaiting grading for one assessment
+type PendingGradingItem struct {
+	AssessmentID      uuid.UUID  `json:"assessmentId"`
+	CourseID          uuid.UUID  `json:"courseId"`
+	Title             string     `json:"title"`
+	Pending           int64      `json:"pending"`
+	OldestSubmittedAt *time.Time `json:"oldestSubmittedAt"`
+}
+
+// GetPendingGrading summarises submitted or in-review submissions per
+// assessment, optionally narrowed to a course and/or assessment author.
+func (s *AssessmentService) GetPendingGrading(courseID, createdBy *uuid.UUID) ([]PendingGradingItem, error) {
+	query := s.db.Table("submissions").
+		Select("assessments.id AS assessment_id, assessments.course_id, assessments.title, COUNT(submissions.id) AS pending, MIN(submissions.submitted_at) AS oldest_submitted_at").
+		Joins("JOIN assessments ON assessments.id = submissions.assessment_id AND assessments.deleted_at IS NULL").
+		Where("submissions.status IN ?", []models.SubmissionStatus{models.SubmissionStatusSubmitted, models.SubmissionStatusGradingPending, models.SubmissionStatusReviewing})
+
+	if courseID != nil {
+		query = query.Where("assessments.course_id = ?", *courseID)
+	}
+	if createdBy != nil {
+		query = query.Where("assessments.created_by = ?", *createdBy)
+	}
+
+	var items []PendingGradingItem
+	err := query.Group("assessments.id, assessments.course_id, assessments.title").
+		Order("oldest_submitted_at ASC").
+		Scan(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending grading: %w", err)
+	}
+	return items, nil
+}
+
 // Question Operations
 func (s *AssessmentService) AddQuestion(question *models.Question) error {
 	return s.db.Create(question).Error
@@ -111,8 +198,20 @@ func (s *AssessmentService) UpdateQuestion(question *models.Question) error {
 	return s.db.Save(question).Error
 }
 
-func (s *AssessmentService) DeleteQuestion(id uuid.UUID) error {
-	return s.db.Delete(&models.Question{}, id).Error
+// DeleteQuestion moves a question to the trash. Its options are left in
+// place so restoring the question brings them back.
+func (s *AssessmentService) DeleteQuestion(assessmentID, id uuid.UUID) error {
+	result := s.db.Where("assessment_id = ?", assessmentID).Delete(&models.Question{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete question: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuestionNotFound
+	}
+
+	// Invalidate cache
+	s.cache.Delete(fmt.Sprintf("assessment:%s", assessmentID))
+	return nil
 }
 
 // Submission Operations
@@ -120,6 +219,113 @@ func (s *AssessmentService) CreateSubmission(submission *models.Submission) erro
 	return s.db.Create(submission).Error
 }
 
+// StartSubmission opens a new attempt at assessmentID for studentID, up to
+// the assessment's MaxAttempts (0 means unlimited). Attempts can only start
+// inside the availability window unless overrideToken redeems an override
+// for the student. Timed assessments get a deadline the client can count
+// down to, capped at when the attempt closes.
+//
+// Attempt numbers are unique per student and assessment, so two concurrent
+// starts can't both take the last attempt: the loser retries against the
+// updated count.
+func (s *AssessmentService) StartSubmission(assessmentID, studentID uuid.UUID, overrideToken string) (*models.Submission, error) {
+	assessment, err := s.GetAssessmentByID(assessmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAssessmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assessment: %w", err)
+	}
+
+	closesAt := assessment.ClosesAt()
+	if overrideToken != "" {
+		override, err := s.availability.redeem(assessmentID, studentID, overrideToken)
+		if err != nil {
+			return nil, err
+		}
+		closesAt = &override.ValidUntil
+
+		submission, err := s.openAttempt(assessment, studentID, closesAt)
+		if err != nil {
+			s.availability.release(override)
+			return nil, err
+		}
+		log.Printf("Student %s started assessment %s with availability override %s", studentID, assessmentID, override.ID)
+		return submission, nil
+	}
+
+	now := time.Now()
+	if assessment.AvailableFrom != nil && now.Before(*assessment.AvailableFrom) {
+		return nil, ErrAssessmentNotOpen
+	}
+	if assessment.AvailableTo != nil && now.After(*assessment.AvailableTo) {
+		return nil, ErrAssessmentClosed
+	}
+	return s.openAttempt(assessment, studentID, closesAt)
+}
+
+// openAttempt creates the next attempt, drawing its pool questions once the
+// student is known to have an attempt left
+func (s *AssessmentService) openAttempt(assessment *models.Assessment, studentID uuid.UUID, closesAt *time.Time) (*models.Submission, error) {
+	var order []uuid.UUID
+	for try := 0; ; try++ {
+		var used int
+		if err := s.db.Model(&models.Submission{}).
+			Where("assessment_id = ? AND student_id = ?", assessment.ID, studentID).
+			Select("COALESCE(MAX(attempt_number), 0)").Scan(&used).Error; err != nil {
+			return nil, fmt.Errorf("failed to count attempts: %w", err)
+		}
+		if assessment.MaxAttempts > 0 && used >= assessment.MaxAttempts {
+			return nil, fmt.Errorf("%w: all %d attempts have been used", ErrAttemptsExhausted, assessment.MaxAttempts)
+		}
+
+		if order == nil {
+			drawn, err := s.banks.drawPools(assessment.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(drawn) > 0 {
+				// Reload so first-time copies of drawn questions are included
+				if assessment, err = s.GetAssessmentByID(assessment.ID); err != nil {
+					return nil, fmt.Errorf("failed to get assessment: %w", err)
+				}
+			}
+			order = questionOrder(assessment, drawn)
+		}
+
+		startedAt := time.Now()
+		submission := &models.Submission{
+			AssessmentID:  assessment.ID,
+			StudentID:     studentID,
+			AttemptNumber: used + 1,
+			Status:        models.SubmissionStatusInProgress,
+			StartedAt:     startedAt,
+			ClosesAt:      closesAt,
+			QuestionOrder: order,
+			OptionOrder:   optionOrder(assessment, order),
+		}
+		if assessment.TimeLimit > 0 {
+			deadline := startedAt.Add(time.Duration(assessment.TimeLimit) * time.Minute)
+			submission.Deadline = &deadline
+		}
+		if closesAt != nil && (submission.Deadline == nil || closesAt.Before(*submission.Deadline)) {
+			submission.Deadline = closesAt
+		}
+
+		err := s.CreateSubmission(submission)
+		if err == nil {
+			return submission, nil
+		}
+		if !isDuplicateKey(err) || try == startAttemptRetries {
+			return nil, fmt.Errorf("failed to create submission: %w", err)
+		}
+	}
+}
+
+func isDuplicateKey(err error) bool {
+	return errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key")
+}
+
 func (s *AssessmentService) GetSubmission(id uuid.UUID) (*models.Submission, error) {
 	var submission models.Submission
 	err := s.db.Preload("Answers").First(&submission, "id = ?", id).Error
@@ -133,37 +339,116 @@ func (s *AssessmentService) GetStudentSubmissions(studentID, assessmentID uuid.U
 	return submissions, err
 }
 
-func (s *AssessmentService) SubmitAssessment(submissionID uuid.UUID, answers []models.SubmissionAnswer) error {
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// SubmitAssessment saves answers for an in-progress submission and closes it.
+// Time spent is measured here from StartedAt rather than trusted from the
+// client, and submissions past the deadline plus grace are rejected or
+// flagged late.
+func (s *AssessmentService) SubmitAssessment(submissionID uuid.UUID, answers []models.SubmissionAnswer) (*models.Submission, error) {
+	var submission models.Submission
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&submission, "id = ?", submissionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSubmissionNotFound
+			}
+			return err
+		}
+		if submission.Status != models.SubmissionStatusInProgress {
+			return ErrSubmissionClosed
 		}
-	}()
 
-	// Save answers
-	for _, answer := range answers {
-		answer.SubmissionID = submissionID
-		if err := tx.Create(&answer).Error; err != nil {
-			tx.Rollback()
+		now := time.Now()
+		if submission.ClosesAt != nil && now.After(*submission.ClosesAt) {
+			return ErrAssessmentClosed
+		}
+		late := submission.Deadline != nil && now.After(submission.Deadline.Add(s.grace))
+		if late && s.rejectLate {
+			return ErrSubmissionPastDeadline
+		}
+
+		rows, err := s.answerRows(tx, &submission, answers)
+		if err != nil {
 			return err
 		}
+
+		// Only the request that moves the submission out of in_progress
+		// gets to save answers, so concurrent submits can't both land
+		result := tx.Model(&models.Submission{}).
+			Where("id = ? AND status = ?", submissionID, models.SubmissionStatusInProgress).
+			Updates(map[string]interface{}{
+				"status":       models.SubmissionStatusSubmitted,
+				"submitted_at": &now,
+				"time_spent":   int(now.Sub(submission.StartedAt).Seconds()),
+				"late":         late,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSubmissionClosed
+		}
+
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+
+		submission.Status = models.SubmissionStatusSubmitted
+		submission.SubmittedAt = &now
+		submission.TimeSpent = int(now.Sub(submission.StartedAt).Seconds())
+		submission.Late = late
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Update submission status and time
-	now := time.Now()
-	if err := tx.Model(&models.Submission{}).Where("id = ?", submissionID).
-		Updates(map[string]interface{}{
-			"status":       models.SubmissionStatusSubmitted,
-			"submitted_at": &now,
-		}).Error; err != nil {
-		tx.Rollback()
-		return err
+	if submission.Late {
+		log.Printf("Submission %s arrived after its deadline of %s", submission.ID, submission.Deadline.Format(time.RFC3339))
+	}
+	return &submission, nil
+}
+
+// answerRows builds the rows to store for a submission's answers. Only the
+// student's responses are taken from answers; IDs, scores and results are
+// left for the database and grading to fill in. Every answer must be for a
+// distinct question on the attempt's paper.
+func (s *AssessmentService) answerRows(tx *gorm.DB, submission *models.Submission, answers []models.SubmissionAnswer) ([]models.SubmissionAnswer, error) {
+	var assessment models.Assessment
+	if err := tx.Preload("Questions").First(&assessment, "id = ?", submission.AssessmentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get assessment: %w", err)
+	}
+	onPaper := make(map[uuid.UUID]bool, len(assessment.Questions))
+	for _, question := range paperQuestions(&assessment, submission) {
+		onPaper[question.ID] = true
 	}
 
-	return tx.Commit().Error
+	rows := make([]models.SubmissionAnswer, 0, len(answers))
+	answered := make(map[uuid.UUID]bool, len(answers))
+	for _, answer := range answers {
+		if !onPaper[answer.QuestionID] {
+			return nil, fmt.Errorf("%w: question %s is not on this attempt", ErrInvalidAnswer, answer.QuestionID)
+		}
+		if answered[answer.QuestionID] {
+			return nil, fmt.Errorf("%w: question %s is answered more than once", ErrInvalidAnswer, answer.QuestionID)
+		}
+		answered[answer.QuestionID] = true
+
+		rows = append(rows, models.SubmissionAnswer{
+			SubmissionID:    submission.ID,
+			QuestionID:      answer.QuestionID,
+			SelectedOptions: answer.SelectedOptions,
+			TextAnswer:      answer.TextAnswer,
+			Language:        answer.Language,
+		})
+	}
+	return rows, nil
 }
 
+// GradeSubmission auto-grades a submitted attempt. Answers and the score are
+// saved together, so a submission is either fully graded or not at all. If
+// the code runner fails the submission is left pending and the grading sweeper
+// tries again later.
 func (s *AssessmentService) GradeSubmission(submissionID uuid.UUID) error {
 	var submission models.Submission
 	if err := s.db.Preload("Answers").First(&submission, submissionID).Error; err != nil {
@@ -178,14 +463,17 @@ func (s *AssessmentService) GradeSubmission(submissionID uuid.UUID) error {
 	totalScore := 0.0
 	maxScore := 0.0
 
-	// Create a map for quick question lookup
+	// Create a map for quick question lookup. Only questions on this attempt
+	// count, so answers to pool questions it didn't draw earn nothing.
 	questionMap := make(map[uuid.UUID]models.Question)
-	for _, question := range assessment.Questions {
+	for _, question := range paperQuestions(&assessment, &submission) {
 		questionMap[question.ID] = question
 		maxScore += question.Points
 	}
 
-	// Grade each answer
+	// Grade each answer. Code is run before anything is written so a runner
+	// failure leaves the submission untouched.
+	graded := make([]models.SubmissionAnswer, 0, len(submission.Answers))
 	for _, answer := range submission.Answers {
 		question, exists := questionMap[answer.QuestionID]
 		if !exists {
@@ -193,24 +481,70 @@ func (s *AssessmentService) GradeSubmission(submissionID uuid.UUID) error {
 		}
 
 		pointsEarned := s.gradeAnswer(answer, question)
+		if question.Type == models.QuestionTypeCode {
+			points, results, err := s.gradeCodeAnswer(context.Background(), answer, question)
+			if err != nil {
+				log.Printf("Failed to run code answer %s of submission %s: %v", answer.ID, submission.ID, err)
+				return s.deferGrading(&submission, err)
+			}
+			pointsEarned = points
+			answer.CodeResults = results
+		}
 		totalScore += pointsEarned
 
-		// Update answer with points and correctness
 		isCorrect := pointsEarned == question.Points
-		s.db.Model(&answer).Updates(map[string]interface{}{
-			"points_earned": pointsEarned,
-			"is_correct":    isCorrect,
-		})
+		answer.PointsEarned = &pointsEarned
+		answer.IsCorrect = &isCorrect
+		graded = append(graded, answer)
 	}
 
-	// Update submission with final score
 	passed := totalScore >= (maxScore * assessment.PassingScore / 100)
-	return s.db.Model(&submission).Updates(map[string]interface{}{
-		"score":     totalScore,
-		"max_score": maxScore,
-		"passed":    passed,
-		"status":    models.SubmissionStatusGraded,
-	}).Error
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range graded {
+			if err := tx.Model(&graded[i]).Select("points_earned", "is_correct", "code_results").Updates(&graded[i]).Error; err != nil {
+				return fmt.Errorf("failed to save graded answer: %w", err)
+			}
+		}
+
+		// Only the first grader of a submission records the result
+		result := tx.Model(&submission).
+			Where("status IN ?", []models.SubmissionStatus{models.SubmissionStatusSubmitted, models.SubmissionStatusGradingPending}).
+			Updates(map[string]interface{}{
+				"score":           totalScore,
+				"max_score":       maxScore,
+				"passed":          passed,
+				"status":          models.SubmissionStatusGraded,
+				"next_grading_at": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSubmissionClosed
+		}
+		return nil
+	})
+	if errors.Is(err, ErrSubmissionClosed) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := publishEvent(assessmentEventsChannel, eventSubmissionGraded, "Submission", submission.ID, submission.StudentID.String(), map[string]interface{}{
+		"submissionId":   submission.ID,
+		"assessmentId":   assessment.ID,
+		"courseId":       assessment.CourseID,
+		"studentId":      submission.StudentID,
+		"attemptNumber":  submission.AttemptNumber,
+		"score":          totalScore,
+		"maxScore":       maxScore,
+		"passed":         passed,
+		"organizationId": assessment.OrganizationID,
+	}); err != nil {
+		log.Printf("Failed to publish submission graded event for %s: %v", submission.ID, err)
+	}
+	return nil
 }
 
 func (s *AssessmentService) gradeAnswer(answer models.SubmissionAnswer, question models.Question) float64 {
@@ -222,6 +556,9 @@ func (s *AssessmentService) gradeAnswer(answer models.SubmissionAnswer, question
 	case models.QuestionTypeText, models.QuestionTypeEssay:
 		// Manual grading required - return 0 for now
 		return 0.0
+	case models.QuestionTypeCode:
+		// Needs the code runner - see gradeCodeAnswer
+		return 0.0
 	default:
 		return 0.0
 	}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+// AttemptQuestion is a question as a student sees it while taking an
+// assessment: nothing that gives the answer away
+type AttemptQuestion struct {
+	ID       uuid.UUID           `json:"id"`
+	Type     models.QuestionType `json:"type"`
+	Question string              `json:"question"`
+	Points   float64             `json:"points"`
+	Required bool                `json:"required"`
+	MediaURL string              `json:"mediaUrl,omitempty"`
+	Options  []AttemptOption     `json:"options,omitempty"`
+	Code     *AttemptCode        `json:"code,omitempty"`
+}
+
+// AttemptCode is what students see of a code question: its languages,
+// starter code, limits and the test cases that aren't hidden
+type AttemptCode struct {
+	Languages     []string              `json:"languages"`
+	StarterCode   map[string]string     `json:"starterCode,omitempty"`
+	Examples      []models.CodeTestCase `json:"examples,omitempty"`
+	TimeLimitMs   int                   `json:"timeLimitMs,omitempty"`
+	MemoryLimitKB int                   `json:"memoryLimitKb,omitempty"`
+}
+
+type AttemptOption struct {
+	ID   uuid.UUID `json:"id"`
+	Text string    `json:"text"`
+}
+
+// questionOrder is the order an attempt's questions are shown in: the
+// assessment's own questions by OrderIndex, then the questions drawn from its
+// pools, all shuffled when the assessment randomizes questions
+func questionOrder(assessment *models.Assessment, drawn []uuid.UUID) []uuid.UUID {
+	var questions []models.Question
+	for _, question := range assessment.Questions {
+		if question.PoolID == nil {
+			questions = append(questions, question)
+		}
+	}
+	sort.SliceStable(questions, func(i, j int) bool { return questions[i].OrderIndex < questions[j].OrderIndex })
+
+	order := make([]uuid.UUID, 0, len(questions)+len(drawn))
+	for _, question := range questions {
+		order = append(order, question.ID)
+	}
+	order = append(order, drawn...)
+	if assessment.RandomizeQuestions {
+		rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	return order
+}
+
+// optionOrder shuffles the options of each question in questions when the
+// assessment randomizes them, or returns nil to keep OrderIndex order.
+// True/false options keep their order; shuffling them only confuses.
+func optionOrder(assessment *models.Assessment, questions []uuid.UUID) map[uuid.UUID][]uuid.UUID {
+	if !assessment.RandomizeOptions {
+		return nil
+	}
+
+	onPaper := make(map[uuid.UUID]bool, len(questions))
+	for _, id := range questions {
+		onPaper[id] = true
+	}
+
+	order := map[uuid.UUID][]uuid.UUID{}
+	for _, question := range assessment.Questions {
+		if !onPaper[question.ID] || len(question.Options) < 2 || question.Type == models.QuestionTypeTrueFalse {
+			continue
+		}
+		ids := make([]uuid.UUID, len(question.Options))
+		for i, option := range question.Options {
+			ids[i] = option.ID
+		}
+		rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+		order[question.ID] = ids
+	}
+	return order
+}
+
+// PaperLength is how many questions each attempt at assessment has: its own
+// questions plus what its pools draw
+func (s *AssessmentService) PaperLength(assessment *models.Assessment) (int, error) {
+	length := 0
+	for _, question := range assessment.Questions {
+		if question.PoolID == nil {
+			length++
+		}
+	}
+
+	pools, err := s.banks.GetPools(assessment.ID)
+	if err != nil {
+		return 0, err
+	}
+	for _, pool := range pools {
+		length += pool.Count
+	}
+	return length, nil
+}
+
+// AttemptQuestions returns submission's questions, and their options, in
+// the order persisted when the attempt started, so resuming shows them the
+// same way. Anything added since then follows in OrderIndex order; deleted
+// questions and options are dropped.
+func (s *AssessmentService) AttemptQuestions(submission *models.Submission) ([]AttemptQuestion, error) {
+	assessment, err := s.GetAssessmentByID(submission.AssessmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAssessmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assessment: %w", err)
+	}
+	return attemptQuestions(assessment, submission), nil
+}
+
+func attemptQuestions(assessment *models.Assessment, submission *models.Submission) []AttemptQuestion {
+	ordered := paperQuestions(assessment, submission)
+
+	questions := make([]AttemptQuestion, 0, len(ordered))
+	for _, question := range ordered {
+		options := orderedOptions(question, submission.OptionOrder[question.ID])
+
+		entry := AttemptQuestion{
+			ID:       question.ID,
+			Type:     question.Type,
+			Question: question.Question,
+			Points:   question.Points,
+			Required: question.Required,
+			MediaURL: question.MediaURL,
+		}
+		for _, option := range options {
+			entry.Options = append(entry.Options, AttemptOption{ID: option.ID, Text: option.Text})
+		}
+		if spec := question.Code; spec != nil {
+			entry.Code = &AttemptCode{
+				Languages:     spec.Languages,
+				StarterCode:   spec.StarterCode,
+				TimeLimitMs:   spec.TimeLimitMs,
+				MemoryLimitKB: spec.MemoryLimitKB,
+			}
+			for _, test := range spec.TestCases {
+				if !test.Hidden {
+					entry.Code.Examples = append(entry.Code.Examples, test)
+				}
+			}
+		}
+		questions = append(questions, entry)
+	}
+	return questions
+}
+
+// paperQuestions returns the questions on submission's attempt in the order
+// it shows them. Pool questions are only on the attempts that drew them.
+func paperQuestions(assessment *models.Assessment, submission *models.Submission) []models.Question {
+	byID := make(map[uuid.UUID]models.Question, len(assessment.Questions))
+	for _, question := range assessment.Questions {
+		byID[question.ID] = question
+	}
+
+	ordered := make([]models.Question, 0, len(assessment.Questions))
+	placed := map[uuid.UUID]bool{}
+	for _, id := range submission.QuestionOrder {
+		if question, ok := byID[id]; ok && !placed[id] {
+			ordered = append(ordered, question)
+			placed[id] = true
+		}
+	}
+	var added []models.Question
+	for _, question := range assessment.Questions {
+		if !placed[question.ID] && question.PoolID == nil {
+			added = append(added, question)
+		}
+	}
+	sort.SliceStable(added, func(i, j int) bool { return added[i].OrderIndex < added[j].OrderIndex })
+	return append(ordered, added...)
+}
+
+// orderedOptions puts question's options in order, falling back to
+// OrderIndex for options it doesn't list
+func orderedOptions(question models.Question, order []uuid.UUID) []models.QuestionOption {
+	options := append([]models.QuestionOption(nil), question.Options...)
+	sort.SliceStable(options, func(i, j int) bool { return options[i].OrderIndex < options[j].OrderIndex })
+	if len(order) == 0 {
+		return options
+	}
+
+	position := make(map[uuid.UUID]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+	sort.SliceStable(options, func(i, j int) bool {
+		pi, iListed := position[options[i].ID]
+		pj, jListed := position[options[j].ID]
+		if iListed != jListed {
+			return iListed
+		}
+		return iListed && pi < pj
+	})
+	return options
+}
+
+// AttemptPaper is everything a student needs to take an attempt, with
+// correct answers and explanations left out
+type AttemptPaper struct {
+	Assessment AttemptAssessment `json:"assessment"`
+	Submission AttemptSubmission `json:"submission"`
+	Questions  []AttemptQuestion `json:"questions"`
+	ServerTime time.Time         `json:"serverTime"`
+}
+
+type AttemptAssessment struct {
+	ID           uuid.UUID             `json:"id"`
+	Title        string                `json:"title"`
+	Description  string                `json:"description"`
+	Instructions string                `json:"instructions"`
+	Type         models.AssessmentType `json:"type"`
+	TimeLimit    int                   `json:"timeLimit"`
+	PassingScore float64               `json:"passingScore"`
+}
+
+type AttemptSubmission struct {
+	ID            uuid.UUID                 `json:"id"`
+	AttemptNumber int                       `json:"attemptNumber"`
+	Status        models.SubmissionStatus   `json:"status"`
+	StartedAt     time.Time                 `json:"startedAt"`
+	Deadline      *time.Time                `json:"deadline"`
+	ClosesAt      *time.Time                `json:"closesAt"`
+	Answers       []models.SubmissionAnswer `json:"answers,omitempty"`
+}
+
+// TakePaper returns submission's attempt paper. Saved answers are included
+// without their grading.
+func (s *AssessmentService) TakePaper(submission *models.Submission) (*AttemptPaper, error) {
+	assessment, err := s.GetAssessmentByID(submission.AssessmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAssessmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assessment: %w", err)
+	}
+
+	paper := &AttemptPaper{
+		Assessment: AttemptAssessment{
+			ID:           assessment.ID,
+			Title:        assessment.Title,
+			Description:  assessment.Description,
+			Instructions: assessment.Instructions,
+			Type:         assessment.Type,
+			TimeLimit:    assessment.TimeLimit,
+			PassingScore: assessment.PassingScore,
+		},
+		Submission: AttemptSubmission{
+			ID:            submission.ID,
+			AttemptNumber: submission.AttemptNumber,
+			Status:        submission.Status,
+			StartedAt:     submission.StartedAt,
+			Deadline:      submission.Deadline,
+			ClosesAt:      submission.ClosesAt,
+		},
+		Questions:  attemptQuestions(assessment, submission),
+		ServerTime: time.Now().UTC(),
+	}
+	for _, answer := range submission.Answers {
+		answer.IsCorrect = nil
+		answer.PointsEarned = nil
+		answer.CodeResults = nil
+		paper.Submission.Answers = append(paper.Submission.Answers, answer)
+	}
+	return paper, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAssessmentNotOpen = errors.New("assessment is not open yet")
+	ErrAssessmentClosed  = errors.New("assessment is closed")
+	ErrInvalidOverride   = errors.New("override token is invalid, expired or already used")
+)
+
+// AvailabilityService issues and redeems overrides of assessment
+// availability windows
+type AvailabilityService struct {
+	db *gorm.DB
+}
+
+func NewAvailabilityService() *AvailabilityService {
+	return &AvailabilityService{db: config.DB}
+}
+
+// IssueOverride lets studentID start one attempt at assessmentID until
+// validUntil, whatever the availability window says. The returned token is
+// the only copy; it is stored hashed.
+func (s *AvailabilityService) IssueOverride(assessmentID, studentID, issuedBy uuid.UUID, validUntil time.Time, reason string) (*models.AvailabilityOverride, string, error) {
+	if !validUntil.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: validUntil must be in the future", ErrInvalidOverride)
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("failed to generate override token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+
+	override := &models.AvailabilityOverride{
+		AssessmentID: assessmentID,
+		StudentID:    studentID,
+		TokenHash:    hashOverrideToken(token),
+		ValidUntil:   validUntil,
+		Reason:       reason,
+		IssuedBy:     issuedBy,
+	}
+	if err := s.db.Create(override).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create override: %w", err)
+	}
+	return override, token, nil
+}
+
+// redeem marks studentID's override token for assessmentID used and returns
+// it. Marking and checking happen in one update so a token can't be spent
+// twice.
+func (s *AvailabilityService) redeem(assessmentID, studentID uuid.UUID, token string) (*models.AvailabilityOverride, error) {
+	now := time.Now()
+	hash := hashOverrideToken(token)
+
+	result := s.db.Model(&models.AvailabilityOverride{}).
+		Where("token_hash = ? AND assessment_id = ? AND student_id = ? AND used_at IS NULL AND valid_until > ?", hash, assessmentID, studentID, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to redeem override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidOverride
+	}
+
+	var override models.AvailabilityOverride
+	if err := s.db.First(&override, "token_hash = ?", hash).Error; err != nil {
+		return nil, fmt.Errorf("failed to get override: %w", err)
+	}
+	return &override, nil
+}
+
+// release returns a redeemed override when the attempt it was for couldn't
+// be started
+func (s *AvailabilityService) release(override *models.AvailabilityOverride) {
+	s.db.Model(override).Update("used_at", nil)
+}
+
+func hashOverrideToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modex/assessment/src/models"
+)
+
+// Submissions whose code couldn't be run are graded again after a delay that
+// doubles with each failure, up to maxGradingBackoff
+const (
+	gradingRetryBackoff = time.Minute
+	maxGradingBackoff   = time.Hour
+	defaultGradingSweep = time.Minute
+	gradingSweepBatch   = 50
+	gradingClaimLease   = 10 * time.Minute
+)
+
+// validateQuestions checks questions before they are saved
+func validateQuestions(questions []models.Question) error {
+	for i := range questions {
+		if err := validateCodeQuestion(&questions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCodeQuestion checks a code question can be graded: it offers
+// supported languages and has test cases to run
+func validateCodeQuestion(question *models.Question) error {
+	if question.Type != models.QuestionTypeCode {
+		return nil
+	}
+
+	spec := question.Code
+	if spec == nil || len(spec.Languages) == 0 {
+		return fmt.Errorf("%w: code questions need at least one language", ErrInvalidQuestion)
+	}
+	for _, language := range spec.Languages {
+		if _, ok := CodeLanguages[language]; !ok {
+			return fmt.Errorf("%w: %s is not a supported language", ErrInvalidQuestion, language)
+		}
+	}
+	if len(spec.TestCases) == 0 {
+		return fmt.Errorf("%w: code questions need at least one test case", ErrInvalidQuestion)
+	}
+	for i, test := range spec.TestCases {
+		if test.Weight < 0 {
+			return fmt.Errorf("%w: test case %d has a negative weight", ErrInvalidQuestion, i)
+		}
+	}
+	return nil
+}
+
+// gradeCodeAnswer runs answer against each of question's test cases and
+// awards the question's points in proportion to the weight of the tests
+// passed. It only fails when the runner does, so the answer can be graded
+// again once it's back.
+func (s *AssessmentService) gradeCodeAnswer(ctx context.Context, answer models.SubmissionAnswer, question models.Question) (float64, []models.CodeTestResult, error) {
+	spec := question.Code
+	if spec == nil || len(spec.TestCases) == 0 {
+		return 0, nil, nil
+	}
+
+	verdict := ""
+	switch {
+	case strings.TrimSpace(answer.TextAnswer) == "":
+		verdict = "No code submitted"
+	case !codeLanguageAllowed(spec, answer.Language):
+		verdict = "Language not allowed"
+	}
+
+	totalWeight, earnedWeight := 0.0, 0.0
+	results := make([]models.CodeTestResult, 0, len(spec.TestCases))
+	for _, test := range spec.TestCases {
+		weight := test.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		totalWeight += weight
+
+		result := models.CodeTestResult{Name: test.Name, Hidden: test.Hidden, Status: verdict}
+		if verdict == "" {
+			run, err := s.runner.Run(ctx, codeRun(answer, test, spec))
+			if err != nil {
+				return 0, nil, err
+			}
+			result.Status = run.Status
+			result.Passed = run.Ran && outputMatches(run.Stdout, test.ExpectedOutput)
+			result.TimeMs = run.TimeMs
+			result.MemoryKB = run.MemoryKB
+			if !test.Hidden {
+				result.Stdout = run.Stdout
+				result.Stderr = run.Stderr
+			}
+			if run.Ran && !result.Passed {
+				result.Status = "Wrong Answer"
+			}
+		}
+		if result.Passed {
+			earnedWeight += weight
+		}
+		results = append(results, result)
+	}
+
+	return question.Points * earnedWeight / totalWeight, results, nil
+}
+
+func codeLanguageAllowed(spec *models.CodeQuestion, language string) bool {
+	for _, allowed := range spec.Languages {
+		if allowed == language {
+			return true
+		}
+	}
+	return false
+}
+
+// outputMatches compares program output ignoring trailing whitespace on each
+// line and trailing blank lines, which students can't see in their output
+func outputMatches(actual, expected string) bool {
+	return normalizeOutput(actual) == normalizeOutput(expected)
+}
+
+func normalizeOutput(output string) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// codeRun builds the sandbox run of answer against one test case
+func codeRun(answer models.SubmissionAnswer, test models.CodeTestCase, spec *models.CodeQuestion) CodeRun {
+	return CodeRun{
+		Language:      answer.Language,
+		Source:        answer.TextAnswer,
+		Stdin:         test.Input,
+		TimeLimit:     time.Duration(spec.TimeLimitMs) * time.Millisecond,
+		MemoryLimitKB: spec.MemoryLimitKB,
+	}
+}
+
+// deferGrading marks submission pending until the code runner can grade it
+// and schedules the next attempt, returning cause
+func (s *AssessmentService) deferGrading(submission *models.Submission, cause error) error {
+	backoff := gradingRetryBackoff
+	for i := 0; i < submission.GradingAttempts && backoff < maxGradingBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxGradingBackoff {
+		backoff = maxGradingBackoff
+	}
+	next := time.Now().UTC().Add(backoff)
+
+	if err := s.db.Model(submission).
+		Where("status IN ?", []models.SubmissionStatus{models.SubmissionStatusSubmitted, models.SubmissionStatusGradingPending}).
+		Updates(map[string]interface{}{
+			"status":           models.SubmissionStatusGradingPending,
+			"grading_attempts": submission.GradingAttempts + 1,
+			"next_grading_at":  next,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to defer grading after %v: %w", cause, err)
+	}
+	return fmt.Errorf("grading deferred until %s: %w", next.Format(time.RFC3339), cause)
+}
+
+// RunGradingSweeper grades submissions left pending by code runner failures
+// every CODE_GRADING_SWEEP_INTERVAL until ctx is cancelled
+func (s *AssessmentService) RunGradingSweeper(ctx context.Context) {
+	ticker := time.NewTicker(durationFromEnv("CODE_GRADING_SWEEP_INTERVAL", defaultGradingSweep))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		graded, err := s.SweepPendingGrading()
+		if err != nil {
+			log.Printf("Failed to sweep pending grading: %v", err)
+		} else if graded > 0 {
+			log.Printf("Graded %d submissions pending code grading", graded)
+		}
+	}
+}
+
+// SweepPendingGrading grades the pending submissions whose retry time has
+// come. Each is claimed first so concurrent replicas don't run it twice.
+func (s *AssessmentService) SweepPendingGrading() (int, error) {
+	now := time.Now().UTC()
+	var due []models.Submission
+	if err := s.db.Select("id", "next_grading_at").
+		Where("status = ? AND next_grading_at <= ?", models.SubmissionStatusGradingPending, now).
+		Order("next_grading_at ASC").Limit(gradingSweepBatch).
+		Find(&due).Error; err != nil {
+		return 0, fmt.Errorf("failed to list pending grading: %w", err)
+	}
+
+	graded := 0
+	for _, submission := range due {
+		claim := s.db.Model(&models.Submission{}).
+			Where("id = ? AND status = ? AND next_grading_at = ?", submission.ID, models.SubmissionStatusGradingPending, submission.NextGradingAt).
+			Update("next_grading_at", now.Add(gradingClaimLease))
+		if claim.Error != nil {
+			return graded, fmt.Errorf("failed to claim submission %s: %w", submission.ID, claim.Error)
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		if err := s.GradeSubmission(submission.ID); err != nil {
+			log.Printf("Grading submission %s failed again: %v", submission.ID, err)
+			continue
+		}
+		graded++
+	}
+	return graded, nil
+}
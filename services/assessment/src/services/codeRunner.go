@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCodeRunnerNotConfigured = errors.New("code runner is not configured")
+	ErrUnsupportedLanguage     = errors.New("unsupported language")
+)
+
+// Resource limits for a single run. Question limits are clamped to the max
+// values so one question can't tie up the runner.
+const (
+	defaultCodeTimeLimit   = 2 * time.Second
+	maxCodeTimeLimit       = 10 * time.Second
+	defaultCodeMemoryLimit = 128 * 1024 // KB
+	maxCodeMemoryLimit     = 512 * 1024 // KB
+)
+
+// CodeLanguages are the languages code questions may offer, by the name
+// stored on questions and answers
+var CodeLanguages = map[string]int{
+	"c":          50, // GCC 9.2.0
+	"cpp":        54, // GCC 9.2.0
+	"go":         60, // 1.13.5
+	"java":       62, // OpenJDK 13.0.1
+	"javascript": 63, // Node.js 12.14.0
+	"python":     71, // 3.8.1
+	"ruby":       72, // 2.7.0
+	"rust":       73, // 1.40.0
+	"typescript": 74, // 3.7.4
+}
+
+// CodeRun is one execution of a program in the sandbox
+type CodeRun struct {
+	Language      string
+	Source        string
+	Stdin         string
+	TimeLimit     time.Duration
+	MemoryLimitKB int
+}
+
+// CodeRunResult is what came out of a CodeRun
+type CodeRunResult struct {
+	Status   string // the runner's verdict
+	Ran      bool   // compiled and exited cleanly within its limits
+	Stdout   string
+	Stderr   string
+	TimeMs   int
+	MemoryKB int
+}
+
+// CodeRunner runs untrusted student code in an isolated sandbox
+type CodeRunner interface {
+	Run(ctx context.Context, run CodeRun) (*CodeRunResult, error)
+}
+
+// judge0Runner runs code on a Judge0 instance, which executes each
+// submission in its own isolate sandbox without network access
+type judge0Runner struct {
+	baseURL   string
+	authToken string
+	client    *http.Client
+}
+
+// NewCodeRunner returns the Judge0 runner at JUDGE0_URL, authenticating with
+// JUDGE0_AUTH_TOKEN when set. Without JUDGE0_URL every run fails with
+// ErrCodeRunnerNotConfigured.
+func NewCodeRunner() CodeRunner {
+	return &judge0Runner{
+		baseURL:   strings.TrimRight(os.Getenv("JUDGE0_URL"), "/"),
+		authToken: os.Getenv("JUDGE0_AUTH_TOKEN"),
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Judge0 status IDs
+const (
+	judge0Accepted    = 3
+	judge0WrongAnswer = 4
+)
+
+func (r *judge0Runner) Run(ctx context.Context, run CodeRun) (*CodeRunResult, error) {
+	if r.baseURL == "" {
+		return nil, ErrCodeRunnerNotConfigured
+	}
+	languageID, ok := CodeLanguages[run.Language]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, run.Language)
+	}
+
+	timeLimit := run.TimeLimit
+	if timeLimit <= 0 {
+		timeLimit = defaultCodeTimeLimit
+	}
+	if timeLimit > maxCodeTimeLimit {
+		timeLimit = maxCodeTimeLimit
+	}
+	memoryLimit := run.MemoryLimitKB
+	if memoryLimit <= 0 {
+		memoryLimit = defaultCodeMemoryLimit
+	}
+	if memoryLimit > maxCodeMemoryLimit {
+		memoryLimit = maxCodeMemoryLimit
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"language_id":     languageID,
+		"source_code":     base64.StdEncoding.EncodeToString([]byte(run.Source)),
+		"stdin":           base64.StdEncoding.EncodeToString([]byte(run.Stdin)),
+		"cpu_time_limit":  timeLimit.Seconds(),
+		"wall_time_limit": (timeLimit * 3).Seconds(),
+		"memory_limit":    memoryLimit,
+		"enable_network":  false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/submissions?base64_encoded=true&wait=true", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build run: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.authToken != "" {
+		req.Header.Set("X-Auth-Token", r.authToken)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("code runner unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("code runner returned %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Stdout        string `json:"stdout"`
+		Stderr        string `json:"stderr"`
+		CompileOutput string `json:"compile_output"`
+		Time          string `json:"time"` // seconds
+		Memory        int    `json:"memory"`
+		Status        struct {
+			ID          int    `json:"id"`
+			Description string `json:"description"`
+		} `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode run: %w", err)
+	}
+
+	result := &CodeRunResult{
+		Status:   out.Status.Description,
+		Ran:      out.Status.ID == judge0Accepted || out.Status.ID == judge0WrongAnswer,
+		Stdout:   decodeJudge0(out.Stdout),
+		Stderr:   decodeJudge0(out.CompileOutput) + decodeJudge0(out.Stderr),
+		MemoryKB: out.Memory,
+	}
+	if seconds, err := strconv.ParseFloat(out.Time, 64); err == nil {
+		result.TimeMs = int(seconds * 1000)
+	}
+	return result, nil
+}
+
+func decodeJudge0(field string) string {
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(field, "\n", ""))
+	if err != nil {
+		return field
+	}
+	return string(data)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Course permissions defined by course-management
+const (
+	CoursePermissionManageContent    = "manage_content"
+	CoursePermissionGradeSubmissions = "grade_submissions"
+)
+
+// CourseClient asks course-management about courses assessments belong to.
+// COURSE_SERVICE_URL is its base URL; calls present INTERNAL_SERVICE_KEY.
+type CourseClient struct {
+	baseURL    string
+	serviceKey string
+	client     *http.Client
+}
+
+func NewCourseClient() *CourseClient {
+	baseURL := os.Getenv("COURSE_SERVICE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:3002"
+	}
+	return &CourseClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		serviceKey: os.Getenv("INTERNAL_SERVICE_KEY"),
+		client:     &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// HasPermission reports whether userID holds permission on courseID, as the
+// course's instructor or a collaborator
+func (c *CourseClient) HasPermission(ctx context.Context, courseID, userID uuid.UUID, permission string) (bool, error) {
+	query := url.Values{"userId": {userID.String()}, "permission": {permission}}
+	endpoint := fmt.Sprintf("%s/api/v1/internal/courses/%s/permissions/check?%s", c.baseURL, courseID, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build permission check: %w", err)
+	}
+	req.Header.Set("X-Service-Key", c.serviceKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("course service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("course service returned %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Allowed bool `json:"allowed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, fmt.Errorf("failed to decode permission check: %w", err)
+	}
+	return body.Allowed, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+)
+
+// Events are published to Redis channels named after the event bus topics,
+// the same way course-management does
+const (
+	assessmentEventsChannel = "assessment-events"
+	eventSubmissionGraded   = "SUBMISSION_GRADED"
+)
+
+// domainEvent mirrors the event bus BaseEvent envelope
+type domainEvent struct {
+	ID            string                 `json:"id"`
+	AggregateID   string                 `json:"aggregateId"`
+	AggregateType string                 `json:"aggregateType"`
+	EventType     string                 `json:"eventType"`
+	Version       int                    `json:"version"`
+	Timestamp     time.Time              `json:"timestamp"`
+	UserID        string                 `json:"userId,omitempty"`
+	Data          interface{}            `json:"data"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func publishEvent(channel, eventType, aggregateType string, aggregateID uuid.UUID, userID string, data interface{}) error {
+	payload, err := json.Marshal(domainEvent{
+		ID:            uuid.New().String(),
+		AggregateID:   aggregateID.String(),
+		AggregateType: aggregateType,
+		EventType:     eventType,
+		Version:       1,
+		Timestamp:     time.Now().UTC(),
+		UserID:        userID,
+		Data:          data,
+		Metadata:      map[string]interface{}{"source": "assessment"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := config.RedisClient.Publish(context.Background(), channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
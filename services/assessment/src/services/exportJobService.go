@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/shared/exports"
+	"github.com/modex/shared/objectstore"
+)
+
+// ErrObjectStoreNotConfigured is returned when no export bucket or credentials are set
+var ErrObjectStoreNotConfigured = exports.ErrNotConfigured
+
+// Export kinds built by this service
+const (
+	ExportKindGradebook = "gradebook"
+)
+
+const exportCleanupInterval = 15 * time.Minute
+
+var (
+	ErrExportNotFound = exports.ErrNotFound
+	ErrExportNotReady = exports.ErrNotReady
+	ErrExportExpired  = exports.ErrExpired
+)
+
+// Exporter writes one kind of export to w and names the file it produced
+type Exporter = exports.Exporter
+
+// ExportJobService builds large exports in the background and hands them out
+// through expiring links to object storage. Files are deleted once
+// EXPORT_RETENTION has passed. The job lifecycle lives in the shared exports
+// package.
+type ExportJobService struct {
+	*exports.Service
+}
+
+func NewExportJobService() *ExportJobService {
+	return &ExportJobService{
+		Service: exports.NewService(
+			config.DB,
+			objectstore.New(os.Getenv("EXPORT_BUCKET")),
+			map[string]Exporter{
+				ExportKindGradebook: exportGradebook,
+			},
+			exports.Options{
+				Retention: durationFromEnv("EXPORT_RETENTION", exports.DefaultRetention),
+				LinkTTL:   durationFromEnv("EXPORT_LINK_TTL", exports.DefaultLinkTTL),
+			},
+		),
+	}
+}
+
+// RunCleaner deletes expired export files and fails stuck jobs every 15
+// minutes until ctx is cancelled
+func (s *ExportJobService) RunCleaner(ctx context.Context) {
+	ticker := time.NewTicker(exportCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		expired, err := s.Cleanup(ctx)
+		if err != nil {
+			log.Printf("Failed to clean up exports: %v", err)
+		} else if expired > 0 {
+			log.Printf("Deleted %d expired exports", expired)
+		}
+	}
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	if raw := os.Getenv(name); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"github.com/modex/shared/exports"
+)
+
+const exportContentTypeCSV = "text/csv"
+
+// WriteGradebookCSV writes a gradebook as one row per student, with a column
+// per assessment and the overall grade last
+func WriteGradebookCSV(w io.Writer, book *Gradebook) error {
+	writer := csv.NewWriter(w)
+	header := []string{"student_id"}
+	for _, col := range book.Columns {
+		header = append(header, col.Title)
+	}
+	header = append(header, "overall")
+	writer.Write(header)
+
+	for _, row := range book.Rows {
+		record := []string{row.StudentID.String()}
+		for _, col := range book.Columns {
+			record = append(record, gradeCell(row.Grades[col.AssessmentID]))
+		}
+		record = append(record, gradeCell(row.Overall))
+		writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// GradebookFileName names a course's gradebook download
+func GradebookFileName(courseID uuid.UUID) string {
+	return fmt.Sprintf("gradebook-%s.csv", courseID)
+}
+
+func gradeCell(grade *models.Grade) string {
+	if grade == nil {
+		return ""
+	}
+	if grade.Scale == models.GradingScalePercentage {
+		return grade.Label
+	}
+	return fmt.Sprintf("%s (%.2f%%)", grade.Label, grade.Percentage)
+}
+
+// exportGradebook builds a course's gradebook CSV for an export job
+func exportGradebook(ctx context.Context, job *models.ExportJob, w io.Writer) (string, string, error) {
+	courseID, err := exports.ParamUUID(job, "courseId")
+	if err != nil {
+		return "", "", err
+	}
+	var orgID *uuid.UUID
+	if _, ok := job.Params["organizationId"]; ok {
+		id, err := exports.ParamUUID(job, "organizationId")
+		if err != nil {
+			return "", "", err
+		}
+		orgID = &id
+	}
+
+	book, err := NewGradingScaleService().Gradebook(courseID, orgID)
+	if err != nil {
+		return "", "", err
+	}
+	if err := WriteGradebookCSV(w, book); err != nil {
+		return "", "", fmt.Errorf("failed to write gradebook: %w", err)
+	}
+	return GradebookFileName(courseID), exportContentTypeCSV, nil
+}
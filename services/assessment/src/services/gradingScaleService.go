@@ -0,0 +1,416 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrGradingScaleNotFound = errors.New("grading scale not found")
+	ErrInvalidGradingScale  = errors.New("invalid grading scale")
+)
+
+func gpa(points float64) *float64 { return &points }
+
+// GradingScalePresets are the built-in scales instructors can start from
+var GradingScalePresets = map[string]models.GradingScale{
+	"letter": {
+		Name: "Letter (A–F)",
+		Type: models.GradingScaleLetter,
+		Bands: []models.GradeBand{
+			{Label: "A", MinPercent: 90, Passing: true},
+			{Label: "B", MinPercent: 80, Passing: true},
+			{Label: "C", MinPercent: 70, Passing: true},
+			{Label: "D", MinPercent: 60, Passing: true},
+			{Label: "F", MinPercent: 0},
+		},
+	},
+	"gpa4": {
+		Name: "GPA (4.0)",
+		Type: models.GradingScaleGPA,
+		Bands: []models.GradeBand{
+			{Label: "A", MinPercent: 93, GPAPoints: gpa(4.0), Passing: true},
+			{Label: "A-", MinPercent: 90, GPAPoints: gpa(3.7), Passing: true},
+			{Label: "B+", MinPercent: 87, GPAPoints: gpa(3.3), Passing: true},
+			{Label: "B", MinPercent: 83, GPAPoints: gpa(3.0), Passing: true},
+			{Label: "B-", MinPercent: 80, GPAPoints: gpa(2.7), Passing: true},
+			{Label: "C+", MinPercent: 77, GPAPoints: gpa(2.3), Passing: true},
+			{Label: "C", MinPercent: 73, GPAPoints: gpa(2.0), Passing: true},
+			{Label: "C-", MinPercent: 70, GPAPoints: gpa(1.7), Passing: true},
+			{Label: "D", MinPercent: 60, GPAPoints: gpa(1.0), Passing: true},
+			{Label: "F", MinPercent: 0, GPAPoints: gpa(0.0)},
+		},
+	},
+	"pass_fail": {
+		Name: "Pass/Fail",
+		Type: models.GradingScalePassFail,
+	},
+	"percentage": {
+		Name: "Percentage",
+		Type: models.GradingScalePercentage,
+	},
+}
+
+// defaultGradingScale applies when neither the course nor its organization
+// has chosen one, matching the old percentage-only presentation
+var defaultGradingScale = models.GradingScale{
+	Name: "Percentage",
+	Type: models.GradingScalePercentage,
+}
+
+// GradebookColumn is an assessment shown in the gradebook
+type GradebookColumn struct {
+	AssessmentID uuid.UUID `json:"assessmentId"`
+	Title        string    `json:"title"`
+	PassingScore float64   `json:"passingScore"`
+}
+
+// GradebookRow is one student's best graded attempt at each assessment
+type GradebookRow struct {
+	StudentID uuid.UUID                   `json:"studentId"`
+	Grades    map[uuid.UUID]*models.Grade `json:"grades"`
+	Overall   *models.Grade               `json:"overall"`
+}
+
+// Gradebook presents a course's graded submissions on its grading scale
+type Gradebook struct {
+	CourseID uuid.UUID           `json:"courseId"`
+	Scale    models.GradingScale `json:"scale"`
+	Columns  []GradebookColumn   `json:"columns"`
+	Rows     []GradebookRow      `json:"rows"`
+}
+
+// GradingScaleService manages course and organization grading scales and
+// presents scores on them
+type GradingScaleService struct {
+	db *gorm.DB
+}
+
+func NewGradingScaleService() *GradingScaleService {
+	return &GradingScaleService{db: config.DB}
+}
+
+// Resolve returns the scale that applies to a course: its own, else its
+// organization's default, else plain percentages
+func (s *GradingScaleService) Resolve(courseID uuid.UUID, orgID *uuid.UUID) (*models.GradingScale, error) {
+	var scale models.GradingScale
+	err := s.db.Where("course_id = ?", courseID).First(&scale).Error
+	if err == nil {
+		return &scale, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get grading scale: %w", err)
+	}
+
+	if orgID != nil {
+		err = s.db.Where("organization_id = ? AND course_id IS NULL", *orgID).First(&scale).Error
+		if err == nil {
+			return &scale, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get grading scale: %w", err)
+		}
+	}
+
+	fallback := defaultGradingScale
+	return &fallback, nil
+}
+
+// GetOrganizationScale returns an organization's default scale
+func (s *GradingScaleService) GetOrganizationScale(orgID uuid.UUID) (*models.GradingScale, error) {
+	var scale models.GradingScale
+	if err := s.db.Where("organization_id = ? AND course_id IS NULL", orgID).First(&scale).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGradingScaleNotFound
+		}
+		return nil, fmt.Errorf("failed to get grading scale: %w", err)
+	}
+	return &scale, nil
+}
+
+// SetCourseScale creates or replaces a course's grading scale
+func (s *GradingScaleService) SetCourseScale(courseID uuid.UUID, scale *models.GradingScale) error {
+	if err := normalizeGradingScale(scale); err != nil {
+		return err
+	}
+	scale.ID = uuid.Nil
+	scale.CourseID = &courseID
+
+	return s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "course_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"organization_id", "name", "type", "bands", "pass_percent", "created_by", "updated_at"}),
+	}).Create(scale).Error
+}
+
+// SetOrganizationScale creates or replaces an organization's default scale
+func (s *GradingScaleService) SetOrganizationScale(orgID uuid.UUID, scale *models.GradingScale) error {
+	if err := normalizeGradingScale(scale); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.GradingScale
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("organization_id = ? AND course_id IS NULL", orgID).First(&existing).Error
+		switch {
+		case err == nil:
+			scale.ID = existing.ID
+			scale.CreatedAt = existing.CreatedAt
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		default:
+			scale.ID = uuid.Nil
+		}
+		scale.OrganizationID = &orgID
+		scale.CourseID = nil
+		return tx.Save(scale).Error
+	})
+}
+
+// DeleteCourseScale removes a course's own scale so it falls back to its
+// organization's default
+func (s *GradingScaleService) DeleteCourseScale(courseID uuid.UUID) error {
+	result := s.db.Where("course_id = ?", courseID).Delete(&models.GradingScale{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete grading scale: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrGradingScaleNotFound
+	}
+	return nil
+}
+
+// GradeSubmissions attaches a grade to each scored submission of an
+// assessment using its course's scale
+func (s *GradingScaleService) GradeSubmissions(assessmentID uuid.UUID, orgID *uuid.UUID, submissions []models.Submission) error {
+	var assessment models.Assessment
+	if err := s.db.Select("id", "course_id", "passing_score").First(&assessment, "id = ?", assessmentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAssessmentNotFound
+		}
+		return fmt.Errorf("failed to get assessment: %w", err)
+	}
+
+	scale, err := s.Resolve(assessment.CourseID, orgID)
+	if err != nil {
+		return err
+	}
+	for i := range submissions {
+		if submissions[i].Score != nil && submissions[i].MaxScore > 0 {
+			submissions[i].Grade = ApplyGradingScale(scale, *submissions[i].Score/submissions[i].MaxScore*100, assessment.PassingScore)
+		}
+	}
+	return nil
+}
+
+// Gradebook returns every student's best graded attempt at each of a course's
+// assessments, plus an overall grade across them
+func (s *GradingScaleService) Gradebook(courseID uuid.UUID, orgID *uuid.UUID) (*Gradebook, error) {
+	scale, err := s.Resolve(courseID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var assessments []models.Assessment
+	if err := s.db.Select("id", "title", "passing_score").
+		Where("course_id = ?", courseID).Order("created_at ASC").
+		Find(&assessments).Error; err != nil {
+		return nil, fmt.Errorf("failed to get assessments: %w", err)
+	}
+
+	book := &Gradebook{CourseID: courseID, Scale: *scale, Columns: []GradebookColumn{}, Rows: []GradebookRow{}}
+	if len(assessments) == 0 {
+		return book, nil
+	}
+
+	ids := make([]uuid.UUID, len(assessments))
+	passing := make(map[uuid.UUID]float64, len(assessments))
+	for i, a := range assessments {
+		ids[i] = a.ID
+		passing[a.ID] = a.PassingScore
+		book.Columns = append(book.Columns, GradebookColumn{AssessmentID: a.ID, Title: a.Title, PassingScore: a.PassingScore})
+	}
+
+	var submissions []models.Submission
+	if err := s.db.Select("student_id", "assessment_id", "score", "max_score").
+		Where("assessment_id IN ? AND status = ? AND score IS NOT NULL AND max_score > 0", ids, models.SubmissionStatusGraded).
+		Find(&submissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get graded submissions: %w", err)
+	}
+
+	type attempt struct{ score, max float64 }
+	best := map[uuid.UUID]map[uuid.UUID]attempt{}
+	for _, sub := range submissions {
+		byAssessment, ok := best[sub.StudentID]
+		if !ok {
+			byAssessment = map[uuid.UUID]attempt{}
+			best[sub.StudentID] = byAssessment
+		}
+		current, seen := byAssessment[sub.AssessmentID]
+		if !seen || *sub.Score/sub.MaxScore > current.score/current.max {
+			byAssessment[sub.AssessmentID] = attempt{score: *sub.Score, max: sub.MaxScore}
+		}
+	}
+
+	// Overall is pass/fail against the strictest assessment's passing score
+	overallPassing := 0.0
+	for _, p := range passing {
+		overallPassing = math.Max(overallPassing, p)
+	}
+
+	for studentID, byAssessment := range best {
+		row := GradebookRow{StudentID: studentID, Grades: map[uuid.UUID]*models.Grade{}}
+		var total, max float64
+		for assessmentID, a := range byAssessment {
+			row.Grades[assessmentID] = ApplyGradingScale(scale, a.score/a.max*100, passing[assessmentID])
+			total += a.score
+			max += a.max
+		}
+		row.Overall = ApplyGradingScale(scale, total/max*100, overallPassing)
+		book.Rows = append(book.Rows, row)
+	}
+	sort.Slice(book.Rows, func(i, j int) bool {
+		return book.Rows[i].StudentID.String() < book.Rows[j].StudentID.String()
+	})
+	return book, nil
+}
+
+// StudentResult is a student's best graded attempt at an assessment
+type StudentResult struct {
+	AssessmentID uuid.UUID `json:"assessmentId"`
+	Percentage   float64   `json:"percentage"`
+	Passed       bool      `json:"passed"`
+}
+
+// BestResults returns the student's best graded attempt at each of
+// assessmentIDs they have attempted, for unlock rules in course-management
+func (s *GradingScaleService) BestResults(studentID uuid.UUID, assessmentIDs []uuid.UUID) ([]StudentResult, error) {
+	results := []StudentResult{}
+	if len(assessmentIDs) == 0 {
+		return results, nil
+	}
+
+	var submissions []models.Submission
+	if err := s.db.Select("assessment_id", "score", "max_score", "passed").
+		Where("student_id = ? AND assessment_id IN ? AND status = ? AND score IS NOT NULL AND max_score > 0",
+			studentID, assessmentIDs, models.SubmissionStatusGraded).
+		Find(&submissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get graded submissions: %w", err)
+	}
+
+	best := map[uuid.UUID]int{}
+	for _, sub := range submissions {
+		result := StudentResult{
+			AssessmentID: sub.AssessmentID,
+			Percentage:   math.Round(*sub.Score/sub.MaxScore*10000) / 100,
+			Passed:       sub.Passed != nil && *sub.Passed,
+		}
+		i, seen := best[sub.AssessmentID]
+		switch {
+		case !seen:
+			best[sub.AssessmentID] = len(results)
+			results = append(results, result)
+		case result.Percentage > results[i].Percentage:
+			result.Passed = result.Passed || results[i].Passed
+			results[i] = result
+		default:
+			results[i].Passed = results[i].Passed || result.Passed
+		}
+	}
+	return results, nil
+}
+
+// ApplyGradingScale presents a percentage on a scale. passingScore is the
+// assessment's own threshold, used by scales that don't set one.
+func ApplyGradingScale(scale *models.GradingScale, percentage, passingScore float64) *models.Grade {
+	percentage = math.Round(percentage*100) / 100
+	grade := &models.Grade{Scale: scale.Type, Percentage: percentage}
+
+	threshold := passingScore
+	if scale.PassPercent > 0 {
+		threshold = scale.PassPercent
+	}
+
+	switch scale.Type {
+	case models.GradingScaleLetter, models.GradingScaleGPA:
+		for _, band := range scale.Bands {
+			if percentage >= band.MinPercent {
+				grade.Label = band.Label
+				grade.GPAPoints = band.GPAPoints
+				grade.Passed = band.Passing
+				return grade
+			}
+		}
+	case models.GradingScalePassFail:
+		grade.Passed = percentage >= threshold
+		grade.Label = "Fail"
+		if grade.Passed {
+			grade.Label = "Pass"
+		}
+		return grade
+	}
+
+	grade.Passed = percentage >= threshold
+	grade.Label = fmt.Sprintf("%.2f%%", percentage)
+	return grade
+}
+
+// normalizeGradingScale validates a scale and orders its bands highest first
+func normalizeGradingScale(scale *models.GradingScale) error {
+	scale.Name = strings.TrimSpace(scale.Name)
+	if scale.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidGradingScale)
+	}
+	if scale.PassPercent < 0 || scale.PassPercent > 100 {
+		return fmt.Errorf("%w: passPercent must be between 0 and 100", ErrInvalidGradingScale)
+	}
+
+	switch scale.Type {
+	case models.GradingScalePercentage, models.GradingScalePassFail:
+		scale.Bands = nil
+		return nil
+	case models.GradingScaleLetter, models.GradingScaleGPA:
+	default:
+		return fmt.Errorf("%w: type must be letter, pass_fail, percentage or gpa", ErrInvalidGradingScale)
+	}
+
+	if len(scale.Bands) == 0 {
+		return fmt.Errorf("%w: %s scales need at least one band", ErrInvalidGradingScale, scale.Type)
+	}
+	sort.Slice(scale.Bands, func(i, j int) bool {
+		return scale.Bands[i].MinPercent > scale.Bands[j].MinPercent
+	})
+
+	labels := map[string]bool{}
+	for i, band := range scale.Bands {
+		band.Label = strings.TrimSpace(band.Label)
+		scale.Bands[i].Label = band.Label
+		switch {
+		case band.Label == "":
+			return fmt.Errorf("%w: every band needs a label", ErrInvalidGradingScale)
+		case labels[band.Label]:
+			return fmt.Errorf("%w: duplicate band %q", ErrInvalidGradingScale, band.Label)
+		case band.MinPercent < 0 || band.MinPercent > 100:
+			return fmt.Errorf("%w: band %q minPercent must be between 0 and 100", ErrInvalidGradingScale, band.Label)
+		case i > 0 && band.MinPercent == scale.Bands[i-1].MinPercent:
+			return fmt.Errorf("%w: bands %q and %q start at the same percentage", ErrInvalidGradingScale, scale.Bands[i-1].Label, band.Label)
+		case scale.Type == models.GradingScaleGPA && band.GPAPoints == nil:
+			return fmt.Errorf("%w: band %q needs gpaPoints", ErrInvalidGradingScale, band.Label)
+		}
+		labels[band.Label] = true
+	}
+
+	if scale.Bands[len(scale.Bands)-1].MinPercent != 0 {
+		return fmt.Errorf("%w: the lowest band must start at 0 so every score gets a grade", ErrInvalidGradingScale)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+// ResolvedEntity is an assessment record found under a looked-up ID, in the
+// shape course-management's support lookup merges with its own
+type ResolvedEntity struct {
+	Service string                 `json:"service"`
+	Type    string                 `json:"type"`
+	ID      uuid.UUID              `json:"id"`
+	Summary map[string]interface{} `json:"summary"`
+	Links   map[string]string      `json:"links,omitempty"`
+	Deleted bool                   `json:"deleted,omitempty"`
+}
+
+type IDResolutionService struct {
+	db *gorm.DB
+}
+
+func NewIDResolutionService() *IDResolutionService {
+	return &IDResolutionService{db: config.DB}
+}
+
+// Resolve returns every assessment record stored under id, soft-deleted ones
+// included
+func (s *IDResolutionService) Resolve(id uuid.UUID) ([]ResolvedEntity, error) {
+	matches := []ResolvedEntity{}
+
+	var assessment models.Assessment
+	if found, err := s.find(&assessment, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("assessment", id, assessment.DeletedAt.Valid, map[string]interface{}{
+			"title":     assessment.Title,
+			"courseId":  assessment.CourseID,
+			"status":    assessment.Status,
+			"createdBy": assessment.CreatedBy,
+		}, map[string]string{"self": "/api/v1/assessments/" + id.String()}))
+	}
+
+	var question models.Question
+	if found, err := s.find(&question, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("question", id, question.DeletedAt.Valid, map[string]interface{}{
+			"assessmentId": question.AssessmentID,
+			"type":         question.Type,
+			"orderIndex":   question.OrderIndex,
+		}, map[string]string{"assessment": "/api/v1/assessments/" + question.AssessmentID.String()}))
+	}
+
+	var option models.QuestionOption
+	if found, err := s.find(&option, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("question_option", id, false, map[string]interface{}{
+			"questionId": option.QuestionID,
+			"isCorrect":  option.IsCorrect,
+		}, nil))
+	}
+
+	var submission models.Submission
+	if found, err := s.find(&submission, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("submission", id, false, map[string]interface{}{
+			"assessmentId":  submission.AssessmentID,
+			"studentId":     submission.StudentID,
+			"attemptNumber": submission.AttemptNumber,
+			"status":        submission.Status,
+			"score":         submission.Score,
+		}, map[string]string{
+			"self":       "/api/v1/assessments/submissions/" + id.String(),
+			"assessment": "/api/v1/assessments/" + submission.AssessmentID.String(),
+		}))
+	}
+
+	var answer models.SubmissionAnswer
+	if found, err := s.find(&answer, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("submission_answer", id, false, map[string]interface{}{
+			"submissionId": answer.SubmissionID,
+			"questionId":   answer.QuestionID,
+		}, map[string]string{"submission": "/api/v1/assessments/submissions/" + answer.SubmissionID.String()}))
+	}
+
+	var bank models.QuestionBank
+	if found, err := s.find(&bank, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("question_bank", id, bank.DeletedAt.Valid, map[string]interface{}{
+			"name":           bank.Name,
+			"organizationId": bank.OrganizationID,
+			"createdBy":      bank.CreatedBy,
+		}, map[string]string{"self": "/api/v1/question-banks/" + id.String()}))
+	}
+
+	var bankQuestion models.BankQuestion
+	if found, err := s.find(&bankQuestion, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("bank_question", id, bankQuestion.DeletedAt.Valid, map[string]interface{}{
+			"bankId": bankQuestion.BankID,
+			"type":   bankQuestion.Type,
+		}, map[string]string{"bank": "/api/v1/question-banks/" + bankQuestion.BankID.String()}))
+	}
+
+	var scale models.GradingScale
+	if found, err := s.find(&scale, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("grading_scale", id, false, map[string]interface{}{
+			"name":           scale.Name,
+			"type":           scale.Type,
+			"courseId":       scale.CourseID,
+			"organizationId": scale.OrganizationID,
+		}, nil))
+	}
+
+	var job models.ExportJob
+	if found, err := s.find(&job, id); err != nil {
+		return nil, err
+	} else if found {
+		matches = append(matches, resolved("export_job", id, false, map[string]interface{}{
+			"kind":        job.Kind,
+			"status":      job.Status,
+			"requestedBy": job.RequestedBy,
+		}, map[string]string{"self": "/api/v1/exports/" + id.String()}))
+	}
+
+	return matches, nil
+}
+
+func (s *IDResolutionService) find(dest interface{}, id uuid.UUID) (bool, error) {
+	err := s.db.Unscoped().Where("id = ?", id).Take(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve ID: %w", err)
+	}
+	return true, nil
+}
+
+func resolved(entityType string, id uuid.UUID, deleted bool, summary map[string]interface{}, links map[string]string) ResolvedEntity {
+	return ResolvedEntity{
+		Service: "assessment",
+		Type:    entityType,
+		ID:      id,
+		Summary: summary,
+		Links:   links,
+		Deleted: deleted,
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+// Erasure requests are announced by course-management on the user-events topic
+const (
+	userEventsChannel         = "user-events"
+	eventUserErasureRequested = "USER_ERASURE_REQUESTED"
+)
+
+// UserDataExport holds the assessment data tied to a user
+type UserDataExport struct {
+	UserID             uuid.UUID           `json:"userId"`
+	GeneratedAt        time.Time           `json:"generatedAt"`
+	AssessmentsCreated []models.Assessment `json:"assessmentsCreated"`
+	Submissions        []models.Submission `json:"submissions"`
+}
+
+// UserErasureResult summarises what erasure changed in the assessment service
+type UserErasureResult struct {
+	DeletedSubmissions    int64 `json:"deletedSubmissions"`
+	AnonymizedAssessments int64 `json:"anonymizedAssessments"`
+}
+
+type PrivacyService struct {
+	db    *gorm.DB
+	cache *CacheService
+}
+
+func NewPrivacyService() *PrivacyService {
+	return &PrivacyService{
+		db:    config.DB,
+		cache: NewCacheService(),
+	}
+}
+
+// ExportUserData returns the assessments a user authored and the submissions they made
+func (s *PrivacyService) ExportUserData(userID uuid.UUID) (*UserDataExport, error) {
+	export := &UserDataExport{UserID: userID, GeneratedAt: time.Now().UTC()}
+
+	if err := s.db.Preload("Questions.Options").
+		Where("created_by = ?", userID).
+		Find(&export.AssessmentsCreated).Error; err != nil {
+		return nil, fmt.Errorf("failed to export assessments: %w", err)
+	}
+
+	if err := s.db.Preload("Answers").
+		Where("student_id = ?", userID).
+		Order("created_at ASC").
+		Find(&export.Submissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to export submissions: %w", err)
+	}
+
+	return export, nil
+}
+
+// EraseUserData deletes a student's submissions and detaches authored
+// assessments from the user, leaving them in place for the course.
+func (s *PrivacyService) EraseUserData(userID uuid.UUID) (*UserErasureResult, error) {
+	result := &UserErasureResult{}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		submissionIDs := tx.Model(&models.Submission{}).Select("id").Where("student_id = ?", userID)
+		if err := tx.Where("submission_id IN (?)", submissionIDs).Delete(&models.SubmissionAnswer{}).Error; err != nil {
+			return fmt.Errorf("failed to delete submission answers: %w", err)
+		}
+
+		deleted := tx.Where("student_id = ?", userID).Delete(&models.Submission{})
+		if deleted.Error != nil {
+			return fmt.Errorf("failed to delete submissions: %w", deleted.Error)
+		}
+		result.DeletedSubmissions = deleted.RowsAffected
+
+		anonymized := tx.Model(&models.Assessment{}).Where("created_by = ?", userID).Update("created_by", uuid.Nil)
+		if anonymized.Error != nil {
+			return fmt.Errorf("failed to anonymize assessments: %w", anonymized.Error)
+		}
+		result.AnonymizedAssessments = anonymized.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.DeletePattern("assessment:*")
+	return result, nil
+}
+
+// ListenForErasures erases user data whenever USER_ERASURE_REQUESTED is
+// published, until ctx is cancelled. Pub/sub drops messages while nobody is
+// listening, so this is only a backstop: course-management erases through
+// the internal privacy endpoint and waits for it to succeed.
+func (s *PrivacyService) ListenForErasures(ctx context.Context) {
+	pubsub := config.RedisClient.Subscribe(ctx, userEventsChannel)
+	defer pubsub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-pubsub.Channel():
+			if !ok {
+				return
+			}
+
+			var event struct {
+				EventType   string `json:"eventType"`
+				AggregateID string `json:"aggregateId"`
+			}
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil || event.EventType != eventUserErasureRequested {
+				continue
+			}
+
+			userID, err := uuid.Parse(event.AggregateID)
+			if err != nil {
+				log.Printf("Ignoring erasure request with invalid user ID %q", event.AggregateID)
+				continue
+			}
+
+			result, err := s.EraseUserData(userID)
+			if err != nil {
+				log.Printf("Failed to erase assessment data for user %s: %v", userID, err)
+				continue
+			}
+			log.Printf("Erased assessment data for user %s: %d submissions deleted, %d assessments anonymized",
+				userID, result.DeletedSubmissions, result.AnonymizedAssessments)
+		}
+	}
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBankNotFound       = errors.New("question bank not found")
+	ErrBankAccessDenied   = errors.New("question bank belongs to another organization")
+	ErrListingNotFound    = errors.New("listing not found")
+	ErrAlreadyListed      = errors.New("question bank is already listed")
+	ErrLicenseNotAccepted = errors.New("license must be accepted to import")
+	ErrOwnListing         = errors.New("cannot import your own organization's bank")
+	ErrImportNotFound     = errors.New("import not found")
+	ErrQuestionNotInBank  = errors.New("question not found in bank")
+	ErrAssessmentNotFound = errors.New("assessment not found")
+)
+
+var validLicenses = map[models.LicenseType]bool{
+	models.LicenseCCBY:          true,
+	models.LicenseCCBYSA:        true,
+	models.LicenseCCBYNC:        true,
+	models.LicenseInstitutional: true,
+	models.LicenseProprietary:   true,
+}
+
+// BankAccess describes how an organization can see a bank
+type BankAccess struct {
+	Bank    *models.QuestionBank `json:"bank"`
+	Owned   bool                 `json:"owned"`
+	Import  *models.BankImport   `json:"import,omitempty"`
+	Listing *models.BankListing  `json:"listing,omitempty"`
+}
+
+// RevocationResult summarises the effect of revoking a listing
+type RevocationResult struct {
+	RevokedImports    int64 `json:"revokedImports"`
+	RemovedQuestions  int64 `json:"removedQuestions"`  // copies removed from draft assessments
+	RetainedQuestions int64 `json:"retainedQuestions"` // copies kept in published assessments
+}
+
+// BankUsageSummary aggregates usage of a bank by one organization
+type BankUsageSummary struct {
+	OrganizationID uuid.UUID  `json:"organizationId"`
+	Questions      int64      `json:"questions"`
+	Assessments    int64      `json:"assessments"`
+	LastUsedAt     *time.Time `json:"lastUsedAt"`
+}
+
+type QuestionBankService struct {
+	db    *gorm.DB
+	cache *CacheService
+}
+
+func NewQuestionBankService() *QuestionBankService {
+	return &QuestionBankService{
+		db:    config.DB,
+		cache: NewCacheService(),
+	}
+}
+
+// Bank Operations
+func (s *QuestionBankService) CreateBank(bank *models.QuestionBank) error {
+	if err := s.db.Create(bank).Error; err != nil {
+		return fmt.Errorf("failed to create question bank: %w", err)
+	}
+	return nil
+}
+
+// ListBanks returns the banks an organization owns and the banks it has imported
+func (s *QuestionBankService) ListBanks(orgID uuid.UUID) ([]models.QuestionBank, []models.BankImport, error) {
+	var owned []models.QuestionBank
+	if err := s.db.Where("organization_id = ?", orgID).Order("created_at DESC").Find(&owned).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to list question banks: %w", err)
+	}
+
+	var imports []models.BankImport
+	if err := s.db.Where("organization_id = ? AND status = ?", orgID, models.ImportStatusActive).
+		Order("created_at DESC").Find(&imports).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to list imported banks: %w", err)
+	}
+
+	return owned, imports, nil
+}
+
+// GetBank loads a bank with its questions if orgID owns it or holds an
+// active import of it.
+func (s *QuestionBankService) GetBank(bankID, orgID uuid.UUID) (*BankAccess, error) {
+	var bank models.QuestionBank
+	if err := s.db.Preload("Questions").First(&bank, "id = ?", bankID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBankNotFound
+		}
+		return nil, fmt.Errorf("failed to get question bank: %w", err)
+	}
+
+	if bank.OrganizationID == orgID {
+		return &BankAccess{Bank: &bank, Owned: true}, nil
+	}
+
+	var imp models.BankImport
+	err := s.db.Where("bank_id = ? AND organization_id = ? AND status = ?", bankID, orgID, models.ImportStatusActive).
+		First(&imp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrBankAccessDenied
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bank import: %w", err)
+	}
+
+	var listing models.BankListing
+	if err := s.db.First(&listing, "id = ?", imp.ListingID).Error; err != nil {
+		return nil, fmt.Errorf("failed to get listing: %w", err)
+	}
+
+	return &BankAccess{Bank: &bank, Import: &imp, Listing: &listing}, nil
+}
+
+// AddQuestion adds a question to a bank owned by orgID
+func (s *QuestionBankService) AddQuestion(bankID, orgID uuid.UUID, question *models.BankQuestion) error {
+	if err := s.requireOwner(bankID, orgID); err != nil {
+		return err
+	}
+
+	question.ID = uuid.Nil
+	question.BankID = bankID
+	if err := s.db.Create(question).Error; err != nil {
+		return fmt.Errorf("failed to add bank question: %w", err)
+	}
+	return nil
+}
+
+// Marketplace Operations
+
+// PublishBank lists a bank in the marketplace. A previously revoked listing is
+// reactivated with the new terms; organizations whose imports were revoked
+// must import it again.
+func (s *QuestionBankService) PublishBank(bankID, orgID uuid.UUID, listing *models.BankListing) error {
+	if err := ValidateListing(listing); err != nil {
+		return err
+	}
+	if err := s.requireOwner(bankID, orgID); err != nil {
+		return err
+	}
+
+	var existing models.BankListing
+	err := s.db.Where("bank_id = ?", bankID).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to check existing listing: %w", err)
+	}
+	if err == nil && existing.Status == models.ListingStatusActive {
+		return ErrAlreadyListed
+	}
+
+	listing.BankID = bankID
+	listing.PublisherOrgID = orgID
+	listing.Status = models.ListingStatusActive
+	listing.PublishedAt = time.Now().UTC()
+	listing.RevokedAt = nil
+	listing.RevocationReason = ""
+	if err == nil {
+		listing.ID = existing.ID
+		listing.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.db.Save(listing).Error; err != nil {
+		return fmt.Errorf("failed to publish question bank: %w", err)
+	}
+	return nil
+}
+
+// ValidateListing checks a listing's license terms before publishing
+func ValidateListing(listing *models.BankListing) error {
+	if !validLicenses[listing.License] {
+		return fmt.Errorf("unsupported license %q", listing.License)
+	}
+	if listing.License == models.LicenseProprietary && listing.LicenseTerms == "" {
+		return fmt.Errorf("licenseTerms are required for a proprietary license")
+	}
+	return nil
+}
+
+// ListMarketplace returns active listings, optionally narrowed to one license
+func (s *QuestionBankService) ListMarketplace(license models.LicenseType) ([]models.BankListing, error) {
+	query := s.db.Where("status = ?", models.ListingStatusActive)
+	if license != "" {
+		query = query.Where("license = ?", license)
+	}
+
+	var listings []models.BankListing
+	if err := query.Order("published_at DESC").Find(&listings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list marketplace: %w", err)
+	}
+	return listings, nil
+}
+
+// ImportListing gives orgID access to a listed bank. The caller must echo the
+// listing's license to show the terms were accepted.
+func (s *QuestionBankService) ImportListing(listingID, orgID, userID uuid.UUID, acceptedLicense models.LicenseType) (*models.BankImport, error) {
+	var listing models.BankListing
+	if err := s.db.Where("id = ? AND status = ?", listingID, models.ListingStatusActive).First(&listing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrListingNotFound
+		}
+		return nil, fmt.Errorf("failed to get listing: %w", err)
+	}
+	if listing.PublisherOrgID == orgID {
+		return nil, ErrOwnListing
+	}
+	if acceptedLicense != listing.License {
+		return nil, ErrLicenseNotAccepted
+	}
+
+	var imp models.BankImport
+	err := s.db.Where("listing_id = ? AND organization_id = ?", listingID, orgID).First(&imp).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing import: %w", err)
+	}
+
+	imp.ListingID = listing.ID
+	imp.BankID = listing.BankID
+	imp.OrganizationID = orgID
+	imp.ImportedBy = userID
+	imp.AcceptedLicense = acceptedLicense
+	imp.Status = models.ImportStatusActive
+	imp.RevokedAt = nil
+
+	if err := s.db.Save(&imp).Error; err != nil {
+		return nil, fmt.Errorf("failed to import question bank: %w", err)
+	}
+	return &imp, nil
+}
+
+// RemoveImport drops an organization's import. Questions already copied into
+// assessments are kept.
+func (s *QuestionBankService) RemoveImport(importID, orgID uuid.UUID) error {
+	result := s.db.Model(&models.BankImport{}).
+		Where("id = ? AND organization_id = ? AND status = ?", importID, orgID, models.ImportStatusActive).
+		Update("status", models.ImportStatusRemoved)
+	if result.Error != nil {
+		return fmt.Errorf("failed to remove import: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrImportNotFound
+	}
+	return nil
+}
+
+// RevokeListing withdraws a bank from the marketplace and revokes every
+// import. Copies of its questions are removed from importers' draft
+// assessments; published assessments keep them, since students may already
+// have answered them.
+func (s *QuestionBankService) RevokeListing(bankID, orgID uuid.UUID, reason string) (*RevocationResult, error) {
+	if err := s.requireOwner(bankID, orgID); err != nil {
+		return nil, err
+	}
+
+	result := &RevocationResult{}
+	var affectedAssessments []uuid.UUID
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		revoked := tx.Model(&models.BankListing{}).
+			Where("bank_id = ? AND status = ?", bankID, models.ListingStatusActive).
+			Updates(map[string]interface{}{
+				"status":            models.ListingStatusRevoked,
+				"revoked_at":        now,
+				"revocation_reason": reason,
+			})
+		if revoked.Error != nil {
+			return fmt.Errorf("failed to revoke listing: %w", revoked.Error)
+		}
+		if revoked.RowsAffected == 0 {
+			return ErrListingNotFound
+		}
+
+		imports := tx.Model(&models.BankImport{}).
+			Where("bank_id = ? AND status = ?", bankID, models.ImportStatusActive).
+			Updates(map[string]interface{}{"status": models.ImportStatusRevoked, "revoked_at": now})
+		if imports.Error != nil {
+			return fmt.Errorf("failed to revoke imports: %w", imports.Error)
+		}
+		result.RevokedImports = imports.RowsAffected
+
+		var usages []models.BankQuestionUsage
+		if err := tx.Where("bank_id = ? AND import_id IS NOT NULL", bankID).Find(&usages).Error; err != nil {
+			return fmt.Errorf("failed to load bank usage: %w", err)
+		}
+		if len(usages) == 0 {
+			return nil
+		}
+
+		questionIDs := make([]uuid.UUID, len(usages))
+		for i, usage := range usages {
+			questionIDs[i] = usage.QuestionID
+		}
+
+		draftAssessments := tx.Model(&models.Assessment{}).Select("id").Where("status = ?", models.AssessmentStatusDraft)
+		var removable []models.Question
+		if err := tx.Where("id IN ? AND assessment_id IN (?)", questionIDs, draftAssessments).Find(&removable).Error; err != nil {
+			return fmt.Errorf("failed to find copied questions: %w", err)
+		}
+
+		if len(removable) > 0 {
+			removableIDs := make([]uuid.UUID, len(removable))
+			for i, q := range removable {
+				removableIDs[i] = q.ID
+				affectedAssessments = append(affectedAssessments, q.AssessmentID)
+			}
+			if err := tx.Delete(&models.Question{}, removableIDs).Error; err != nil {
+				return fmt.Errorf("failed to remove copied questions: %w", err)
+			}
+		}
+
+		var remaining int64
+		if err := tx.Model(&models.Question{}).Where("id IN ?", questionIDs).Count(&remaining).Error; err != nil {
+			return fmt.Errorf("failed to count retained questions: %w", err)
+		}
+		result.RemovedQuestions = int64(len(removable))
+		result.RetainedQuestions = remaining
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range affectedAssessments {
+		s.cache.Delete(fmt.Sprintf("assessment:%s", id))
+	}
+	log.Printf("Revoked listing for question bank %s: %d imports revoked, %d questions removed, %d retained",
+		bankID, result.RevokedImports, result.RemovedQuestions, result.RetainedQuestions)
+
+	return result, nil
+}
+
+// Usage Operations
+
+// UseQuestion copies a bank question into an assessment and records the usage
+// against the owning bank, so publishers can see where their items are used.
+func (s *QuestionBankService) UseQuestion(bankID, bankQuestionID, orgID, userID, assessmentID uuid.UUID) (*models.Question, error) {
+	access, err := s.GetBank(bankID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var source *models.BankQuestion
+	for i := range access.Bank.Questions {
+		if access.Bank.Questions[i].ID == bankQuestionID {
+			source = &access.Bank.Questions[i]
+			break
+		}
+	}
+	if source == nil {
+		return nil, ErrQuestionNotInBank
+	}
+
+	question := &models.Question{
+		AssessmentID:         assessmentID,
+		Type:                 source.Type,
+		Question:             source.Question,
+		Explanation:          source.Explanation,
+		Points:               source.Points,
+		MediaURL:             source.MediaURL,
+		Required:             true,
+		SourceBankQuestionID: &source.ID,
+		Code:                 source.Code,
+	}
+	for i, option := range source.Options {
+		question.Options = append(question.Options, models.QuestionOption{
+			Text:       option.Text,
+			IsCorrect:  option.IsCorrect,
+			OrderIndex: i,
+		})
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var assessment models.Assessment
+		if err := tx.Select("id").First(&assessment, "id = ?", assessmentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAssessmentNotFound
+			}
+			return fmt.Errorf("failed to get assessment: %w", err)
+		}
+
+		var next int
+		if err := tx.Model(&models.Question{}).Where("assessment_id = ?", assessmentID).
+			Select("COALESCE(MAX(order_index), -1) + 1").Scan(&next).Error; err != nil {
+			return fmt.Errorf("failed to get question order: %w", err)
+		}
+		question.OrderIndex = next
+
+		if err := tx.Create(question).Error; err != nil {
+			return fmt.Errorf("failed to copy bank question: %w", err)
+		}
+
+		usage := models.BankQuestionUsage{
+			BankID:         bankID,
+			BankQuestionID: source.ID,
+			OrganizationID: orgID,
+			AssessmentID:   assessmentID,
+			QuestionID:     question.ID,
+			UsedBy:         userID,
+		}
+		if access.Import != nil {
+			usage.ImportID = &access.Import.ID
+		}
+		if err := tx.Create(&usage).Error; err != nil {
+			return fmt.Errorf("failed to record bank usage: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Delete(fmt.Sprintf("assessment:%s", assessmentID))
+	return question, nil
+}
+
+// GetUsage summarises, per organization, how often a bank's questions have
+// been used. Only the owning organization may view it.
+func (s *QuestionBankService) GetUsage(bankID, orgID uuid.UUID) ([]BankUsageSummary, error) {
+	if err := s.requireOwner(bankID, orgID); err != nil {
+		return nil, err
+	}
+
+	var summary []BankUsageSummary
+	err := s.db.Model(&models.BankQuestionUsage{}).
+		Select("organization_id, COUNT(*) AS questions, COUNT(DISTINCT assessment_id) AS assessments, MAX(created_at) AS last_used_at").
+		Where("bank_id = ?", bankID).
+		Group("organization_id").
+		Order("questions DESC").
+		Scan(&summary).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank usage: %w", err)
+	}
+	return summary, nil
+}
+
+func (s *QuestionBankService) requireOwner(bankID, orgID uuid.UUID) error {
+	var bank models.QuestionBank
+	if err := s.db.Select("id", "organization_id").First(&bank, "id = ?", bankID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBankNotFound
+		}
+		return fmt.Errorf("failed to get question bank: %w", err)
+	}
+	if bank.OrganizationID != orgID {
+		return ErrBankAccessDenied
+	}
+	return nil
+}
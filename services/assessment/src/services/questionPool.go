@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPool  = errors.New("invalid question pool")
+	ErrPoolTooSmall = errors.New("question pool has fewer matching questions than it draws")
+)
+
+// GetPools lists an assessment's question pools in draw order
+func (s *QuestionBankService) GetPools(assessmentID uuid.UUID) ([]models.AssessmentPool, error) {
+	var pools []models.AssessmentPool
+	if err := s.db.Where("assessment_id = ?", assessmentID).Order("order_index ASC").Find(&pools).Error; err != nil {
+		return nil, fmt.Errorf("failed to get question pools: %w", err)
+	}
+	return pools, nil
+}
+
+// SetPools replaces an assessment's question pools. orgID must be able to
+// read every bank drawn from, and each bank must hold enough matching
+// questions for its draw. Copies drawn by the old pools stay with the attempts
+// that drew them.
+func (s *QuestionBankService) SetPools(assessmentID, orgID, userID uuid.UUID, pools []models.AssessmentPool) ([]models.AssessmentPool, error) {
+	for i := range pools {
+		pool := &pools[i]
+		if pool.Count < 1 || pool.Points < 0 {
+			return nil, fmt.Errorf("%w: pool %d must draw at least one question with non-negative points", ErrInvalidPool, i)
+		}
+
+		access, err := s.GetBank(pool.BankID, orgID)
+		if err != nil {
+			return nil, err
+		}
+		if available := len(poolCandidates(access.Bank.Questions, pool.Tags)); available < pool.Count {
+			return nil, fmt.Errorf("%w: pool %d draws %d but bank %s has %d matching questions", ErrPoolTooSmall, i, pool.Count, pool.BankID, available)
+		}
+
+		pool.ID = uuid.Nil
+		pool.AssessmentID = assessmentID
+		pool.OrganizationID = orgID
+		pool.CreatedBy = userID
+		pool.OrderIndex = i
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var assessment models.Assessment
+		if err := tx.Select("id").First(&assessment, "id = ?", assessmentID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAssessmentNotFound
+			}
+			return fmt.Errorf("failed to get assessment: %w", err)
+		}
+
+		if err := tx.Where("assessment_id = ?", assessmentID).Delete(&models.AssessmentPool{}).Error; err != nil {
+			return fmt.Errorf("failed to clear question pools: %w", err)
+		}
+		if len(pools) == 0 {
+			return nil
+		}
+		if err := tx.Create(&pools).Error; err != nil {
+			return fmt.Errorf("failed to create question pools: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+// drawPools draws every pool of assessmentID and returns the drawn questions'
+// IDs in pool order. A bank question is copied into the assessment the first
+// time its pool draws it, and that copy is reused by later attempts.
+func (s *QuestionBankService) drawPools(assessmentID uuid.UUID) ([]uuid.UUID, error) {
+	pools, err := s.GetPools(assessmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var drawn []uuid.UUID
+	copied := false
+	for _, pool := range pools {
+		access, err := s.GetBank(pool.BankID, pool.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to draw pool %s: %w", pool.ID, err)
+		}
+
+		candidates := poolCandidates(access.Bank.Questions, pool.Tags)
+		if len(candidates) < pool.Count {
+			return nil, fmt.Errorf("%w: pool %s", ErrPoolTooSmall, pool.ID)
+		}
+
+		for _, i := range rand.Perm(len(candidates))[:pool.Count] {
+			id, created, err := s.poolCopy(pool, access, candidates[i])
+			if err != nil {
+				return nil, err
+			}
+			drawn = append(drawn, id)
+			copied = copied || created
+		}
+	}
+
+	if copied {
+		s.cache.Delete(fmt.Sprintf("assessment:%s", assessmentID))
+	}
+	return drawn, nil
+}
+
+// poolCopy returns the assessment's copy of source drawn by pool, creating
+// it and recording the bank usage if this is its first draw
+func (s *QuestionBankService) poolCopy(pool models.AssessmentPool, access *BankAccess, source models.BankQuestion) (uuid.UUID, bool, error) {
+	var existing models.Question
+	err := s.db.Select("id").
+		Where("assessment_id = ? AND pool_id = ? AND source_bank_question_id = ?", pool.AssessmentID, pool.ID, source.ID).
+		Order("created_at ASC").First(&existing).Error
+	if err == nil {
+		return existing.ID, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, false, fmt.Errorf("failed to get pool question: %w", err)
+	}
+
+	points := source.Points
+	if pool.Points > 0 {
+		points = pool.Points
+	}
+	question := &models.Question{
+		AssessmentID:         pool.AssessmentID,
+		Type:                 source.Type,
+		Question:             source.Question,
+		Explanation:          source.Explanation,
+		Points:               points,
+		MediaURL:             source.MediaURL,
+		Required:             true,
+		SourceBankQuestionID: &source.ID,
+		PoolID:               &pool.ID,
+		Code:                 source.Code,
+	}
+	for i, option := range source.Options {
+		question.Options = append(question.Options, models.QuestionOption{
+			Text:       option.Text,
+			IsCorrect:  option.IsCorrect,
+			OrderIndex: i,
+		})
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var next int
+		if err := tx.Model(&models.Question{}).Where("assessment_id = ?", pool.AssessmentID).
+			Select("COALESCE(MAX(order_index), -1) + 1").Scan(&next).Error; err != nil {
+			return fmt.Errorf("failed to get question order: %w", err)
+		}
+		question.OrderIndex = next
+
+		if err := tx.Create(question).Error; err != nil {
+			return fmt.Errorf("failed to copy pool question: %w", err)
+		}
+
+		usage := models.BankQuestionUsage{
+			BankID:         pool.BankID,
+			BankQuestionID: source.ID,
+			OrganizationID: pool.OrganizationID,
+			AssessmentID:   pool.AssessmentID,
+			QuestionID:     question.ID,
+			UsedBy:         pool.CreatedBy,
+		}
+		if access.Import != nil {
+			usage.ImportID = &access.Import.ID
+		}
+		if err := tx.Create(&usage).Error; err != nil {
+			return fmt.Errorf("failed to record bank usage: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	return question.ID, true, nil
+}
+
+// poolCandidates filters a bank's questions to those carrying any of tags,
+// or returns them all when tags is empty
+func poolCandidates(questions []models.BankQuestion, tags []string) []models.BankQuestion {
+	if len(tags) == 0 {
+		return questions
+	}
+
+	wanted := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		wanted[tag] = true
+	}
+
+	var matching []models.BankQuestion
+	for _, question := range questions {
+		for _, tag := range question.Tags {
+			if wanted[tag] {
+				matching = append(matching, question)
+				break
+			}
+		}
+	}
+	return matching
+}
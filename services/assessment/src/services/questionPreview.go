@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/models"
+	"github.com/modex/shared/sanitize"
+)
+
+var ErrInvalidQuestion = errors.New("invalid question")
+
+// defaultQuestionEmbedHosts are the video players question media may embed
+// when QUESTION_EMBED_HOSTS is unset
+var defaultQuestionEmbedHosts = []string{
+	"www.youtube-nocookie.com",
+	"www.youtube.com",
+	"player.vimeo.com",
+	"fast.wistia.net",
+}
+
+// Media kinds in a question preview
+const (
+	MediaImage = "image"
+	MediaVideo = "video"
+	MediaAudio = "audio"
+	MediaEmbed = "embed" // an allowed video player, shown in an iframe
+	MediaLink  = "link"  // anything else, shown as a link
+)
+
+var mediaExtensions = map[string]string{
+	".png": MediaImage, ".jpg": MediaImage, ".jpeg": MediaImage, ".gif": MediaImage, ".webp": MediaImage, ".svg": MediaImage,
+	".mp4": MediaVideo, ".webm": MediaVideo, ".mov": MediaVideo, ".m3u8": MediaVideo,
+	".mp3": MediaAudio, ".wav": MediaAudio, ".ogg": MediaAudio, ".m4a": MediaAudio,
+}
+
+// QuestionPreview is a question as a student will see it: sanitized text,
+// options in presentation order without their answers, and resolved media
+type QuestionPreview struct {
+	Type     models.QuestionType `json:"type"`
+	Question string              `json:"question"` // sanitized HTML
+	Points   float64             `json:"points"`
+	Required bool                `json:"required"`
+	Input    string              `json:"input"` // single_choice, multiple_choice, short_text or long_text
+	Media    *PreviewMedia       `json:"media,omitempty"`
+	Options  []PreviewOption     `json:"options,omitempty"`
+	Seed     int64               `json:"seed"`               // reproduces this option order
+	Warnings []string            `json:"warnings,omitempty"` // problems a student would notice or grading would trip on
+}
+
+type PreviewMedia struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+type PreviewOption struct {
+	ID   uuid.UUID `json:"id"`
+	Text string    `json:"text"` // sanitized HTML
+}
+
+// QuestionPreviewService renders unsaved questions the way the assessment
+// player will, so authoring tools can show an exact preview
+type QuestionPreviewService struct {
+	policy sanitize.Policy
+}
+
+// NewQuestionPreviewService creates a QuestionPreviewService. QUESTION_EMBED_HOSTS
+// is a comma-separated iframe host allowlist; external images go through the
+// content-delivery image proxy at CONTENT_DELIVERY_URL when it is set.
+func NewQuestionPreviewService() *QuestionPreviewService {
+	hosts := defaultQuestionEmbedHosts
+	if raw := os.Getenv("QUESTION_EMBED_HOSTS"); raw != "" {
+		hosts = strings.Split(raw, ",")
+	}
+
+	policy := sanitize.Policy{EmbedHosts: make(map[string]bool, len(hosts))}
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			policy.EmbedHosts[host] = true
+		}
+	}
+	if base := strings.TrimRight(os.Getenv("CONTENT_DELIVERY_URL"), "/"); base != "" {
+		policy.ImageProxyURL = base + "/proxy/image?url="
+	}
+
+	return &QuestionPreviewService{policy: policy}
+}
+
+// Preview renders question for a student. When shuffle is set the options
+// are shuffled with seed, or a random seed when it is nil, mirroring an
+// assessment with RandomizeOptions.
+func (s *QuestionPreviewService) Preview(question *models.Question, shuffle bool, seed *int64) (*QuestionPreview, error) {
+	input, ok := questionInputs[question.Type]
+	if !ok {
+		return nil, ErrInvalidQuestion
+	}
+
+	preview := &QuestionPreview{
+		Type:     question.Type,
+		Question: s.policy.SanitizeHTML(question.Question),
+		Points:   question.Points,
+		Required: question.Required,
+		Input:    input,
+	}
+	if strings.TrimSpace(question.Question) == "" {
+		preview.Warnings = append(preview.Warnings, "Question text is empty")
+	}
+	if question.Points <= 0 {
+		preview.Warnings = append(preview.Warnings, "Question is worth no points")
+	}
+
+	if question.MediaURL != "" {
+		if media, ok := s.resolveMedia(question.MediaURL); ok {
+			preview.Media = media
+		} else {
+			preview.Warnings = append(preview.Warnings, "Media URL is not allowed and will not be shown")
+		}
+	}
+
+	if input == "single_choice" || input == "multiple_choice" {
+		preview.Options, preview.Warnings = s.previewOptions(question, preview.Warnings)
+	}
+	if err := validateCodeQuestion(question); err != nil {
+		preview.Warnings = append(preview.Warnings, strings.TrimPrefix(err.Error(), ErrInvalidQuestion.Error()+": "))
+	}
+
+	if seed != nil {
+		preview.Seed = *seed
+	} else {
+		preview.Seed = time.Now().UnixNano()
+	}
+	if shuffle {
+		random := rand.New(rand.NewSource(preview.Seed))
+		random.Shuffle(len(preview.Options), func(i, j int) {
+			preview.Options[i], preview.Options[j] = preview.Options[j], preview.Options[i]
+		})
+	}
+
+	return preview, nil
+}
+
+var questionInputs = map[models.QuestionType]string{
+	models.QuestionTypeSingleChoice:   "single_choice",
+	models.QuestionTypeTrueFalse:      "single_choice",
+	models.QuestionTypeMultipleChoice: "multiple_choice",
+	models.QuestionTypeText:           "short_text",
+	models.QuestionTypeEssay:          "long_text",
+	models.QuestionTypeCode:           "code",
+}
+
+// previewOptions orders options as authored and checks them against what
+// grading expects. True/false questions without options get the defaults.
+func (s *QuestionPreviewService) previewOptions(question *models.Question, warnings []string) ([]PreviewOption, []string) {
+	options := append([]models.QuestionOption(nil), question.Options...)
+	if question.Type == models.QuestionTypeTrueFalse && len(options) == 0 {
+		options = []models.QuestionOption{{Text: "True", OrderIndex: 0}, {Text: "False", OrderIndex: 1}}
+	}
+	sortOptions(options)
+
+	correct := 0
+	previews := make([]PreviewOption, 0, len(options))
+	for _, option := range options {
+		id := option.ID
+		if id == uuid.Nil {
+			id = uuid.New()
+		}
+		if option.IsCorrect {
+			correct++
+		}
+		if strings.TrimSpace(option.Text) == "" {
+			warnings = append(warnings, "An option has no text")
+		}
+		previews = append(previews, PreviewOption{ID: id, Text: s.policy.SanitizeHTML(option.Text)})
+	}
+
+	switch {
+	case len(previews) < 2:
+		warnings = append(warnings, "Choice questions need at least two options")
+	case correct == 0 && len(question.Options) > 0:
+		warnings = append(warnings, "No option is marked correct")
+	case correct > 1 && question.Type != models.QuestionTypeMultipleChoice:
+		warnings = append(warnings, "More than one option is marked correct, but students can only pick one")
+	}
+	return previews, warnings
+}
+
+// resolveMedia classifies a media URL the way the player shows it. Embeds
+// must come from an allowed host over https.
+func (s *QuestionPreviewService) resolveMedia(raw string) (*PreviewMedia, bool) {
+	link, external, ok := sanitize.SafeURL(strings.TrimSpace(raw), false)
+	if !ok {
+		return nil, false
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, false
+	}
+
+	if s.policy.EmbedHosts[strings.ToLower(u.Hostname())] {
+		if u.Scheme != "https" {
+			return nil, false
+		}
+		return &PreviewMedia{Kind: MediaEmbed, URL: link}, true
+	}
+
+	kind, ok := mediaExtensions[strings.ToLower(path.Ext(u.Path))]
+	if !ok {
+		return &PreviewMedia{Kind: MediaLink, URL: link}, true
+	}
+	if kind == MediaImage && external {
+		link = s.policy.ProxyImage(link)
+	}
+	return &PreviewMedia{Kind: kind, URL: link}, true
+}
+
+func sortOptions(options []models.QuestionOption) {
+	for i := 1; i < len(options); i++ {
+		for j := i; j > 0 && options[j].OrderIndex < options[j-1].OrderIndex; j-- {
+			options[j], options[j-1] = options[j-1], options[j]
+		}
+	}
+}
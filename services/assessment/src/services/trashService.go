@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/assessment/src/config"
+	"github.com/modex/assessment/src/models"
+	"gorm.io/gorm"
+)
+
+// DefaultTrashRetention is how long deleted assessments and questions can be
+// restored when TRASH_RETENTION_DAYS is unset
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+var (
+	// ErrQuestionNotFound is returned when a question doesn't belong to the
+	// given assessment
+	ErrQuestionNotFound = errors.New("question not found")
+	// ErrNotInTrash is returned when restoring something that isn't deleted
+	// or has already been purged
+	ErrNotInTrash = errors.New("item not found in trash")
+	// ErrAssessmentInTrash is returned when restoring a question whose
+	// assessment is itself deleted
+	ErrAssessmentInTrash = errors.New("assessment is in the trash")
+)
+
+// TrashItem is a deleted assessment or question awaiting restore or purge
+type TrashItem struct {
+	Type         string    `json:"type"` // "assessment" or "question"
+	ID           uuid.UUID `json:"id"`
+	AssessmentID uuid.UUID `json:"assessmentId"`
+	CourseID     uuid.UUID `json:"courseId"`
+	Title        string    `json:"title"`
+	DeletedAt    time.Time `json:"deletedAt"`
+	PurgeAt      time.Time `json:"purgeAt"`
+}
+
+// TrashService lists, restores and purges soft-deleted assessments and
+// questions. Deleting an assessment leaves its questions untouched, so
+// restoring it brings them back as they were.
+type TrashService struct {
+	db        *gorm.DB
+	cache     *CacheService
+	retention time.Duration
+}
+
+func NewTrashService() *TrashService {
+	retention := DefaultTrashRetention
+	if days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && days > 0 {
+		retention = time.Duration(days) * 24 * time.Hour
+	}
+
+	return &TrashService{
+		db:        config.DB,
+		cache:     NewCacheService(),
+		retention: retention,
+	}
+}
+
+// ListTrash returns a course's deleted assessments, and deleted questions of
+// assessments that are still live, most recently deleted first
+func (s *TrashService) ListTrash(courseID uuid.UUID) ([]TrashItem, error) {
+	var assessments []models.Assessment
+	if err := s.db.Unscoped().
+		Where("course_id = ? AND deleted_at IS NOT NULL", courseID).
+		Find(&assessments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deleted assessments: %w", err)
+	}
+
+	var questions []struct {
+		models.Question
+		CourseID uuid.UUID
+	}
+	if err := s.db.Unscoped().Model(&models.Question{}).
+		Select("questions.*, assessments.course_id").
+		Joins("JOIN assessments ON assessments.id = questions.assessment_id AND assessments.deleted_at IS NULL").
+		Where("assessments.course_id = ? AND questions.deleted_at IS NOT NULL", courseID).
+		Scan(&questions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deleted questions: %w", err)
+	}
+
+	items := make([]TrashItem, 0, len(assessments)+len(questions))
+	for _, a := range assessments {
+		items = append(items, TrashItem{
+			Type:         "assessment",
+			ID:           a.ID,
+			AssessmentID: a.ID,
+			CourseID:     a.CourseID,
+			Title:        a.Title,
+			DeletedAt:    a.DeletedAt.Time,
+			PurgeAt:      a.DeletedAt.Time.Add(s.retention),
+		})
+	}
+	for _, q := range questions {
+		items = append(items, TrashItem{
+			Type:         "question",
+			ID:           q.ID,
+			AssessmentID: q.AssessmentID,
+			CourseID:     q.CourseID,
+			Title:        q.Question.Question,
+			DeletedAt:    q.DeletedAt.Time,
+			PurgeAt:      q.DeletedAt.Time.Add(s.retention),
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// RestoreAssessment brings a deleted assessment back with its questions
+func (s *TrashService) RestoreAssessment(id uuid.UUID) (*models.Assessment, error) {
+	var assessment models.Assessment
+	if err := s.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&assessment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotInTrash
+		}
+		return nil, fmt.Errorf("failed to find deleted assessment: %w", err)
+	}
+
+	if err := s.db.Unscoped().Model(&assessment).Update("deleted_at", nil).Error; err != nil {
+		return nil, fmt.Errorf("failed to restore assessment: %w", err)
+	}
+	assessment.DeletedAt = gorm.DeletedAt{}
+
+	s.cache.Delete(fmt.Sprintf("assessment:%s", assessment.ID))
+	s.cache.DeletePattern(fmt.Sprintf("assessment:course:%s:*", assessment.CourseID))
+	return &assessment, nil
+}
+
+// RestoreQuestion brings a deleted question back with its options. The
+// question's assessment must not be in the trash.
+func (s *TrashService) RestoreQuestion(id uuid.UUID) (*models.Question, error) {
+	var question models.Question
+	if err := s.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&question).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotInTrash
+		}
+		return nil, fmt.Errorf("failed to find deleted question: %w", err)
+	}
+
+	var assessment models.Assessment
+	if err := s.db.Unscoped().Select("id", "course_id", "deleted_at").First(&assessment, "id = ?", question.AssessmentID).Error; err != nil {
+		return nil, fmt.Errorf("failed to find question's assessment: %w", err)
+	}
+	if assessment.DeletedAt.Valid {
+		return nil, ErrAssessmentInTrash
+	}
+
+	if err := s.db.Unscoped().Model(&question).Update("deleted_at", nil).Error; err != nil {
+		return nil, fmt.Errorf("failed to restore question: %w", err)
+	}
+	question.DeletedAt = gorm.DeletedAt{}
+
+	// Options are never soft-deleted, so they come back with the question
+	if err := s.db.Where("question_id = ?", question.ID).Order("order_index ASC").Find(&question.Options).Error; err != nil {
+		return nil, fmt.Errorf("failed to load question options: %w", err)
+	}
+
+	s.cache.Delete(fmt.Sprintf("assessment:%s", assessment.ID))
+	return &question, nil
+}
+
+// PurgeExpired permanently removes assessments and questions that have been
+// in the trash longer than the retention window. Purging an assessment also
+// removes its submissions.
+func (s *TrashService) PurgeExpired() (int64, int64, error) {
+	cutoff := time.Now().Add(-s.retention)
+	var purgedAssessments, purgedQuestions int64
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		expiredAssessments := tx.Unscoped().Model(&models.Assessment{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+		assessmentQuestions := tx.Unscoped().Model(&models.Question{}).
+			Select("id").
+			Where("assessment_id IN (?)", expiredAssessments)
+		expiredQuestions := tx.Unscoped().Model(&models.Question{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff)
+
+		steps := []struct {
+			model interface{}
+			where string
+			arg   interface{}
+		}{
+			{&models.SubmissionAnswer{}, "submission_id IN (SELECT id FROM submissions WHERE assessment_id IN (?))", expiredAssessments},
+			{&models.Submission{}, "assessment_id IN (?)", expiredAssessments},
+			{&models.QuestionOption{}, "question_id IN (?)", assessmentQuestions},
+			{&models.QuestionOption{}, "question_id IN (?)", expiredQuestions},
+		}
+		for _, step := range steps {
+			if err := tx.Unscoped().Where(step.where, step.arg).Delete(step.model).Error; err != nil {
+				return fmt.Errorf("failed to purge %T: %w", step.model, err)
+			}
+		}
+
+		result := tx.Unscoped().Where("assessment_id IN (?) OR (deleted_at IS NOT NULL AND deleted_at < ?)", expiredAssessments, cutoff).
+			Delete(&models.Question{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge questions: %w", result.Error)
+		}
+		purgedQuestions = result.RowsAffected
+
+		result = tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(&models.Assessment{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge assessments: %w", result.Error)
+		}
+		purgedAssessments = result.RowsAffected
+		return nil
+	})
+	return purgedAssessments, purgedQuestions, err
+}
+
+// RunPurger purges expired trash once an hour until ctx is cancelled
+func (s *TrashService) RunPurger(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		assessments, questions, err := s.PurgeExpired()
+		if err != nil {
+			log.Printf("Failed to purge trash: %v", err)
+		} else if assessments > 0 || questions > 0 {
+			log.Printf("Purged %d assessments and %d questions from trash", assessments, questions)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -82,6 +82,23 @@ func MigrateDatabase() error {
 	// 	&models.Lesson{},
 	// 	&models.CourseTag{},
 	// 	&models.Prerequisite{},
+	// 	&models.CourseCollaborator{},
+	// 	&models.CompletionRule{},
+	// 	&models.CourseTranslation{},
+	// 	&models.CoursePrice{},
+	// 	&models.SubscriptionTier{},
+	// 	&models.CourseTier{},
+	// 	&models.CourseFlag{},
+	// 	&models.ModerationAction{},
+	// 	&models.LinkCheckReport{},
+	// 	&models.WaitlistEntry{},
+	// 	&models.CourseReview{},
+	// 	&models.SeatReservation{},
+	// 	&models.OrganizationResidency{},
+	// 	&models.OfficeHourSlot{},
+	// 	&models.OfficeHourBooking{},
+	// 	&models.ExportJob{},
+	// 	&models.LessonCompletion{},
 	// }
 	
 	// if err := DB.AutoMigrate(models...); err != nil {
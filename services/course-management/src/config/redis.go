@@ -24,6 +24,11 @@ func InitRedis() error {
 		return fmt.Errorf("UPSTASH_REDIS_REST_URL and UPSTASH_REDIS_REST_TOKEN environment variables are required")
 	}
 
+	// A retried connect replaces the client from the failed attempt
+	if RedisClient != nil {
+		RedisClient.Close()
+	}
+
 	// Parse the URL to extract host and port
 	// For Upstash, we'll use the REST API approach
 	RedisClient = redis.NewClient(&redis.Options{
@@ -33,13 +38,16 @@ func InitRedis() error {
 		TLSConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
 		},
+		Limiter: &redisBreaker{},
 	})
 
 	// Test connection
 	_, err := RedisClient.Ping(Ctx).Result()
 	if err != nil {
+		SetDependencyState("redis", !DegradedStartEnabled(), err)
 		return fmt.Errorf("failed to connect to Redis: %w", err)
 	}
+	SetDependencyState("redis", !DegradedStartEnabled(), nil)
 
 	log.Println("Redis connection established successfully")
 	return nil
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisProbeInterval is how often a command is let through to test whether
+// Redis is back once it has gone away
+const redisProbeInterval = 5 * time.Second
+
+// ErrRedisUnavailable is returned for Redis commands while Redis is down,
+// so callers fall back to the database without waiting on dial timeouts
+var ErrRedisUnavailable = errors.New("redis is unavailable")
+
+// redisBreaker is a circuit breaker for the Redis client. It opens on a
+// connection error, fails commands fast while open apart from a periodic
+// probe, and closes again when a command gets through.
+type redisBreaker struct {
+	mu        sync.Mutex
+	open      bool
+	lastProbe time.Time
+}
+
+func (b *redisBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return nil
+	}
+	if time.Since(b.lastProbe) >= redisProbeInterval {
+		b.lastProbe = time.Now()
+		return nil
+	}
+	return ErrRedisUnavailable
+}
+
+func (b *redisBreaker) ReportResult(err error) {
+	down := isRedisConnectionError(err)
+
+	b.mu.Lock()
+	changed := b.open != down
+	b.open = down
+	if down && changed {
+		b.lastProbe = time.Now()
+	}
+	b.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if down {
+		log.Printf("Redis unavailable, continuing without cache: %v", err)
+	} else {
+		log.Println("Redis connection restored")
+	}
+	SetDependencyState("redis", !DegradedStartEnabled(), errorIf(down, err))
+}
+
+// isRedisConnectionError separates failures to reach Redis from replies
+// such as redis.Nil or a server error
+func isRedisConnectionError(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed)
+}
+
+func errorIf(condition bool, err error) error {
+	if condition {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Data residency regions an organization's data can be pinned to
+const (
+	RegionUS = "us"
+	RegionEU = "eu"
+)
+
+// Regions lists the supported data residency regions
+var Regions = []string{RegionUS, RegionEU}
+
+var regionalDBs = map[string]*gorm.DB{}
+
+// ValidRegion reports whether region is a supported residency region
+func ValidRegion(region string) bool {
+	for _, r := range Regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRegion is where organizations without a residency setting live,
+// read from DATA_REGION_DEFAULT
+func DefaultRegion() string {
+	region := strings.ToLower(os.Getenv("DATA_REGION_DEFAULT"))
+	if ValidRegion(region) {
+		return region
+	}
+	return RegionUS
+}
+
+// InitRegionalDatabases connects to each region's shard from
+// DATABASE_URL_<REGION>. Regions without a URL are served by DB.
+func InitRegionalDatabases() error {
+	for _, region := range Regions {
+		dsn := os.Getenv("DATABASE_URL_" + strings.ToUpper(region))
+		if dsn == "" {
+			continue
+		}
+
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger:          logger.Default.LogMode(logger.Warn),
+			PrepareStmt:     true,
+			CreateBatchSize: 100,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s database: %w", region, err)
+		}
+
+		regionalDBs[region] = db
+		log.Printf("Connected to %s data residency database", region)
+	}
+	return nil
+}
+
+// RegionDB returns the database shard holding a region's data
+func RegionDB(region string) *gorm.DB {
+	if db, ok := regionalDBs[region]; ok {
+		return db
+	}
+	return DB
+}
+
+// RegionBucket returns the object storage bucket for a region, read from
+// STORAGE_BUCKET_<REGION>
+func RegionBucket(region string) string {
+	return os.Getenv("STORAGE_BUCKET_" + strings.ToUpper(region))
+}
+
+// CloseRegionalDatabases closes the regional shard connections
+func CloseRegionalDatabases() {
+	for region, db := range regionalDBs {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		delete(regionalDBs, region)
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStartupAttempts = 6
+	defaultStartupBackoff  = time.Second
+	maxStartupBackoff      = 30 * time.Second
+)
+
+// Dependency statuses reported by /health/ready
+const (
+	DependencyUp       = "up"
+	DependencyDown     = "down"
+	DependencyDegraded = "degraded" // down, but the service runs without it
+)
+
+// DependencyState is the last known state of a backing service
+type DependencyState struct {
+	Status   string    `json:"status"`
+	Required bool      `json:"required"`
+	Error    string    `json:"error,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+var (
+	dependencyMu     sync.RWMutex
+	dependencyStates = map[string]DependencyState{}
+)
+
+// ConnectWithRetry calls connect until it succeeds, backing off
+// exponentially between attempts, so a dependency that is briefly down
+// while infrastructure restarts doesn't crash the service. Attempts and the
+// first delay come from STARTUP_RETRY_ATTEMPTS and STARTUP_RETRY_BACKOFF.
+func ConnectWithRetry(name string, connect func() error) error {
+	attempts := defaultStartupAttempts
+	if parsed, err := strconv.Atoi(os.Getenv("STARTUP_RETRY_ATTEMPTS")); err == nil && parsed > 0 {
+		attempts = parsed
+	}
+	backoff := defaultStartupBackoff
+	if parsed, err := time.ParseDuration(os.Getenv("STARTUP_RETRY_BACKOFF")); err == nil && parsed > 0 {
+		backoff = parsed
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return err
+		}
+
+		log.Printf("%s unavailable (attempt %d of %d), retrying in %s: %v", name, attempt, attempts, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxStartupBackoff {
+			backoff = maxStartupBackoff
+		}
+	}
+}
+
+// DegradedStartEnabled reports whether DEGRADED_START allows the service to
+// start, and stay ready, without Redis. Reads then skip the cache and go
+// straight to the database.
+func DegradedStartEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("DEGRADED_START"))
+	return enabled
+}
+
+// SetDependencyState records whether a dependency is reachable. A
+// dependency that isn't required is reported as degraded rather than down.
+func SetDependencyState(name string, required bool, err error) {
+	status := DependencyUp
+	message := ""
+	if err != nil {
+		status = DependencyDown
+		if !required {
+			status = DependencyDegraded
+		}
+		message = err.Error()
+	}
+
+	dependencyMu.Lock()
+	defer dependencyMu.Unlock()
+
+	state, ok := dependencyStates[name]
+	if !ok || state.Status != status {
+		state.Since = time.Now().UTC()
+	}
+	state.Status = status
+	state.Required = required
+	state.Error = message
+	dependencyStates[name] = state
+}
+
+// DependencyStates returns a snapshot of every recorded dependency
+func DependencyStates() map[string]DependencyState {
+	dependencyMu.RLock()
+	defer dependencyMu.RUnlock()
+
+	states := make(map[string]DependencyState, len(dependencyStates))
+	for name, state := range dependencyStates {
+		states[name] = state
+	}
+	return states
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// currentUserID parses the authenticated user's ID, writing a 401 when it is missing or invalid
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or missing user ID"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// requestUserID identifies the caller on routes that don't require sign-in,
+// which must run OptionalAuth. Anonymous callers get uuid.Nil.
+func requestUserID(c *gin.Context) uuid.UUID {
+	if userID, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		return userID
+	}
+	return uuid.Nil
+}
+
+// authorizeCourse checks that the current user holds every perm on courseID,
+// writing the error response and returning false otherwise.
+func authorizeCourse(c *gin.Context, policy *services.PolicyService, courseID uuid.UUID, perms ...models.CoursePermission) bool {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return false
+	}
+
+	for _, perm := range perms {
+		allowed, err := policy.Can(userID, courseID, perm)
+		if err != nil {
+			if errors.Is(err, services.ErrCourseNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "course not found or access denied"})
+				return false
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":      "missing course permission",
+				"permission": perm,
+			})
+			return false
+		}
+	}
+
+	return true
+}
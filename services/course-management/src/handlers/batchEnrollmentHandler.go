@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+)
+
+// maxBatchCSVSize bounds uploaded enrollment CSVs
+const maxBatchCSVSize = 5 << 20
+
+// BatchEnrollmentHandler handles bulk enrollment imports
+type BatchEnrollmentHandler struct {
+	batchService *services.BatchEnrollmentService
+	policy       *services.PolicyService
+}
+
+// NewBatchEnrollmentHandler creates a new BatchEnrollmentHandler
+func NewBatchEnrollmentHandler() *BatchEnrollmentHandler {
+	return &BatchEnrollmentHandler{
+		batchService: services.NewBatchEnrollmentService(),
+		policy:       services.NewPolicyService(),
+	}
+}
+
+// requireOwner ensures the current user owns the course
+func (h *BatchEnrollmentHandler) requireOwner(c *gin.Context, courseID uuid.UUID) (uuid.UUID, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	owner, err := h.policy.IsOwner(userID, courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	if !owner {
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found or access denied"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// EnrollBatch accepts a CSV of student emails or user IDs, either as a
+// multipart "file" field or a raw text/csv body, and starts an async import.
+func (h *BatchEnrollmentHandler) EnrollBatch(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userID, ok := h.requireOwner(c, courseUUID)
+	if !ok {
+		return
+	}
+
+	var reader io.Reader
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file field is required"})
+			return
+		}
+		defer file.Close()
+		if header.Size > maxBatchCSVSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "CSV file is too large"})
+			return
+		}
+		reader = file
+	} else {
+		reader = http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchCSVSize)
+	}
+
+	rows, err := services.ParseEnrollmentCSV(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.batchService.StartJob(courseUUID, userID, rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":   "Batch enrollment started",
+		"jobId":     job.ID,
+		"total":     job.Total,
+		"statusUrl": c.Request.URL.Path + "/" + job.ID.String(),
+	})
+}
+
+// GetBatchJob returns the progress and per-row results of an import
+func (h *BatchEnrollmentHandler) GetBatchJob(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	jobUUID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job ID"})
+		return
+	}
+
+	if _, ok := h.requireOwner(c, courseUUID); !ok {
+		return
+	}
+
+	job, err := h.batchService.GetJob(courseUUID, jobUUID)
+	if err != nil {
+		if errors.Is(err, services.ErrBatchJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
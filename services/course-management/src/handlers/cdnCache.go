@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/services"
+)
+
+// cachePublicly lets browsers keep a response briefly and the CDN keep it
+// until CDN_MAX_AGE or a purge of one of keys, whichever comes first.
+// Responses already marked private, e.g. with experiment assignments, are
+// left alone. Prices follow the visitor's country, so the CDN must key on it.
+func cachePublicly(c *gin.Context, keys ...string) {
+	if strings.Contains(c.Writer.Header().Get("Cache-Control"), "private") {
+		return
+	}
+
+	maxAge := int(services.CDNMaxAge().Seconds())
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=60, s-maxage=%d, stale-while-revalidate=60", maxAge))
+	c.Header("Surrogate-Key", strings.Join(append(keys, services.SurrogateKeyCourses), " "))
+	c.Writer.Header().Add("Vary", "X-Country, CF-IPCountry")
+}
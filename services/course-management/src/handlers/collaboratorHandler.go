@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// CollaboratorHandler manages per-course collaborator permissions
+type CollaboratorHandler struct {
+	policy *services.PolicyService
+}
+
+// NewCollaboratorHandler creates a new CollaboratorHandler
+func NewCollaboratorHandler() *CollaboratorHandler {
+	return &CollaboratorHandler{policy: services.NewPolicyService()}
+}
+
+// requireOwner ensures the current user owns the course; only owners manage collaborators
+func (h *CollaboratorHandler) requireOwner(c *gin.Context, courseID uuid.UUID) bool {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return false
+	}
+
+	owner, err := h.policy.IsOwner(userID, courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !owner {
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found or access denied"})
+		return false
+	}
+	return true
+}
+
+// GetCollaborators lists a course's collaborators and their permissions
+func (h *CollaboratorHandler) GetCollaborators(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !h.requireOwner(c, courseUUID) {
+		return
+	}
+
+	collaborators, err := h.policy.GetCollaborators(courseUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collaborators":        collaborators,
+		"availablePermissions": models.AllCoursePermissions,
+	})
+}
+
+// SetCollaborator grants a user a set of permissions on a course
+func (h *CollaboratorHandler) SetCollaborator(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	collaboratorUUID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req struct {
+		Permissions []models.CoursePermission `json:"permissions" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.requireOwner(c, courseUUID) {
+		return
+	}
+
+	ownerID, _ := currentUserID(c)
+	if collaboratorUUID == ownerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course owner already holds every permission"})
+		return
+	}
+
+	collaborator, err := h.policy.SetCollaborator(courseUUID, collaboratorUUID, ownerID, req.Permissions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Collaborator permissions updated successfully",
+		"collaborator": collaborator,
+	})
+}
+
+// RemoveCollaborator revokes a collaborator's access to a course
+func (h *CollaboratorHandler) RemoveCollaborator(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	collaboratorUUID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if !h.requireOwner(c, courseUUID) {
+		return
+	}
+
+	if err := h.policy.RemoveCollaborator(courseUUID, collaboratorUUID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collaborator removed successfully"})
+}
+
+// CheckPermission answers policy queries from other services, e.g. assessment
+// asking whether a user may grade submissions for a course.
+func (h *CollaboratorHandler) CheckPermission(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(c.Query("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	perm := models.CoursePermission(c.Query("permission"))
+	allowed, err := h.policy.Can(userUUID, courseUUID, perm)
+	if err != nil {
+		if errors.Is(err, services.ErrCourseNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"courseId":   courseUUID,
+		"userId":     userUUID,
+		"permission": perm,
+		"allowed":    allowed,
+	})
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// CompletionHandler handles course completion rules
+type CompletionHandler struct {
+	completionService *services.CompletionService
+	policy            *services.PolicyService
+}
+
+// NewCompletionHandler creates a new CompletionHandler
+func NewCompletionHandler() *CompletionHandler {
+	return &CompletionHandler{
+		completionService: services.NewCompletionService(),
+		policy:            services.NewPolicyService(),
+	}
+}
+
+// GetCompletionRules returns the completion criteria configured for a course
+func (h *CompletionHandler) GetCompletionRules(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	rule, err := h.completionService.GetRule(courseUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"completionRules": rule})
+}
+
+// UpdateCompletionRules replaces the completion criteria for a course
+func (h *CompletionHandler) UpdateCompletionRules(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	var req struct {
+		RequireAllLessons     *bool       `json:"requireAllLessons"`
+		RequiredAssessmentIDs []uuid.UUID `json:"requiredAssessmentIds"`
+		MinimumGrade          *float64    `json:"minimumGrade"`
+		AttendanceThreshold   *float64    `json:"attendanceThreshold"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	rule := &models.CompletionRule{
+		CourseID:              courseUUID,
+		RequireAllLessons:     true,
+		RequiredAssessmentIDs: req.RequiredAssessmentIDs,
+		MinimumGrade:          req.MinimumGrade,
+		AttendanceThreshold:   req.AttendanceThreshold,
+	}
+	if req.RequireAllLessons != nil {
+		rule.RequireAllLessons = *req.RequireAllLessons
+	}
+
+	if err := h.completionService.SaveRule(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Completion rules updated successfully",
+		"completionRules": rule,
+	})
+}
+
+// EvaluateCompletion is called by the progress service with a student's
+// progress snapshot and reports which completion criteria are met.
+func (h *CompletionHandler) EvaluateCompletion(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	var progress services.StudentProgress
+	if err := c.ShouldBindJSON(&progress); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.completionService.Evaluate(courseUUID, progress)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
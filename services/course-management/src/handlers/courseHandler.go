@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/modex/course-management/src/config"
@@ -17,6 +18,13 @@ type CourseHandler struct {
 	db            *gorm.DB
 	cache         *services.CacheService
 	courseService *services.CourseService
+	policy        *services.PolicyService
+	translations  *services.TranslationService
+	experiments   *services.ExperimentService
+	pricing       *services.PricingService
+	tiers         *services.TierService
+	renderer      *services.ContentRenderer
+	feeds         *services.CourseFeedService
 }
 
 func NewCourseHandler() *CourseHandler {
@@ -24,6 +32,13 @@ func NewCourseHandler() *CourseHandler {
 		db:            config.DB,
 		cache:         services.NewCacheService(),
 		courseService: services.NewCourseService(),
+		policy:        services.NewPolicyService(),
+		translations:  services.NewTranslationService(),
+		experiments:   services.NewExperimentService(),
+		pricing:       services.NewPricingService(),
+		tiers:         services.NewTierService(),
+		renderer:      services.NewContentRenderer(),
+		feeds:         services.NewCourseFeedService(),
 	}
 }
 
@@ -49,6 +64,15 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		return
 	}
 
+	currency := "USD"
+	if req.Currency != "" {
+		var ok bool
+		if currency, ok = services.NormalizeCurrency(req.Currency); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency code"})
+			return
+		}
+	}
+
 	instructorID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found"})
@@ -74,12 +98,15 @@ func (h *CourseHandler) CreateCourse(c *gin.Context) {
 		Language:     req.Language,
 		Duration:     req.Duration,
 		Price:        req.Price,
-		Currency:     req.Currency,
+		Currency:     currency,
 		MaxStudents:  req.MaxStudents,
 		InstructorID: instructorUUID,
 		Status:       models.CourseStatusDraft,
 		IsPublished:  false,
 	}
+	if orgID, err := uuid.Parse(c.GetHeader("X-Organization-ID")); err == nil {
+		course.OrganizationID = &orgID
+	}
 
 	if err := h.courseService.CreateCourse(course, req.Tags); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -121,6 +148,9 @@ func (h *CourseHandler) GetCourse(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		for i := range course.Modules {
+			h.renderer.RefreshLessons(course.Modules[i].Lessons)
+		}
 
 		// Cache the result
 		if err := h.cache.SetCourse(courseID, &course); err != nil {
@@ -131,11 +161,49 @@ func (h *CourseHandler) GetCourse(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{"course": course})
+	if course.SuspendedAt != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+		return
+	}
+
+	// Translations are applied after caching so the cache holds the default locale
+	locale, err := h.translations.LocalizeCourse(&course, services.ParseAcceptLanguage(c.GetHeader("Accept-Language")))
+	if err != nil {
+		utils.Warn("Failed to localize course", map[string]interface{}{
+			"error":    err.Error(),
+			"courseID": courseID,
+		})
+	}
+	c.Header("Content-Language", locale)
+	c.Header("Vary", "Accept-Language, X-Currency")
+
+	// Identity headers are set by OptionalAuth from the verified token
+	assignments := h.experiments.ApplyToCourse(c.Request.Context(), &course, c.GetHeader("X-User-ID"), c.GetHeader("X-Organization-ID"))
+
+	// Resolved after experiments so a price variant is what gets converted
+	courses := []models.Course{course}
+	if !h.applyDisplayPrices(c, courses) {
+		return
+	}
+	course = courses[0]
+	response := gin.H{"course": course}
+	if len(assignments) > 0 {
+		response["experiments"] = assignments
+		c.Header("Cache-Control", "private")
+	}
+	cachePublicly(c, services.CourseSurrogateKey(courseID))
+
+	respondWithETag(c, course.Version, response)
 }
 
-// GetCourses retrieves paginated courses with filtering
+// GetCourses retrieves paginated courses with filtering. ?fields= limits
+// each course to the listed fields, e.g. for catalog cards.
 func (h *CourseHandler) GetCourses(c *gin.Context) {
+	fields, ok := sparseFields(c)
+	if !ok {
+		return
+	}
+
 	// Get pagination parameters
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "10"))
@@ -157,6 +225,21 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 		Search:   search,
 	}
 
+	// ?tier=pro lists the courses a pro subscriber can access, including
+	// those of lower-ranked tiers
+	if tier := strings.ToLower(strings.TrimSpace(c.Query("tier"))); tier != "" {
+		tierKeys, err := h.tiers.CoveredTierKeys(tier)
+		if err != nil {
+			if errors.Is(err, services.ErrTierNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown tier"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		filter.TierKeys = tierKeys
+	}
+
 	// Get courses
 	courses, total, err := h.courseService.GetCourses(page, pageSize, offset, filter)
 	if err != nil {
@@ -164,8 +247,26 @@ func (h *CourseHandler) GetCourses(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"courses": courses,
+	if _, err := h.translations.LocalizeCourses(courses, services.ParseAcceptLanguage(c.GetHeader("Accept-Language"))); err != nil {
+		utils.Warn("Failed to localize courses", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	c.Header("Vary", "Accept-Language, X-Currency")
+
+	if !h.applyDisplayPrices(c, courses) {
+		return
+	}
+
+	items, err := selectFields(courses, fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	cachePublicly(c, services.SurrogateKeyCatalog)
+	respondWithETag(c, 0, gin.H{
+		"courses": items,
 		"pagination": gin.H{
 			"page":       page,
 			"pageSize":   pageSize,
@@ -202,6 +303,7 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 		Thumbnail   *string  `json:"thumbnailUrl"`
 		Preview     *string  `json:"previewUrl"`
 		Tags        []string `json:"tags"`
+		Version     *int     `json:"version"`
 	}
 
 	utils.Info("Updating course", map[string]interface{}{
@@ -216,9 +318,38 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 		return
 	}
 
-	// Check if course exists and user owns it
+	if req.Currency != nil {
+		currency, ok := services.NormalizeCurrency(*req.Currency)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency code"})
+			return
+		}
+		req.Currency = &currency
+	}
+
+	version, ok := expectedVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	// Pricing changes and content changes are separate collaborator permissions
+	var perms []models.CoursePermission
+	if req.Price != nil || req.Currency != nil {
+		perms = append(perms, models.PermissionManagePricing)
+	}
+	if req.Title != nil || req.Description != nil || req.Category != nil || req.Level != nil ||
+		req.Language != nil || req.Duration != nil || req.MaxStudents != nil || req.Tags != nil {
+		perms = append(perms, models.PermissionManageContent)
+	}
+	if len(perms) == 0 {
+		perms = append(perms, models.PermissionManageContent)
+	}
+	if !authorizeCourse(c, h.policy, courseUUID, perms...) {
+		return
+	}
+
 	var course models.Course
-	if err := h.db.Where("id = ? AND instructor_id = ?", courseUUID, c.GetString("user_id")).First(&course).Error; err != nil {
+	if err := h.db.First(&course, courseUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "course not found or access denied"})
 			return
@@ -227,6 +358,11 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 		return
 	}
 
+	if course.Version != version {
+		respondVersionConflict(c, course.Version)
+		return
+	}
+
 	// Update course fields
 	if req.Title != nil {
 		course.Title = *req.Title
@@ -257,13 +393,20 @@ func (h *CourseHandler) UpdateCourse(c *gin.Context) {
 		course.MaxStudents = *req.MaxStudents
 	}
 
-	if err := h.courseService.UpdateCourse(&course, req.Tags); err != nil {
+	if err := h.courseService.UpdateCourse(&course, req.Tags, version); err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			var current models.Course
+			h.db.Select("version").First(&current, courseUUID)
+			respondVersionConflict(c, current.Version)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Invalidate cache
 	h.cache.InvalidateCourse(courseID)
+	h.feeds.Invalidate()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Course updated successfully",
@@ -300,6 +443,7 @@ func (h *CourseHandler) DeleteCourse(c *gin.Context) {
 
 	// Invalidate cache
 	h.cache.InvalidateCourse(courseID)
+	h.feeds.Invalidate()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Course deleted successfully"})
 }
@@ -331,6 +475,10 @@ func (h *CourseHandler) PublishCourse(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "course is already published"})
 		return
 	}
+	if course.SuspendedAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "course is suspended"})
+		return
+	}
 
 	// Check if course has at least one module and lesson
 	var moduleCount int64
@@ -355,6 +503,33 @@ func (h *CourseHandler) PublishCourse(c *gin.Context) {
 
 	// Invalidate cache
 	h.cache.InvalidateCourse(courseID)
+	h.feeds.Invalidate()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Course published successfully"})
 }
+
+// applyDisplayPrices resolves the price shown for each course from the
+// X-Currency header and the visitor's country, as forwarded by the gateway or
+// CDN. It writes a 400 response and returns false for an unknown currency.
+func (h *CourseHandler) applyDisplayPrices(c *gin.Context, courses []models.Course) bool {
+	var currency string
+	if header := c.GetHeader("X-Currency"); header != "" {
+		var ok bool
+		if currency, ok = services.NormalizeCurrency(header); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency code"})
+			return false
+		}
+	}
+
+	region, _ := services.NormalizeRegion(c.GetHeader("X-Country"))
+	if region == "" {
+		region, _ = services.NormalizeRegion(c.GetHeader("CF-IPCountry"))
+	}
+
+	if err := h.pricing.ResolveDisplayPrices(courses, currency, region); err != nil {
+		utils.Warn("Failed to resolve display prices", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return true
+}
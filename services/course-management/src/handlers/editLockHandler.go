@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// EditLockHandler lets co-authors claim parts of a course's curriculum while
+// they edit them
+type EditLockHandler struct {
+	locks  *services.EditLockService
+	policy *services.PolicyService
+}
+
+// NewEditLockHandler creates a new EditLockHandler
+func NewEditLockHandler() *EditLockHandler {
+	return &EditLockHandler{
+		locks:  services.NewEditLockService(),
+		policy: services.NewPolicyService(),
+	}
+}
+
+// AcquireEditLockRequest represents the request body for acquiring a lock
+type AcquireEditLockRequest struct {
+	// Takeover moves a lock held by someone else to the caller
+	Takeover bool `json:"takeover"`
+}
+
+// lockTarget parses the course and locked resource from the path and checks
+// the caller may edit the course's content
+func (h *EditLockHandler) lockTarget(c *gin.Context) (uuid.UUID, string, uuid.UUID, bool) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return uuid.Nil, "", uuid.Nil, false
+	}
+	resourceID, err := uuid.Parse(c.Param("resourceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid resource ID"})
+		return uuid.Nil, "", uuid.Nil, false
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return uuid.Nil, "", uuid.Nil, false
+	}
+	return courseUUID, c.Param("resourceType"), resourceID, true
+}
+
+// GetEditLocks lists who is currently editing what in a course
+func (h *EditLockHandler) GetEditLocks(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	locks, err := h.locks.List(courseUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"locks": locks})
+}
+
+// AcquireEditLock claims a course, module or assessment for editing. The
+// returned token must be sent as X-Lock-Token to heartbeat and release it.
+func (h *EditLockHandler) AcquireEditLock(c *gin.Context) {
+	var req AcquireEditLockRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	courseUUID, resourceType, resourceID, ok := h.lockTarget(c)
+	if !ok {
+		return
+	}
+	userID, _ := currentUserID(c)
+
+	lock, err := h.locks.Acquire(courseUUID, resourceType, resourceID, userID, req.Takeover)
+	if errors.Is(err, services.ErrEditLockHeld) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+			"lock":  lock,
+		})
+		return
+	}
+	if err != nil {
+		h.respondError(c, courseUUID, resourceType, resourceID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Edit lock acquired successfully",
+		"lock":             lock,
+		"heartbeatSeconds": int(h.locks.TTL().Seconds() / 3),
+	})
+}
+
+// HeartbeatEditLock keeps the caller's lock alive
+func (h *EditLockHandler) HeartbeatEditLock(c *gin.Context) {
+	courseUUID, resourceType, resourceID, ok := h.lockTarget(c)
+	if !ok {
+		return
+	}
+
+	expiresAt, err := h.locks.Heartbeat(courseUUID, resourceType, resourceID, c.GetHeader("X-Lock-Token"))
+	if err != nil {
+		h.respondError(c, courseUUID, resourceType, resourceID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"expiresAt": expiresAt})
+}
+
+// ReleaseEditLock gives up the caller's lock
+func (h *EditLockHandler) ReleaseEditLock(c *gin.Context) {
+	courseUUID, resourceType, resourceID, ok := h.lockTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.locks.Release(courseUUID, resourceType, resourceID, c.GetHeader("X-Lock-Token")); err != nil {
+		h.respondError(c, courseUUID, resourceType, resourceID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Edit lock released successfully"})
+}
+
+// respondError writes lock errors. A lost lock reports whoever holds it now,
+// so an editor who was taken over knows who to talk to.
+func (h *EditLockHandler) respondError(c *gin.Context, courseID uuid.UUID, resourceType string, resourceID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLockResource):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrEditLockLost):
+		holder, _ := h.locks.Holder(courseID, resourceType, resourceID)
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+			"lock":  holder,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// ExportHandler starts background exports and serves their status and files
+type ExportHandler struct {
+	exportService *services.ExportJobService
+	policy        *services.PolicyService
+}
+
+// NewExportHandler creates a new ExportHandler
+func NewExportHandler() *ExportHandler {
+	return &ExportHandler{
+		exportService: services.NewExportJobService(),
+		policy:        services.NewPolicyService(),
+	}
+}
+
+// CreateCoursePackageExport starts packaging a course with its modules and lessons
+func (h *ExportHandler) CreateCoursePackageExport(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	h.createExport(c, services.ExportKindCoursePackage, map[string]interface{}{
+		"courseId": courseUUID.String(),
+	})
+}
+
+// CreateUserDataExport starts a GDPR export of everything tied to ?userId=
+func (h *ExportHandler) CreateUserDataExport(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Query("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a valid userId query parameter is required"})
+		return
+	}
+
+	h.createExport(c, services.ExportKindUserData, map[string]interface{}{
+		"userId": userUUID.String(),
+	})
+}
+
+// GetExport reports a job's progress, with a download link once it's done
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+
+	response := gin.H{"export": job}
+	if job.Status == models.ExportSucceeded {
+		link, expiresAt, err := h.exportService.DownloadURL(job)
+		if err != nil {
+			respondExportError(c, err)
+			return
+		}
+		response["downloadUrl"] = link
+		response["downloadUrlExpiresAt"] = expiresAt
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadExport redirects to a fresh link to the job's file
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	job, ok := h.loadJob(c)
+	if !ok {
+		return
+	}
+
+	link, _, err := h.exportService.DownloadURL(job)
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, link)
+}
+
+func (h *ExportHandler) createExport(c *gin.Context, kind string, params map[string]interface{}) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.exportService.Create(kind, userID, params)
+	if err != nil {
+		respondExportError(c, err)
+		return
+	}
+
+	c.Header("Location", "/api/v1/exports/"+job.ID.String())
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Export started successfully",
+		"export":  job,
+	})
+}
+
+func (h *ExportHandler) loadJob(c *gin.Context) (*models.ExportJob, bool) {
+	jobID, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid export ID"})
+		return nil, false
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	job, err := h.exportService.Get(jobID, userID, c.GetString("user_role") == "admin")
+	if err != nil {
+		respondExportError(c, err)
+		return nil, false
+	}
+	return job, true
+}
+
+func respondExportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrExportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrExportNotReady):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrExportExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrObjectStoreNotConfigured):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "exports are not available"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
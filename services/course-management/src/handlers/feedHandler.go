@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/services"
+	"github.com/modex/course-management/src/utils"
+)
+
+// FeedHandler serves syndication feeds of the public catalog
+type FeedHandler struct {
+	feeds *services.CourseFeedService
+}
+
+// NewFeedHandler creates a new FeedHandler
+func NewFeedHandler() *FeedHandler {
+	return &FeedHandler{feeds: services.NewCourseFeedService()}
+}
+
+// GetCourseFeed returns recently published and updated courses as Atom,
+// optionally limited to ?category=
+func (h *FeedHandler) GetCourseFeed(c *gin.Context) {
+	category := c.Query("category")
+	if len(category) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category"})
+		return
+	}
+
+	feed, err := h.feeds.Feed(category, requestURL(c))
+	if err != nil {
+		utils.Error("Failed to build course feed", map[string]interface{}{
+			"error":    err.Error(),
+			"category": category,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build course feed"})
+		return
+	}
+
+	cachePublicly(c, services.SurrogateKeyCatalog)
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", feed)
+}
+
+// requestURL rebuilds the URL the client asked for, honouring the
+// forwarded headers the gateway sets
+func requestURL(c *gin.Context) string {
+	scheme := c.GetHeader("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+	}
+	host := c.GetHeader("X-Forwarded-Host")
+	if host == "" {
+		host = c.Request.Host
+	}
+	return scheme + "://" + host + c.Request.URL.RequestURI()
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sparseFields parses ?fields=title,price into the set of JSON fields a
+// list client asked for. It returns nil when the parameter is absent, and
+// writes a 400 and returns false when it is malformed. "id" is always
+// included so clients can link items back to their detail pages.
+func sparseFields(c *gin.Context) (map[string]bool, bool) {
+	raw := c.Query("fields")
+	if raw == "" {
+		return nil, true
+	}
+
+	fields := map[string]bool{"id": true}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if !validFieldName(name) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "fields must be a comma-separated list of field names"})
+			return nil, false
+		}
+		fields[name] = true
+	}
+	return fields, true
+}
+
+// selectFields reduces each item of a list to the requested top-level JSON
+// fields. Unknown names are ignored so older clients keep working as fields
+// are renamed or removed.
+func selectFields(items interface{}, fields map[string]bool) (interface{}, error) {
+	if fields == nil {
+		return items, nil
+	}
+
+	encoded, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var decoded []map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+
+	for _, item := range decoded {
+		for name := range item {
+			if !fields[name] {
+				delete(item, name)
+			}
+		}
+	}
+	return decoded, nil
+}
+
+func validFieldName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, ch := range name {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '_') {
+			return false
+		}
+	}
+	return true
+}
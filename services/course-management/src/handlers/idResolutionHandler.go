@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+)
+
+// IDResolutionHandler identifies arbitrary IDs for support tooling
+type IDResolutionHandler struct {
+	resolver *services.IDResolutionService
+}
+
+// NewIDResolutionHandler creates a new IDResolutionHandler
+func NewIDResolutionHandler() *IDResolutionHandler {
+	return &IDResolutionHandler{resolver: services.NewIDResolutionService()}
+}
+
+// ResolveID reports which course and assessment records an ID belongs to.
+// An ID nothing matches returns 200 with no matches, since "not ours" is an
+// answer support needs too.
+func (h *IDResolutionHandler) ResolveID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	resolution, err := h.resolver.Resolve(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resolution)
+}
@@ -1,23 +1,57 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/modex/course-management/src/config"
 	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
 	"gorm.io/gorm"
 )
 
 // LessonHandler handles lesson-related HTTP requests
 type LessonHandler struct {
-	db *gorm.DB
+	db       *gorm.DB
+	policy   *services.PolicyService
+	renderer *services.ContentRenderer
+	access   *services.LessonAccessService
+	outlines *services.CourseOutlineService
 }
 
 // NewLessonHandler creates a new LessonHandler
 func NewLessonHandler() *LessonHandler {
-	return &LessonHandler{db: config.DB}
+	return &LessonHandler{
+		db:       config.DB,
+		policy:   services.NewPolicyService(),
+		renderer: services.NewContentRenderer(),
+		access:   services.NewLessonAccessService(),
+		outlines: services.NewCourseOutlineService(),
+	}
+}
+
+// loadLessonForEdit loads a lesson and its course ID, and checks the caller
+// may manage the course's content
+func (h *LessonHandler) loadLessonForEdit(c *gin.Context, lessonUUID uuid.UUID) (*models.Lesson, uuid.UUID, bool) {
+	var lesson models.Lesson
+	if err := h.db.First(&lesson, lessonUUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "lesson not found or access denied"})
+		return nil, uuid.Nil, false
+	}
+
+	var module models.Module
+	if err := h.db.Select("id", "course_id").First(&module, lesson.ModuleID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "lesson not found or access denied"})
+		return nil, uuid.Nil, false
+	}
+
+	if !authorizeCourse(c, h.policy, module.CourseID, models.PermissionManageContent) {
+		return nil, uuid.Nil, false
+	}
+
+	return &lesson, module.CourseID, true
 }
 
 // CreateLesson creates a new lesson
@@ -27,11 +61,13 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 		Title       string `json:"title" binding:"required"`
 		Description string `json:"description"`
 		Content     string `json:"content"`
+		ContentFormat string `json:"contentFormat"`
 		OrderIndex  int    `json:"orderIndex"`
 		Duration    int    `json:"duration"`
 		LessonType  string `json:"lessonType"`
 		VideoURL    string `json:"videoUrl"`
 		DownloadURL string `json:"downloadUrl"`
+		UnlockConditions []models.LessonUnlockCondition `json:"unlockConditions"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -46,32 +82,51 @@ func (h *LessonHandler) CreateLesson(c *gin.Context) {
 		return
 	}
 
-	// Check if module exists and user owns the course
+	format := models.ContentFormatMarkdown
+	if req.ContentFormat != "" {
+		format = models.ContentFormat(req.ContentFormat)
+		if !services.ValidContentFormat(format) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid content format"})
+			return
+		}
+	}
+
+	// Check if module exists and user may manage the course content
 	var module models.Module
-	if err := h.db.Joins("JOIN courses ON courses.id = modules.course_id").
-		Where("modules.id = ? AND courses.instructor_id = ?", moduleUUID, c.GetString("user_id")).
-		First(&module).Error; err != nil {
+	if err := h.db.First(&module, moduleUUID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "module not found or access denied"})
 		return
 	}
 
+	if !authorizeCourse(c, h.policy, module.CourseID, models.PermissionManageContent) {
+		return
+	}
+
+	if !h.validUnlockConditions(c, module.CourseID, uuid.Nil, req.UnlockConditions) {
+		return
+	}
+
 	// Create lesson
 	lesson := &models.Lesson{
 		ModuleID:    moduleUUID,
 		Title:       req.Title,
 		Description: req.Description,
 		Content:     req.Content,
+		ContentFormat: format,
 		OrderIndex:  req.OrderIndex,
 		Duration:    req.Duration,
 		LessonType:  models.LessonType(req.LessonType),
 		VideoURL:    req.VideoURL,
 		DownloadURL: req.DownloadURL,
+		UnlockConditions: req.UnlockConditions,
 	}
+	h.renderer.RenderLesson(lesson)
 
 	if err := h.db.Create(lesson).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.outlines.Invalidate(module.CourseID)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Lesson created successfully",
@@ -99,7 +154,17 @@ func (h *LessonHandler) GetLesson(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"lesson": lesson})
+	if len(lesson.UnlockConditions) > 0 {
+		courseID, ok := h.lessonCourse(c, &lesson)
+		if !ok || !h.checkUnlocked(c, courseID, &lesson) {
+			return
+		}
+	}
+
+	lessons := []models.Lesson{lesson}
+	h.renderer.RefreshLessons(lessons)
+
+	respondWithETag(c, lessons[0].Version, gin.H{"lesson": lessons[0]})
 }
 
 // GetLessonsByModule retrieves all lessons for a module
@@ -112,13 +177,25 @@ func (h *LessonHandler) GetLessonsByModule(c *gin.Context) {
 		return
 	}
 
+	fields, ok := sparseFields(c)
+	if !ok {
+		return
+	}
+
 	var lessons []models.Lesson
 	if err := h.db.Where("module_id = ?", moduleUUID).Order("order_index ASC").Find(&lessons).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.renderer.RefreshLessons(lessons)
+
+	items, err := selectFields(lessons, fields)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"lessons": lessons})
+	respondWithETag(c, 0, gin.H{"lessons": items})
 }
 
 // UpdateLesson updates an existing lesson
@@ -135,11 +212,14 @@ func (h *LessonHandler) UpdateLesson(c *gin.Context) {
 		Title       *string `json:"title"`
 		Description *string `json:"description"`
 		Content     *string `json:"content"`
+		ContentFormat *string `json:"contentFormat"`
 		OrderIndex  *int    `json:"orderIndex"`
 		Duration    *int    `json:"duration"`
 		LessonType  *string `json:"lessonType"`
 		VideoURL    *string `json:"videoUrl"`
 		DownloadURL *string `json:"downloadUrl"`
+		UnlockConditions *[]models.LessonUnlockCondition `json:"unlockConditions"`
+		Version     *int    `json:"version"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -147,13 +227,14 @@ func (h *LessonHandler) UpdateLesson(c *gin.Context) {
 		return
 	}
 
-	// Check if lesson exists and user owns the course
-	var lesson models.Lesson
-	if err := h.db.Joins("JOIN modules ON modules.id = lessons.module_id").
-		Joins("JOIN courses ON courses.id = modules.course_id").
-		Where("lessons.id = ? AND courses.instructor_id = ?", lessonUUID, c.GetString("user_id")).
-		First(&lesson).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "lesson not found or access denied"})
+	version, ok := expectedVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	// Check if lesson exists and user may manage the course content
+	lesson, courseID, ok := h.loadLessonForEdit(c, lessonUUID)
+	if !ok {
 		return
 	}
 
@@ -167,6 +248,14 @@ func (h *LessonHandler) UpdateLesson(c *gin.Context) {
 	if req.Content != nil {
 		lesson.Content = *req.Content
 	}
+	if req.ContentFormat != nil {
+		format := models.ContentFormat(*req.ContentFormat)
+		if !services.ValidContentFormat(format) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid content format"})
+			return
+		}
+		lesson.ContentFormat = format
+	}
 	if req.OrderIndex != nil {
 		lesson.OrderIndex = *req.OrderIndex
 	}
@@ -182,11 +271,27 @@ func (h *LessonHandler) UpdateLesson(c *gin.Context) {
 	if req.DownloadURL != nil {
 		lesson.DownloadURL = *req.DownloadURL
 	}
+	if req.UnlockConditions != nil {
+		if !h.validUnlockConditions(c, courseID, lesson.ID, *req.UnlockConditions) {
+			return
+		}
+		lesson.UnlockConditions = *req.UnlockConditions
+	}
+
+	h.renderer.RenderLesson(lesson)
 
-	if err := h.db.Save(&lesson).Error; err != nil {
+	lesson.Version = version + 1
+	if err := services.UpdateWithVersion(h.db, lesson, version); err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			var current models.Lesson
+			h.db.Select("version").First(&current, lessonUUID)
+			respondVersionConflict(c, current.Version)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.outlines.Invalidate(courseID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Lesson updated successfully",
@@ -204,20 +309,17 @@ func (h *LessonHandler) DeleteLesson(c *gin.Context) {
 		return
 	}
 
-	// Check if lesson exists and user owns the course
-	var lesson models.Lesson
-	if err := h.db.Joins("JOIN modules ON modules.id = lessons.module_id").
-		Joins("JOIN courses ON courses.id = modules.course_id").
-		Where("lessons.id = ? AND courses.instructor_id = ?", lessonUUID, c.GetString("user_id")).
-		First(&lesson).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "lesson not found or access denied"})
+	// Check if lesson exists and user may manage the course content
+	lesson, courseID, ok := h.loadLessonForEdit(c, lessonUUID)
+	if !ok {
 		return
 	}
 
-	if err := h.db.Delete(&lesson).Error; err != nil {
+	if err := h.db.Delete(lesson).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.outlines.Invalidate(courseID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Lesson deleted successfully"})
 }
@@ -241,24 +343,133 @@ func (h *LessonHandler) ReorderLesson(c *gin.Context) {
 		return
 	}
 
-	// Check if lesson exists and user owns the course
-	var lesson models.Lesson
-	if err := h.db.Joins("JOIN modules ON modules.id = lessons.module_id").
-		Joins("JOIN courses ON courses.id = modules.course_id").
-		Where("lessons.id = ? AND courses.instructor_id = ?", lessonUUID, c.GetString("user_id")).
-		First(&lesson).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "lesson not found or access denied"})
+	// Check if lesson exists and user may manage the course content
+	lesson, courseID, ok := h.loadLessonForEdit(c, lessonUUID)
+	if !ok {
 		return
 	}
 
-	lesson.OrderIndex = req.OrderIndex
-	if err := h.db.Save(&lesson).Error; err != nil {
+	if err := h.db.Model(lesson).Updates(map[string]interface{}{
+		"order_index": req.OrderIndex,
+		"version":     gorm.Expr("version + 1"),
+	}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	lesson.OrderIndex = req.OrderIndex
+	lesson.Version++
+	h.outlines.Invalidate(courseID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Lesson reordered successfully",
 		"lesson":  lesson,
 	})
 }
+
+// CompleteLesson records that the current user finished a lesson. Locked
+// lessons can't be completed.
+func (h *LessonHandler) CompleteLesson(c *gin.Context) {
+	lessonUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid lesson ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var lesson models.Lesson
+	if err := h.db.First(&lesson, lessonUUID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "lesson not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	courseID, ok := h.lessonCourse(c, &lesson)
+	if !ok {
+		return
+	}
+
+	if len(lesson.UnlockConditions) > 0 && !h.checkUnlocked(c, courseID, &lesson) {
+		return
+	}
+
+	completion, err := h.access.RecordCompletion(userID, courseID, lesson.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Lesson completed successfully",
+		"completion": completion,
+	})
+}
+
+// checkUnlocked enforces the unlock conditions of a lesson in courseID for the caller, writing
+// a 403 with the unmet requirements when it is locked. Anyone who may manage
+// the course's content sees every lesson.
+func (h *LessonHandler) checkUnlocked(c *gin.Context, courseID uuid.UUID, lesson *models.Lesson) bool {
+	// The answer depends on who is asking
+	c.Header("Cache-Control", "private")
+
+	userID := requestUserID(c)
+	if userID != uuid.Nil {
+		allowed, err := h.policy.Can(userID, courseID, models.PermissionManageContent)
+		if err != nil && !errors.Is(err, services.ErrCourseNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		if allowed {
+			return true
+		}
+	}
+
+	access, err := h.access.Evaluate(c.Request.Context(), courseID, userID, []models.Lesson{*lesson})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if result := access[lesson.ID]; result.Locked {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":              "lesson is locked",
+			"unlockRequirements": result.Requirements,
+		})
+		return false
+	}
+	return true
+}
+
+// lessonCourse looks up the course a lesson belongs to
+func (h *LessonHandler) lessonCourse(c *gin.Context, lesson *models.Lesson) (uuid.UUID, bool) {
+	var module models.Module
+	if err := h.db.Select("id", "course_id").First(&module, lesson.ModuleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "lesson not found"})
+			return uuid.Nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, false
+	}
+	return module.CourseID, true
+}
+
+// validUnlockConditions validates conditions for a lesson in courseID,
+// writing a 400 when they are invalid
+func (h *LessonHandler) validUnlockConditions(c *gin.Context, courseID, lessonID uuid.UUID, conditions []models.LessonUnlockCondition) bool {
+	if err := h.access.ValidateConditions(courseID, lessonID, conditions); err != nil {
+		if errors.Is(err, services.ErrInvalidUnlockCondition) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
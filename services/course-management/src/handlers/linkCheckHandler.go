@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+	"github.com/modex/course-management/src/utils"
+)
+
+// LinkCheckHandler exposes broken link reports to course editors
+type LinkCheckHandler struct {
+	linkCheckService *services.LinkCheckService
+	policy           *services.PolicyService
+}
+
+// NewLinkCheckHandler creates a new LinkCheckHandler
+func NewLinkCheckHandler() *LinkCheckHandler {
+	return &LinkCheckHandler{
+		linkCheckService: services.NewLinkCheckService(),
+		policy:           services.NewPolicyService(),
+	}
+}
+
+// GetLinkReport returns the latest broken link report for a course
+func (h *LinkCheckHandler) GetLinkReport(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	report, err := h.linkCheckService.GetReport(courseUUID)
+	if err != nil {
+		if errors.Is(err, services.ErrLinkReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "course has not been checked yet"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report})
+}
+
+// CheckLinks starts an immediate link check of a course. The check runs in the
+// background; poll GetLinkReport for the result.
+func (h *LinkCheckHandler) CheckLinks(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	go func() {
+		if _, err := h.linkCheckService.CheckCourse(context.Background(), courseUUID); err != nil {
+			utils.Error("Link check failed", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": courseUUID,
+			})
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Link check started"})
+}
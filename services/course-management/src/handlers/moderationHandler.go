@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+	"github.com/modex/course-management/src/utils"
+)
+
+// ModerationHandler handles course reports and admin moderation actions
+type ModerationHandler struct {
+	moderationService *services.ModerationService
+}
+
+// NewModerationHandler creates a new ModerationHandler
+func NewModerationHandler() *ModerationHandler {
+	return &ModerationHandler{
+		moderationService: services.NewModerationService(),
+	}
+}
+
+// FlagCourse lets any signed-in user report a course
+func (h *ModerationHandler) FlagCourse(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason  string `json:"reason" binding:"required"`
+		Details string `json:"details"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	flag, err := h.moderationService.FlagCourse(courseUUID, userID, models.FlagReason(req.Reason), req.Details)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Course reported successfully",
+		"flag":    flag,
+	})
+}
+
+// GetFlaggedCourses lists courses with open reports, or reports in the
+// status given by ?status=
+func (h *ModerationHandler) GetFlaggedCourses(c *gin.Context) {
+	status := models.FlagStatus(c.DefaultQuery("status", string(models.FlagStatusOpen)))
+	switch status {
+	case models.FlagStatusOpen, models.FlagStatusActioned, models.FlagStatusDismissed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid flag status"})
+		return
+	}
+
+	page, pageSize, offset := c.GetInt("page"), c.GetInt("page_size"), c.GetInt("offset")
+	courses, total, err := h.moderationService.ListFlaggedCourses(status, pageSize, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"courses": courses,
+		"pagination": gin.H{
+			"page":       page,
+			"pageSize":   pageSize,
+			"total":      total,
+			"totalPages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// GetCourseFlags lists every report against a course
+func (h *ModerationHandler) GetCourseFlags(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	flags, err := h.moderationService.GetCourseFlags(courseUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flags": flags})
+}
+
+// SuspendCourse hides a course from the catalog and freezes its enrollments
+func (h *ModerationHandler) SuspendCourse(c *gin.Context) {
+	h.moderate(c, true, h.moderationService.SuspendCourse, "Course suspended successfully")
+}
+
+// ReinstateCourse lifts a suspension
+func (h *ModerationHandler) ReinstateCourse(c *gin.Context) {
+	h.moderate(c, false, h.moderationService.ReinstateCourse, "Course reinstated successfully")
+}
+
+// ForceUnpublishCourse takes a published course back to draft
+func (h *ModerationHandler) ForceUnpublishCourse(c *gin.Context) {
+	h.moderate(c, true, h.moderationService.ForceUnpublish, "Course unpublished successfully")
+}
+
+// DismissFlags closes a course's open reports without further action
+func (h *ModerationHandler) DismissFlags(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Resolution string `json:"resolution"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dismissed, err := h.moderationService.DismissFlags(courseUUID, adminID, req.Resolution)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Flags dismissed successfully",
+		"dismissed": dismissed,
+	})
+}
+
+// moderate runs a course moderation action for the calling admin. Actions
+// that penalise a course require a reason.
+func (h *ModerationHandler) moderate(c *gin.Context, reasonRequired bool, action func(courseID, adminID uuid.UUID, reason string) (*models.Course, error), message string) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if reasonRequired && req.Reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason is required"})
+		return
+	}
+
+	course, err := action(courseUUID, adminID, req.Reason)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	utils.Info("Course moderated", map[string]interface{}{
+		"courseID": courseUUID,
+		"adminID":  adminID,
+		"result":   message,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"course":  course,
+	})
+}
+
+// GetAvailability tells the enrollment and payment services whether a course
+// can currently be sold and enrolled in
+func (h *ModerationHandler) GetAvailability(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	availability, err := h.moderationService.GetAvailability(courseUUID)
+	if err != nil {
+		respondModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func respondModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+	case errors.Is(err, services.ErrInvalidFlagReason):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid flag reason"})
+	case errors.Is(err, services.ErrCourseSuspended),
+		errors.Is(err, services.ErrCourseNotSuspended),
+		errors.Is(err, services.ErrCourseNotPublished):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,20 +1,43 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/modex/course-management/src/config"
 	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
 	"gorm.io/gorm"
 	"net/http"
 )
 
 type ModuleHandler struct {
-	db *gorm.DB
+	db       *gorm.DB
+	policy   *services.PolicyService
+	outlines *services.CourseOutlineService
 }
 
 func NewModuleHandler() *ModuleHandler {
-	return &ModuleHandler{db: config.DB}
+	return &ModuleHandler{
+		db:       config.DB,
+		policy:   services.NewPolicyService(),
+		outlines: services.NewCourseOutlineService(),
+	}
+}
+
+// loadModuleForEdit loads a module and checks the caller may manage its course's content
+func (h *ModuleHandler) loadModuleForEdit(c *gin.Context, moduleUUID uuid.UUID) (*models.Module, bool) {
+	var module models.Module
+	if err := h.db.First(&module, moduleUUID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "module not found or access denied"})
+		return nil, false
+	}
+
+	if !authorizeCourse(c, h.policy, module.CourseID, models.PermissionManageContent) {
+		return nil, false
+	}
+
+	return &module, true
 }
 
 func (h *ModuleHandler) CreateModule(c *gin.Context) {
@@ -37,9 +60,7 @@ func (h *ModuleHandler) CreateModule(c *gin.Context) {
 		return
 	}
 
-	var course models.Course
-	if err := h.db.Where("id = ? AND instructor_id = ?", courseUUID, c.GetString("user_id")).First(&course).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "course not found or access denied"})
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
 		return
 	}
 
@@ -55,6 +76,7 @@ func (h *ModuleHandler) CreateModule(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.outlines.Invalidate(courseUUID)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Module created successfully",
@@ -81,7 +103,7 @@ func (h *ModuleHandler) GetModule(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"module": module})
+	respondWithETag(c, module.Version, gin.H{"module": module})
 }
 
 func (h *ModuleHandler) GetModulesByCourse(c *gin.Context) {
@@ -93,13 +115,29 @@ func (h *ModuleHandler) GetModulesByCourse(c *gin.Context) {
 		return
 	}
 
+	fields, ok := sparseFields(c)
+	if !ok {
+		return
+	}
+
+	query := h.db.Where("course_id = ?", courseUUID).Order("order_index ASC")
+	if fields == nil || fields["lessons"] {
+		query = query.Preload("Lessons")
+	}
+
 	var modules []models.Module
-	if err := h.db.Preload("Lessons").Where("course_id = ?", courseUUID).Order("order_index ASC").Find(&modules).Error; err != nil {
+	if err := query.Find(&modules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := selectFields(modules, fields)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"modules": modules})
+	respondWithETag(c, 0, gin.H{"modules": items})
 }
 
 func (h *ModuleHandler) UpdateModule(c *gin.Context) {
@@ -116,6 +154,7 @@ func (h *ModuleHandler) UpdateModule(c *gin.Context) {
 		Description *string `json:"description"`
 		OrderIndex  *int    `json:"orderIndex"`
 		Duration    *int    `json:"duration"`
+		Version     *int    `json:"version"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -123,12 +162,14 @@ func (h *ModuleHandler) UpdateModule(c *gin.Context) {
 		return
 	}
 
-	// Check if module exists and user owns the course
-	var module models.Module
-	if err := h.db.Joins("JOIN courses ON courses.id = modules.course_id").
-		Where("modules.id = ? AND courses.instructor_id = ?", moduleUUID, c.GetString("user_id")).
-		First(&module).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "module not found or access denied"})
+	version, ok := expectedVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	// Check if module exists and user may manage the course content
+	module, ok := h.loadModuleForEdit(c, moduleUUID)
+	if !ok {
 		return
 	}
 
@@ -145,10 +186,18 @@ func (h *ModuleHandler) UpdateModule(c *gin.Context) {
 		module.Duration = *req.Duration
 	}
 
-	if err := h.db.Save(&module).Error; err != nil {
+	module.Version = version + 1
+	if err := services.UpdateWithVersion(h.db, module, version); err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			var current models.Module
+			h.db.Select("version").First(&current, moduleUUID)
+			respondVersionConflict(c, current.Version)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.outlines.Invalidate(module.CourseID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Module updated successfully",
@@ -165,18 +214,16 @@ func (h *ModuleHandler) DeleteModule(c *gin.Context) {
 		return
 	}
 
-	var module models.Module
-	if err := h.db.Joins("JOIN courses ON courses.id = modules.course_id").
-		Where("modules.id = ? AND courses.instructor_id = ?", moduleUUID, c.GetString("user_id")).
-		First(&module).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "module not found or access denied"})
+	module, ok := h.loadModuleForEdit(c, moduleUUID)
+	if !ok {
 		return
 	}
 
-	if err := h.db.Delete(&module).Error; err != nil {
+	if err := h.db.Delete(module).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.outlines.Invalidate(module.CourseID)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Module deleted successfully"})
 }
@@ -199,19 +246,21 @@ func (h *ModuleHandler) ReorderModule(c *gin.Context) {
 		return
 	}
 
-	var module models.Module
-	if err := h.db.Joins("JOIN courses ON courses.id = modules.course_id").
-		Where("modules.id = ? AND courses.instructor_id = ?", moduleUUID, c.GetString("user_id")).
-		First(&module).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "module not found or access denied"})
+	module, ok := h.loadModuleForEdit(c, moduleUUID)
+	if !ok {
 		return
 	}
 
-	module.OrderIndex = req.OrderIndex
-	if err := h.db.Save(&module).Error; err != nil {
+	if err := h.db.Model(module).Updates(map[string]interface{}{
+		"order_index": req.OrderIndex,
+		"version":     gorm.Expr("version + 1"),
+	}).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	module.OrderIndex = req.OrderIndex
+	module.Version++
+	h.outlines.Invalidate(module.CourseID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Module reordered successfully",
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// maxCalendarRange bounds the window a calendar feed request may cover
+const maxCalendarRange = 92 * 24 * time.Hour
+
+// OfficeHoursHandler handles instructors' office-hours slots, students'
+// bookings and the calendar feed they appear in
+type OfficeHoursHandler struct {
+	officeHoursService *services.OfficeHoursService
+	policy             *services.PolicyService
+}
+
+// NewOfficeHoursHandler creates a new OfficeHoursHandler
+func NewOfficeHoursHandler() *OfficeHoursHandler {
+	return &OfficeHoursHandler{
+		officeHoursService: services.NewOfficeHoursService(),
+		policy:             services.NewPolicyService(),
+	}
+}
+
+// CreateSlotRequest describes a new office-hours slot
+type CreateSlotRequest struct {
+	StartsAt   time.Time `json:"startsAt" binding:"required"`
+	EndsAt     time.Time `json:"endsAt" binding:"required"`
+	Capacity   int       `json:"capacity" binding:"required,min=1"`
+	Location   string    `json:"location" binding:"max=255"`
+	MeetingURL string    `json:"meetingUrl" binding:"omitempty,url,max=500"`
+	Notes      string    `json:"notes" binding:"max=2000"`
+}
+
+// BookSlotRequest carries an optional note for the instructor
+type BookSlotRequest struct {
+	Note string `json:"note" binding:"max=1000"`
+}
+
+// GetSlots lists a course's upcoming office-hours slots
+func (h *OfficeHoursHandler) GetSlots(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	slots, err := h.officeHoursService.ListSlots(courseUUID, time.Now())
+	if err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"slots": slots})
+}
+
+// CreateSlot opens an office-hours slot hosted by the current user
+func (h *OfficeHoursHandler) CreateSlot(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+	userID, _ := currentUserID(c)
+
+	var req CreateSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slot, err := h.officeHoursService.CreateSlot(courseUUID, userID, services.CreateSlotInput{
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Capacity:   req.Capacity,
+		Location:   req.Location,
+		MeetingURL: req.MeetingURL,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Office-hours slot created successfully",
+		"slot":    slot,
+	})
+}
+
+// CancelSlot withdraws a slot, cancelling its bookings
+func (h *OfficeHoursHandler) CancelSlot(c *gin.Context) {
+	courseUUID, slotID, ok := parseSlotParams(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	if err := h.officeHoursService.CancelSlot(courseUUID, slotID); err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Office-hours slot cancelled successfully"})
+}
+
+// GetSlotBookings lists who has booked a slot
+func (h *OfficeHoursHandler) GetSlotBookings(c *gin.Context) {
+	courseUUID, slotID, ok := parseSlotParams(c)
+	if !ok {
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	bookings, err := h.officeHoursService.ListSlotBookings(courseUUID, slotID)
+	if err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bookings": bookings})
+}
+
+// BookSlot reserves a place in a slot for the current user
+func (h *OfficeHoursHandler) BookSlot(c *gin.Context) {
+	courseUUID, slotID, ok := parseSlotParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req BookSlotRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	booking, err := h.officeHoursService.Book(c.Request.Context(), courseUUID, slotID, userID, req.Note)
+	if err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Office hours booked successfully",
+		"booking": booking,
+	})
+}
+
+// CancelBooking gives up the current user's place in a slot
+func (h *OfficeHoursHandler) CancelBooking(c *gin.Context) {
+	courseUUID, slotID, ok := parseSlotParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.officeHoursService.CancelBooking(courseUUID, slotID, userID); err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Booking cancelled successfully"})
+}
+
+// GetMyBookings lists the current user's upcoming office-hours bookings
+func (h *OfficeHoursHandler) GetMyBookings(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	bookings, err := h.officeHoursService.ListBookings(userID, time.Now())
+	if err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bookings": bookings})
+}
+
+// GetMyCalendar returns the current user's calendar feed
+func (h *OfficeHoursHandler) GetMyCalendar(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	h.respondCalendar(c, userID)
+}
+
+// GetUserCalendar returns a user's calendar feed to another service, in the
+// shape the notification service's digests read
+func (h *OfficeHoursHandler) GetUserCalendar(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+	h.respondCalendar(c, userID)
+}
+
+func (h *OfficeHoursHandler) respondCalendar(c *gin.Context, userID uuid.UUID) {
+	from, to, ok := parseCalendarRange(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.officeHoursService.CalendarEvents(userID, from, to)
+	if err != nil {
+		respondOfficeHoursError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": events})
+}
+
+// parseCalendarRange reads the RFC 3339 from/to query, defaulting to the
+// next 30 days
+func parseCalendarRange(c *gin.Context) (time.Time, time.Time, bool) {
+	from := time.Now().UTC()
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 timestamp"})
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	to := from.Add(30 * 24 * time.Hour)
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 timestamp"})
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	if !to.After(from) || to.Sub(from) > maxCalendarRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be after from and at most 92 days later"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+func parseSlotParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	slotID, err := uuid.Parse(c.Param("slotId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid slot ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return courseUUID, slotID, true
+}
+
+func respondOfficeHoursError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+	case errors.Is(err, services.ErrSlotNotFound),
+		errors.Is(err, services.ErrBookingNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidSlot):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotEnrolled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSlotFull),
+		errors.Is(err, services.ErrSlotClosed),
+		errors.Is(err, services.ErrAlreadyBooked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+)
+
+// OutlineHandler serves the learner-facing course outline
+type OutlineHandler struct {
+	outlines *services.CourseOutlineService
+}
+
+// NewOutlineHandler creates a new OutlineHandler
+func NewOutlineHandler() *OutlineHandler {
+	return &OutlineHandler{
+		outlines: services.NewCourseOutlineService(),
+	}
+}
+
+// GetCourseOutline returns the course's modules and lessons with the
+// caller's lock and completion state, for rendering a course player without
+// loading the full course. Anonymous outlines are the same for everyone and
+// are cached publicly.
+func (h *OutlineHandler) GetCourseOutline(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userID := requestUserID(c)
+	outline, err := h.outlines.Outline(c.Request.Context(), courseUUID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrCourseNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if userID != uuid.Nil {
+		c.Header("Cache-Control", "private, max-age=30")
+	}
+	c.Writer.Header().Add("Vary", "Authorization")
+	cachePublicly(c, services.CourseSurrogateKey(courseUUID.String()))
+
+	respondWithETag(c, 0, gin.H{"outline": outline})
+}
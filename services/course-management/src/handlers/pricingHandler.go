@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// PricingHandler manages regional and multi-currency course prices
+type PricingHandler struct {
+	pricingService *services.PricingService
+	policy         *services.PolicyService
+	cache          *services.CacheService
+}
+
+// NewPricingHandler creates a new PricingHandler
+func NewPricingHandler() *PricingHandler {
+	return &PricingHandler{
+		pricingService: services.NewPricingService(),
+		policy:         services.NewPolicyService(),
+		cache:          services.NewCacheService(),
+	}
+}
+
+// GetPrices lists the explicit prices of a course
+func (h *PricingHandler) GetPrices(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	prices, err := h.pricingService.GetPrices(courseUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"prices": prices})
+}
+
+// SetPrices replaces the explicit prices of a course. An empty list removes
+// them, leaving only the base price and converted prices.
+func (h *PricingHandler) SetPrices(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	var req struct {
+		Prices []struct {
+			Currency string  `json:"currency" binding:"required"`
+			Region   string  `json:"region"`
+			Amount   float64 `json:"amount"`
+		} `json:"prices"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prices := make([]models.CoursePrice, len(req.Prices))
+	for i, p := range req.Prices {
+		prices[i] = models.CoursePrice{Currency: p.Currency, Region: p.Region, Amount: p.Amount}
+	}
+	if err := h.pricingService.ValidatePrices(prices); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManagePricing) {
+		return
+	}
+
+	if err := h.pricingService.ReplacePrices(courseUUID, prices); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.cache.InvalidateCourse(courseUUID.String())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Prices updated successfully",
+		"prices":  prices,
+	})
+}
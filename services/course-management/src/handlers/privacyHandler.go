@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+	"github.com/modex/course-management/src/utils"
+)
+
+// PrivacyHandler handles GDPR data access and erasure requests
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+}
+
+// NewPrivacyHandler creates a new PrivacyHandler
+func NewPrivacyHandler() *PrivacyHandler {
+	return &PrivacyHandler{privacyService: services.NewPrivacyService()}
+}
+
+// ExportUserData returns a zip archive of everything tied to ?userId=
+func (h *PrivacyHandler) ExportUserData(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Query("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a valid userId query parameter is required"})
+		return
+	}
+
+	export, err := h.privacyService.ExportUserData(c.Request.Context(), userUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Build the archive in memory so a failure can still be reported as JSON
+	var buf bytes.Buffer
+	if err := h.privacyService.WriteArchive(&buf, export); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Info("User data exported", map[string]interface{}{
+		"userID":      userUUID,
+		"requestedBy": c.GetString("user_id"),
+	})
+
+	filename := fmt.Sprintf("user-%s-export.zip", userUUID)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// EraseUserData deletes or anonymizes the user's course data and notifies
+// the other services to erase theirs.
+func (h *PrivacyHandler) EraseUserData(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	requestedBy, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.privacyService.EraseUserData(c.Request.Context(), userUUID, requestedBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "result": result})
+		return
+	}
+
+	utils.Info("User data erased", map[string]interface{}{
+		"userID":            userUUID,
+		"requestedBy":       requestedBy,
+		"deletedCourses":    len(result.DeletedCourses),
+		"anonymizedCourses": len(result.AnonymizedCourses),
+	})
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "User data erased successfully",
+		"result":  result,
+	})
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+	"github.com/modex/course-management/src/utils"
+)
+
+// Provisioning event types forwarded by the identity layer's SCIM endpoints
+const (
+	ProvisioningEventUserProvisioned   = "user.provisioned"
+	ProvisioningEventUserDeprovisioned = "user.deprovisioned"
+)
+
+// ProvisioningHandler handles identity lifecycle hooks from the identity layer
+type ProvisioningHandler struct {
+	provisioningService *services.ProvisioningService
+}
+
+// NewProvisioningHandler creates a new ProvisioningHandler
+func NewProvisioningHandler() *ProvisioningHandler {
+	return &ProvisioningHandler{provisioningService: services.NewProvisioningService()}
+}
+
+// HandleProvisioningEvent applies a SCIM provisioning or deprovisioning event.
+// Deprovisioned instructors hand their courses over to the given successor.
+func (h *ProvisioningHandler) HandleProvisioningEvent(c *gin.Context) {
+	var req struct {
+		Event       string `json:"event" binding:"required"`
+		UserID      string `json:"userId" binding:"required"`
+		SuccessorID string `json:"successorId"`
+		Role        string `json:"role"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userUUID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	switch req.Event {
+	case ProvisioningEventUserProvisioned:
+		// Nothing is owned yet; acknowledge so the identity layer can proceed
+		utils.Info("User provisioned", map[string]interface{}{
+			"userID": userUUID,
+			"role":   req.Role,
+		})
+		c.JSON(http.StatusOK, gin.H{"message": "Provisioning acknowledged"})
+
+	case ProvisioningEventUserDeprovisioned:
+		successorUUID, err := uuid.Parse(req.SuccessorID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "a valid successorId is required to deprovision a user"})
+			return
+		}
+
+		courseIDs, err := h.provisioningService.TransferOwnership(userUUID, successorUUID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		utils.Info("Transferred course ownership on deprovision", map[string]interface{}{
+			"userID":      userUUID,
+			"successorID": successorUUID,
+			"courses":     len(courseIDs),
+		})
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":            "Deprovisioning applied",
+			"transferredCourses": courseIDs,
+		})
+
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported provisioning event"})
+	}
+}
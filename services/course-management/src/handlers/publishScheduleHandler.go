@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+)
+
+// PublishScheduleHandler lets course owners schedule a future publish
+type PublishScheduleHandler struct {
+	scheduleService *services.PublishScheduleService
+	policy          *services.PolicyService
+}
+
+// NewPublishScheduleHandler creates a new PublishScheduleHandler
+func NewPublishScheduleHandler() *PublishScheduleHandler {
+	return &PublishScheduleHandler{
+		scheduleService: services.NewPublishScheduleService(),
+		policy:          services.NewPolicyService(),
+	}
+}
+
+// SchedulePublishRequest represents the request body for scheduling a publish
+type SchedulePublishRequest struct {
+	PublishAt time.Time `json:"publishAt" binding:"required"`
+}
+
+// requireOwner ensures the current user owns the course; like publishing,
+// scheduling is limited to the owner
+func (h *PublishScheduleHandler) requireOwner(c *gin.Context, courseID uuid.UUID) bool {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return false
+	}
+
+	owner, err := h.policy.IsOwner(userID, courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !owner {
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found or access denied"})
+		return false
+	}
+	return true
+}
+
+// SchedulePublish sets or reschedules when a draft course is published
+func (h *PublishScheduleHandler) SchedulePublish(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	var req SchedulePublishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.requireOwner(c, courseUUID) {
+		return
+	}
+
+	course, err := h.scheduleService.Schedule(courseUUID, req.PublishAt)
+	if err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Course publish scheduled successfully",
+		"courseId":  course.ID,
+		"publishAt": course.PublishAt,
+	})
+}
+
+// CancelScheduledPublish clears a course's scheduled publish
+func (h *PublishScheduleHandler) CancelScheduledPublish(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !h.requireOwner(c, courseUUID) {
+		return
+	}
+
+	if err := h.scheduleService.Cancel(courseUUID); err != nil {
+		respondScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduled publish cancelled successfully"})
+}
+
+func respondScheduleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found or access denied"})
+	case errors.Is(err, services.ErrPublishAtInPast):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNoPublishScheduled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCourseAlreadyPublished),
+		errors.Is(err, services.ErrCourseNotPublishable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/services"
+)
+
+// ResidencyHandler handles per-organization data residency settings
+type ResidencyHandler struct {
+	residencyService *services.ResidencyService
+}
+
+// NewResidencyHandler creates a new ResidencyHandler
+func NewResidencyHandler() *ResidencyHandler {
+	return &ResidencyHandler{residencyService: services.NewResidencyService()}
+}
+
+// GetResidency returns the region an organization's data lives in. Other
+// services call the internal route to pick a database shard or storage bucket.
+func (h *ResidencyHandler) GetResidency(c *gin.Context) {
+	orgID, err := uuid.Parse(c.Param("orgId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization ID"})
+		return
+	}
+
+	placement, err := h.residencyService.Resolve(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, placement)
+}
+
+// SetResidency pins an organization's data to a region
+func (h *ResidencyHandler) SetResidency(c *gin.Context) {
+	orgID, err := uuid.Parse(c.Param("orgId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization ID"})
+		return
+	}
+
+	var req struct {
+		Region string `json:"region" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var updatedBy *uuid.UUID
+	if id, err := uuid.Parse(c.GetString("user_id")); err == nil {
+		updatedBy = &id
+	}
+
+	placement, err := h.residencyService.SetRegion(c.Request.Context(), orgID, req.Region, updatedBy)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRegion):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "regions": config.Regions})
+		case errors.Is(err, services.ErrResidencyLocked):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Data residency set successfully",
+		"residency": placement,
+	})
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+)
+
+// ReviewHandler handles course reviews
+type ReviewHandler struct {
+	reviewService *services.ReviewService
+}
+
+// NewReviewHandler creates a new ReviewHandler
+func NewReviewHandler() *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: services.NewReviewService(),
+	}
+}
+
+// ReviewRequest represents the request body for posting or editing a review
+type ReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Title  string `json:"title" binding:"max=200"`
+	Body   string `json:"body" binding:"max=5000"`
+}
+
+func (r ReviewRequest) input() services.ReviewInput {
+	return services.ReviewInput{Rating: r.Rating, Title: r.Title, Body: r.Body}
+}
+
+// GetReviews lists a course's reviews
+func (h *ReviewHandler) GetReviews(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	page, pageSize, offset := c.GetInt("page"), c.GetInt("page_size"), c.GetInt("offset")
+	reviews, total, err := h.reviewService.ListReviews(courseUUID, pageSize, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"pagination": gin.H{
+			"page":       page,
+			"pageSize":   pageSize,
+			"total":      total,
+			"totalPages": (total + int64(pageSize) - 1) / int64(pageSize),
+		},
+	})
+}
+
+// CreateReview posts the current user's review of a course they completed
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.CreateReview(c.Request.Context(), courseUUID, userID, req.input())
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Review posted successfully",
+		"review":  review,
+	})
+}
+
+// UpdateReview edits the current user's review while it is still editable
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	courseUUID, reviewUUID, ok := reviewParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.reviewService.UpdateReview(courseUUID, reviewUUID, userID, req.input())
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Review updated successfully",
+		"review":  review,
+	})
+}
+
+// DeleteReview removes the current user's review
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	courseUUID, reviewUUID, ok := reviewParams(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(courseUUID, reviewUUID, userID); err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+func reviewParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	reviewUUID, err := uuid.Parse(c.Param("reviewId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return courseUUID, reviewUUID, true
+}
+
+func respondReviewError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+	case errors.Is(err, services.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRating):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReviewNotEligible),
+		errors.Is(err, services.ErrReviewEditWindowClosed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyReviewed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReviewRateLimited):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		var serviceErr *services.ServiceError
+		if errors.As(err, &serviceErr) {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "could not verify enrollment"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
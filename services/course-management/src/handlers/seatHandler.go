@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/services"
+)
+
+// SeatHandler handles seat reservations for capacity-limited courses. The
+// enrollment service reserves a seat when checkout starts, then confirms or
+// releases it.
+type SeatHandler struct {
+	seatService *services.SeatService
+}
+
+// NewSeatHandler creates a new SeatHandler
+func NewSeatHandler() *SeatHandler {
+	return &SeatHandler{
+		seatService: services.NewSeatService(),
+	}
+}
+
+// ReserveSeatRequest identifies the student checking out
+type ReserveSeatRequest struct {
+	UserID string `json:"userId" binding:"required,max=64"`
+}
+
+// SeatReservationRequest identifies an existing reservation
+type SeatReservationRequest struct {
+	ReservationID string `json:"reservationId" binding:"required"`
+}
+
+// ReserveSeat holds a seat for a student during checkout
+func (h *SeatHandler) ReserveSeat(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	var req ReserveSeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reservation, err := h.seatService.Reserve(c.Request.Context(), courseUUID, req.UserID)
+	if err != nil {
+		respondSeatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Seat reserved successfully",
+		"reservation": reservation,
+	})
+}
+
+// ConfirmSeat turns a held seat into an enrollment
+func (h *SeatHandler) ConfirmSeat(c *gin.Context) {
+	courseUUID, reservationID, ok := bindSeatReservation(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.seatService.Confirm(c.Request.Context(), courseUUID, reservationID)
+	if err != nil {
+		respondSeatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Seat confirmed successfully",
+		"reservation": reservation,
+	})
+}
+
+// ReleaseSeat gives a held or confirmed seat back
+func (h *SeatHandler) ReleaseSeat(c *gin.Context) {
+	courseUUID, reservationID, ok := bindSeatReservation(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.seatService.Release(c.Request.Context(), courseUUID, reservationID)
+	if err != nil {
+		respondSeatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Seat released successfully",
+		"reservation": reservation,
+	})
+}
+
+// GetSeats reports a course's taken, held and free seats
+func (h *SeatHandler) GetSeats(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	availability, err := h.seatService.GetAvailability(c.Request.Context(), courseUUID)
+	if err != nil {
+		respondSeatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+func bindSeatReservation(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	var req SeatReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	reservationID, err := uuid.Parse(req.ReservationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid reservation ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return courseUUID, reservationID, true
+}
+
+func respondSeatError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+	case errors.Is(err, services.ErrReservationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCourseFull),
+		errors.Is(err, services.ErrReservationClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReservationExpired):
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
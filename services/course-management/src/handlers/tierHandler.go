@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+	"github.com/modex/course-management/src/utils"
+)
+
+// TierHandler manages subscription tiers and the courses included in them
+type TierHandler struct {
+	tierService *services.TierService
+	policy      *services.PolicyService
+	cache       *services.CacheService
+}
+
+// NewTierHandler creates a new TierHandler
+func NewTierHandler() *TierHandler {
+	return &TierHandler{
+		tierService: services.NewTierService(),
+		policy:      services.NewPolicyService(),
+		cache:       services.NewCacheService(),
+	}
+}
+
+// GetTiers lists active subscription tiers
+func (h *TierHandler) GetTiers(c *gin.Context) {
+	tiers, err := h.tierService.ListTiers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tiers": tiers})
+}
+
+// CreateTier defines a new subscription tier
+func (h *TierHandler) CreateTier(c *gin.Context) {
+	var req struct {
+		Key         string `json:"key" binding:"required"`
+		Name        string `json:"name" binding:"required"`
+		Description string `json:"description"`
+		Rank        int    `json:"rank"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key := strings.ToLower(strings.TrimSpace(req.Key))
+	if !services.ValidTierKey(key) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tier key"})
+		return
+	}
+
+	tier := models.SubscriptionTier{
+		Key:         key,
+		Name:        req.Name,
+		Description: req.Description,
+		Rank:        req.Rank,
+		IsActive:    true,
+	}
+	if err := h.tierService.CreateTier(&tier); err != nil {
+		if errors.Is(err, services.ErrTierExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "tier already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Info("Subscription tier created", map[string]interface{}{
+		"tier": tier.Key,
+	})
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Tier created successfully",
+		"tier":    tier,
+	})
+}
+
+// UpdateTier changes a tier's name, description, rank or active flag.
+// Deactivating a tier keeps its course flags but stops it granting access.
+func (h *TierHandler) UpdateTier(c *gin.Context) {
+	var req struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Rank        *int    `json:"rank"`
+		IsActive    *bool   `json:"isActive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tier, err := h.tierService.GetTier(c.Param("key"))
+	if err != nil {
+		respondTierError(c, err)
+		return
+	}
+
+	if req.Name != nil {
+		tier.Name = *req.Name
+	}
+	if req.Description != nil {
+		tier.Description = *req.Description
+	}
+	if req.Rank != nil {
+		tier.Rank = *req.Rank
+	}
+	if req.IsActive != nil {
+		tier.IsActive = *req.IsActive
+	}
+
+	if err := h.tierService.SaveTier(tier); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tier updated successfully",
+		"tier":    tier,
+	})
+}
+
+// DeleteTier removes a tier and unflags the courses included in it
+func (h *TierHandler) DeleteTier(c *gin.Context) {
+	key := c.Param("key")
+	if err := h.tierService.DeleteTier(key); err != nil {
+		respondTierError(c, err)
+		return
+	}
+
+	utils.Info("Subscription tier deleted", map[string]interface{}{
+		"tier": key,
+	})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tier deleted successfully"})
+}
+
+// SetCourseTiers replaces the tiers a course is included in. An empty list
+// takes the course out of every subscription.
+func (h *TierHandler) SetCourseTiers(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	var req struct {
+		Tiers []string `json:"tiers"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManagePricing) {
+		return
+	}
+
+	courseTiers, err := h.tierService.SetCourseTiers(courseUUID, req.Tiers)
+	if err != nil {
+		if errors.Is(err, services.ErrTierNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown or inactive tier"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.cache.InvalidateCourse(courseUUID.String())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Course tiers updated successfully",
+		"tiers":   courseTiers,
+	})
+}
+
+// CheckEntitlement lets the payment and enrollment services ask whether a
+// subscriber's tiers include a course. Pass one or more tiers as
+// ?tier=pro&tier=team or ?tier=pro,team.
+func (h *TierHandler) CheckEntitlement(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	var tiers []string
+	for _, value := range c.QueryArray("tier") {
+		for _, key := range strings.Split(value, ",") {
+			tiers = append(tiers, strings.ToLower(strings.TrimSpace(key)))
+		}
+	}
+	if len(tiers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tier is required"})
+		return
+	}
+
+	entitlement, err := h.tierService.CheckEntitlement(courseUUID, tiers)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entitlement)
+}
+
+func respondTierError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrTierNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tier not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
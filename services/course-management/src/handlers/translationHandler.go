@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// TranslationHandler manages localized course metadata
+type TranslationHandler struct {
+	translationService *services.TranslationService
+	policy             *services.PolicyService
+}
+
+// NewTranslationHandler creates a new TranslationHandler
+func NewTranslationHandler() *TranslationHandler {
+	return &TranslationHandler{
+		translationService: services.NewTranslationService(),
+		policy:             services.NewPolicyService(),
+	}
+}
+
+// GetTranslations lists every translation of a course
+func (h *TranslationHandler) GetTranslations(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	translations, err := h.translationService.GetTranslations(courseUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"translations": translations})
+}
+
+// SaveTranslation creates or replaces the course translation for :locale
+func (h *TranslationHandler) SaveTranslation(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	locale, ok := services.NormalizeLocale(c.Param("locale"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid locale"})
+		return
+	}
+
+	var req struct {
+		Title           string `json:"title" binding:"required"`
+		Description     string `json:"description"`
+		MetaTitle       string `json:"metaTitle"`
+		MetaDescription string `json:"metaDescription"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	translation := &models.CourseTranslation{
+		CourseID:        courseUUID,
+		Locale:          locale,
+		Title:           req.Title,
+		Description:     req.Description,
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+	}
+	if err := h.translationService.SaveTranslation(translation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Translation saved successfully",
+		"translation": translation,
+	})
+}
+
+// DeleteTranslation removes the course translation for :locale
+func (h *TranslationHandler) DeleteTranslation(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	locale, ok := services.NormalizeLocale(c.Param("locale"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid locale"})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	if err := h.translationService.DeleteTranslation(courseUUID, locale); err != nil {
+		if errors.Is(err, services.ErrTranslationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "translation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Translation deleted successfully"})
+}
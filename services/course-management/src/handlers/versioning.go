@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// expectedVersion resolves the version the client last read, from the If-Match
+// header (e.g. `"3"`, or an ETag from a GET such as `"3-9f86d081"`) or the
+// version field of the request body. When neither is present it writes a 428
+// response and returns false.
+func expectedVersion(c *gin.Context, bodyVersion *int) (int, bool) {
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		tag := strings.Trim(strings.TrimPrefix(strings.TrimSpace(ifMatch), "W/"), `"`)
+		tag, _, _ = strings.Cut(tag, "-")
+		version, err := strconv.Atoi(tag)
+		if err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid If-Match header"})
+			return 0, false
+		}
+		return version, true
+	}
+
+	if bodyVersion != nil {
+		return *bodyVersion, true
+	}
+
+	c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header or version field is required"})
+	return 0, false
+}
+
+// respondVersionConflict writes a 409 including the version currently stored
+func respondVersionConflict(c *gin.Context, currentVersion int) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error":          "resource was modified by another request",
+		"currentVersion": currentVersion,
+	})
+}
+
+// respondWithETag writes body as JSON under a strong ETag, or a bare 304 when
+// If-None-Match already names it. The tag hashes the rendered body, so
+// translated, converted and experiment variants each get their own; a
+// non-zero version prefixes it so the tag also works as If-Match on updates.
+func respondWithETag(c *gin.Context, version int, body interface{}) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	sum := sha256.Sum256(payload)
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:8]))
+	if version > 0 {
+		etag = fmt.Sprintf(`"%d-%s"`, version, hex.EncodeToString(sum[:8]))
+	}
+	c.Header("ETag", etag)
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", payload)
+}
+
+// etagMatches applies If-None-Match's weak comparison to a list of tags
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/services"
+)
+
+// WaitlistHandler handles enrollment capacity checks and course waitlists
+type WaitlistHandler struct {
+	waitlistService *services.WaitlistService
+	policy          *services.PolicyService
+}
+
+// NewWaitlistHandler creates a new WaitlistHandler
+func NewWaitlistHandler() *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: services.NewWaitlistService(),
+		policy:          services.NewPolicyService(),
+	}
+}
+
+// JoinWaitlist puts the current user in line for a full course
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.waitlistService.Join(courseUUID, userID)
+	if err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Joined waitlist successfully",
+		"entry":   entry,
+	})
+}
+
+// LeaveWaitlist takes the current user out of a course's waitlist
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.waitlistService.Leave(courseUUID, userID); err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left waitlist successfully"})
+}
+
+// GetMyWaitlistEntry returns the current user's place in a course's waitlist
+func (h *WaitlistHandler) GetMyWaitlistEntry(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.waitlistService.GetEntry(courseUUID, userID)
+	if err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entry": entry})
+}
+
+// GetWaitlist lists the students waiting for a course
+func (h *WaitlistHandler) GetWaitlist(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	if !authorizeCourse(c, h.policy, courseUUID, models.PermissionManageContent) {
+		return
+	}
+
+	entries, err := h.waitlistService.GetWaitlist(courseUUID)
+	if err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"waitlist": entries,
+		"total":    len(entries),
+	})
+}
+
+// CheckEnrollment tells the enrollment service whether a student may enroll.
+// It passes the course's current enrollment count as ?enrolled= and the
+// student as ?userId=.
+func (h *WaitlistHandler) CheckEnrollment(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	enrolled, ok := enrolledParam(c)
+	if !ok {
+		return
+	}
+
+	var userID *uuid.UUID
+	if raw := c.Query("userId"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+			return
+		}
+		userID = &id
+	}
+
+	check, err := h.waitlistService.CheckEnrollment(courseUUID, userID, enrolled)
+	if err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// PromoteWaitlist is called by the enrollment service when a seat frees up,
+// with the course's current enrollment count as ?enrolled=
+func (h *WaitlistHandler) PromoteWaitlist(c *gin.Context) {
+	courseUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid course ID"})
+		return
+	}
+
+	enrolled, ok := enrolledParam(c)
+	if !ok {
+		return
+	}
+
+	promoted, err := h.waitlistService.PromoteWaiting(courseUUID, enrolled)
+	if err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promoted": promoted,
+		"total":    len(promoted),
+	})
+}
+
+func enrolledParam(c *gin.Context) (int, bool) {
+	enrolled, err := strconv.Atoi(c.Query("enrolled"))
+	if err != nil || enrolled < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "enrolled must be a non-negative integer"})
+		return 0, false
+	}
+	return enrolled, true
+}
+
+func respondWaitlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "course not found"})
+	case errors.Is(err, services.ErrNotWaitlisted):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWaitlistClosed),
+		errors.Is(err, services.ErrAlreadyWaitlisted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
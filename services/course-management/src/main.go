@@ -6,6 +6,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/modex/course-management/src/config"
 	"github.com/modex/course-management/src/routes"
+	"github.com/modex/course-management/src/services"
 	"log"
 	"net/http"
 	"os"
@@ -20,15 +21,26 @@ func main() {
 		log.Println("No .env file found, using environment variables")
 	}
 
-	// Initialize database
-	if err := config.InitDatabase(); err != nil {
+	// Dependencies are retried with backoff so the service rides out
+	// infrastructure restarts instead of crash looping
+	if err := config.ConnectWithRetry("Database", config.InitDatabase); err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 	defer config.CloseDatabase()
 
-	// Initialize Redis
-	if err := config.InitRedis(); err != nil {
-		log.Fatal("Failed to initialize Redis:", err)
+	// Connect to regional shards for organizations with data residency settings
+	if err := config.ConnectWithRetry("Regional databases", config.InitRegionalDatabases); err != nil {
+		log.Fatal("Failed to initialize regional databases:", err)
+	}
+	defer config.CloseRegionalDatabases()
+
+	// Initialize Redis. With DEGRADED_START the service starts without it,
+	// serving reads from the database until it comes back.
+	if err := config.ConnectWithRetry("Redis", config.InitRedis); err != nil {
+		if !config.DegradedStartEnabled() || config.RedisClient == nil {
+			log.Fatal("Failed to initialize Redis:", err)
+		}
+		log.Println("Starting without Redis:", err)
 	}
 	defer config.CloseRedis()
 
@@ -51,6 +63,15 @@ func main() {
 	// Swagger documentation (if enabled)
 	// routes.SetupSwaggerRoutes(router)
 
+	// Background jobs stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	services.NewLinkCheckService().StartScheduler(jobsCtx, services.LinkCheckInterval())
+	services.NewPublishScheduleService().StartScheduler(jobsCtx, services.PublishSchedulerInterval())
+	services.NewSeatService().StartReconciler(jobsCtx, services.SeatReconcileInterval())
+	services.NewOfficeHoursService().StartReminders(jobsCtx, services.OfficeHoursReminderInterval())
+	services.NewExportJobService().StartCleanup(jobsCtx, services.ExportCleanupInterval())
+
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -75,6 +96,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
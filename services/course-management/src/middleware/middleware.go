@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
+	"github.com/modex/shared/jwt"
 	"github.com/ulule/limiter/v3"
 	limitergin "github.com/ulule/limiter/v3/drivers/middleware/gin"
 	"github.com/ulule/limiter/v3/drivers/store/memory"
@@ -18,8 +21,8 @@ func CORS() gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Idempotency-Key", "If-Match", "X-Currency"},
+		ExposeHeaders:    []string{"Content-Length", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	})
@@ -37,34 +40,81 @@ func RateLimit() gin.HandlerFunc {
 	return limitergin.NewMiddleware(instance)
 }
 
+// Identity headers handlers read the caller from. AuthRequired and
+// OptionalAuth replace whatever the client sent with the verified token's
+// claims.
+var identityHeaders = []string{"X-User-ID", "X-User-Role", "X-Organization-ID"}
+
+// AuthRequired verifies the bearer token against JWT_SECRET, the secret the
+// API gateway signs user tokens with, and exposes the caller as user_id,
+// user_role and organization_id.
 func AuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		for _, header := range identityHeaders {
+			c.Request.Header.Del(header)
+		}
+
+		if c.GetHeader("Authorization") == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 			c.Abort()
 			return
 		}
+		if authenticate(c) {
+			c.Next()
+		}
+	}
+}
 
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
-			c.Abort()
-			return
+// OptionalAuth is AuthRequired for routes anonymous callers may use: a bearer
+// token is verified when one is sent, and identity headers from the client
+// are always dropped so only a verified caller is ever seen.
+func OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, header := range identityHeaders {
+			c.Request.Header.Del(header)
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token required"})
-			c.Abort()
-			return
+		if c.GetHeader("Authorization") == "" || authenticate(c) {
+			c.Next()
 		}
+	}
+}
 
-		// TODO: Validate JWT token with user-management service
-		// For now, we'll just set a mock user ID
-		c.Set("user_id", "mock-user-id")
-		c.Set("user_role", "instructor")
-		c.Next()
+// authenticate verifies the request's bearer token and sets the caller's
+// identity, writing the error response and returning false when it fails
+func authenticate(c *gin.Context) bool {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Authentication not configured"})
+		c.Abort()
+		return false
 	}
+
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		c.Abort()
+		return false
+	}
+
+	claims, err := jwt.Verify(token, secret)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		c.Abort()
+		return false
+	}
+
+	c.Set("user_id", claims.User())
+	c.Set("user_role", claims.Role)
+	c.Set("organization_id", claims.OrganizationID)
+	c.Request.Header.Set("X-User-ID", claims.User())
+	if claims.Role != "" {
+		c.Request.Header.Set("X-User-Role", claims.Role)
+	}
+	if claims.OrganizationID != "" {
+		c.Request.Header.Set("X-Organization-ID", claims.OrganizationID)
+	}
+	return true
 }
 
 func InstructorRequired() gin.HandlerFunc {
@@ -79,6 +129,18 @@ func InstructorRequired() gin.HandlerFunc {
 	}
 }
 
+func AdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("user_role")
+		if !exists || role != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 func Pagination() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		page := 1
@@ -129,3 +191,26 @@ func ValidateUUID(param string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// ServiceAuthRequired guards internal endpoints that are only called by
+// other platform services. Callers must present the shared INTERNAL_SERVICE_KEY.
+func ServiceAuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		expected := os.Getenv("INTERNAL_SERVICE_KEY")
+		if expected == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal service key not configured"})
+			c.Abort()
+			return
+		}
+
+		key := c.GetHeader("X-Service-Key")
+		if subtle.ConstantTimeCompare([]byte(key), []byte(expected)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid service key"})
+			c.Abort()
+			return
+		}
+
+		c.Set("service_caller", true)
+		c.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CompletionRule defines when a student has completed a course. Every
+// configured criterion must be met; unset criteria are ignored.
+type CompletionRule struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"courseId"`
+
+	// Criteria
+	RequireAllLessons     bool        `gorm:"default:true" json:"requireAllLessons"`
+	RequiredAssessmentIDs []uuid.UUID `gorm:"type:jsonb;serializer:json" json:"requiredAssessmentIds"`
+	MinimumGrade          *float64    `gorm:"type:decimal(5,2)" json:"minimumGrade"`        // percentage, 0-100
+	AttendanceThreshold   *float64    `gorm:"type:decimal(5,2)" json:"attendanceThreshold"` // percentage of live sessions attended
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+func (CompletionRule) TableName() string {
+	return "course_completion_rules"
+}
@@ -22,6 +22,7 @@ type Course struct {
 	Duration    int            `gorm:"type:integer;default:0" json:"duration"` // in minutes
 	Price       float64        `gorm:"type:decimal(10,2);default:0" json:"price"`
 	Currency    string         `gorm:"type:varchar(3);default:'USD'" json:"currency"`
+	DisplayPrice *DisplayPrice `gorm:"-" json:"displayPrice,omitempty"` // resolved per request
 	
 	// Course content
 	ThumbnailURL string        `gorm:"type:varchar(500)" json:"thumbnailUrl"`
@@ -31,6 +32,15 @@ type Course struct {
 	Status      CourseStatus   `gorm:"type:varchar(20);default:'draft'" json:"status"`
 	IsPublished bool           `gorm:"default:false" json:"isPublished"`
 	PublishedAt *time.Time    `gorm:"type:timestamp" json:"publishedAt"`
+	PublishAt   *time.Time    `gorm:"type:timestamp;index" json:"publishAt,omitempty"` // scheduled publish time for drafts
+	
+	// Moderation: a suspended course is hidden from the catalog and closed to new enrollments
+	SuspendedAt      *time.Time `gorm:"type:timestamp;index" json:"suspendedAt,omitempty"`
+	ModerationReason string     `gorm:"type:text" json:"moderationReason,omitempty"`
+	
+	// Reviews, kept in step with course_reviews
+	RatingAverage float64    `gorm:"type:decimal(3,2);default:0" json:"ratingAverage"`
+	ReviewCount   int        `gorm:"type:integer;default:0" json:"reviewCount"`
 	
 	// Enrollment settings
 	MaxStudents int            `gorm:"type:integer;default:0" json:"maxStudents"` // 0 = unlimited
@@ -40,12 +50,17 @@ type Course struct {
 	MetaTitle       string     `gorm:"type:varchar(255)" json:"metaTitle"`
 	MetaDescription string     `gorm:"type:varchar(500)" json:"metaDescription"`
 	Tags            []CourseTag `gorm:"foreignKey:CourseID" json:"tags"`
+	Tiers           []CourseTier `gorm:"foreignKey:CourseID" json:"tiers,omitempty"` // subscription tiers that include the course
 	
 	// Relationships
 	InstructorID uuid.UUID   `gorm:"type:uuid;not null" json:"instructorId"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index" json:"organizationId,omitempty"` // tenant its events are delivered to
 	Modules     []Module    `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"modules"`
 	Prerequisites []Prerequisite `gorm:"foreignKey:CourseID" json:"prerequisites"`
 	
+	// Optimistic locking
+	Version   int            `gorm:"type:integer;not null;default:1" json:"version"`
+	
 	// Timestamps
 	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
@@ -83,6 +98,9 @@ type Module struct {
 	// Content
 	Lessons []Lesson `gorm:"foreignKey:ModuleID;constraint:OnDelete:CASCADE" json:"lessons"`
 	
+	// Optimistic locking
+	Version   int            `gorm:"type:integer;not null;default:1" json:"version"`
+	
 	// Timestamps
 	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
 	UpdatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
@@ -96,6 +114,11 @@ type Lesson struct {
 	Title       string         `gorm:"type:varchar(255);not null" json:"title"`
 	Description string         `gorm:"type:text" json:"description"`
 	Content     string         `gorm:"type:text" json:"content"`
+	ContentFormat ContentFormat `gorm:"type:varchar(20);default:'markdown'" json:"contentFormat"`
+	// RenderedContent is Content rendered to sanitized HTML; clients display
+	// this rather than rendering Content themselves
+	RenderedContent string      `gorm:"type:text" json:"renderedContent"`
+	RenderHash      string      `gorm:"type:varchar(64)" json:"-"`
 	OrderIndex  int            `gorm:"type:integer;not null" json:"order_index"`
 	Duration    int            `gorm:"type:integer;default:0" json:"duration"` // in minutes
 	
@@ -103,6 +126,12 @@ type Lesson struct {
 	LessonType  LessonType     `gorm:"type:varchar(20);default:'video'" json:"lessonType"`
 	VideoURL    string         `gorm:"type:varchar(500)" json:"videoUrl"`
 	DownloadURL string         `gorm:"type:varchar(500)" json:"downloadUrl"`
+
+	// UnlockConditions must all hold before a student may open the lesson
+	UnlockConditions []LessonUnlockCondition `gorm:"type:jsonb;serializer:json" json:"unlockConditions"`
+	
+	// Optimistic locking
+	Version     int            `gorm:"type:integer;not null;default:1" json:"version"`
 	
 	// Timestamps
 	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
@@ -121,6 +150,14 @@ const (
 	LessonTypeLive     LessonType = "live"
 )
 
+// ContentFormat is the markup a lesson's Content is written in
+type ContentFormat string
+
+const (
+	ContentFormatMarkdown ContentFormat = "markdown"
+	ContentFormatHTML     ContentFormat = "html"
+)
+
 // CourseTag represents tags for courses
 type CourseTag struct {
 	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -139,6 +176,46 @@ type Prerequisite struct {
 	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
 }
 
+// CoursePermission is a fine-grained capability granted to a course collaborator
+type CoursePermission string
+
+const (
+	PermissionModerateDiscussions CoursePermission = "moderate_discussions"
+	PermissionGradeSubmissions    CoursePermission = "grade_submissions"
+	PermissionManageContent       CoursePermission = "manage_content"
+	PermissionManagePricing       CoursePermission = "manage_pricing"
+)
+
+// AllCoursePermissions lists every permission a collaborator can be granted
+var AllCoursePermissions = []CoursePermission{
+	PermissionModerateDiscussions,
+	PermissionGradeSubmissions,
+	PermissionManageContent,
+	PermissionManagePricing,
+}
+
+// CourseCollaborator grants a user a set of permissions on a course
+type CourseCollaborator struct {
+	ID          uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID    uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_course_collaborator" json:"courseId"`
+	UserID      uuid.UUID          `gorm:"type:uuid;not null;uniqueIndex:idx_course_collaborator;index" json:"userId"`
+	Permissions []CoursePermission `gorm:"type:jsonb;serializer:json" json:"permissions"`
+	GrantedBy   uuid.UUID          `gorm:"type:uuid;not null" json:"grantedBy"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+// HasPermission reports whether the collaborator was granted perm
+func (cc CourseCollaborator) HasPermission(perm CoursePermission) bool {
+	for _, p := range cc.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
 // Table names
 func (Course) TableName() string {
 	return "courses"
@@ -159,3 +236,7 @@ func (CourseTag) TableName() string {
 func (Prerequisite) TableName() string {
 	return "course_prerequisites"
 }
+
+func (CourseCollaborator) TableName() string {
+	return "course_collaborators"
+}
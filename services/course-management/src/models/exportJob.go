@@ -0,0 +1,18 @@
+package models
+
+import "github.com/modex/shared/exports"
+
+// ExportJob is an export too slow to build within a request. The model is
+// shared with the other Go services so jobs serialize the same everywhere.
+type ExportJob = exports.Job
+
+// ExportStatus tracks an export job
+type ExportStatus = exports.Status
+
+const (
+	ExportPending   = exports.Pending
+	ExportRunning   = exports.Running
+	ExportSucceeded = exports.Succeeded
+	ExportFailed    = exports.Failed
+	ExportExpired   = exports.Expired
+)
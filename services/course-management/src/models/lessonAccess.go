@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UnlockConditionType identifies what a student must do to unlock a lesson
+type UnlockConditionType string
+
+const (
+	// UnlockPreviousLessonCompleted requires the lesson just before this one,
+	// in course order, to be completed
+	UnlockPreviousLessonCompleted UnlockConditionType = "previous_lesson_completed"
+	// UnlockLessonCompleted requires LessonID to be completed
+	UnlockLessonCompleted UnlockConditionType = "lesson_completed"
+	// UnlockAssessmentPassed requires a graded attempt at AssessmentID scoring
+	// at least MinScore percent, or passing the assessment when MinScore is unset
+	UnlockAssessmentPassed UnlockConditionType = "assessment_passed"
+)
+
+// LessonUnlockCondition is one condition on opening a lesson
+type LessonUnlockCondition struct {
+	Type         UnlockConditionType `json:"type"`
+	LessonID     *uuid.UUID          `json:"lessonId,omitempty"`
+	AssessmentID *uuid.UUID          `json:"assessmentId,omitempty"`
+	MinScore     *float64            `json:"minScore,omitempty"` // percentage, 0-100
+}
+
+// LessonCompletion records that a student finished a lesson
+type LessonCompletion struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_lesson_completion_user_lesson" json:"userId"`
+	LessonID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_lesson_completion_user_lesson" json:"lessonId"`
+	CourseID    uuid.UUID `gorm:"type:uuid;not null;index" json:"courseId"`
+	CompletedAt time.Time `gorm:"type:timestamp;not null" json:"completedAt"`
+}
+
+func (LessonCompletion) TableName() string {
+	return "lesson_completions"
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LinkCheckReport is the latest result of checking a course's media URLs and
+// the links in its lesson content. Each run replaces the previous report.
+type LinkCheckReport struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID    uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex" json:"courseId"`
+	TotalLinks  int          `gorm:"type:integer;not null;default:0" json:"totalLinks"`
+	BrokenCount int          `gorm:"type:integer;not null;default:0;index" json:"brokenCount"`
+	Broken      []BrokenLink `gorm:"type:jsonb;serializer:json" json:"broken"`
+	CheckedAt   time.Time    `gorm:"type:timestamp;not null" json:"checkedAt"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+// BrokenLink is one URL that failed its check, with where it is used
+type BrokenLink struct {
+	URL         string     `json:"url"`
+	Field       string     `json:"field"` // e.g. thumbnailUrl, lesson.videoUrl, lesson.content
+	LessonID    *uuid.UUID `json:"lessonId,omitempty"`
+	LessonTitle string     `json:"lessonTitle,omitempty"`
+	StatusCode  int        `json:"statusCode,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+func (LinkCheckReport) TableName() string {
+	return "link_check_reports"
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourseFlag is a user's report that a course breaks platform policy
+type CourseFlag struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"courseId"`
+	ReporterID uuid.UUID  `gorm:"type:uuid;not null" json:"reporterId"`
+	Reason     FlagReason `gorm:"type:varchar(30);not null" json:"reason"`
+	Details    string     `gorm:"type:text" json:"details"`
+	Status     FlagStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+
+	ResolvedBy *uuid.UUID `gorm:"type:uuid" json:"resolvedBy,omitempty"`
+	ResolvedAt *time.Time `gorm:"type:timestamp" json:"resolvedAt,omitempty"`
+	Resolution string     `gorm:"type:text" json:"resolution,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+}
+
+// FlagReason categorises a course report
+type FlagReason string
+
+const (
+	FlagReasonSpam          FlagReason = "spam"
+	FlagReasonInappropriate FlagReason = "inappropriate"
+	FlagReasonCopyright     FlagReason = "copyright"
+	FlagReasonMisleading    FlagReason = "misleading"
+	FlagReasonOther         FlagReason = "other"
+)
+
+// FlagStatus tracks a report through moderation
+type FlagStatus string
+
+const (
+	FlagStatusOpen      FlagStatus = "open"
+	FlagStatusActioned  FlagStatus = "actioned"  // closed by a suspension or forced unpublish
+	FlagStatusDismissed FlagStatus = "dismissed" // reviewed, no action taken
+)
+
+// ModerationAction records an admin intervention on a course
+type ModerationAction struct {
+	ID       uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID uuid.UUID            `gorm:"type:uuid;not null;index" json:"courseId"`
+	AdminID  uuid.UUID            `gorm:"type:uuid;not null" json:"adminId"`
+	Action   ModerationActionType `gorm:"type:varchar(20);not null" json:"action"`
+	Reason   string               `gorm:"type:text" json:"reason"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+}
+
+// ModerationActionType names an admin intervention
+type ModerationActionType string
+
+const (
+	ModerationSuspend      ModerationActionType = "suspend"
+	ModerationReinstate    ModerationActionType = "reinstate"
+	ModerationUnpublish    ModerationActionType = "unpublish"
+	ModerationDismissFlags ModerationActionType = "dismiss_flags"
+)
+
+func (CourseFlag) TableName() string {
+	return "course_flags"
+}
+
+func (ModerationAction) TableName() string {
+	return "moderation_actions"
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OfficeHourSlot is a block of time an instructor opens for students of a
+// course to book. Booked is kept in step with active bookings so capacity can
+// be checked under the slot's row lock.
+type OfficeHourSlot struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_office_hour_slots_course_start" json:"courseId"`
+	InstructorID uuid.UUID  `gorm:"type:uuid;not null;index" json:"instructorId"`
+	StartsAt     time.Time  `gorm:"type:timestamp;not null;index:idx_office_hour_slots_course_start" json:"startsAt"`
+	EndsAt       time.Time  `gorm:"type:timestamp;not null" json:"endsAt"`
+	Capacity     int        `gorm:"not null;default:1" json:"capacity"`
+	Booked       int        `gorm:"not null;default:0" json:"booked"`
+	Location     string     `gorm:"type:varchar(255)" json:"location,omitempty"`
+	MeetingURL   string     `gorm:"type:varchar(500)" json:"meetingUrl,omitempty"`
+	Notes        string     `gorm:"type:text" json:"notes,omitempty"`
+	CancelledAt  *time.Time `gorm:"type:timestamp" json:"cancelledAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+func (OfficeHourSlot) TableName() string {
+	return "office_hour_slots"
+}
+
+// OfficeHourBooking is a student's place in an office-hours slot. A student
+// who cancels and books again reuses the same row.
+type OfficeHourBooking struct {
+	ID             uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SlotID         uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_office_hour_bookings_slot_user" json:"slotId"`
+	CourseID       uuid.UUID     `gorm:"type:uuid;not null;index" json:"courseId"`
+	UserID         uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_office_hour_bookings_slot_user;index" json:"userId"`
+	Status         BookingStatus `gorm:"type:varchar(20);not null;default:'booked'" json:"status"`
+	Note           string        `gorm:"type:text" json:"note,omitempty"`
+	ReminderSentAt *time.Time    `gorm:"type:timestamp" json:"reminderSentAt,omitempty"`
+	CancelledAt    *time.Time    `gorm:"type:timestamp" json:"cancelledAt,omitempty"`
+
+	Slot *OfficeHourSlot `gorm:"foreignKey:SlotID" json:"slot,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+// BookingStatus tracks an office-hours booking
+type BookingStatus string
+
+const (
+	BookingBooked    BookingStatus = "booked"
+	BookingCancelled BookingStatus = "cancelled" // by the student, or with the slot
+)
+
+func (OfficeHourBooking) TableName() string {
+	return "office_hour_bookings"
+}
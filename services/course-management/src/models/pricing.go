@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CoursePrice is an explicit price for a course in one currency, optionally
+// limited to a region (ISO 3166-1 alpha-2). An empty region applies to every
+// region that has no more specific price in that currency.
+type CoursePrice struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_course_price_currency_region" json:"courseId"`
+	Currency string    `gorm:"type:varchar(3);not null;uniqueIndex:idx_course_price_currency_region" json:"currency"`
+	Region   string    `gorm:"type:varchar(2);not null;default:'';uniqueIndex:idx_course_price_currency_region" json:"region"`
+	Amount   float64   `gorm:"type:decimal(10,2);not null" json:"amount"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+// PriceSource explains where a display price came from
+type PriceSource string
+
+const (
+	PriceSourceRegional  PriceSource = "regional"  // CoursePrice for the currency and region
+	PriceSourceCurrency  PriceSource = "currency"  // CoursePrice for the currency, any region
+	PriceSourceBase      PriceSource = "base"      // the course's own price and currency
+	PriceSourceConverted PriceSource = "converted" // base price converted at the current rate
+)
+
+// DisplayPrice is the price shown to a particular visitor
+type DisplayPrice struct {
+	Amount   float64     `json:"amount"`
+	Currency string      `json:"currency"`
+	Region   string      `json:"region,omitempty"`
+	Source   PriceSource `json:"source"`
+}
+
+func (CoursePrice) TableName() string {
+	return "course_prices"
+}
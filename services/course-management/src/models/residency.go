@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationResidency pins an organization's data to a region. The record
+// itself lives in the primary database so every service can find it.
+type OrganizationResidency struct {
+	OrganizationID uuid.UUID  `gorm:"type:uuid;primary_key" json:"organizationId"`
+	Region         string     `gorm:"type:varchar(10);not null;index" json:"region"`
+	UpdatedBy      *uuid.UUID `gorm:"type:uuid" json:"updatedBy,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+func (OrganizationResidency) TableName() string {
+	return "organization_residency"
+}
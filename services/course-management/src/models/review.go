@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CourseReview is a rating and write-up from a student who completed the
+// course. Each student may review a course once.
+type CourseReview struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_course_reviews_course_user;index" json:"courseId"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_course_reviews_course_user" json:"userId"`
+	Rating   int       `gorm:"type:smallint;not null" json:"rating"` // 1-5
+	Title    string    `gorm:"type:varchar(200)" json:"title"`
+	Body     string    `gorm:"type:text" json:"body"`
+
+	EditableUntil time.Time `gorm:"type:timestamp;not null" json:"editableUntil"`
+
+	CreatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+func (CourseReview) TableName() string {
+	return "course_reviews"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SeatReservation holds a seat in a capacity-limited course while a student
+// checks out. Postgres is the record; Redis keeps the live counters. A
+// student holds at most one seat per course at a time.
+type SeatReservation struct {
+	ID          uuid.UUID         `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID    uuid.UUID         `gorm:"type:uuid;not null;index:idx_seat_reservations_course_status;uniqueIndex:idx_seat_reservations_held_user,where:status = 'held'" json:"courseId"`
+	UserID      string            `gorm:"type:varchar(64);not null;index;uniqueIndex:idx_seat_reservations_held_user,where:status = 'held'" json:"userId"`
+	Status      ReservationStatus `gorm:"type:varchar(20);not null;default:'held';index:idx_seat_reservations_course_status" json:"status"`
+	ExpiresAt   time.Time         `gorm:"type:timestamp;not null;index" json:"expiresAt"`
+	ConfirmedAt *time.Time        `gorm:"type:timestamp" json:"confirmedAt,omitempty"`
+	ReleasedAt  *time.Time        `gorm:"type:timestamp" json:"releasedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+// ReservationStatus tracks a seat reservation
+type ReservationStatus string
+
+const (
+	ReservationHeld      ReservationStatus = "held"      // awaiting checkout
+	ReservationConfirmed ReservationStatus = "confirmed" // student enrolled
+	ReservationReleased  ReservationStatus = "released"  // checkout abandoned or student withdrew
+	ReservationExpired   ReservationStatus = "expired"   // hold timed out
+)
+
+func (SeatReservation) TableName() string {
+	return "seat_reservations"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionTier is a membership plan that unlocks a set of courses.
+// Tiers are ranked: a subscriber to a tier is entitled to every course
+// included in that tier or in any lower-ranked active tier.
+type SubscriptionTier struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Key         string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"key"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	Rank        int       `gorm:"type:integer;not null;default:0" json:"rank"`
+	IsActive    bool      `gorm:"default:true" json:"isActive"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+// CourseTier flags a course as included in a subscription tier
+type CourseTier struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_course_tier" json:"course_id"`
+	TierKey  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_course_tier;index" json:"tier"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+}
+
+func (SubscriptionTier) TableName() string {
+	return "subscription_tiers"
+}
+
+func (CourseTier) TableName() string {
+	return "course_tiers"
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourseTranslation holds a course's catalog metadata in one locale. The
+// course's own fields are its default locale (Course.Language).
+type CourseTranslation struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_course_translation_locale" json:"courseId"`
+	Locale   string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_course_translation_locale" json:"locale"`
+
+	Title           string `gorm:"type:varchar(255);not null" json:"title"`
+	Description     string `gorm:"type:text" json:"description"`
+	MetaTitle       string `gorm:"type:varchar(255)" json:"metaTitle"`
+	MetaDescription string `gorm:"type:varchar(500)" json:"metaDescription"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+func (CourseTranslation) TableName() string {
+	return "course_translations"
+}
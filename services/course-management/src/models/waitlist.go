@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistEntry is a student waiting for a seat in a full course. Entries are
+// promoted in the order students joined.
+type WaitlistEntry struct {
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CourseID   uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_waitlist_course_user;index:idx_waitlist_course_status" json:"courseId"`
+	UserID     uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_waitlist_course_user" json:"userId"`
+	Status     WaitlistStatus `gorm:"type:varchar(20);not null;default:'waiting';index:idx_waitlist_course_status" json:"status"`
+	JoinedAt   time.Time      `gorm:"type:timestamp;not null" json:"joinedAt"`
+	PromotedAt *time.Time     `gorm:"type:timestamp" json:"promotedAt,omitempty"`
+	Position   int            `gorm:"-" json:"position,omitempty"` // 1-based place in line while waiting
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updated_at"`
+}
+
+// WaitlistStatus tracks a waitlist entry
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting  WaitlistStatus = "waiting"
+	WaitlistPromoted WaitlistStatus = "promoted" // offered a freed seat
+	WaitlistLeft     WaitlistStatus = "left"
+)
+
+func (WaitlistEntry) TableName() string {
+	return "course_waitlist"
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupCalendarRoutes configures users' office-hours bookings and calendar feeds
+func SetupCalendarRoutes(router *gin.RouterGroup) {
+	officeHoursHandler := handlers.NewOfficeHoursHandler()
+
+	protected := router.Group("")
+	protected.Use(middleware.AuthRequired())
+	{
+		protected.GET("/office-hours/bookings/me", officeHoursHandler.GetMyBookings)
+		protected.GET("/calendar/me/events", officeHoursHandler.GetMyCalendar)
+	}
+
+	internal := router.Group("/internal/calendar")
+	internal.Use(middleware.ServiceAuthRequired())
+	{
+		internal.GET("/users/:userId/events", officeHoursHandler.GetUserCalendar)
+	}
+}
@@ -9,18 +9,57 @@ import (
 // SetupCourseRoutes configures course-related routes
 func SetupCourseRoutes(router *gin.RouterGroup) {
 	courseHandler := handlers.NewCourseHandler()
+	collaboratorHandler := handlers.NewCollaboratorHandler()
+	batchEnrollmentHandler := handlers.NewBatchEnrollmentHandler()
+	completionHandler := handlers.NewCompletionHandler()
+	translationHandler := handlers.NewTranslationHandler()
+	pricingHandler := handlers.NewPricingHandler()
+	tierHandler := handlers.NewTierHandler()
+	moderationHandler := handlers.NewModerationHandler()
+	linkCheckHandler := handlers.NewLinkCheckHandler()
+	publishScheduleHandler := handlers.NewPublishScheduleHandler()
+	waitlistHandler := handlers.NewWaitlistHandler()
+	reviewHandler := handlers.NewReviewHandler()
+	seatHandler := handlers.NewSeatHandler()
+	officeHoursHandler := handlers.NewOfficeHoursHandler()
+	exportHandler := handlers.NewExportHandler()
+	editLockHandler := handlers.NewEditLockHandler()
+	outlineHandler := handlers.NewOutlineHandler()
 	
-	// Public routes
-	courses := router.Group("/courses")
+	// Public routes; signed-in callers are identified when they send a token
+	courses := router.Group("/courses", middleware.OptionalAuth())
 	{
 		courses.GET("", middleware.Pagination(), courseHandler.GetCourses)
 		courses.GET("/:id", middleware.ValidateUUID("id"), courseHandler.GetCourse)
+		courses.GET("/:id/outline", middleware.ValidateUUID("id"), outlineHandler.GetCourseOutline)
+		courses.GET("/:id/completion-rules", middleware.ValidateUUID("id"), completionHandler.GetCompletionRules)
+		courses.GET("/:id/translations", middleware.ValidateUUID("id"), translationHandler.GetTranslations)
+		courses.GET("/:id/prices", middleware.ValidateUUID("id"), pricingHandler.GetPrices)
+		courses.GET("/:id/reviews", middleware.ValidateUUID("id"), middleware.Pagination(), reviewHandler.GetReviews)
+		courses.GET("/:id/office-hours", middleware.ValidateUUID("id"), officeHoursHandler.GetSlots)
 	}
 
 	// Protected routes (require authentication)
 	protected := courses.Group("")
-	protected.Use(middleware.AuthRequired())
+	protected.Use(middleware.AuthRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
 	{
+		// Any signed-in user may report a course
+		protected.POST("/:id/flags", middleware.ValidateUUID("id"), moderationHandler.FlagCourse)
+
+		// Waitlists for full courses
+		protected.POST("/:id/waitlist", middleware.ValidateUUID("id"), waitlistHandler.JoinWaitlist)
+		protected.DELETE("/:id/waitlist", middleware.ValidateUUID("id"), waitlistHandler.LeaveWaitlist)
+		protected.GET("/:id/waitlist/me", middleware.ValidateUUID("id"), waitlistHandler.GetMyWaitlistEntry)
+
+		// Reviews from students who completed the course
+		protected.POST("/:id/reviews", middleware.ValidateUUID("id"), reviewHandler.CreateReview)
+		protected.PUT("/:id/reviews/:reviewId", middleware.ValidateUUID("id"), reviewHandler.UpdateReview)
+		protected.DELETE("/:id/reviews/:reviewId", middleware.ValidateUUID("id"), reviewHandler.DeleteReview)
+
+		// Office-hours bookings for enrolled students
+		protected.POST("/:id/office-hours/:slotId/bookings", middleware.ValidateUUID("id"), officeHoursHandler.BookSlot)
+		protected.DELETE("/:id/office-hours/:slotId/bookings", middleware.ValidateUUID("id"), officeHoursHandler.CancelBooking)
+
 		// Instructor-only routes
 		instructor := protected.Group("")
 		instructor.Use(middleware.InstructorRequired())
@@ -29,6 +68,67 @@ func SetupCourseRoutes(router *gin.RouterGroup) {
 			instructor.PUT("/:id", middleware.ValidateUUID("id"), courseHandler.UpdateCourse)
 			instructor.DELETE("/:id", middleware.ValidateUUID("id"), courseHandler.DeleteCourse)
 			instructor.POST("/:id/publish", middleware.ValidateUUID("id"), courseHandler.PublishCourse)
+			instructor.PUT("/:id/publish-schedule", middleware.ValidateUUID("id"), publishScheduleHandler.SchedulePublish)
+			instructor.DELETE("/:id/publish-schedule", middleware.ValidateUUID("id"), publishScheduleHandler.CancelScheduledPublish)
+
+			// Collaborator permissions (owner only)
+			instructor.GET("/:id/collaborators", middleware.ValidateUUID("id"), collaboratorHandler.GetCollaborators)
+			instructor.PUT("/:id/collaborators/:userId", middleware.ValidateUUID("id"), collaboratorHandler.SetCollaborator)
+			instructor.DELETE("/:id/collaborators/:userId", middleware.ValidateUUID("id"), collaboratorHandler.RemoveCollaborator)
+
+			// Bulk enrollment import
+			instructor.POST("/:id/enroll-batch", middleware.ValidateUUID("id"), batchEnrollmentHandler.EnrollBatch)
+			instructor.GET("/:id/enroll-batch/:jobId", middleware.ValidateUUID("id"), batchEnrollmentHandler.GetBatchJob)
+
+			// Completion rules
+			instructor.PUT("/:id/completion-rules", middleware.ValidateUUID("id"), completionHandler.UpdateCompletionRules)
+
+			// Translations
+			instructor.PUT("/:id/translations/:locale", middleware.ValidateUUID("id"), translationHandler.SaveTranslation)
+			instructor.DELETE("/:id/translations/:locale", middleware.ValidateUUID("id"), translationHandler.DeleteTranslation)
+
+			// Regional pricing
+			instructor.PUT("/:id/prices", middleware.ValidateUUID("id"), pricingHandler.SetPrices)
+
+			// Subscription tiers
+			instructor.PUT("/:id/tiers", middleware.ValidateUUID("id"), tierHandler.SetCourseTiers)
+
+			// Broken link reports
+			instructor.GET("/:id/link-report", middleware.ValidateUUID("id"), linkCheckHandler.GetLinkReport)
+			instructor.POST("/:id/link-report", middleware.ValidateUUID("id"), linkCheckHandler.CheckLinks)
+
+			// Waitlist
+			instructor.GET("/:id/waitlist", middleware.ValidateUUID("id"), waitlistHandler.GetWaitlist)
+
+			// Office-hours slots
+			instructor.POST("/:id/office-hours", middleware.ValidateUUID("id"), officeHoursHandler.CreateSlot)
+			instructor.DELETE("/:id/office-hours/:slotId", middleware.ValidateUUID("id"), officeHoursHandler.CancelSlot)
+			instructor.GET("/:id/office-hours/:slotId/bookings", middleware.ValidateUUID("id"), officeHoursHandler.GetSlotBookings)
+
+			// Course package export, built in the background
+			instructor.POST("/:id/exports", middleware.ValidateUUID("id"), exportHandler.CreateCoursePackageExport)
+
+			// Advisory edit locks for co-authors
+			instructor.GET("/:id/locks", middleware.ValidateUUID("id"), editLockHandler.GetEditLocks)
+			instructor.PUT("/:id/locks/:resourceType/:resourceId", middleware.ValidateUUID("id"), editLockHandler.AcquireEditLock)
+			instructor.POST("/:id/locks/:resourceType/:resourceId/heartbeat", middleware.ValidateUUID("id"), editLockHandler.HeartbeatEditLock)
+			instructor.DELETE("/:id/locks/:resourceType/:resourceId", middleware.ValidateUUID("id"), editLockHandler.ReleaseEditLock)
 		}
 	}
+
+	// Internal routes for other services
+	internal := router.Group("/internal/courses")
+	internal.Use(middleware.ServiceAuthRequired())
+	{
+		internal.GET("/:id/permissions/check", middleware.ValidateUUID("id"), collaboratorHandler.CheckPermission)
+		internal.POST("/:id/completion/evaluate", middleware.ValidateUUID("id"), completionHandler.EvaluateCompletion)
+		internal.GET("/:id/entitlement", middleware.ValidateUUID("id"), tierHandler.CheckEntitlement)
+		internal.GET("/:id/availability", middleware.ValidateUUID("id"), moderationHandler.GetAvailability)
+		internal.GET("/:id/enrollment-check", middleware.ValidateUUID("id"), waitlistHandler.CheckEnrollment)
+		internal.POST("/:id/waitlist/promote", middleware.ValidateUUID("id"), waitlistHandler.PromoteWaitlist)
+		internal.GET("/:id/seats", middleware.ValidateUUID("id"), seatHandler.GetSeats)
+		internal.POST("/:id/seats/reserve", middleware.ValidateUUID("id"), seatHandler.ReserveSeat)
+		internal.POST("/:id/seats/confirm", middleware.ValidateUUID("id"), seatHandler.ConfirmSeat)
+		internal.POST("/:id/seats/release", middleware.ValidateUUID("id"), seatHandler.ReleaseSeat)
+	}
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupExportRoutes configures status polling and downloads for background exports
+func SetupExportRoutes(router *gin.RouterGroup) {
+	exportHandler := handlers.NewExportHandler()
+
+	exports := router.Group("/exports")
+	exports.Use(middleware.AuthRequired())
+	{
+		exports.GET("/:jobId", exportHandler.GetExport)
+		exports.GET("/:jobId/download", exportHandler.DownloadExport)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+)
+
+// SetupFeedRoutes configures public syndication feeds of the catalog
+func SetupFeedRoutes(router *gin.RouterGroup) {
+	feedHandler := handlers.NewFeedHandler()
+
+	feeds := router.Group("/feeds")
+	{
+		feeds.GET("/courses.atom", feedHandler.GetCourseFeed)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/modex/course-management/src/config"
 	"github.com/modex/course-management/src/middleware"
+	"net/http"
 	"time"
 )
 
@@ -12,6 +13,7 @@ func SetupRoutes(router *gin.Engine) {
 	router.Use(middleware.CORS())
 	router.Use(middleware.RequestID())
 	router.Use(middleware.RateLimit())
+	router.Use(middleware.Compress())
 
 	setupHealthRoutes(router)
 
@@ -20,6 +22,15 @@ func SetupRoutes(router *gin.Engine) {
 		SetupCourseRoutes(api)
 		SetupModuleRoutes(api)
 		SetupLessonRoutes(api)
+		SetupProvisioningRoutes(api)
+		SetupPrivacyRoutes(api)
+		SetupTierRoutes(api)
+		SetupModerationRoutes(api)
+		SetupResidencyRoutes(api)
+		SetupCalendarRoutes(api)
+		SetupExportRoutes(api)
+		SetupFeedRoutes(api)
+		SetupSupportRoutes(api)
 	}
 }
 
@@ -39,23 +50,38 @@ func healthCheck(c *gin.Context) {
 	})
 }
 
+// readinessCheck pings the database and Redis. The service is ready while
+// every required dependency is up; with DEGRADED_START, Redis being down
+// reports "degraded" but still ready, since reads fall back to the database.
 func readinessCheck(c *gin.Context) {
-	dbStatus := "healthy"
-	if err := config.DB.Raw("SELECT 1").Error; err != nil {
-		dbStatus = "unhealthy"
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	sqlDB, err := config.DB.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
 	}
+	config.SetDependencyState("database", true, err)
 
-	redisStatus := "healthy"
-	if _, err := config.RedisClient.Ping(context.Background()).Result(); err != nil {
-		redisStatus = "unhealthy"
+	if config.RedisClient != nil {
+		_, err := config.RedisClient.Ping(ctx).Result()
+		config.SetDependencyState("redis", !config.DegradedStartEnabled(), err)
 	}
 
-	c.JSON(200, gin.H{
-		"status": "ready",
-		"checks": gin.H{
-			"database": dbStatus,
-			"redis":    redisStatus,
-		},
+	status, code := "ready", http.StatusOK
+	checks := config.DependencyStates()
+	for _, check := range checks {
+		switch {
+		case check.Status == config.DependencyDown:
+			status, code = "not ready", http.StatusServiceUnavailable
+		case check.Status == config.DependencyDegraded && code == http.StatusOK:
+			status = "degraded"
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"status":    status,
+		"checks":    checks,
 		"timestamp": time.Now().UTC(),
 	})
 }
@@ -10,16 +10,23 @@ import (
 func SetupLessonRoutes(router *gin.RouterGroup) {
 	lessonHandler := handlers.NewLessonHandler()
 	
-	// Public routes
-	lessons := router.Group("/lessons")
+	// Public routes; signed-in callers are identified when they send a token
+	lessons := router.Group("/lessons", middleware.OptionalAuth())
 	{
 		lessons.GET("/:id", middleware.ValidateUUID("id"), lessonHandler.GetLesson)
 		lessons.GET("/module/:module_id", middleware.ValidateUUID("module_id"), lessonHandler.GetLessonsByModule)
 	}
 
+	// Students record lessons they finish
+	learner := lessons.Group("")
+	learner.Use(middleware.AuthRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
+	{
+		learner.POST("/:id/complete", middleware.ValidateUUID("id"), lessonHandler.CompleteLesson)
+	}
+
 	// Protected routes
 	protected := lessons.Group("")
-	protected.Use(middleware.AuthRequired(), middleware.InstructorRequired())
+	protected.Use(middleware.AuthRequired(), middleware.InstructorRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
 	{
 		protected.POST("", lessonHandler.CreateLesson)
 		protected.PUT("/:id", middleware.ValidateUUID("id"), lessonHandler.UpdateLesson)
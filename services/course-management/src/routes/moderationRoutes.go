@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupModerationRoutes configures admin course moderation endpoints
+func SetupModerationRoutes(router *gin.RouterGroup) {
+	moderationHandler := handlers.NewModerationHandler()
+
+	admin := router.Group("/admin/courses")
+	admin.Use(middleware.AuthRequired(), middleware.AdminRequired())
+	{
+		admin.GET("/flagged", middleware.Pagination(), moderationHandler.GetFlaggedCourses)
+		admin.GET("/:id/flags", middleware.ValidateUUID("id"), moderationHandler.GetCourseFlags)
+		admin.POST("/:id/flags/dismiss", middleware.ValidateUUID("id"), moderationHandler.DismissFlags)
+		admin.POST("/:id/suspend", middleware.ValidateUUID("id"), moderationHandler.SuspendCourse)
+		admin.POST("/:id/reinstate", middleware.ValidateUUID("id"), moderationHandler.ReinstateCourse)
+		admin.POST("/:id/unpublish", middleware.ValidateUUID("id"), moderationHandler.ForceUnpublishCourse)
+	}
+}
@@ -19,7 +19,7 @@ func SetupModuleRoutes(router *gin.RouterGroup) {
 
 	// Protected routes
 	protected := modules.Group("")
-	protected.Use(middleware.AuthRequired(), middleware.InstructorRequired())
+	protected.Use(middleware.AuthRequired(), middleware.InstructorRequired(), middleware.Idempotency(middleware.DefaultIdempotencyTTL))
 	{
 		protected.POST("", moduleHandler.CreateModule)
 		protected.PUT("/:id", middleware.ValidateUUID("id"), moduleHandler.UpdateModule)
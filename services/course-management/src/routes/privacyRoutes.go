@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupPrivacyRoutes configures GDPR export and erasure endpoints for admins
+func SetupPrivacyRoutes(router *gin.RouterGroup) {
+	privacyHandler := handlers.NewPrivacyHandler()
+	exportHandler := handlers.NewExportHandler()
+
+	privacy := router.Group("/privacy")
+	privacy.Use(middleware.AuthRequired(), middleware.AdminRequired())
+	{
+		privacy.GET("/export", privacyHandler.ExportUserData)
+		privacy.POST("/exports", exportHandler.CreateUserDataExport)
+		privacy.DELETE("/user/:id", middleware.ValidateUUID("id"), privacyHandler.EraseUserData)
+	}
+}
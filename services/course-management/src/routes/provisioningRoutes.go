@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupProvisioningRoutes configures identity lifecycle hooks called by the identity layer
+func SetupProvisioningRoutes(router *gin.RouterGroup) {
+	provisioningHandler := handlers.NewProvisioningHandler()
+
+	provisioning := router.Group("/internal/provisioning")
+	provisioning.Use(middleware.ServiceAuthRequired())
+	{
+		provisioning.POST("/events", provisioningHandler.HandleProvisioningEvent)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupResidencyRoutes configures organization data residency endpoints
+func SetupResidencyRoutes(router *gin.RouterGroup) {
+	residencyHandler := handlers.NewResidencyHandler()
+
+	admin := router.Group("/admin/organizations")
+	admin.Use(middleware.AuthRequired(), middleware.AdminRequired())
+	{
+		admin.GET("/:orgId/residency", residencyHandler.GetResidency)
+		admin.PUT("/:orgId/residency", residencyHandler.SetResidency)
+	}
+
+	internal := router.Group("/internal/organizations")
+	internal.Use(middleware.ServiceAuthRequired())
+	{
+		internal.GET("/:orgId/residency", residencyHandler.GetResidency)
+	}
+}
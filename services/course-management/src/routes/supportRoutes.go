@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupSupportRoutes configures lookups used by internal support tooling
+func SetupSupportRoutes(router *gin.RouterGroup) {
+	idResolutionHandler := handlers.NewIDResolutionHandler()
+
+	support := router.Group("/internal/resolve")
+	support.Use(middleware.ServiceAuthRequired())
+	{
+		support.GET("/:id", idResolutionHandler.ResolveID)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/modex/course-management/src/handlers"
+	"github.com/modex/course-management/src/middleware"
+)
+
+// SetupTierRoutes configures subscription tier management endpoints
+func SetupTierRoutes(router *gin.RouterGroup) {
+	tierHandler := handlers.NewTierHandler()
+
+	tiers := router.Group("/tiers")
+	{
+		tiers.GET("", tierHandler.GetTiers)
+	}
+
+	admin := tiers.Group("")
+	admin.Use(middleware.AuthRequired(), middleware.AdminRequired())
+	{
+		admin.POST("", tierHandler.CreateTier)
+		admin.PUT("/:key", tierHandler.UpdateTier)
+		admin.DELETE("/:key", tierHandler.DeleteTier)
+	}
+}
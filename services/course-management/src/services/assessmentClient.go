@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AssessmentClient talks to the assessment service on behalf of course-management
+type AssessmentClient struct {
+	serviceClient
+}
+
+// NewAssessmentClient creates a client for ASSESSMENT_SERVICE_URL
+func NewAssessmentClient() *AssessmentClient {
+	return &AssessmentClient{
+		serviceClient: newServiceClient("assessment", "ASSESSMENT_SERVICE_URL", "http://localhost:3004"),
+	}
+}
+
+// ExportUserData returns the assessment service's privacy export for userID
+func (c *AssessmentClient) ExportUserData(ctx context.Context, userID string) (json.RawMessage, error) {
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/internal/privacy/users/"+userID+"/export", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// AssessmentErasure summarises what erasure changed in the assessment service
+type AssessmentErasure struct {
+	DeletedSubmissions    int64 `json:"deletedSubmissions"`
+	AnonymizedAssessments int64 `json:"anonymizedAssessments"`
+}
+
+// EraseUserData erases userID's assessment data. Erasure is idempotent, so a
+// failed call can safely be repeated.
+func (c *AssessmentClient) EraseUserData(ctx context.Context, userID string) (*AssessmentErasure, error) {
+	var resp struct {
+		Data AssessmentErasure `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/internal/privacy/users/"+userID, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// CourseGrade is a percentage presented on a course's grading scale
+type CourseGrade struct {
+	Scale      string   `json:"scale"`
+	Percentage float64  `json:"percentage"`
+	Label      string   `json:"label"`
+	GPAPoints  *float64 `json:"gpaPoints,omitempty"`
+	Passed     bool     `json:"passed"`
+}
+
+// FormatGrade presents percentage on the course's grading scale
+func (c *AssessmentClient) FormatGrade(ctx context.Context, courseID string, percentage float64) (*CourseGrade, error) {
+	query := url.Values{"percentage": {strconv.FormatFloat(percentage, 'f', -1, 64)}}
+	var resp struct {
+		Data CourseGrade `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/internal/grading/courses/"+courseID+"/grade?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// ResolveID returns the assessment records held under id, if any
+func (c *AssessmentClient) ResolveID(ctx context.Context, id string) ([]ResolvedEntity, error) {
+	var resp struct {
+		Data []ResolvedEntity `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/internal/resolve/"+id, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// AssessmentResult is a student's best graded attempt at an assessment
+type AssessmentResult struct {
+	AssessmentID uuid.UUID `json:"assessmentId"`
+	Percentage   float64   `json:"percentage"`
+	Passed       bool      `json:"passed"`
+}
+
+// StudentResults returns the student's best graded result at each of
+// assessmentIDs. Assessments they haven't had graded are left out.
+func (c *AssessmentClient) StudentResults(ctx context.Context, studentID string, assessmentIDs []uuid.UUID) ([]AssessmentResult, error) {
+	ids := make([]string, len(assessmentIDs))
+	for i, id := range assessmentIDs {
+		ids[i] = id.String()
+	}
+	query := url.Values{"assessmentIds": {strings.Join(ids, ",")}}
+	var resp struct {
+		Data []AssessmentResult `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/internal/grading/students/"+studentID+"/results?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// BatchJobStatus is the lifecycle state of a bulk enrollment job
+type BatchJobStatus string
+
+const (
+	BatchJobPending    BatchJobStatus = "pending"
+	BatchJobProcessing BatchJobStatus = "processing"
+	BatchJobCompleted  BatchJobStatus = "completed"
+)
+
+// Per-row outcomes
+const (
+	BatchRowPending  = "pending"
+	BatchRowEnrolled = "enrolled"
+	BatchRowFailed   = "failed"
+	BatchRowSkipped  = "skipped"
+)
+
+const (
+	// MaxBatchEnrollmentRows caps the size of a single import
+	MaxBatchEnrollmentRows = 5000
+	batchJobTTL            = 7 * 24 * time.Hour
+	batchWorkers           = 5
+)
+
+// ErrBatchJobNotFound is returned for unknown or expired job IDs
+var ErrBatchJobNotFound = errors.New("batch enrollment job not found")
+
+// BatchEnrollmentRow is the result for one CSV row
+type BatchEnrollmentRow struct {
+	Row        int    `json:"row"`
+	Identifier string `json:"identifier"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// BatchEnrollmentJob tracks an asynchronous bulk enrollment
+type BatchEnrollmentJob struct {
+	ID          uuid.UUID            `json:"id"`
+	CourseID    uuid.UUID            `json:"courseId"`
+	RequestedBy uuid.UUID            `json:"requestedBy"`
+	Status      BatchJobStatus       `json:"status"`
+	Total       int                  `json:"total"`
+	Succeeded   int                  `json:"succeeded"`
+	Failed      int                  `json:"failed"`
+	Skipped     int                  `json:"skipped"`
+	Rows        []BatchEnrollmentRow `json:"rows"`
+	CreatedAt   time.Time            `json:"createdAt"`
+	CompletedAt *time.Time           `json:"completedAt,omitempty"`
+}
+
+// BatchEnrollmentService imports CSVs of students and enrolls them through the enrollment service
+type BatchEnrollmentService struct {
+	enrollments *EnrollmentClient
+}
+
+// NewBatchEnrollmentService creates a new BatchEnrollmentService
+func NewBatchEnrollmentService() *BatchEnrollmentService {
+	return &BatchEnrollmentService{enrollments: NewEnrollmentClient()}
+}
+
+// ParseEnrollmentCSV reads one student per row. The identifier is taken from an
+// "email", "userId" or "user_id" column when a header is present, otherwise
+// from the first column. Blank lines are ignored.
+func ParseEnrollmentCSV(r io.Reader) ([]BatchEnrollmentRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	column := 0
+	start := 0
+	if len(records) > 0 {
+		for i, name := range records[0] {
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "email", "userid", "user_id", "id":
+				column = i
+				start = 1
+			}
+			if start == 1 {
+				break
+			}
+		}
+	}
+
+	var rows []BatchEnrollmentRow
+	for i := start; i < len(records); i++ {
+		if column >= len(records[i]) {
+			continue
+		}
+		identifier := strings.TrimSpace(records[i][column])
+		if identifier == "" {
+			continue
+		}
+		rows = append(rows, BatchEnrollmentRow{
+			Row:        i + 1,
+			Identifier: identifier,
+			Status:     BatchRowPending,
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("CSV contains no students")
+	}
+	if len(rows) > MaxBatchEnrollmentRows {
+		return nil, fmt.Errorf("CSV exceeds the limit of %d rows", MaxBatchEnrollmentRows)
+	}
+
+	return rows, nil
+}
+
+// StartJob stores a new job and processes it in the background
+func (s *BatchEnrollmentService) StartJob(courseID, requestedBy uuid.UUID, rows []BatchEnrollmentRow) (*BatchEnrollmentJob, error) {
+	job := &BatchEnrollmentJob{
+		ID:          uuid.New(),
+		CourseID:    courseID,
+		RequestedBy: requestedBy,
+		Status:      BatchJobPending,
+		Total:       len(rows),
+		Rows:        rows,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if err := s.saveJob(job); err != nil {
+		return nil, err
+	}
+
+	go s.process(job)
+
+	return job, nil
+}
+
+// GetJob loads a job belonging to courseID
+func (s *BatchEnrollmentService) GetJob(courseID, jobID uuid.UUID) (*BatchEnrollmentJob, error) {
+	data, err := config.RedisClient.Get(config.Ctx, batchJobKey(jobID)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrBatchJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load batch job: %w", err)
+	}
+
+	var job BatchEnrollmentJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("failed to decode batch job: %w", err)
+	}
+	if job.CourseID != courseID {
+		return nil, ErrBatchJobNotFound
+	}
+
+	return &job, nil
+}
+
+func (s *BatchEnrollmentService) process(job *BatchEnrollmentJob) {
+	var mu sync.Mutex
+	job.Status = BatchJobProcessing
+	s.saveJob(job)
+
+	seen := make(map[string]bool)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < batchWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				status, errMsg := s.enrollRow(job.CourseID, job.Rows[i].Identifier)
+
+				mu.Lock()
+				job.Rows[i].Status = status
+				job.Rows[i].Error = errMsg
+				switch status {
+				case BatchRowEnrolled:
+					job.Succeeded++
+				case BatchRowSkipped:
+					job.Skipped++
+				default:
+					job.Failed++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range job.Rows {
+		key := strings.ToLower(job.Rows[i].Identifier)
+		if seen[key] {
+			mu.Lock()
+			job.Rows[i].Status = BatchRowSkipped
+			job.Rows[i].Error = "duplicate row"
+			job.Skipped++
+			mu.Unlock()
+			continue
+		}
+		seen[key] = true
+		indexes <- i
+
+		// Persist progress periodically so pollers see it move
+		if i > 0 && i%100 == 0 {
+			mu.Lock()
+			s.saveJob(job)
+			mu.Unlock()
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	now := time.Now().UTC()
+	job.Status = BatchJobCompleted
+	job.CompletedAt = &now
+	if err := s.saveJob(job); err != nil {
+		utils.Error("Failed to save batch enrollment job", map[string]interface{}{
+			"error": err.Error(),
+			"jobID": job.ID,
+		})
+	}
+
+	utils.Info("Batch enrollment completed", map[string]interface{}{
+		"jobID":     job.ID,
+		"courseID":  job.CourseID,
+		"succeeded": job.Succeeded,
+		"failed":    job.Failed,
+		"skipped":   job.Skipped,
+	})
+}
+
+// enrollRow enrolls one identifier, returning the row status and error message
+func (s *BatchEnrollmentService) enrollRow(courseID uuid.UUID, identifier string) (string, string) {
+	req := EnrollmentRequest{CourseID: courseID.String(), Source: "batch_import"}
+
+	if id, err := uuid.Parse(identifier); err == nil {
+		req.UserID = id.String()
+	} else if addr, err := mail.ParseAddress(identifier); err == nil {
+		req.Email = strings.ToLower(addr.Address)
+	} else {
+		return BatchRowFailed, "not a valid email or user ID"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if err := s.enrollments.CreateEnrollment(ctx, req); err != nil {
+		var serviceErr *ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.StatusCode == 409 {
+			return BatchRowSkipped, "already enrolled"
+		}
+		return BatchRowFailed, err.Error()
+	}
+
+	return BatchRowEnrolled, ""
+}
+
+func (s *BatchEnrollmentService) saveJob(job *BatchEnrollmentJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch job: %w", err)
+	}
+	return config.RedisClient.Set(config.Ctx, batchJobKey(job.ID), data, batchJobTTL).Err()
+}
+
+func batchJobKey(jobID uuid.UUID) string {
+	return config.CacheKey("enroll-batch", jobID.String())
+}
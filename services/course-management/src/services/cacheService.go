@@ -12,12 +12,14 @@ import (
 // CacheService handles Redis caching operations
 type CacheService struct {
 	defaultTTL time.Duration
+	cdn        *CDNPurger
 }
 
 // NewCacheService creates a new CacheService
 func NewCacheService() *CacheService {
 	return &CacheService{
 		defaultTTL: 5 * time.Minute,
+		cdn:        NewCDNPurger(),
 	}
 }
 
@@ -51,10 +53,13 @@ func (s *CacheService) GetCourse(courseID string, dest *models.Course) (bool, er
 	return true, nil
 }
 
-// InvalidateCourse removes a course from cache
+// InvalidateCourse removes a course and its outline from cache, and from the
+// CDN along with the catalog listings that may show it
 func (s *CacheService) InvalidateCourse(courseID string) error {
+	s.cdn.Purge(CourseSurrogateKey(courseID), SurrogateKeyCatalog)
+
 	key := fmt.Sprintf("course:%s", courseID)
-	return config.RedisClient.Del(config.Ctx, key).Err()
+	return config.RedisClient.Del(config.Ctx, key, courseOutlineKeyPrefix+courseID).Err()
 }
 
 // SetCourseList caches a course list
@@ -89,12 +94,16 @@ func (s *CacheService) GetCourseList(filter string, dest *[]models.Course) (bool
 
 // InvalidateCourseList removes a course list from cache
 func (s *CacheService) InvalidateCourseList(filter string) error {
+	s.cdn.Purge(SurrogateKeyCatalog)
+
 	key := fmt.Sprintf("courses:list:%s", filter)
 	return config.RedisClient.Del(config.Ctx, key).Err()
 }
 
 // InvalidateAllCourses removes all course-related cache entries
 func (s *CacheService) InvalidateAllCourses() error {
+	s.cdn.Purge(SurrogateKeyCourses)
+
 	// Use pattern matching to delete all course-related keys
 	keys, err := config.RedisClient.Keys(config.Ctx, "course:*").Result()
 	if err != nil {
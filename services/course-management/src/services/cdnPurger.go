@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/modex/course-management/src/utils"
+)
+
+// Surrogate keys tag cacheable responses so the CDN can purge them by what
+// they contain rather than by URL
+const (
+	// SurrogateKeyCourses tags every response built from course data
+	SurrogateKeyCourses = "courses"
+	// SurrogateKeyCatalog tags catalog listings and feeds
+	SurrogateKeyCatalog = "course-catalog"
+)
+
+// DefaultCDNMaxAge applies when CDN_MAX_AGE is unset. The CDN can hold
+// responses this long because writes purge them.
+const DefaultCDNMaxAge = time.Hour
+
+// CDNMaxAge reads CDN_MAX_AGE as a Go duration
+func CDNMaxAge() time.Duration {
+	if parsed, err := time.ParseDuration(os.Getenv("CDN_MAX_AGE")); err == nil && parsed > 0 {
+		return parsed
+	}
+	return DefaultCDNMaxAge
+}
+
+// CourseSurrogateKey tags responses that include a single course
+func CourseSurrogateKey(courseID string) string {
+	return "course-" + courseID
+}
+
+// CDNPurger purges CDN-cached responses by surrogate key. It speaks the
+// Fastly batch purge API: CDN_PURGE_URL is the service's purge endpoint
+// (https://api.fastly.com/service/<id>/purge) and CDN_PURGE_TOKEN its API
+// token. Without CDN_PURGE_URL purging is a no-op, for environments that
+// don't sit behind a CDN.
+type CDNPurger struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+// NewCDNPurger creates a new CDNPurger
+func NewCDNPurger() *CDNPurger {
+	return &CDNPurger{
+		url:        os.Getenv("CDN_PURGE_URL"),
+		token:      os.Getenv("CDN_PURGE_TOKEN"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Purge asks the CDN to drop everything tagged with any of keys. It runs in
+// the background, since a failed purge only leaves responses to expire on
+// their own and shouldn't fail the write that triggered it.
+func (p *CDNPurger) Purge(keys ...string) {
+	if p.url == "" || len(keys) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		if err := p.purge(ctx, keys); err != nil {
+			utils.Warn("CDN purge failed", map[string]interface{}{
+				"error": err.Error(),
+				"keys":  keys,
+			})
+		}
+	}()
+}
+
+func (p *CDNPurger) purge(ctx context.Context, keys []string) error {
+	body, err := json.Marshal(map[string][]string{"surrogate_keys": keys})
+	if err != nil {
+		return fmt.Errorf("failed to marshal purge request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build purge request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Fastly-Key", p.token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("CDN unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("CDN returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+)
+
+// Completion criteria names reported in evaluation results
+const (
+	CriterionAllLessons          = "all_lessons_viewed"
+	CriterionRequiredAssessments = "required_assessments_passed"
+	CriterionMinimumGrade        = "minimum_grade"
+	CriterionAttendance          = "attendance_threshold"
+)
+
+// StudentProgress is the progress snapshot the progress service submits for evaluation
+type StudentProgress struct {
+	StudentID           uuid.UUID   `json:"studentId" binding:"required"`
+	CompletedLessonIDs  []uuid.UUID `json:"completedLessonIds"`
+	PassedAssessmentIDs []uuid.UUID `json:"passedAssessmentIds"`
+	Grade               *float64    `json:"grade"`
+	AttendanceRate      *float64    `json:"attendanceRate"`
+}
+
+// CriterionResult reports whether one completion criterion is met
+type CriterionResult struct {
+	Criterion string `json:"criterion"`
+	Met       bool   `json:"met"`
+	Detail    string `json:"detail"`
+}
+
+// CompletionResult is the outcome of evaluating a student's progress
+type CompletionResult struct {
+	CourseID  uuid.UUID         `json:"courseId"`
+	StudentID uuid.UUID         `json:"studentId"`
+	Completed bool              `json:"completed"`
+	Criteria  []CriterionResult `json:"criteria"`
+}
+
+// CompletionService manages course completion rules and evaluates progress against them
+type CompletionService struct {
+	db          *gorm.DB
+	events      *EventPublisher
+	assessments *AssessmentClient
+}
+
+// NewCompletionService creates a new CompletionService
+func NewCompletionService() *CompletionService {
+	return &CompletionService{
+		db:          config.DB,
+		events:      NewEventPublisher(),
+		assessments: NewAssessmentClient(),
+	}
+}
+
+// GetRule returns the course's completion rule, or the default rule (all
+// lessons viewed) when none has been configured.
+func (s *CompletionService) GetRule(courseID uuid.UUID) (*models.CompletionRule, error) {
+	var rule models.CompletionRule
+	err := s.db.Where("course_id = ?", courseID).First(&rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.CompletionRule{CourseID: courseID, RequireAllLessons: true}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completion rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// SaveRule validates and stores the completion rule for a course
+func (s *CompletionService) SaveRule(rule *models.CompletionRule) error {
+	if rule.MinimumGrade != nil && (*rule.MinimumGrade < 0 || *rule.MinimumGrade > 100) {
+		return fmt.Errorf("minimumGrade must be between 0 and 100")
+	}
+	if rule.AttendanceThreshold != nil && (*rule.AttendanceThreshold < 0 || *rule.AttendanceThreshold > 100) {
+		return fmt.Errorf("attendanceThreshold must be between 0 and 100")
+	}
+
+	var existing models.CompletionRule
+	err := s.db.Where("course_id = ?", rule.CourseID).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to load completion rule: %w", err)
+	}
+	if err == nil {
+		rule.ID = existing.ID
+		rule.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.db.Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to save completion rule: %w", err)
+	}
+	return nil
+}
+
+// Evaluate checks a student's progress against the course's rule and emits
+// COURSE_COMPLETED when every criterion is met.
+func (s *CompletionService) Evaluate(courseID uuid.UUID, progress StudentProgress) (*CompletionResult, error) {
+	rule, err := s.GetRule(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompletionResult{CourseID: courseID, StudentID: progress.StudentID, Completed: true}
+	add := func(criterion string, met bool, detail string) {
+		result.Criteria = append(result.Criteria, CriterionResult{Criterion: criterion, Met: met, Detail: detail})
+		if !met {
+			result.Completed = false
+		}
+	}
+
+	if rule.RequireAllLessons {
+		var lessonIDs []uuid.UUID
+		if err := s.db.Model(&models.Lesson{}).
+			Joins("JOIN modules ON modules.id = lessons.module_id").
+			Where("modules.course_id = ?", courseID).
+			Pluck("lessons.id", &lessonIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to get course lessons: %w", err)
+		}
+
+		completed := toSet(progress.CompletedLessonIDs)
+		done := 0
+		for _, id := range lessonIDs {
+			if completed[id] {
+				done++
+			}
+		}
+		add(CriterionAllLessons, done == len(lessonIDs), fmt.Sprintf("%d of %d lessons viewed", done, len(lessonIDs)))
+	}
+
+	if len(rule.RequiredAssessmentIDs) > 0 {
+		passed := toSet(progress.PassedAssessmentIDs)
+		done := 0
+		for _, id := range rule.RequiredAssessmentIDs {
+			if passed[id] {
+				done++
+			}
+		}
+		add(CriterionRequiredAssessments, done == len(rule.RequiredAssessmentIDs),
+			fmt.Sprintf("%d of %d required assessments passed", done, len(rule.RequiredAssessmentIDs)))
+	}
+
+	if rule.MinimumGrade != nil {
+		met := progress.Grade != nil && *progress.Grade >= *rule.MinimumGrade
+		add(CriterionMinimumGrade, met, fmt.Sprintf("grade %s, minimum %.2f", formatPercent(progress.Grade), *rule.MinimumGrade))
+	}
+
+	if rule.AttendanceThreshold != nil {
+		met := progress.AttendanceRate != nil && *progress.AttendanceRate >= *rule.AttendanceThreshold
+		add(CriterionAttendance, met, fmt.Sprintf("attendance %s, threshold %.2f", formatPercent(progress.AttendanceRate), *rule.AttendanceThreshold))
+	}
+
+	if result.Completed {
+		data := map[string]interface{}{
+			"courseId":       courseID,
+			"studentId":      progress.StudentID,
+			"completionDate": time.Now().UTC(),
+		}
+		if progress.Grade != nil {
+			data["finalScore"] = *progress.Grade
+			// Certificates show the grade on the course's grading scale
+			grade, err := s.assessments.FormatGrade(context.Background(), courseID.String(), *progress.Grade)
+			if err != nil {
+				utils.Warn("Failed to format final grade", map[string]interface{}{
+					"error":    err.Error(),
+					"courseID": courseID,
+				})
+			} else {
+				data["finalGrade"] = grade
+			}
+		}
+		if err := s.events.Publish(TopicEnrollmentEvents, "COURSE_COMPLETED", "Enrollment", courseID, progress.StudentID.String(), data); err != nil {
+			utils.Warn("Course completion event not published", map[string]interface{}{
+				"courseID":  courseID,
+				"studentID": progress.StudentID,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func toSet(ids []uuid.UUID) map[uuid.UUID]bool {
+	set := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+func formatPercent(value *float64) string {
+	if value == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f", *value)
+}
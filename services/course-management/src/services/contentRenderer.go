@@ -0,0 +1,130 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"github.com/modex/shared/sanitize"
+	"gorm.io/gorm"
+)
+
+// rendererVersion is part of every render hash. Bump it whenever the markdown
+// or sanitization rules change so stored renders are regenerated on read.
+const rendererVersion = 1
+
+// defaultEmbedHosts are the video players lessons may embed when
+// LESSON_EMBED_HOSTS is unset
+var defaultEmbedHosts = []string{
+	"www.youtube-nocookie.com",
+	"www.youtube.com",
+	"player.vimeo.com",
+	"fast.wistia.net",
+}
+
+// ContentRenderer turns lesson content into sanitized HTML and keeps the
+// stored render in step with the content and the rendering rules.
+type ContentRenderer struct {
+	db     *gorm.DB
+	policy sanitize.Policy
+	// fingerprint identifies the policy so a config change invalidates renders
+	fingerprint string
+}
+
+// NewContentRenderer creates a ContentRenderer. LESSON_EMBED_HOSTS is a
+// comma-separated iframe host allowlist; external images are routed through
+// the content-delivery image proxy at CONTENT_DELIVERY_URL when it is set.
+func NewContentRenderer() *ContentRenderer {
+	hosts := defaultEmbedHosts
+	if raw := os.Getenv("LESSON_EMBED_HOSTS"); raw != "" {
+		hosts = strings.Split(raw, ",")
+	}
+
+	policy := sanitize.Policy{EmbedHosts: make(map[string]bool, len(hosts))}
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			policy.EmbedHosts[host] = true
+		}
+	}
+	if base := strings.TrimRight(os.Getenv("CONTENT_DELIVERY_URL"), "/"); base != "" {
+		policy.ImageProxyURL = base + "/proxy/image?url="
+	}
+
+	sortedHosts := make([]string, 0, len(policy.EmbedHosts))
+	for host := range policy.EmbedHosts {
+		sortedHosts = append(sortedHosts, host)
+	}
+	sort.Strings(sortedHosts)
+
+	return &ContentRenderer{
+		db:          config.DB,
+		policy:      policy,
+		fingerprint: fmt.Sprintf("v%d|%s|%s", rendererVersion, strings.Join(sortedHosts, ","), policy.ImageProxyURL),
+	}
+}
+
+// ValidContentFormat reports whether format is a supported lesson content format
+func ValidContentFormat(format models.ContentFormat) bool {
+	return format == models.ContentFormatMarkdown || format == models.ContentFormatHTML
+}
+
+// Render converts content in the given format to sanitized HTML
+func (r *ContentRenderer) Render(content string, format models.ContentFormat) string {
+	if format == models.ContentFormatHTML {
+		return r.policy.SanitizeHTML(content)
+	}
+	return r.policy.SanitizeHTML(renderMarkdown(content))
+}
+
+// RenderLesson refreshes a lesson's rendered content if the content or the
+// rendering rules changed since it was last rendered, reporting whether it did.
+func (r *ContentRenderer) RenderLesson(lesson *models.Lesson) bool {
+	if lesson.ContentFormat == "" {
+		lesson.ContentFormat = models.ContentFormatMarkdown
+	}
+
+	hash := r.renderHash(lesson.Content, lesson.ContentFormat)
+	if lesson.RenderHash == hash {
+		return false
+	}
+
+	lesson.RenderedContent = r.Render(lesson.Content, lesson.ContentFormat)
+	lesson.RenderHash = hash
+	return true
+}
+
+// RefreshLessons re-renders stale lessons in place and stores the new renders.
+// Failing to store a render is logged rather than returned since the caller
+// still has a correct render to serve.
+func (r *ContentRenderer) RefreshLessons(lessons []models.Lesson) {
+	for i := range lessons {
+		lesson := &lessons[i]
+		if !r.RenderLesson(lesson) {
+			continue
+		}
+
+		// UpdateColumns leaves updated_at and the version alone: the
+		// lesson itself did not change
+		if err := r.db.Model(lesson).UpdateColumns(map[string]interface{}{
+			"content_format":   lesson.ContentFormat,
+			"rendered_content": lesson.RenderedContent,
+			"render_hash":      lesson.RenderHash,
+		}).Error; err != nil {
+			utils.Warn("Failed to store rendered lesson content", map[string]interface{}{
+				"error":    err.Error(),
+				"lessonID": lesson.ID,
+			})
+		}
+	}
+}
+
+func (r *ContentRenderer) renderHash(content string, format models.ContentFormat) string {
+	sum := sha256.Sum256([]byte(r.fingerprint + "|" + string(format) + "|" + content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// courseFeedKeyPrefix sits under course:* so InvalidateAllCourses clears
+	// cached feeds along with everything else
+	courseFeedKeyPrefix = "course:feed:atom:"
+	// DefaultCourseFeedTTL applies when COURSE_FEED_TTL is unset
+	DefaultCourseFeedTTL = time.Hour
+	courseFeedSize       = 50
+)
+
+// CourseFeedService renders the public catalog as an Atom feed for partner
+// sites and internal portals. Feeds are cached per category and rebuilt when
+// courses are published or changed.
+type CourseFeedService struct {
+	db      *gorm.DB
+	ttl     time.Duration
+	baseURL string
+}
+
+// NewCourseFeedService creates a new CourseFeedService. Entry links point at
+// CATALOG_BASE_URL, the public site that shows courses at /courses/<slug>.
+func NewCourseFeedService() *CourseFeedService {
+	ttl := DefaultCourseFeedTTL
+	if raw := os.Getenv("COURSE_FEED_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			ttl = parsed
+		}
+	}
+
+	return &CourseFeedService{
+		db:      config.DB,
+		ttl:     ttl,
+		baseURL: strings.TrimRight(os.Getenv("CATALOG_BASE_URL"), "/"),
+	}
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+}
+
+// Feed returns the Atom document for category, or for the whole catalog when
+// category is empty. selfURL is the address the feed was requested at; it is
+// cached with the feed, so requests should arrive through the gateway.
+func (s *CourseFeedService) Feed(category, selfURL string) ([]byte, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	key := courseFeedKeyPrefix + category
+
+	cached, err := config.RedisClient.Get(config.Ctx, key).Bytes()
+	if err == nil {
+		return cached, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		utils.Warn("Failed to read cached course feed", map[string]interface{}{
+			"error":    err.Error(),
+			"category": category,
+		})
+	}
+
+	feed, err := s.build(category, selfURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.RedisClient.Set(config.Ctx, key, feed, s.ttl).Err(); err != nil {
+		utils.Warn("Failed to cache course feed", map[string]interface{}{
+			"error":    err.Error(),
+			"category": category,
+		})
+	}
+	return feed, nil
+}
+
+// Invalidate drops every cached feed so the next request rebuilds it from
+// the database. Called whenever a course is published, changed or removed.
+func (s *CourseFeedService) Invalidate() {
+	keys, err := config.RedisClient.Keys(config.Ctx, courseFeedKeyPrefix+"*").Result()
+	if err == nil && len(keys) > 0 {
+		err = config.RedisClient.Del(config.Ctx, keys...).Err()
+	}
+	if err != nil {
+		utils.Warn("Failed to invalidate course feeds", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+func (s *CourseFeedService) build(category, selfURL string) ([]byte, error) {
+	query := s.db.Where("status = ? AND is_published = ? AND suspended_at IS NULL", models.CourseStatusPublished, true)
+	if category != "" {
+		query = query.Where("LOWER(category) = ?", category)
+	}
+
+	var courses []models.Course
+	if err := query.Preload("Tags").
+		Order("updated_at DESC").
+		Limit(courseFeedSize).
+		Find(&courses).Error; err != nil {
+		return nil, fmt.Errorf("failed to list courses for feed: %w", err)
+	}
+
+	id, title := "urn:modex:feeds:courses", "Modex course catalog"
+	if category != "" {
+		id += ":" + category
+		title += " - " + category
+	}
+
+	// An empty feed still needs an updated time; the epoch keeps the
+	// document stable between rebuilds
+	updated := time.Unix(0, 0).UTC()
+	entries := make([]atomEntry, 0, len(courses))
+	for _, course := range courses {
+		if course.UpdatedAt.After(updated) {
+			updated = course.UpdatedAt.UTC()
+		}
+		entries = append(entries, s.entry(&course))
+	}
+
+	feed := atomFeed{
+		ID:      id,
+		Title:   title,
+		Updated: updated.Format(time.RFC3339),
+		Author:  atomPerson{Name: "Modex"},
+		Links:   []atomLink{{Rel: "self", Type: "application/atom+xml", Href: selfURL}},
+		Entries: entries,
+	}
+	if s.baseURL != "" {
+		feed.Links = append(feed.Links, atomLink{Rel: "alternate", Type: "text/html", Href: s.baseURL + "/courses"})
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render course feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+func (s *CourseFeedService) entry(course *models.Course) atomEntry {
+	entry := atomEntry{
+		ID:      "urn:uuid:" + course.ID.String(),
+		Title:   course.Title,
+		Updated: course.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if course.PublishedAt != nil {
+		entry.Published = course.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	if s.baseURL != "" {
+		entry.Links = []atomLink{{Rel: "alternate", Type: "text/html", Href: s.baseURL + "/courses/" + course.Slug}}
+	}
+
+	if course.Category != "" {
+		entry.Categories = append(entry.Categories, atomCategory{Term: course.Category})
+	}
+	for _, tag := range course.Tags {
+		entry.Categories = append(entry.Categories, atomCategory{Term: tag.Name})
+	}
+
+	summary := course.MetaDescription
+	if summary == "" {
+		summary = course.Description
+	}
+	if summary != "" {
+		entry.Summary = &atomText{Type: "text", Body: summary}
+	}
+	return entry
+}
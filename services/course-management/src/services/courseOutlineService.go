@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// courseOutlineKeyPrefix sits under course:* so InvalidateAllCourses
+	// clears cached outlines too
+	courseOutlineKeyPrefix = "course:outline:"
+	// DefaultCourseOutlineTTL applies when COURSE_OUTLINE_TTL is unset
+	DefaultCourseOutlineTTL = 30 * time.Minute
+)
+
+// CourseOutline is the compact course tree learners navigate by
+type CourseOutline struct {
+	CourseID    uuid.UUID       `json:"courseId"`
+	Title       string          `json:"title"`
+	Slug        string          `json:"slug"`
+	Version     int             `json:"version"`
+	Duration    int             `json:"duration"` // in minutes
+	LessonCount int             `json:"lessonCount"`
+	Completed   int             `json:"completedLessons"`
+	Modules     []OutlineModule `json:"modules"`
+}
+
+// OutlineModule is a module in a course outline
+type OutlineModule struct {
+	ID       uuid.UUID       `json:"id"`
+	Title    string          `json:"title"`
+	Duration int             `json:"duration"`
+	Lessons  []OutlineLesson `json:"lessons"`
+}
+
+// OutlineLesson is a lesson in a course outline. Locked and Completed are
+// filled in for the learner asking.
+type OutlineLesson struct {
+	ID                 uuid.UUID                      `json:"id"`
+	Title              string                         `json:"title"`
+	LessonType         models.LessonType              `json:"lessonType"`
+	Duration           int                            `json:"duration"`
+	HasVideo           bool                           `json:"hasVideo"`
+	HasDownload        bool                           `json:"hasDownload"`
+	UnlockConditions   []models.LessonUnlockCondition `json:"unlockConditions,omitempty"`
+	Locked             bool                           `json:"locked"`
+	Completed          bool                           `json:"completed"`
+	UnlockRequirements []UnlockRequirement            `json:"unlockRequirements,omitempty"`
+}
+
+// CourseOutlineService builds learner outlines. The course structure is
+// cached in Redis and shared by every learner; lock and completion state is
+// laid over it per request.
+type CourseOutlineService struct {
+	db     *gorm.DB
+	access *LessonAccessService
+	cdn    *CDNPurger
+	ttl    time.Duration
+}
+
+// NewCourseOutlineService creates a new CourseOutlineService
+func NewCourseOutlineService() *CourseOutlineService {
+	ttl := DefaultCourseOutlineTTL
+	if raw := os.Getenv("COURSE_OUTLINE_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			ttl = parsed
+		}
+	}
+
+	return &CourseOutlineService{
+		db:     config.DB,
+		access: NewLessonAccessService(),
+		cdn:    NewCDNPurger(),
+		ttl:    ttl,
+	}
+}
+
+// Outline returns courseID's outline personalized for userID, or for an
+// anonymous visitor when userID is uuid.Nil. Suspended courses are reported
+// as ErrCourseNotFound.
+func (s *CourseOutlineService) Outline(ctx context.Context, courseID, userID uuid.UUID) (*CourseOutline, error) {
+	outline, err := s.structure(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lessons []models.Lesson
+	for _, module := range outline.Modules {
+		for _, lesson := range module.Lessons {
+			lessons = append(lessons, models.Lesson{ID: lesson.ID, UnlockConditions: lesson.UnlockConditions})
+		}
+	}
+
+	access, err := s.access.Evaluate(ctx, courseID, userID, lessons)
+	if err != nil {
+		return nil, err
+	}
+
+	for m := range outline.Modules {
+		for l := range outline.Modules[m].Lessons {
+			lesson := &outline.Modules[m].Lessons[l]
+			state := access[lesson.ID]
+			lesson.Locked = state.Locked
+			lesson.Completed = state.Completed
+			if state.Locked {
+				lesson.UnlockRequirements = state.Requirements
+			}
+			if state.Completed {
+				outline.Completed++
+			}
+		}
+	}
+	return outline, nil
+}
+
+// Invalidate drops courseID's cached structure, here and at the CDN for
+// anonymous outlines. Call it after any change to the course's modules or
+// lessons.
+func (s *CourseOutlineService) Invalidate(courseID uuid.UUID) {
+	s.cdn.Purge(CourseSurrogateKey(courseID.String()))
+	if err := config.RedisClient.Del(config.Ctx, courseOutlineKeyPrefix+courseID.String()).Err(); err != nil {
+		utils.Warn("Failed to invalidate course outline", map[string]interface{}{
+			"error":    err.Error(),
+			"courseID": courseID,
+		})
+	}
+}
+
+// structure returns the cached outline without learner state, building it
+// from the database on a miss
+func (s *CourseOutlineService) structure(courseID uuid.UUID) (*CourseOutline, error) {
+	key := courseOutlineKeyPrefix + courseID.String()
+
+	cached, err := config.RedisClient.Get(config.Ctx, key).Bytes()
+	if err == nil {
+		var outline CourseOutline
+		if err := json.Unmarshal(cached, &outline); err == nil {
+			return &outline, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		utils.Warn("Failed to read cached course outline", map[string]interface{}{
+			"error":    err.Error(),
+			"courseID": courseID,
+		})
+	}
+
+	outline, err := s.build(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(outline); err == nil {
+		if err := config.RedisClient.Set(config.Ctx, key, data, s.ttl).Err(); err != nil {
+			utils.Warn("Failed to cache course outline", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": courseID,
+			})
+		}
+	}
+	return outline, nil
+}
+
+func (s *CourseOutlineService) build(courseID uuid.UUID) (*CourseOutline, error) {
+	var course models.Course
+	err := s.db.Select("id", "title", "slug", "version", "suspended_at").
+		Preload("Modules", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "course_id", "title", "order_index", "duration").Order("order_index ASC")
+		}).
+		Preload("Modules.Lessons", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "module_id", "title", "order_index", "duration", "lesson_type", "video_url", "download_url", "unlock_conditions").
+				Order("order_index ASC")
+		}).
+		First(&course, "id = ?", courseID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCourseNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course.SuspendedAt != nil {
+		return nil, ErrCourseNotFound
+	}
+
+	outline := &CourseOutline{
+		CourseID: course.ID,
+		Title:    course.Title,
+		Slug:     course.Slug,
+		Version:  course.Version,
+		Modules:  make([]OutlineModule, 0, len(course.Modules)),
+	}
+	for _, module := range course.Modules {
+		entry := OutlineModule{ID: module.ID, Title: module.Title, Lessons: make([]OutlineLesson, 0, len(module.Lessons))}
+		for _, lesson := range module.Lessons {
+			entry.Lessons = append(entry.Lessons, OutlineLesson{
+				ID:               lesson.ID,
+				Title:            lesson.Title,
+				LessonType:       lesson.LessonType,
+				Duration:         lesson.Duration,
+				HasVideo:         lesson.VideoURL != "",
+				HasDownload:      lesson.DownloadURL != "",
+				UnlockConditions: lesson.UnlockConditions,
+			})
+			entry.Duration += lesson.Duration
+		}
+		// Modules without lesson durations fall back to their own estimate
+		if entry.Duration == 0 {
+			entry.Duration = module.Duration
+		}
+		outline.Duration += entry.Duration
+		outline.LessonCount += len(entry.Lessons)
+		outline.Modules = append(outline.Modules, entry)
+	}
+	return outline, nil
+}
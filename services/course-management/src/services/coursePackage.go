@@ -0,0 +1,86 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/shared/exports"
+	"gorm.io/gorm"
+)
+
+// coursePackageFormat identifies the archive layout so importers can check it
+const coursePackageFormat = "modex-course-package/1"
+
+// exportCoursePackage writes a course and its modules and lessons as a zip:
+// manifest.json, course.json with the full outline, and each lesson's source
+// under lessons/ named by module and lesson position
+func exportCoursePackage(ctx context.Context, job *models.ExportJob, w io.Writer) (string, string, error) {
+	courseID, err := exports.ParamUUID(job, "courseId")
+	if err != nil {
+		return "", "", err
+	}
+
+	var course models.Course
+	if err := config.DB.WithContext(ctx).
+		Preload("Modules", func(db *gorm.DB) *gorm.DB { return db.Order("order_index ASC") }).
+		Preload("Modules.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("order_index ASC") }).
+		Preload("Tags").
+		First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", "", ErrCourseNotFound
+		}
+		return "", "", fmt.Errorf("failed to load course: %w", err)
+	}
+
+	archive := zip.NewWriter(w)
+	writeJSON := func(name string, data interface{}) error {
+		entry, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to package: %w", name, err)
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	}
+
+	if err := writeJSON("manifest.json", map[string]interface{}{
+		"format":     coursePackageFormat,
+		"courseId":   course.ID,
+		"version":    course.Version,
+		"exportedAt": time.Now().UTC(),
+	}); err != nil {
+		return "", "", err
+	}
+	if err := writeJSON("course.json", course); err != nil {
+		return "", "", err
+	}
+
+	for m, module := range course.Modules {
+		for l, lesson := range module.Lessons {
+			ext := "md"
+			if lesson.ContentFormat == models.ContentFormatHTML {
+				ext = "html"
+			}
+			name := fmt.Sprintf("lessons/%02d-%02d-%s.%s", m+1, l+1, lesson.ID, ext)
+			entry, err := archive.Create(name)
+			if err != nil {
+				return "", "", fmt.Errorf("failed to add %s to package: %w", name, err)
+			}
+			if _, err := io.WriteString(entry, lesson.Content); err != nil {
+				return "", "", err
+			}
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("course-%s-package.zip", course.ID), exportContentTypeZip, nil
+}
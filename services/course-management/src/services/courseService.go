@@ -17,6 +17,9 @@ type CourseFilter struct {
 	Language string
 	Status   string
 	Search   string
+	// TierKeys restricts results to courses included in any of these
+	// subscription tiers
+	TierKeys []string
 }
 
 type CourseService struct {
@@ -62,7 +65,8 @@ func (s *CourseService) GetCourses(page, pageSize int, offset int, filter Course
 	var courses []models.Course
 	var total int64
 
-	query := s.db.Model(&models.Course{})
+	// Suspended courses never appear in the catalog
+	query := s.db.Model(&models.Course{}).Where("suspended_at IS NULL")
 
 	// Apply filters
 	if filter.Category != "" {
@@ -81,6 +85,9 @@ func (s *CourseService) GetCourses(page, pageSize int, offset int, filter Course
 		query = query.Where("title ILIKE ? OR description ILIKE ?", 
 			"%"+filter.Search+"%", "%"+filter.Search+"%")
 	}
+	if len(filter.TierKeys) > 0 {
+		query = query.Where("id IN (SELECT course_id FROM course_tiers WHERE tier_key IN ?)", filter.TierKeys)
+	}
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -90,6 +97,7 @@ func (s *CourseService) GetCourses(page, pageSize int, offset int, filter Course
 	// Get courses with preloaded relationships
 	if err := query.
 		Preload("Tags").
+		Preload("Tiers").
 		Order("created_at DESC").
 		Limit(pageSize).
 		Offset(offset).
@@ -103,7 +111,7 @@ func (s *CourseService) GetCourses(page, pageSize int, offset int, filter Course
 // GetCourseByID retrieves a course by ID
 func (s *CourseService) GetCourseByID(id uuid.UUID) (*models.Course, error) {
 	var course models.Course
-	if err := s.db.Preload("Modules.Lessons").Preload("Tags").Preload("Prerequisites").First(&course, id).Error; err != nil {
+	if err := s.db.Preload("Modules.Lessons").Preload("Tags").Preload("Tiers").Preload("Prerequisites").First(&course, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("course not found")
 		}
@@ -112,12 +120,15 @@ func (s *CourseService) GetCourseByID(id uuid.UUID) (*models.Course, error) {
 	return &course, nil
 }
 
-// UpdateCourse updates an existing course
-func (s *CourseService) UpdateCourse(course *models.Course, tags []string) error {
+// UpdateCourse updates an existing course if it is still at expectedVersion.
+// Returns ErrVersionConflict when another edit landed first.
+func (s *CourseService) UpdateCourse(course *models.Course, tags []string, expectedVersion int) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
 		// Update course
-		if err := tx.Save(course).Error; err != nil {
-			return fmt.Errorf("failed to update course: %w", err)
+		course.Version = expectedVersion + 1
+		if err := UpdateWithVersion(tx, course, expectedVersion); err != nil {
+			course.Version = expectedVersion
+			return err
 		}
 
 		// Update tags if provided
@@ -167,8 +178,20 @@ func (s *CourseService) DeleteCourse(id uuid.UUID) error {
 			return fmt.Errorf("failed to delete prerequisites: %w", err)
 		}
 
+		if err := tx.Where("course_id = ?", id).Delete(&models.CourseTranslation{}).Error; err != nil {
+			return fmt.Errorf("failed to delete translations: %w", err)
+		}
+
+		if err := tx.Where("course_id = ?", id).Delete(&models.CoursePrice{}).Error; err != nil {
+			return fmt.Errorf("failed to delete course prices: %w", err)
+		}
+
+		if err := tx.Where("course_id = ?", id).Delete(&models.CourseTier{}).Error; err != nil {
+			return fmt.Errorf("failed to delete course tiers: %w", err)
+		}
+
 		// Delete lessons (will cascade to modules)
-		if err := tx.Where("course_id IN (SELECT id FROM modules WHERE course_id = ?)", id).Delete(&models.Lesson{}).Error; err != nil {
+		if err := tx.Where("module_id IN (SELECT id FROM modules WHERE course_id = ?)", id).Delete(&models.Lesson{}).Error; err != nil {
 			return fmt.Errorf("failed to delete lessons: %w", err)
 		}
 
@@ -194,6 +217,7 @@ func (s *CourseService) PublishCourse(id uuid.UUID) error {
 			"status":       models.CourseStatusPublished,
 			"is_published": true,
 			"published_at": time.Now(),
+			"publish_at":   nil,
 		}).Error
 }
 
@@ -283,7 +307,7 @@ func (s *CourseService) GetPopularCourses(limit int) ([]models.Course, error) {
 	// This would need to be implemented with enrollment service integration
 	// For now, return courses ordered by creation date
 	if err := s.db.Preload("Tags").
-		Where("status = ? AND is_published = ?", models.CourseStatusPublished, true).
+		Where("status = ? AND is_published = ? AND suspended_at IS NULL", models.CourseStatusPublished, true).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&courses).Error; err != nil {
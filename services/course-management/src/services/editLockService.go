@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// DefaultEditLockTTL applies when EDIT_LOCK_TTL is unset. Editors heartbeat
+// well within it; a lock whose editor went away simply expires.
+const DefaultEditLockTTL = 2 * time.Minute
+
+// Resources an edit lock can cover
+const (
+	LockResourceCourse     = "course"
+	LockResourceModule     = "module"
+	LockResourceAssessment = "assessment"
+)
+
+var (
+	// ErrEditLockHeld is returned when another editor holds the lock
+	ErrEditLockHeld = errors.New("resource is locked by another editor")
+	// ErrEditLockLost is returned when heartbeating or releasing a lock that
+	// expired or was taken over
+	ErrEditLockLost = errors.New("edit lock is no longer held")
+	// ErrInvalidLockResource is returned for unknown resource types or
+	// resources outside the course
+	ErrInvalidLockResource = errors.New("invalid lock resource")
+)
+
+// EditLock is an advisory lock on part of a course's curriculum. It tells
+// collaborators who is editing; version checks still guard the writes.
+type EditLock struct {
+	ResourceType  string     `json:"resourceType"`
+	ResourceID    uuid.UUID  `json:"resourceId"`
+	UserID        uuid.UUID  `json:"userId"`
+	Token         string     `json:"token,omitempty"` // only returned to the holder
+	AcquiredAt    time.Time  `json:"acquiredAt"`
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	TakenOverFrom *uuid.UUID `json:"takenOverFrom,omitempty"`
+}
+
+// acquireEditLockScript takes the lock when it is free, already held by the
+// same user, or ARGV[3] asks for a takeover. It returns {1} on success, or
+// {0, holder fields...} when someone else holds it.
+var acquireEditLockScript = redis.NewScript(`
+local holder = redis.call('HGET', KEYS[1], 'userId')
+if holder and holder ~= ARGV[1] and ARGV[3] ~= '1' then
+  return {0, unpack(redis.call('HGETALL', KEYS[1]))}
+end
+local previous = ''
+if holder and holder ~= ARGV[1] then
+  previous = holder
+end
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[1], 'userId', ARGV[1], 'token', ARGV[2], 'acquiredAt', ARGV[4], 'takenOverFrom', previous)
+redis.call('PEXPIRE', KEYS[1], ARGV[5])
+return {1}
+`)
+
+// extendEditLockScript extends or, with ARGV[3] = '1', deletes the lock if
+// ARGV[1] is still its token. It returns 0 when the lock is gone or held
+// under another token.
+var extendEditLockScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'token') ~= ARGV[1] then
+  return 0
+end
+if ARGV[3] == '1' then
+  redis.call('DEL', KEYS[1])
+else
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// EditLockService coordinates co-authors editing the same curriculum with
+// Redis-held advisory locks that expire unless heartbeated
+type EditLockService struct {
+	db  *gorm.DB
+	ttl time.Duration
+}
+
+// NewEditLockService creates a new EditLockService
+func NewEditLockService() *EditLockService {
+	ttl := DefaultEditLockTTL
+	if raw := os.Getenv("EDIT_LOCK_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed >= 10*time.Second {
+			ttl = parsed
+		}
+	}
+	return &EditLockService{db: config.DB, ttl: ttl}
+}
+
+// TTL is how long a lock lives without a heartbeat
+func (s *EditLockService) TTL() time.Duration {
+	return s.ttl
+}
+
+// Acquire locks a resource in courseID for userID. When another editor holds
+// it, Acquire returns their lock and ErrEditLockHeld unless takeover is set,
+// in which case the lock moves to userID and the other editor's next
+// heartbeat fails.
+func (s *EditLockService) Acquire(courseID uuid.UUID, resourceType string, resourceID, userID uuid.UUID, takeover bool) (*EditLock, error) {
+	if err := s.checkResource(courseID, resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	token := uuid.NewString()
+	key := editLockKey(courseID, resourceType, resourceID)
+	result, err := acquireEditLockScript.Run(config.Ctx, config.RedisClient, []string{key},
+		userID.String(), token, boolFlag(takeover), now.Format(time.RFC3339Nano), s.ttl.Milliseconds()).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire edit lock: %w", err)
+	}
+
+	if acquired, _ := result[0].(int64); acquired == 0 {
+		fields := make(map[string]string, len(result)/2)
+		for i := 1; i+1 < len(result); i += 2 {
+			k, _ := result[i].(string)
+			v, _ := result[i+1].(string)
+			fields[k] = v
+		}
+		holder := parseEditLock(resourceType, resourceID, fields)
+		if ttl, err := config.RedisClient.PTTL(config.Ctx, key).Result(); err == nil && ttl > 0 {
+			holder.ExpiresAt = now.Add(ttl)
+		}
+		return holder, ErrEditLockHeld
+	}
+
+	lock := &EditLock{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		UserID:       userID,
+		Token:        token,
+		AcquiredAt:   now,
+		ExpiresAt:    now.Add(s.ttl),
+	}
+	return lock, nil
+}
+
+// Heartbeat keeps a held lock alive for another TTL
+func (s *EditLockService) Heartbeat(courseID uuid.UUID, resourceType string, resourceID uuid.UUID, token string) (time.Time, error) {
+	if err := s.extend(courseID, resourceType, resourceID, token, false); err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().UTC().Add(s.ttl), nil
+}
+
+// Release gives up a held lock
+func (s *EditLockService) Release(courseID uuid.UUID, resourceType string, resourceID uuid.UUID, token string) error {
+	return s.extend(courseID, resourceType, resourceID, token, true)
+}
+
+// Holder returns the current lock on a resource, or nil when it is free
+func (s *EditLockService) Holder(courseID uuid.UUID, resourceType string, resourceID uuid.UUID) (*EditLock, error) {
+	key := editLockKey(courseID, resourceType, resourceID)
+	fields, err := config.RedisClient.HGetAll(config.Ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edit lock: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	lock := parseEditLock(resourceType, resourceID, fields)
+	if ttl, err := config.RedisClient.PTTL(config.Ctx, key).Result(); err == nil && ttl > 0 {
+		lock.ExpiresAt = time.Now().UTC().Add(ttl)
+	}
+	return lock, nil
+}
+
+// List returns every live lock in a course, so editors can see who is
+// working where before they open something
+func (s *EditLockService) List(courseID uuid.UUID) ([]EditLock, error) {
+	keys, err := config.RedisClient.Keys(config.Ctx, fmt.Sprintf("edit-lock:%s:*", courseID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edit locks: %w", err)
+	}
+
+	locks := make([]EditLock, 0, len(keys))
+	for _, key := range keys {
+		parts := strings.Split(key, ":")
+		if len(parts) != 4 {
+			continue
+		}
+		resourceID, err := uuid.Parse(parts[3])
+		if err != nil {
+			continue
+		}
+		lock, err := s.Holder(courseID, parts[2], resourceID)
+		if err != nil {
+			return nil, err
+		}
+		// nil when the lock expired between listing and reading it
+		if lock != nil {
+			locks = append(locks, *lock)
+		}
+	}
+	return locks, nil
+}
+
+func (s *EditLockService) extend(courseID uuid.UUID, resourceType string, resourceID uuid.UUID, token string, release bool) error {
+	if !validLockResource(resourceType) {
+		return ErrInvalidLockResource
+	}
+
+	key := editLockKey(courseID, resourceType, resourceID)
+	held, err := extendEditLockScript.Run(config.Ctx, config.RedisClient, []string{key},
+		token, s.ttl.Milliseconds(), boolFlag(release)).Int()
+	if err != nil {
+		return fmt.Errorf("failed to update edit lock: %w", err)
+	}
+	if held == 0 {
+		return ErrEditLockLost
+	}
+	return nil
+}
+
+// checkResource confirms the resource can be locked under courseID.
+// Assessments live in the assessment service, so only their type is checked.
+func (s *EditLockService) checkResource(courseID uuid.UUID, resourceType string, resourceID uuid.UUID) error {
+	switch resourceType {
+	case LockResourceCourse:
+		if resourceID != courseID {
+			return ErrInvalidLockResource
+		}
+	case LockResourceModule:
+		var count int64
+		if err := s.db.Model(&models.Module{}).Where("id = ? AND course_id = ?", resourceID, courseID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to find module: %w", err)
+		}
+		if count == 0 {
+			return ErrInvalidLockResource
+		}
+	case LockResourceAssessment:
+	default:
+		return ErrInvalidLockResource
+	}
+	return nil
+}
+
+func validLockResource(resourceType string) bool {
+	return resourceType == LockResourceCourse || resourceType == LockResourceModule || resourceType == LockResourceAssessment
+}
+
+func editLockKey(courseID uuid.UUID, resourceType string, resourceID uuid.UUID) string {
+	return fmt.Sprintf("edit-lock:%s:%s:%s", courseID, resourceType, resourceID)
+}
+
+func parseEditLock(resourceType string, resourceID uuid.UUID, fields map[string]string) *EditLock {
+	lock := &EditLock{ResourceType: resourceType, ResourceID: resourceID}
+	lock.UserID, _ = uuid.Parse(fields["userId"])
+	lock.AcquiredAt, _ = time.Parse(time.RFC3339Nano, fields["acquiredAt"])
+	if previous, err := uuid.Parse(fields["takenOverFrom"]); err == nil {
+		lock.TakenOverFrom = &previous
+	}
+	return lock
+}
+
+func boolFlag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
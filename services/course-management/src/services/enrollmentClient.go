@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// EnrollmentClient talks to the enrollment service on behalf of course-management
+type EnrollmentClient struct {
+	serviceClient
+}
+
+// NewEnrollmentClient creates a client for ENROLLMENT_SERVICE_URL
+func NewEnrollmentClient() *EnrollmentClient {
+	return &EnrollmentClient{
+		serviceClient: newServiceClient("enrollment", "ENROLLMENT_SERVICE_URL", "http://localhost:3003"),
+	}
+}
+
+// EnrollmentRequest identifies the student to enroll by user ID or email
+type EnrollmentRequest struct {
+	CourseID string `json:"courseId"`
+	UserID   string `json:"userId,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Source   string `json:"source,omitempty"`
+}
+
+// CreateEnrollment enrolls a single student
+func (c *EnrollmentClient) CreateEnrollment(ctx context.Context, req EnrollmentRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/enrollments", req, nil)
+}
+
+// UserEnrollment is a student's enrollment as reported by the enrollment service
+type UserEnrollment struct {
+	CourseID interface{} `json:"courseId"`
+	Status   string      `json:"status"`
+}
+
+// GetUserEnrollments lists every enrollment for a student
+func (c *EnrollmentClient) GetUserEnrollments(ctx context.Context, userID string) ([]UserEnrollment, error) {
+	var resp struct {
+		Data struct {
+			Enrollments []UserEnrollment `json:"enrollments"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/api/v1/enrollments/user/"+url.PathEscape(userID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.Enrollments, nil
+}
+
+// HasCompleted reports whether the student has a completed enrollment in the course
+func (c *EnrollmentClient) HasCompleted(ctx context.Context, userID, courseID string) (bool, error) {
+	enrollments, err := c.GetUserEnrollments(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range enrollments {
+		if fmt.Sprint(e.CourseID) == courseID && e.Status == "completed" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsEnrolled reports whether the student is enrolled in, or has completed, the course
+func (c *EnrollmentClient) IsEnrolled(ctx context.Context, userID, courseID string) (bool, error) {
+	enrollments, err := c.GetUserEnrollments(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range enrollments {
+		if fmt.Sprint(e.CourseID) == courseID && (e.Status == "enrolled" || e.Status == "completed") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/utils"
+)
+
+// Event topics, matching the event bus topic names
+const (
+	TopicUserEvents       = "user-events"
+	TopicCourseEvents     = "course-events"
+	TopicEnrollmentEvents = "enrollment-events"
+)
+
+// DomainEvent mirrors the event bus BaseEvent envelope
+type DomainEvent struct {
+	ID            string                 `json:"id"`
+	AggregateID   string                 `json:"aggregateId"`
+	AggregateType string                 `json:"aggregateType"`
+	EventType     string                 `json:"eventType"`
+	Version       int                    `json:"version"`
+	Timestamp     time.Time              `json:"timestamp"`
+	UserID        string                 `json:"userId,omitempty"`
+	Data          interface{}            `json:"data"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// EventPublisher publishes domain events to Redis channels named after the event bus topics
+type EventPublisher struct {
+	source string
+}
+
+// NewEventPublisher creates a new EventPublisher
+func NewEventPublisher() *EventPublisher {
+	return &EventPublisher{source: "course-management"}
+}
+
+// Publish emits an event on topic. Failures are returned so callers can decide
+// whether they matter; most callers only log them.
+func (p *EventPublisher) Publish(topic, eventType, aggregateType string, aggregateID uuid.UUID, userID string, data interface{}) error {
+	event := DomainEvent{
+		ID:            uuid.New().String(),
+		AggregateID:   aggregateID.String(),
+		AggregateType: aggregateType,
+		EventType:     eventType,
+		Version:       1,
+		Timestamp:     time.Now().UTC(),
+		UserID:        userID,
+		Data:          data,
+		Metadata:      map[string]interface{}{"source": p.source},
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	if err := config.RedisClient.Publish(config.Ctx, topic, payload).Err(); err != nil {
+		utils.Error("Failed to publish event", map[string]interface{}{
+			"error":     err.Error(),
+			"eventType": eventType,
+			"topic":     topic,
+		})
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+
+	return nil
+}
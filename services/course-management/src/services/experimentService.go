@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+)
+
+// Experiment surfaces course-management knows how to vary
+const (
+	ExperimentSurfaceThumbnail  = "thumbnail"
+	ExperimentSurfacePricing    = "pricing"
+	ExperimentSurfaceCoursePage = "course_page_layout"
+)
+
+const activeExperimentsTTL = time.Minute
+
+// Experiment is a running A/B test defined in the analytics service
+type Experiment struct {
+	ID       string              `json:"id"`
+	Key      string              `json:"key"`
+	Surface  string              `json:"surface"`
+	CourseID *string             `json:"courseId"`
+	Unit     string              `json:"unit"`
+	Variants []ExperimentVariant `json:"variants"`
+}
+
+// ExperimentVariant is one arm of an experiment. Config carries the
+// overrides for the surface, e.g. thumbnailUrl, price or layout.
+type ExperimentVariant struct {
+	Key    string                 `json:"key"`
+	Weight int                    `json:"weight"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// ExperimentAssignment tells the client which variant it was shown
+type ExperimentAssignment struct {
+	ExperimentKey string                 `json:"experimentKey"`
+	Surface       string                 `json:"surface"`
+	Variant       string                 `json:"variant"`
+	Config        map[string]interface{} `json:"config,omitempty"`
+}
+
+// ExperimentService assigns course page visitors to experiment variants.
+// Definitions come from the analytics service and assignment happens locally
+// with the same hashing scheme, so no call is made per request.
+type ExperimentService struct {
+	analytics serviceClient
+}
+
+// NewExperimentService creates a new ExperimentService
+func NewExperimentService() *ExperimentService {
+	return &ExperimentService{
+		analytics: newServiceClient("analytics", "ANALYTICS_SERVICE_URL", "http://localhost:3006"),
+	}
+}
+
+// AssignVariant deterministically picks a variant by weight from
+// sha256("<experimentKey>:<unitID>").
+func AssignVariant(experimentKey, unitID string, variants []ExperimentVariant) ExperimentVariant {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+
+	sum := sha256.Sum256([]byte(experimentKey + ":" + unitID))
+	bucket := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+	for _, v := range variants {
+		if bucket < v.Weight {
+			return v
+		}
+		bucket -= v.Weight
+	}
+	return variants[len(variants)-1]
+}
+
+// ActiveExperiments returns the running experiments that apply to a course
+func (s *ExperimentService) ActiveExperiments(ctx context.Context, courseID uuid.UUID) ([]Experiment, error) {
+	key := config.CacheKey("experiments", "active", courseID.String())
+	if data, err := config.RedisClient.Get(config.Ctx, key).Bytes(); err == nil {
+		var cached []Experiment
+		if json.Unmarshal(data, &cached) == nil {
+			return cached, nil
+		}
+	}
+
+	var resp struct {
+		Data []Experiment `json:"data"`
+	}
+	path := "/api/v1/analytics/internal/experiments/active?courseId=" + url.QueryEscape(courseID.String())
+	if err := s.analytics.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(resp.Data); err == nil {
+		config.RedisClient.Set(config.Ctx, key, data, activeExperimentsTTL)
+	}
+	return resp.Data, nil
+}
+
+// ApplyToCourse assigns the visitor to every running experiment on the
+// course, applies thumbnail and pricing overrides to course, and logs
+// exposures. Visitors without a user or tenant ID see the defaults.
+func (s *ExperimentService) ApplyToCourse(ctx context.Context, course *models.Course, userID, tenantID string) []ExperimentAssignment {
+	if userID == "" && tenantID == "" {
+		return nil
+	}
+
+	experiments, err := s.ActiveExperiments(ctx, course.ID)
+	if err != nil {
+		utils.Warn("Experiments unavailable, serving defaults", map[string]interface{}{
+			"error":    err.Error(),
+			"courseID": course.ID,
+		})
+		return nil
+	}
+
+	var assignments []ExperimentAssignment
+	var exposures []map[string]string
+	for _, exp := range experiments {
+		unitID := userID
+		if exp.Unit == "tenant" {
+			unitID = tenantID
+		}
+		if unitID == "" || len(exp.Variants) == 0 {
+			continue
+		}
+
+		variant := AssignVariant(exp.Key, unitID, exp.Variants)
+		applyVariant(course, exp.Surface, variant.Config)
+
+		assignments = append(assignments, ExperimentAssignment{
+			ExperimentKey: exp.Key,
+			Surface:       exp.Surface,
+			Variant:       variant.Key,
+			Config:        variant.Config,
+		})
+		exposures = append(exposures, map[string]string{
+			"experimentKey": exp.Key,
+			"variantKey":    variant.Key,
+			"unitId":        unitID,
+			"courseId":      course.ID.String(),
+		})
+	}
+
+	if len(exposures) > 0 {
+		go s.logExposures(exposures)
+	}
+	return assignments
+}
+
+func applyVariant(course *models.Course, surface string, overrides map[string]interface{}) {
+	switch surface {
+	case ExperimentSurfaceThumbnail:
+		if thumbnail, ok := overrides["thumbnailUrl"].(string); ok && thumbnail != "" {
+			course.ThumbnailURL = thumbnail
+		}
+	case ExperimentSurfacePricing:
+		if price, ok := overrides["price"].(float64); ok && price >= 0 {
+			course.Price = price
+		}
+	}
+	// Layout variants are rendered by the client from the assignment config
+}
+
+func (s *ExperimentService) logExposures(exposures []map[string]string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	body := map[string]interface{}{"exposures": exposures}
+	if err := s.analytics.do(ctx, http.MethodPost, "/api/v1/analytics/internal/experiments/exposures", body, nil); err != nil {
+		utils.Warn("Failed to log experiment exposures", map[string]interface{}{
+			"error": err.Error(),
+			"count": len(exposures),
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/utils"
+	"github.com/modex/shared/exports"
+	"github.com/modex/shared/objectstore"
+)
+
+// ErrObjectStoreNotConfigured is returned when no export bucket or credentials are set
+var ErrObjectStoreNotConfigured = exports.ErrNotConfigured
+
+// Export kinds built by this service
+const (
+	ExportKindCoursePackage = "course_package"
+	ExportKindUserData      = "user_data"
+)
+
+const (
+	// DefaultExportRetention applies when EXPORT_RETENTION is unset
+	DefaultExportRetention = exports.DefaultRetention
+	// DefaultExportLinkTTL applies when EXPORT_LINK_TTL is unset
+	DefaultExportLinkTTL = exports.DefaultLinkTTL
+	// DefaultExportCleanupInterval applies when EXPORT_CLEANUP_INTERVAL is unset
+	DefaultExportCleanupInterval = 15 * time.Minute
+	exportCleanupLockKey         = "exports:cleanup:lock"
+	exportContentTypeZip         = "application/zip"
+)
+
+var (
+	// ErrExportNotFound is returned for unknown jobs and other users' jobs
+	ErrExportNotFound = exports.ErrNotFound
+	// ErrExportNotReady is returned when downloading a job that hasn't succeeded
+	ErrExportNotReady = exports.ErrNotReady
+	// ErrExportExpired is returned when downloading a job whose file was cleaned up
+	ErrExportExpired = exports.ErrExpired
+)
+
+// Exporter writes one kind of export to w and names the file it produced
+type Exporter = exports.Exporter
+
+// ExportJobService runs exports that would outlast a request. Clients create
+// a job, poll it, and download the result through an expiring link to object
+// storage; files are deleted after EXPORT_RETENTION. The job lifecycle lives
+// in the shared exports package.
+type ExportJobService struct {
+	*exports.Service
+}
+
+// NewExportJobService creates a new ExportJobService with this service's exporters
+func NewExportJobService() *ExportJobService {
+	privacy := NewPrivacyService()
+
+	return &ExportJobService{
+		Service: exports.NewService(
+			config.DB,
+			objectstore.New(os.Getenv("EXPORT_BUCKET")),
+			map[string]Exporter{
+				ExportKindCoursePackage: exportCoursePackage,
+				ExportKindUserData:      privacy.exportUserDataJob,
+			},
+			exports.Options{
+				Retention: durationFromEnv("EXPORT_RETENTION", DefaultExportRetention),
+				LinkTTL:   durationFromEnv("EXPORT_LINK_TTL", DefaultExportLinkTTL),
+				Logger:    exportLogger{},
+			},
+		),
+	}
+}
+
+// ExportCleanupInterval reads EXPORT_CLEANUP_INTERVAL as a Go duration. "0"
+// or "off" disables cleanup.
+func ExportCleanupInterval() time.Duration {
+	raw := os.Getenv("EXPORT_CLEANUP_INTERVAL")
+	if raw == "" {
+		return DefaultExportCleanupInterval
+	}
+	if raw == "off" {
+		return 0
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		utils.Warn("Invalid EXPORT_CLEANUP_INTERVAL, using default", map[string]interface{}{
+			"value": raw,
+		})
+		return DefaultExportCleanupInterval
+	}
+	return interval
+}
+
+// StartCleanup removes expired export files and fails stuck jobs each
+// interval until ctx is cancelled
+func (s *ExportJobService) StartCleanup(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				acquired, err := config.RedisClient.SetNX(ctx, exportCleanupLockKey, "1", interval/2).Result()
+				if err != nil || !acquired {
+					continue
+				}
+				if _, err := s.Cleanup(ctx); err != nil {
+					utils.Error("Failed to clean up exports", map[string]interface{}{
+						"error": err.Error(),
+					})
+				}
+			}
+		}
+	}()
+}
+
+// exportLogger sends the export framework's logs to this service's logger
+type exportLogger struct{}
+
+func (exportLogger) Info(message string, fields map[string]interface{}) {
+	utils.Info(message, fields)
+}
+
+func (exportLogger) Error(message string, fields map[string]interface{}) {
+	utils.Error(message, fields)
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	if raw := os.Getenv(name); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"gorm.io/gorm"
+)
+
+// ResolvedEntity is a record some service holds under a looked-up ID, with
+// enough of it to orient a support engineer and links to read the rest
+type ResolvedEntity struct {
+	Service string                 `json:"service"`
+	Type    string                 `json:"type"`
+	ID      uuid.UUID              `json:"id"`
+	Summary map[string]interface{} `json:"summary"`
+	Links   map[string]string      `json:"links,omitempty"`
+	Deleted bool                   `json:"deleted,omitempty"` // soft-deleted, still in the database
+}
+
+// IDResolution is everything an ID was found to identify
+type IDResolution struct {
+	ID       uuid.UUID        `json:"id"`
+	Matches  []ResolvedEntity `json:"matches"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// IDResolutionService answers "what is this UUID?" for support tooling by
+// checking each course-management table and asking the assessment service
+// to check its own. Enrollment IDs are integers and content-delivery is not
+// consulted, so neither appears in results.
+type IDResolutionService struct {
+	db          *gorm.DB
+	assessments *AssessmentClient
+}
+
+// NewIDResolutionService creates a new IDResolutionService
+func NewIDResolutionService() *IDResolutionService {
+	return &IDResolutionService{
+		db:          config.DB,
+		assessments: NewAssessmentClient(),
+	}
+}
+
+// Resolve looks id up everywhere it could live. UUIDs are random, so more
+// than one match means data was copied between tables. An unreachable
+// assessment service is reported as a warning.
+func (s *IDResolutionService) Resolve(ctx context.Context, id uuid.UUID) (*IDResolution, error) {
+	result := &IDResolution{ID: id, Matches: []ResolvedEntity{}}
+
+	lookups := []func(uuid.UUID) (*ResolvedEntity, error){
+		s.course, s.module, s.lesson, s.review, s.officeHourSlot,
+		s.officeHourBooking, s.waitlistEntry, s.seatReservation, s.courseFlag, s.exportJob,
+	}
+	for _, lookup := range lookups {
+		entity, err := lookup(id)
+		if err != nil {
+			return nil, err
+		}
+		if entity != nil {
+			result.Matches = append(result.Matches, *entity)
+		}
+	}
+
+	remote, err := s.assessments.ResolveID(ctx, id.String())
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("assessment service not checked: %v", err))
+	} else {
+		result.Matches = append(result.Matches, remote...)
+	}
+
+	return result, nil
+}
+
+// find loads the record with id into dest, including soft-deleted ones. It
+// returns false when there is none.
+func (s *IDResolutionService) find(dest interface{}, id uuid.UUID) (bool, error) {
+	err := s.db.Unscoped().Where("id = ?", id).Take(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve ID: %w", err)
+	}
+	return true, nil
+}
+
+func (s *IDResolutionService) course(id uuid.UUID) (*ResolvedEntity, error) {
+	var course models.Course
+	if found, err := s.find(&course, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "course",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"title":        course.Title,
+			"slug":         course.Slug,
+			"status":       course.Status,
+			"instructorId": course.InstructorID,
+			"suspended":    course.SuspendedAt != nil,
+		},
+		Links:   map[string]string{"self": "/api/v1/courses/" + id.String()},
+		Deleted: course.DeletedAt.Valid,
+	}, nil
+}
+
+func (s *IDResolutionService) module(id uuid.UUID) (*ResolvedEntity, error) {
+	var module models.Module
+	if found, err := s.find(&module, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "module",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"title":      module.Title,
+			"courseId":   module.CourseID,
+			"orderIndex": module.OrderIndex,
+		},
+		Links: map[string]string{
+			"self":   "/api/v1/modules/" + id.String(),
+			"course": "/api/v1/courses/" + module.CourseID.String(),
+		},
+		Deleted: module.DeletedAt.Valid,
+	}, nil
+}
+
+func (s *IDResolutionService) lesson(id uuid.UUID) (*ResolvedEntity, error) {
+	var lesson models.Lesson
+	if found, err := s.find(&lesson, id); !found {
+		return nil, err
+	}
+
+	entity := &ResolvedEntity{
+		Service: "course-management",
+		Type:    "lesson",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"title":      lesson.Title,
+			"moduleId":   lesson.ModuleID,
+			"lessonType": lesson.LessonType,
+		},
+		Links: map[string]string{
+			"self":   "/api/v1/lessons/" + id.String(),
+			"module": "/api/v1/modules/" + lesson.ModuleID.String(),
+		},
+		Deleted: lesson.DeletedAt.Valid,
+	}
+
+	var module models.Module
+	if found, err := s.find(&module, lesson.ModuleID); err != nil {
+		return nil, err
+	} else if found {
+		entity.Summary["courseId"] = module.CourseID
+		entity.Links["course"] = "/api/v1/courses/" + module.CourseID.String()
+	}
+	return entity, nil
+}
+
+func (s *IDResolutionService) review(id uuid.UUID) (*ResolvedEntity, error) {
+	var review models.CourseReview
+	if found, err := s.find(&review, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "review",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"courseId": review.CourseID,
+			"userId":   review.UserID,
+			"rating":   review.Rating,
+		},
+		Links:   map[string]string{"course": "/api/v1/courses/" + review.CourseID.String()},
+		Deleted: review.DeletedAt.Valid,
+	}, nil
+}
+
+func (s *IDResolutionService) officeHourSlot(id uuid.UUID) (*ResolvedEntity, error) {
+	var slot models.OfficeHourSlot
+	if found, err := s.find(&slot, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "office_hour_slot",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"courseId":     slot.CourseID,
+			"instructorId": slot.InstructorID,
+			"startsAt":     slot.StartsAt,
+			"booked":       slot.Booked,
+			"capacity":     slot.Capacity,
+			"cancelled":    slot.CancelledAt != nil,
+		},
+		Links: map[string]string{
+			"course":   "/api/v1/courses/" + slot.CourseID.String(),
+			"bookings": fmt.Sprintf("/api/v1/courses/%s/office-hours/%s/bookings", slot.CourseID, id),
+		},
+	}, nil
+}
+
+func (s *IDResolutionService) officeHourBooking(id uuid.UUID) (*ResolvedEntity, error) {
+	var booking models.OfficeHourBooking
+	if found, err := s.find(&booking, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "office_hour_booking",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"slotId":   booking.SlotID,
+			"courseId": booking.CourseID,
+			"userId":   booking.UserID,
+			"status":   booking.Status,
+		},
+		Links: map[string]string{"course": "/api/v1/courses/" + booking.CourseID.String()},
+	}, nil
+}
+
+func (s *IDResolutionService) waitlistEntry(id uuid.UUID) (*ResolvedEntity, error) {
+	var entry models.WaitlistEntry
+	if found, err := s.find(&entry, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "waitlist_entry",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"courseId": entry.CourseID,
+			"userId":   entry.UserID,
+			"status":   entry.Status,
+			"joinedAt": entry.JoinedAt,
+		},
+		Links: map[string]string{
+			"course":   "/api/v1/courses/" + entry.CourseID.String(),
+			"waitlist": fmt.Sprintf("/api/v1/courses/%s/waitlist", entry.CourseID),
+		},
+	}, nil
+}
+
+func (s *IDResolutionService) seatReservation(id uuid.UUID) (*ResolvedEntity, error) {
+	var reservation models.SeatReservation
+	if found, err := s.find(&reservation, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "seat_reservation",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"courseId": reservation.CourseID,
+			"userId":   reservation.UserID,
+			"status":   reservation.Status,
+		},
+		Links: map[string]string{
+			"course": "/api/v1/courses/" + reservation.CourseID.String(),
+			"seats":  fmt.Sprintf("/api/v1/internal/courses/%s/seats", reservation.CourseID),
+		},
+	}, nil
+}
+
+func (s *IDResolutionService) courseFlag(id uuid.UUID) (*ResolvedEntity, error) {
+	var flag models.CourseFlag
+	if found, err := s.find(&flag, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "course_flag",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"courseId":   flag.CourseID,
+			"reporterId": flag.ReporterID,
+			"reason":     flag.Reason,
+			"status":     flag.Status,
+		},
+		Links: map[string]string{"course": "/api/v1/courses/" + flag.CourseID.String()},
+	}, nil
+}
+
+func (s *IDResolutionService) exportJob(id uuid.UUID) (*ResolvedEntity, error) {
+	var job models.ExportJob
+	if found, err := s.find(&job, id); !found {
+		return nil, err
+	}
+	return &ResolvedEntity{
+		Service: "course-management",
+		Type:    "export_job",
+		ID:      id,
+		Summary: map[string]interface{}{
+			"kind":        job.Kind,
+			"status":      job.Status,
+			"requestedBy": job.RequestedBy,
+			"createdAt":   job.CreatedAt,
+		},
+		Links: map[string]string{"self": "/api/v1/exports/" + id.String()},
+	}, nil
+}
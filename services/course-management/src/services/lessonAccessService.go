@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidUnlockCondition is returned for unlock conditions that can't be evaluated
+var ErrInvalidUnlockCondition = errors.New("invalid unlock condition")
+
+// UnlockRequirement reports whether one of a lesson's unlock conditions holds
+type UnlockRequirement struct {
+	models.LessonUnlockCondition
+	Met    bool   `json:"met"`
+	Detail string `json:"detail"`
+}
+
+// LessonAccess is whether a student may open a lesson, and why not
+type LessonAccess struct {
+	LessonID     uuid.UUID           `json:"lessonId"`
+	Locked       bool                `json:"locked"`
+	Completed    bool                `json:"completed"`
+	Requirements []UnlockRequirement `json:"unlockRequirements,omitempty"`
+}
+
+// LessonAccessService evaluates lesson unlock conditions for students and
+// records the lesson completions they depend on
+type LessonAccessService struct {
+	db          *gorm.DB
+	assessments *AssessmentClient
+}
+
+// NewLessonAccessService creates a new LessonAccessService
+func NewLessonAccessService() *LessonAccessService {
+	return &LessonAccessService{
+		db:          config.DB,
+		assessments: NewAssessmentClient(),
+	}
+}
+
+// ValidateConditions checks that conditions on a lesson in courseID refer to
+// things that exist and could be met
+func (s *LessonAccessService) ValidateConditions(courseID, lessonID uuid.UUID, conditions []models.LessonUnlockCondition) error {
+	for _, condition := range conditions {
+		switch condition.Type {
+		case models.UnlockPreviousLessonCompleted:
+		case models.UnlockLessonCompleted:
+			if condition.LessonID == nil {
+				return fmt.Errorf("%w: lesson_completed requires lessonId", ErrInvalidUnlockCondition)
+			}
+			if *condition.LessonID == lessonID {
+				return fmt.Errorf("%w: a lesson can't require itself", ErrInvalidUnlockCondition)
+			}
+			var count int64
+			if err := s.db.Model(&models.Lesson{}).
+				Joins("JOIN modules ON modules.id = lessons.module_id").
+				Where("lessons.id = ? AND modules.course_id = ?", *condition.LessonID, courseID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check required lesson: %w", err)
+			}
+			if count == 0 {
+				return fmt.Errorf("%w: lesson %s is not in this course", ErrInvalidUnlockCondition, *condition.LessonID)
+			}
+		case models.UnlockAssessmentPassed:
+			if condition.AssessmentID == nil {
+				return fmt.Errorf("%w: assessment_passed requires assessmentId", ErrInvalidUnlockCondition)
+			}
+			if condition.MinScore != nil && (*condition.MinScore < 0 || *condition.MinScore > 100) {
+				return fmt.Errorf("%w: minScore must be between 0 and 100", ErrInvalidUnlockCondition)
+			}
+		default:
+			return fmt.Errorf("%w: unknown type %q", ErrInvalidUnlockCondition, condition.Type)
+		}
+	}
+	return nil
+}
+
+// Evaluate works out which of lessons, all in courseID, userID may open and
+// has completed. uuid.Nil stands for an anonymous visitor, for whom every
+// condition is unmet. Assessment results that can't be fetched count as
+// unmet, so an assessment service outage keeps gated lessons locked rather
+// than opening them.
+func (s *LessonAccessService) Evaluate(ctx context.Context, courseID, userID uuid.UUID, lessons []models.Lesson) (map[uuid.UUID]*LessonAccess, error) {
+	access := make(map[uuid.UUID]*LessonAccess, len(lessons))
+
+	var gated []models.Lesson
+	var assessmentIDs []uuid.UUID
+	needsOrder := false
+	for _, lesson := range lessons {
+		access[lesson.ID] = &LessonAccess{LessonID: lesson.ID}
+		if len(lesson.UnlockConditions) == 0 {
+			continue
+		}
+		gated = append(gated, lesson)
+		for _, condition := range lesson.UnlockConditions {
+			switch condition.Type {
+			case models.UnlockPreviousLessonCompleted:
+				needsOrder = true
+			case models.UnlockAssessmentPassed:
+				if condition.AssessmentID != nil {
+					assessmentIDs = append(assessmentIDs, *condition.AssessmentID)
+				}
+			}
+		}
+	}
+
+	completed := map[uuid.UUID]bool{}
+	if userID != uuid.Nil {
+		var lessonIDs []uuid.UUID
+		if err := s.db.Model(&models.LessonCompletion{}).
+			Where("user_id = ? AND course_id = ?", userID, courseID).
+			Pluck("lesson_id", &lessonIDs).Error; err != nil {
+			return nil, fmt.Errorf("failed to get lesson completions: %w", err)
+		}
+		completed = toSet(lessonIDs)
+		for id, entry := range access {
+			entry.Completed = completed[id]
+		}
+	}
+	if len(gated) == 0 {
+		return access, nil
+	}
+
+	previous := map[uuid.UUID]uuid.UUID{}
+	if needsOrder {
+		order, err := s.courseLessonOrder(courseID)
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(order); i++ {
+			previous[order[i]] = order[i-1]
+		}
+	}
+
+	results := map[uuid.UUID]AssessmentResult{}
+	var resultsErr error
+	if userID != uuid.Nil && len(assessmentIDs) > 0 {
+		fetched, err := s.assessments.StudentResults(ctx, userID.String(), assessmentIDs)
+		if err != nil {
+			utils.Warn("Assessment results unavailable for lesson unlocks", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": courseID,
+				"userID":   userID,
+			})
+			resultsErr = err
+		}
+		for _, result := range fetched {
+			results[result.AssessmentID] = result
+		}
+	}
+
+	for _, lesson := range gated {
+		entry := access[lesson.ID]
+		for _, condition := range lesson.UnlockConditions {
+			requirement := UnlockRequirement{LessonUnlockCondition: condition}
+
+			switch condition.Type {
+			case models.UnlockPreviousLessonCompleted:
+				prev, ok := previous[lesson.ID]
+				if !ok {
+					requirement.Met = true
+					requirement.Detail = "first lesson in the course"
+					break
+				}
+				requirement.LessonID = &prev
+				requirement.Met = completed[prev]
+				requirement.Detail = completionDetail(requirement.Met, "previous lesson")
+			case models.UnlockLessonCompleted:
+				requirement.Met = condition.LessonID != nil && completed[*condition.LessonID]
+				requirement.Detail = completionDetail(requirement.Met, "required lesson")
+			case models.UnlockAssessmentPassed:
+				requirement.Met, requirement.Detail = assessmentRequirement(condition, results, resultsErr)
+			default:
+				requirement.Detail = "unknown condition"
+			}
+
+			if !requirement.Met {
+				entry.Locked = true
+			}
+			entry.Requirements = append(entry.Requirements, requirement)
+		}
+	}
+
+	return access, nil
+}
+
+// RecordCompletion marks lesson, in courseID, completed by userID. Completing
+// a lesson twice keeps the first completion.
+func (s *LessonAccessService) RecordCompletion(userID, courseID, lessonID uuid.UUID) (*models.LessonCompletion, error) {
+	completion := &models.LessonCompletion{
+		UserID:      userID,
+		LessonID:    lessonID,
+		CourseID:    courseID,
+		CompletedAt: time.Now().UTC(),
+	}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(completion).Error; err != nil {
+		return nil, fmt.Errorf("failed to record lesson completion: %w", err)
+	}
+	if err := s.db.Where("user_id = ? AND lesson_id = ?", userID, lessonID).First(completion).Error; err != nil {
+		return nil, fmt.Errorf("failed to load lesson completion: %w", err)
+	}
+	return completion, nil
+}
+
+// courseLessonOrder lists a course's lesson IDs in the order students take them
+func (s *LessonAccessService) courseLessonOrder(courseID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := s.db.Model(&models.Lesson{}).
+		Joins("JOIN modules ON modules.id = lessons.module_id AND modules.deleted_at IS NULL").
+		Where("modules.course_id = ?", courseID).
+		Order("modules.order_index ASC, lessons.order_index ASC").
+		Pluck("lessons.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get course lessons: %w", err)
+	}
+	return ids, nil
+}
+
+func completionDetail(met bool, what string) string {
+	if met {
+		return what + " completed"
+	}
+	return what + " not completed"
+}
+
+func assessmentRequirement(condition models.LessonUnlockCondition, results map[uuid.UUID]AssessmentResult, resultsErr error) (bool, string) {
+	if condition.AssessmentID == nil {
+		return false, "no assessment configured"
+	}
+	result, ok := results[*condition.AssessmentID]
+	if !ok {
+		if resultsErr != nil {
+			return false, "assessment results unavailable"
+		}
+		return false, "no graded attempt"
+	}
+	if condition.MinScore != nil {
+		return result.Percentage >= *condition.MinScore,
+			fmt.Sprintf("best score %.2f%%, needs %.2f%%", result.Percentage, *condition.MinScore)
+	}
+	if result.Passed {
+		return true, fmt.Sprintf("passed with %.2f%%", result.Percentage)
+	}
+	return false, fmt.Sprintf("best score %.2f%%, not yet passed", result.Percentage)
+}
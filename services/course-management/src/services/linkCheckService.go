@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+)
+
+const (
+	linkCheckWorkers = 8
+	linkCheckTimeout = 10 * time.Second
+	linkCheckLockKey = "link-check:lock"
+	// DefaultLinkCheckInterval applies when LINK_CHECK_INTERVAL is unset
+	DefaultLinkCheckInterval = 24 * time.Hour
+)
+
+// ErrLinkReportNotFound is returned for courses that have never been checked
+var ErrLinkReportNotFound = errors.New("link check report not found")
+
+// linkRef is a URL found in a course and where it was found
+type linkRef struct {
+	url         string
+	field       string
+	lessonID    *uuid.UUID
+	lessonTitle string
+}
+
+// LinkCheckService finds dead links and missing media in courses
+type LinkCheckService struct {
+	db     *gorm.DB
+	client *http.Client
+	events *EventPublisher
+}
+
+// NewLinkCheckService creates a new LinkCheckService
+func NewLinkCheckService() *LinkCheckService {
+	return &LinkCheckService{
+		db: config.DB,
+		client: &http.Client{
+			Timeout:   linkCheckTimeout,
+			Transport: &http.Transport{DialContext: publicDialer().DialContext},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+		events: NewEventPublisher(),
+	}
+}
+
+// publicDialer refuses connections to loopback, private and link-local
+// addresses so instructor-supplied URLs can't probe the internal network
+func publicDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout: linkCheckTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+				ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+				return fmt.Errorf("refusing to connect to non-public address %s", host)
+			}
+			return nil
+		},
+	}
+}
+
+// LinkCheckInterval reads LINK_CHECK_INTERVAL as a Go duration. "0" or "off"
+// disables scheduled checks.
+func LinkCheckInterval() time.Duration {
+	raw := os.Getenv("LINK_CHECK_INTERVAL")
+	if raw == "" {
+		return DefaultLinkCheckInterval
+	}
+	if raw == "off" {
+		return 0
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		utils.Warn("Invalid LINK_CHECK_INTERVAL, using default", map[string]interface{}{
+			"value": raw,
+		})
+		return DefaultLinkCheckInterval
+	}
+	return interval
+}
+
+// StartScheduler checks every published course each interval until ctx is
+// cancelled. A Redis lock keeps replicas from running the same sweep.
+func (s *LinkCheckService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				acquired, err := config.RedisClient.SetNX(ctx, linkCheckLockKey, "1", interval/2).Result()
+				if err != nil || !acquired {
+					continue
+				}
+				s.CheckPublishedCourses(ctx)
+			}
+		}
+	}()
+}
+
+// CheckPublishedCourses checks every published, unsuspended course
+func (s *LinkCheckService) CheckPublishedCourses(ctx context.Context) {
+	var ids []uuid.UUID
+	if err := s.db.Model(&models.Course{}).
+		Where("status = ? AND suspended_at IS NULL", models.CourseStatusPublished).
+		Pluck("id", &ids).Error; err != nil {
+		utils.Error("Failed to list courses for link check", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	utils.Info("Starting scheduled link check", map[string]interface{}{
+		"courses": len(ids),
+	})
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err := s.CheckCourse(ctx, id); err != nil {
+			utils.Error("Link check failed", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": id,
+			})
+		}
+	}
+}
+
+// CheckCourse checks every link in a course, stores the report and tells the
+// instructor when the set of broken links has changed since the last run.
+func (s *LinkCheckService) CheckCourse(ctx context.Context, courseID uuid.UUID) (*models.LinkCheckReport, error) {
+	var course models.Course
+	if err := s.db.Preload("Modules.Lessons").First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+
+	refs := collectLinks(&course)
+	results := s.checkURLs(ctx, refs)
+
+	report := models.LinkCheckReport{
+		CourseID:   courseID,
+		TotalLinks: len(refs),
+		Broken:     []models.BrokenLink{},
+		CheckedAt:  time.Now().UTC(),
+	}
+	for _, ref := range refs {
+		result := results[ref.url]
+		if result.ok {
+			continue
+		}
+		report.Broken = append(report.Broken, models.BrokenLink{
+			URL:         ref.url,
+			Field:       ref.field,
+			LessonID:    ref.lessonID,
+			LessonTitle: ref.lessonTitle,
+			StatusCode:  result.statusCode,
+			Error:       result.err,
+		})
+	}
+	report.BrokenCount = len(report.Broken)
+
+	previous, err := s.GetReport(courseID)
+	if err != nil && !errors.Is(err, ErrLinkReportNotFound) {
+		return nil, err
+	}
+	if previous != nil {
+		report.ID = previous.ID
+		report.CreatedAt = previous.CreatedAt
+	}
+	if err := s.db.Save(&report).Error; err != nil {
+		return nil, fmt.Errorf("failed to save link check report: %w", err)
+	}
+
+	if report.BrokenCount > 0 && (previous == nil || !sameBrokenURLs(previous.Broken, report.Broken)) {
+		if err := s.events.Publish(TopicCourseEvents, "COURSE_LINKS_BROKEN", "Course", courseID, "", map[string]interface{}{
+			"courseId":     courseID,
+			"instructorId": course.InstructorID,
+			"title":        course.Title,
+			"brokenCount":  report.BrokenCount,
+			"totalLinks":   report.TotalLinks,
+			"checkedAt":    report.CheckedAt,
+		}); err != nil {
+			utils.Warn("Failed to notify instructor of broken links", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": courseID,
+			})
+		}
+	}
+
+	return &report, nil
+}
+
+// GetReport returns the latest link check report for a course
+func (s *LinkCheckService) GetReport(courseID uuid.UUID) (*models.LinkCheckReport, error) {
+	var report models.LinkCheckReport
+	if err := s.db.Where("course_id = ?", courseID).First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLinkReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get link check report: %w", err)
+	}
+	return &report, nil
+}
+
+type linkResult struct {
+	ok         bool
+	statusCode int
+	err        string
+}
+
+// checkURLs checks each distinct URL once
+func (s *LinkCheckService) checkURLs(ctx context.Context, refs []linkRef) map[string]linkResult {
+	results := make(map[string]linkResult)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan string)
+
+	for i := 0; i < linkCheckWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range queue {
+				result := s.checkURL(ctx, u)
+				mu.Lock()
+				results[u] = result
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		if !seen[ref.url] {
+			seen[ref.url] = true
+			queue <- ref.url
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return results
+}
+
+// checkURL sends a HEAD request, retrying with a one-byte GET for servers
+// that don't support HEAD
+func (s *LinkCheckService) checkURL(ctx context.Context, rawURL string) linkResult {
+	status, err := s.request(ctx, http.MethodHead, rawURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden) {
+		status, err = s.request(ctx, http.MethodGet, rawURL)
+	}
+	if err != nil {
+		return linkResult{err: err.Error()}
+	}
+	return linkResult{ok: status < 400, statusCode: status}
+}
+
+func (s *LinkCheckService) request(ctx context.Context, method, rawURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "modex-link-checker/1.0")
+	if method == http.MethodGet {
+		req.Header.Set("Range", "bytes=0-0")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// collectLinks gathers the course's media URLs and the absolute links,
+// images and embeds in each lesson's rendered content
+func collectLinks(course *models.Course) []linkRef {
+	var refs []linkRef
+	add := func(rawURL, field string, lesson *models.Lesson) {
+		rawURL = strings.TrimSpace(rawURL)
+		if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		ref := linkRef{url: rawURL, field: field}
+		if lesson != nil {
+			id := lesson.ID
+			ref.lessonID = &id
+			ref.lessonTitle = lesson.Title
+		}
+		refs = append(refs, ref)
+	}
+
+	add(course.ThumbnailURL, "thumbnailUrl", nil)
+	add(course.PreviewVideoURL, "previewVideoUrl", nil)
+	for i := range course.Modules {
+		for j := range course.Modules[i].Lessons {
+			lesson := &course.Modules[i].Lessons[j]
+			add(lesson.VideoURL, "lesson.videoUrl", lesson)
+			add(lesson.DownloadURL, "lesson.downloadUrl", lesson)
+			for _, link := range contentLinks(lesson.RenderedContent) {
+				add(link, "lesson.content", lesson)
+			}
+		}
+	}
+	return refs
+}
+
+// contentLinks extracts link, image and embed URLs from rendered HTML
+func contentLinks(rendered string) []string {
+	var links []string
+	tokenizer := html.NewTokenizer(strings.NewReader(rendered))
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			return links
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		for _, attr := range token.Attr {
+			if (token.Data == "a" && attr.Key == "href") || ((token.Data == "img" || token.Data == "iframe") && attr.Key == "src") {
+				links = append(links, attr.Val)
+			}
+		}
+	}
+}
+
+func sameBrokenURLs(a, b []models.BrokenLink) bool {
+	urls := func(links []models.BrokenLink) []string {
+		out := make([]string, len(links))
+		for i, l := range links {
+			out[i] = l.Field + " " + l.URL
+		}
+		sort.Strings(out)
+		return out
+	}
+	ua, ub := urls(a), urls(b)
+	if len(ua) != len(ub) {
+		return false
+	}
+	for i := range ua {
+		if ua[i] != ub[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// renderMarkdown converts the markdown subset supported by the lesson editor
+// to HTML: ATX headings, paragraphs, fenced code, blockquotes, flat lists,
+// rules, emphasis, code spans, links and images. Raw HTML blocks pass through
+// untouched, so the output must always be sanitized.
+func renderMarkdown(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	var out strings.Builder
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence := trimmed[:3]
+			lang := strings.TrimSpace(trimmed[3:])
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code")
+			if lang != "" {
+				out.WriteString(` class="language-` + html.EscapeString(lang) + `"`)
+			}
+			out.WriteString(">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case headingPattern.MatchString(trimmed):
+			flush()
+			m := headingPattern.FindStringSubmatch(trimmed)
+			level := len(m[1])
+			text := strings.TrimRight(strings.TrimSpace(m[2]), "# ")
+			out.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, renderInline(text), level))
+
+		case rulePattern.MatchString(trimmed):
+			flush()
+			out.WriteString("<hr>\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+			}
+			i--
+			out.WriteString("<blockquote>\n" + renderMarkdown(strings.Join(quoted, "\n")) + "</blockquote>\n")
+
+		case bulletPattern.MatchString(line) || orderedPattern.MatchString(line):
+			flush()
+			ordered := orderedPattern.MatchString(line)
+			item := bulletPattern
+			tag := "ul"
+			if ordered {
+				item, tag = orderedPattern, "ol"
+			}
+
+			var items []string
+			for ; i < len(lines); i++ {
+				if m := item.FindStringSubmatch(lines[i]); m != nil {
+					items = append(items, m[1])
+					continue
+				}
+				// Indented lines continue the previous item
+				if len(items) > 0 && strings.TrimSpace(lines[i]) != "" && strings.HasPrefix(lines[i], "  ") {
+					items[len(items)-1] += "\n" + strings.TrimSpace(lines[i])
+					continue
+				}
+				break
+			}
+			i--
+
+			out.WriteString("<" + tag + ">\n")
+			for _, it := range items {
+				out.WriteString("<li>" + renderInline(it) + "</li>\n")
+			}
+			out.WriteString("</" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, "<") && len(paragraph) == 0:
+			// Raw HTML block, up to the next blank line
+			var block []string
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+				block = append(block, lines[i])
+			}
+			out.WriteString(strings.Join(block, "\n") + "\n")
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+
+	return out.String()
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	rulePattern    = regexp.MustCompile(`^(\*\s*){3,}$|^(-\s*){3,}$|^(_\s*){3,}$`)
+	bulletPattern  = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	orderedPattern = regexp.MustCompile(`^\s{0,3}\d{1,9}[.)]\s+(.*)$`)
+
+	codeSpanPattern = regexp.MustCompile("`([^`]+)`")
+	inlineRules     = []struct {
+		pattern     *regexp.Regexp
+		replacement string
+	}{
+		{regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`), `<img src="$2" alt="$1">`},
+		{regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`), `<a href="$2">$1</a>`},
+		{regexp.MustCompile(`\*\*(.+?)\*\*`), `<strong>$1</strong>`},
+		{regexp.MustCompile(`__(.+?)__`), `<strong>$1</strong>`},
+		{regexp.MustCompile(`\*([^*\s][^*]*)\*`), `<em>$1</em>`},
+		{regexp.MustCompile(`(^|[^\w])_([^_\s][^_]*)_([^\w]|$)`), `$1<em>$2</em>$3`},
+		{regexp.MustCompile(`~~(.+?)~~`), `<del>$1</del>`},
+	}
+)
+
+// renderInline escapes text and applies inline markdown, leaving code spans
+// verbatim.
+func renderInline(text string) string {
+	var out strings.Builder
+	last := 0
+	for _, loc := range codeSpanPattern.FindAllStringSubmatchIndex(text, -1) {
+		out.WriteString(applyInlineRules(text[last:loc[0]]))
+		out.WriteString("<code>" + html.EscapeString(text[loc[2]:loc[3]]) + "</code>")
+		last = loc[1]
+	}
+	out.WriteString(applyInlineRules(text[last:]))
+	return out.String()
+}
+
+func applyInlineRules(text string) string {
+	text = html.EscapeString(text)
+	for _, rule := range inlineRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidFlagReason is returned for an unknown report category
+	ErrInvalidFlagReason = errors.New("invalid flag reason")
+	// ErrCourseSuspended is returned when suspending an already suspended course
+	ErrCourseSuspended = errors.New("course is already suspended")
+	// ErrCourseNotSuspended is returned when reinstating a course that isn't suspended
+	ErrCourseNotSuspended = errors.New("course is not suspended")
+	// ErrCourseNotPublished is returned when force-unpublishing a course that isn't live
+	ErrCourseNotPublished = errors.New("course is not published")
+)
+
+var flagReasons = map[models.FlagReason]bool{
+	models.FlagReasonSpam:          true,
+	models.FlagReasonInappropriate: true,
+	models.FlagReasonCopyright:     true,
+	models.FlagReasonMisleading:    true,
+	models.FlagReasonOther:         true,
+}
+
+// FlaggedCourse summarises the reports against one course
+type FlaggedCourse struct {
+	CourseID      uuid.UUID                   `json:"courseId"`
+	Title         string                      `json:"title"`
+	InstructorID  uuid.UUID                   `json:"instructorId"`
+	Status        models.CourseStatus         `json:"status"`
+	SuspendedAt   *time.Time                  `json:"suspendedAt,omitempty"`
+	FlagCount     int64                       `json:"flagCount"`
+	Reasons       map[models.FlagReason]int64 `json:"reasons"`
+	LastFlaggedAt time.Time                   `json:"lastFlaggedAt"`
+}
+
+// CourseAvailability reports whether a course is open for enrollment
+type CourseAvailability struct {
+	CourseID    uuid.UUID           `json:"courseId"`
+	Status      models.CourseStatus `json:"status"`
+	Suspended   bool                `json:"suspended"`
+	Enrollable  bool                `json:"enrollable"`
+	SuspendedAt *time.Time          `json:"suspendedAt,omitempty"`
+}
+
+// ModerationService lets admins act on reported courses. Every action is
+// recorded and announced on course-events so enrollment and payment can
+// freeze or resume sales and access.
+type ModerationService struct {
+	db     *gorm.DB
+	cache  *CacheService
+	events *EventPublisher
+}
+
+// NewModerationService creates a new ModerationService
+func NewModerationService() *ModerationService {
+	return &ModerationService{
+		db:     config.DB,
+		cache:  NewCacheService(),
+		events: NewEventPublisher(),
+	}
+}
+
+// FlagCourse files a report against a course. A reporter with an open report
+// on the course gets that report back rather than a duplicate.
+func (s *ModerationService) FlagCourse(courseID, reporterID uuid.UUID, reason models.FlagReason, details string) (*models.CourseFlag, error) {
+	if !flagReasons[reason] {
+		return nil, ErrInvalidFlagReason
+	}
+	if _, err := s.loadCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	var existing models.CourseFlag
+	err := s.db.Where("course_id = ? AND reporter_id = ? AND status = ?", courseID, reporterID, models.FlagStatusOpen).
+		First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing flag: %w", err)
+	}
+
+	flag := models.CourseFlag{
+		CourseID:   courseID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Details:    details,
+		Status:     models.FlagStatusOpen,
+	}
+	if err := s.db.Create(&flag).Error; err != nil {
+		return nil, fmt.Errorf("failed to flag course: %w", err)
+	}
+	return &flag, nil
+}
+
+// ListFlaggedCourses returns courses with reports in the given status, most
+// reported first
+func (s *ModerationService) ListFlaggedCourses(status models.FlagStatus, pageSize, offset int) ([]FlaggedCourse, int64, error) {
+	var total int64
+	if err := s.db.Model(&models.CourseFlag{}).
+		Where("status = ?", status).
+		Distinct("course_id").
+		Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count flagged courses: %w", err)
+	}
+
+	var rows []struct {
+		CourseID      uuid.UUID
+		FlagCount     int64
+		LastFlaggedAt time.Time
+	}
+	if err := s.db.Model(&models.CourseFlag{}).
+		Select("course_id, COUNT(*) AS flag_count, MAX(created_at) AS last_flagged_at").
+		Where("status = ?", status).
+		Group("course_id").
+		Order("flag_count DESC, last_flagged_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list flagged courses: %w", err)
+	}
+	if len(rows) == 0 {
+		return []FlaggedCourse{}, total, nil
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.CourseID
+	}
+
+	var courses []models.Course
+	if err := s.db.Unscoped().
+		Select("id", "title", "instructor_id", "status", "suspended_at").
+		Where("id IN ?", ids).
+		Find(&courses).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to load flagged courses: %w", err)
+	}
+	byID := make(map[uuid.UUID]models.Course, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+	}
+
+	var reasonCounts []struct {
+		CourseID uuid.UUID
+		Reason   models.FlagReason
+		Count    int64
+	}
+	if err := s.db.Model(&models.CourseFlag{}).
+		Select("course_id, reason, COUNT(*) AS count").
+		Where("status = ? AND course_id IN ?", status, ids).
+		Group("course_id, reason").
+		Scan(&reasonCounts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count flag reasons: %w", err)
+	}
+
+	flagged := make([]FlaggedCourse, len(rows))
+	index := make(map[uuid.UUID]int, len(rows))
+	for i, row := range rows {
+		course := byID[row.CourseID]
+		flagged[i] = FlaggedCourse{
+			CourseID:      row.CourseID,
+			Title:         course.Title,
+			InstructorID:  course.InstructorID,
+			Status:        course.Status,
+			SuspendedAt:   course.SuspendedAt,
+			FlagCount:     row.FlagCount,
+			Reasons:       make(map[models.FlagReason]int64),
+			LastFlaggedAt: row.LastFlaggedAt,
+		}
+		index[row.CourseID] = i
+	}
+	for _, rc := range reasonCounts {
+		flagged[index[rc.CourseID]].Reasons[rc.Reason] = rc.Count
+	}
+
+	return flagged, total, nil
+}
+
+// GetCourseFlags returns every report against a course, newest first
+func (s *ModerationService) GetCourseFlags(courseID uuid.UUID) ([]models.CourseFlag, error) {
+	var flags []models.CourseFlag
+	if err := s.db.Where("course_id = ?", courseID).Order("created_at DESC").Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("failed to get course flags: %w", err)
+	}
+	return flags, nil
+}
+
+// SuspendCourse hides a course from the catalog and freezes enrollment in it.
+// Its publishing status is left alone so reinstating restores it as it was.
+func (s *ModerationService) SuspendCourse(courseID, adminID uuid.UUID, reason string) (*models.Course, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.SuspendedAt != nil {
+		return nil, ErrCourseSuspended
+	}
+
+	now := time.Now().UTC()
+	err = s.apply(course, adminID, models.ModerationSuspend, reason, models.FlagStatusActioned, map[string]interface{}{
+		"suspended_at":      now,
+		"moderation_reason": reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	course.SuspendedAt = &now
+	course.ModerationReason = reason
+
+	s.publish("COURSE_SUSPENDED", course, adminID, map[string]interface{}{
+		"courseId":          course.ID,
+		"instructorId":      course.InstructorID,
+		"reason":            reason,
+		"suspendedAt":       now,
+		"enrollmentsFrozen": true,
+	})
+	return course, nil
+}
+
+// ReinstateCourse lifts a suspension
+func (s *ModerationService) ReinstateCourse(courseID, adminID uuid.UUID, reason string) (*models.Course, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.SuspendedAt == nil {
+		return nil, ErrCourseNotSuspended
+	}
+
+	err = s.apply(course, adminID, models.ModerationReinstate, reason, "", map[string]interface{}{
+		"suspended_at":      nil,
+		"moderation_reason": "",
+	})
+	if err != nil {
+		return nil, err
+	}
+	course.SuspendedAt = nil
+	course.ModerationReason = ""
+
+	s.publish("COURSE_REINSTATED", course, adminID, map[string]interface{}{
+		"courseId":     course.ID,
+		"instructorId": course.InstructorID,
+		"reason":       reason,
+	})
+	return course, nil
+}
+
+// ForceUnpublish returns a published course to draft. Existing enrollments
+// keep access; the instructor must fix and republish the course to sell it.
+func (s *ModerationService) ForceUnpublish(courseID, adminID uuid.UUID, reason string) (*models.Course, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != models.CourseStatusPublished {
+		return nil, ErrCourseNotPublished
+	}
+
+	err = s.apply(course, adminID, models.ModerationUnpublish, reason, models.FlagStatusActioned, map[string]interface{}{
+		"status":            models.CourseStatusDraft,
+		"is_published":      false,
+		"moderation_reason": reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	course.Status = models.CourseStatusDraft
+	course.IsPublished = false
+	course.ModerationReason = reason
+
+	s.publish("COURSE_FORCE_UNPUBLISHED", course, adminID, map[string]interface{}{
+		"courseId":     course.ID,
+		"instructorId": course.InstructorID,
+		"reason":       reason,
+	})
+	return course, nil
+}
+
+// DismissFlags closes a course's open reports without acting on the course
+func (s *ModerationService) DismissFlags(courseID, adminID uuid.UUID, resolution string) (int64, error) {
+	if _, err := s.loadCourse(courseID); err != nil {
+		return 0, err
+	}
+
+	var dismissed int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if dismissed, err = resolveFlags(tx, courseID, adminID, models.FlagStatusDismissed, resolution); err != nil {
+			return err
+		}
+		return recordAction(tx, courseID, adminID, models.ModerationDismissFlags, resolution)
+	})
+	return dismissed, err
+}
+
+// GetAvailability reports whether new enrollments in a course are allowed:
+// it must be published and not suspended
+func (s *ModerationService) GetAvailability(courseID uuid.UUID) (*CourseAvailability, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	suspended := course.SuspendedAt != nil
+	return &CourseAvailability{
+		CourseID:    course.ID,
+		Status:      course.Status,
+		Suspended:   suspended,
+		Enrollable:  course.Status == models.CourseStatusPublished && !suspended,
+		SuspendedAt: course.SuspendedAt,
+	}, nil
+}
+
+// apply updates the course, records the action and, when resolveAs is set,
+// closes the course's open reports, all in one transaction
+func (s *ModerationService) apply(course *models.Course, adminID uuid.UUID, action models.ModerationActionType, reason string, resolveAs models.FlagStatus, updates map[string]interface{}) error {
+	updates["version"] = gorm.Expr("version + 1")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Course{}).Where("id = ?", course.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update course: %w", err)
+		}
+		if resolveAs != "" {
+			if _, err := resolveFlags(tx, course.ID, adminID, resolveAs, reason); err != nil {
+				return err
+			}
+		}
+		return recordAction(tx, course.ID, adminID, action, reason)
+	})
+	if err != nil {
+		return err
+	}
+	course.Version++
+
+	s.cache.InvalidateCourse(course.ID.String())
+	s.cache.InvalidateAllCourses()
+	return nil
+}
+
+func (s *ModerationService) publish(eventType string, course *models.Course, adminID uuid.UUID, data map[string]interface{}) {
+	if err := s.events.Publish(TopicCourseEvents, eventType, "Course", course.ID, adminID.String(), data); err != nil {
+		utils.Error("Failed to publish moderation event", map[string]interface{}{
+			"error":     err.Error(),
+			"eventType": eventType,
+			"courseID":  course.ID,
+		})
+	}
+}
+
+func (s *ModerationService) loadCourse(courseID uuid.UUID) (*models.Course, error) {
+	var course models.Course
+	if err := s.db.First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	return &course, nil
+}
+
+func resolveFlags(tx *gorm.DB, courseID, adminID uuid.UUID, status models.FlagStatus, resolution string) (int64, error) {
+	result := tx.Model(&models.CourseFlag{}).
+		Where("course_id = ? AND status = ?", courseID, models.FlagStatusOpen).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": adminID,
+			"resolved_at": time.Now().UTC(),
+			"resolution":  resolution,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to resolve flags: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func recordAction(tx *gorm.DB, courseID, adminID uuid.UUID, action models.ModerationActionType, reason string) error {
+	entry := models.ModerationAction{CourseID: courseID, AdminID: adminID, Action: action, Reason: reason}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record moderation action: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,542 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultOfficeHoursReminderLead applies when OFFICE_HOURS_REMINDER_LEAD is unset
+	DefaultOfficeHoursReminderLead = time.Hour
+	// DefaultOfficeHoursReminderInterval applies when OFFICE_HOURS_REMINDER_INTERVAL is unset
+	DefaultOfficeHoursReminderInterval = 5 * time.Minute
+	officeHoursReminderLockKey         = "office-hours:reminders:lock"
+	maxOfficeHourSlotCapacity          = 100
+	maxOfficeHourSlotLength            = 8 * time.Hour
+)
+
+var (
+	// ErrInvalidSlot is returned for slots with bad times or capacity
+	ErrInvalidSlot = errors.New("invalid office-hours slot")
+	// ErrSlotNotFound is returned for unknown slots
+	ErrSlotNotFound = errors.New("office-hours slot not found")
+	// ErrSlotClosed is returned when booking a slot that was cancelled or has started
+	ErrSlotClosed = errors.New("office-hours slot is no longer open")
+	// ErrSlotFull is returned when every place in a slot is booked
+	ErrSlotFull = errors.New("office-hours slot is full")
+	// ErrAlreadyBooked is returned when the student already holds a place in the slot
+	ErrAlreadyBooked = errors.New("already booked")
+	// ErrBookingNotFound is returned when the student has no booking in the slot
+	ErrBookingNotFound = errors.New("booking not found")
+	// ErrNotEnrolled is returned when a student books office hours for a course they aren't taking
+	ErrNotEnrolled = errors.New("not enrolled in this course")
+)
+
+// CreateSlotInput describes a new office-hours slot
+type CreateSlotInput struct {
+	StartsAt   time.Time
+	EndsAt     time.Time
+	Capacity   int
+	Location   string
+	MeetingURL string
+	Notes      string
+}
+
+// CalendarEvent is an entry in a user's calendar feed. It matches the shape
+// the notification service reads for digests.
+type CalendarEvent struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	Title       string    `json:"title"`
+	CourseID    string    `json:"courseId,omitempty"`
+	CourseTitle string    `json:"courseTitle,omitempty"`
+	At          time.Time `json:"at"`
+	EndsAt      time.Time `json:"endsAt"`
+	Location    string    `json:"location,omitempty"`
+	URL         string    `json:"url,omitempty"`
+}
+
+// CalendarEventOfficeHours is the calendar entry type for office-hours slots
+const CalendarEventOfficeHours = "office_hours"
+
+// OfficeHoursService manages instructors' office-hours slots and students'
+// bookings. Booking takes a row lock on the slot so concurrent requests can't
+// exceed its capacity.
+type OfficeHoursService struct {
+	db           *gorm.DB
+	events       *EventPublisher
+	enrollments  *EnrollmentClient
+	reminderLead time.Duration
+}
+
+// NewOfficeHoursService creates a new OfficeHoursService
+func NewOfficeHoursService() *OfficeHoursService {
+	reminderLead := DefaultOfficeHoursReminderLead
+	if raw := os.Getenv("OFFICE_HOURS_REMINDER_LEAD"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			reminderLead = parsed
+		}
+	}
+
+	return &OfficeHoursService{
+		db:           config.DB,
+		events:       NewEventPublisher(),
+		enrollments:  NewEnrollmentClient(),
+		reminderLead: reminderLead,
+	}
+}
+
+// OfficeHoursReminderInterval reads OFFICE_HOURS_REMINDER_INTERVAL as a Go
+// duration. "0" or "off" disables reminders.
+func OfficeHoursReminderInterval() time.Duration {
+	raw := os.Getenv("OFFICE_HOURS_REMINDER_INTERVAL")
+	if raw == "" {
+		return DefaultOfficeHoursReminderInterval
+	}
+	if raw == "off" {
+		return 0
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		utils.Warn("Invalid OFFICE_HOURS_REMINDER_INTERVAL, using default", map[string]interface{}{
+			"value": raw,
+		})
+		return DefaultOfficeHoursReminderInterval
+	}
+	return interval
+}
+
+// CreateSlot opens a slot on the course hosted by instructorID
+func (s *OfficeHoursService) CreateSlot(courseID, instructorID uuid.UUID, input CreateSlotInput) (*models.OfficeHourSlot, error) {
+	if _, err := s.loadCourse(courseID); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !input.StartsAt.After(time.Now()):
+		return nil, fmt.Errorf("%w: start time must be in the future", ErrInvalidSlot)
+	case !input.EndsAt.After(input.StartsAt):
+		return nil, fmt.Errorf("%w: end time must be after start time", ErrInvalidSlot)
+	case input.EndsAt.Sub(input.StartsAt) > maxOfficeHourSlotLength:
+		return nil, fmt.Errorf("%w: slots can be at most %s long", ErrInvalidSlot, maxOfficeHourSlotLength)
+	case input.Capacity < 1 || input.Capacity > maxOfficeHourSlotCapacity:
+		return nil, fmt.Errorf("%w: capacity must be between 1 and %d", ErrInvalidSlot, maxOfficeHourSlotCapacity)
+	}
+
+	slot := &models.OfficeHourSlot{
+		CourseID:     courseID,
+		InstructorID: instructorID,
+		StartsAt:     input.StartsAt.UTC(),
+		EndsAt:       input.EndsAt.UTC(),
+		Capacity:     input.Capacity,
+		Location:     input.Location,
+		MeetingURL:   input.MeetingURL,
+		Notes:        input.Notes,
+	}
+	if err := s.db.Create(slot).Error; err != nil {
+		return nil, fmt.Errorf("failed to create office-hours slot: %w", err)
+	}
+	return slot, nil
+}
+
+// ListSlots returns the course's open slots that end after from, soonest first
+func (s *OfficeHoursService) ListSlots(courseID uuid.UUID, from time.Time) ([]models.OfficeHourSlot, error) {
+	var slots []models.OfficeHourSlot
+	if err := s.db.Where("course_id = ? AND cancelled_at IS NULL AND ends_at > ?", courseID, from.UTC()).
+		Order("starts_at ASC").
+		Find(&slots).Error; err != nil {
+		return nil, fmt.Errorf("failed to list office-hours slots: %w", err)
+	}
+	return slots, nil
+}
+
+// ListSlotBookings returns the active bookings for one of the course's slots
+func (s *OfficeHoursService) ListSlotBookings(courseID, slotID uuid.UUID) ([]models.OfficeHourBooking, error) {
+	if _, err := s.loadSlot(s.db, courseID, slotID); err != nil {
+		return nil, err
+	}
+
+	var bookings []models.OfficeHourBooking
+	if err := s.db.Where("slot_id = ? AND status = ?", slotID, models.BookingBooked).
+		Order("created_at ASC").
+		Find(&bookings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// CancelSlot withdraws a slot and cancels everyone booked into it
+func (s *OfficeHoursService) CancelSlot(courseID, slotID uuid.UUID) error {
+	now := time.Now().UTC()
+	var slot *models.OfficeHourSlot
+	var cancelled []models.OfficeHourBooking
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		slot, err = s.loadSlot(tx.Clauses(clause.Locking{Strength: "UPDATE"}), courseID, slotID)
+		if err != nil {
+			return err
+		}
+		if slot.CancelledAt != nil {
+			return ErrSlotClosed
+		}
+
+		if err := tx.Where("slot_id = ? AND status = ?", slotID, models.BookingBooked).
+			Find(&cancelled).Error; err != nil {
+			return fmt.Errorf("failed to load bookings: %w", err)
+		}
+		if err := tx.Model(&models.OfficeHourBooking{}).
+			Where("slot_id = ? AND status = ?", slotID, models.BookingBooked).
+			Updates(map[string]interface{}{"status": models.BookingCancelled, "cancelled_at": now}).Error; err != nil {
+			return fmt.Errorf("failed to cancel bookings: %w", err)
+		}
+
+		slot.CancelledAt = &now
+		slot.Booked = 0
+		if err := tx.Model(slot).Updates(map[string]interface{}{"cancelled_at": now, "booked": 0}).Error; err != nil {
+			return fmt.Errorf("failed to cancel slot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range cancelled {
+		s.publish("OFFICE_HOURS_CANCELLED", slot, &cancelled[i], map[string]interface{}{
+			"reason": "slot_cancelled",
+		})
+	}
+	return nil
+}
+
+// Book gives a student a place in a slot. Only students enrolled in the
+// course may book, and a slot closes to new bookings once it starts.
+func (s *OfficeHoursService) Book(ctx context.Context, courseID, slotID, userID uuid.UUID, note string) (*models.OfficeHourBooking, error) {
+	enrolled, err := s.enrollments.IsEnrolled(ctx, userID.String(), courseID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify enrollment: %w", err)
+	}
+	if !enrolled {
+		return nil, ErrNotEnrolled
+	}
+
+	var slot *models.OfficeHourSlot
+	var booking models.OfficeHourBooking
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		slot, err = s.loadSlot(tx.Clauses(clause.Locking{Strength: "UPDATE"}), courseID, slotID)
+		if err != nil {
+			return err
+		}
+		if slot.CancelledAt != nil || !slot.StartsAt.After(time.Now()) {
+			return ErrSlotClosed
+		}
+
+		err = tx.Where("slot_id = ? AND user_id = ?", slotID, userID).First(&booking).Error
+		switch {
+		case err == nil && booking.Status == models.BookingBooked:
+			return ErrAlreadyBooked
+		case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+			return fmt.Errorf("failed to get booking: %w", err)
+		}
+
+		if slot.Booked >= slot.Capacity {
+			return ErrSlotFull
+		}
+
+		if booking.ID == uuid.Nil {
+			booking = models.OfficeHourBooking{
+				SlotID:   slotID,
+				CourseID: courseID,
+				UserID:   userID,
+				Status:   models.BookingBooked,
+				Note:     note,
+			}
+			if err := tx.Create(&booking).Error; err != nil {
+				return fmt.Errorf("failed to create booking: %w", err)
+			}
+		} else {
+			booking.Status = models.BookingBooked
+			booking.Note = note
+			booking.CancelledAt = nil
+			booking.ReminderSentAt = nil
+			if err := tx.Model(&booking).Updates(map[string]interface{}{
+				"status":           booking.Status,
+				"note":             booking.Note,
+				"cancelled_at":     nil,
+				"reminder_sent_at": nil,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to rebook: %w", err)
+			}
+		}
+
+		slot.Booked++
+		if err := tx.Model(slot).Update("booked", slot.Booked).Error; err != nil {
+			return fmt.Errorf("failed to update slot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	booking.Slot = slot
+	s.publish("OFFICE_HOURS_BOOKED", slot, &booking, nil)
+	return &booking, nil
+}
+
+// CancelBooking gives the student's place in a slot back
+func (s *OfficeHoursService) CancelBooking(courseID, slotID, userID uuid.UUID) error {
+	var slot *models.OfficeHourSlot
+	var booking models.OfficeHourBooking
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		slot, err = s.loadSlot(tx.Clauses(clause.Locking{Strength: "UPDATE"}), courseID, slotID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("slot_id = ? AND user_id = ? AND status = ?", slotID, userID, models.BookingBooked).
+			First(&booking).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrBookingNotFound
+			}
+			return fmt.Errorf("failed to get booking: %w", err)
+		}
+
+		now := time.Now().UTC()
+		booking.Status = models.BookingCancelled
+		booking.CancelledAt = &now
+		if err := tx.Model(&booking).Updates(map[string]interface{}{
+			"status":       booking.Status,
+			"cancelled_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to cancel booking: %w", err)
+		}
+
+		if slot.Booked > 0 {
+			slot.Booked--
+		}
+		if err := tx.Model(slot).Update("booked", slot.Booked).Error; err != nil {
+			return fmt.Errorf("failed to update slot: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish("OFFICE_HOURS_CANCELLED", slot, &booking, map[string]interface{}{
+		"reason": "booking_cancelled",
+	})
+	return nil
+}
+
+// ListBookings returns the student's active bookings in slots ending after from
+func (s *OfficeHoursService) ListBookings(userID uuid.UUID, from time.Time) ([]models.OfficeHourBooking, error) {
+	var bookings []models.OfficeHourBooking
+	if err := s.db.Preload("Slot").
+		Joins("JOIN office_hour_slots ON office_hour_slots.id = office_hour_bookings.slot_id").
+		Where("office_hour_bookings.user_id = ? AND office_hour_bookings.status = ? AND office_hour_slots.ends_at > ?",
+			userID, models.BookingBooked, from.UTC()).
+		Order("office_hour_slots.starts_at ASC").
+		Find(&bookings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list bookings: %w", err)
+	}
+	return bookings, nil
+}
+
+// CalendarEvents returns the office hours on userID's calendar between from
+// and to: slots they booked and, for instructors, slots they host
+func (s *OfficeHoursService) CalendarEvents(userID uuid.UUID, from, to time.Time) ([]CalendarEvent, error) {
+	var slots []models.OfficeHourSlot
+	if err := s.db.Where("cancelled_at IS NULL AND starts_at < ? AND ends_at > ?", to.UTC(), from.UTC()).
+		Where("instructor_id = ? OR id IN (?)", userID,
+			s.db.Model(&models.OfficeHourBooking{}).Select("slot_id").
+				Where("user_id = ? AND status = ?", userID, models.BookingBooked)).
+		Order("starts_at ASC").
+		Find(&slots).Error; err != nil {
+		return nil, fmt.Errorf("failed to load calendar: %w", err)
+	}
+
+	titles, err := s.courseTitles(slots)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]CalendarEvent, 0, len(slots))
+	for _, slot := range slots {
+		title := "Office hours"
+		if slot.InstructorID == userID {
+			title = fmt.Sprintf("Office hours (hosting, %d/%d booked)", slot.Booked, slot.Capacity)
+		}
+		events = append(events, CalendarEvent{
+			ID:          "office-hours:" + slot.ID.String(),
+			Type:        CalendarEventOfficeHours,
+			Title:       title,
+			CourseID:    slot.CourseID.String(),
+			CourseTitle: titles[slot.CourseID],
+			At:          slot.StartsAt,
+			EndsAt:      slot.EndsAt,
+			Location:    slot.Location,
+			URL:         slot.MeetingURL,
+		})
+	}
+	return events, nil
+}
+
+// StartReminders sends reminders for upcoming bookings each interval until
+// ctx is cancelled
+func (s *OfficeHoursService) StartReminders(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				acquired, err := config.RedisClient.SetNX(ctx, officeHoursReminderLockKey, "1", interval/2).Result()
+				if err != nil || !acquired {
+					continue
+				}
+				s.SendDueReminders(ctx)
+			}
+		}
+	}()
+}
+
+// SendDueReminders publishes a reminder for each booking whose slot starts
+// within the reminder lead time. Each booking is marked before its reminder
+// goes out so it is never reminded twice.
+func (s *OfficeHoursService) SendDueReminders(ctx context.Context) {
+	now := time.Now().UTC()
+	var due []models.OfficeHourBooking
+	if err := s.db.Preload("Slot").
+		Joins("JOIN office_hour_slots ON office_hour_slots.id = office_hour_bookings.slot_id").
+		Where("office_hour_bookings.status = ? AND office_hour_bookings.reminder_sent_at IS NULL", models.BookingBooked).
+		Where("office_hour_slots.cancelled_at IS NULL AND office_hour_slots.starts_at > ? AND office_hour_slots.starts_at <= ?",
+			now, now.Add(s.reminderLead)).
+		Find(&due).Error; err != nil {
+		utils.Error("Failed to list office-hours bookings due a reminder", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for i := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		result := s.db.Model(&models.OfficeHourBooking{}).
+			Where("id = ? AND reminder_sent_at IS NULL", due[i].ID).
+			Update("reminder_sent_at", now)
+		if result.Error != nil {
+			utils.Error("Failed to mark office-hours reminder", map[string]interface{}{
+				"error":     result.Error.Error(),
+				"bookingID": due[i].ID,
+			})
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		s.publish("OFFICE_HOURS_REMINDER", due[i].Slot, &due[i], nil)
+	}
+}
+
+// publish sends an office-hours event for one booking; failures are logged
+// since the booking itself has already been saved
+func (s *OfficeHoursService) publish(eventType string, slot *models.OfficeHourSlot, booking *models.OfficeHourBooking, extra map[string]interface{}) {
+	var title string
+	if course, err := s.loadCourse(slot.CourseID); err == nil {
+		title = course.Title
+	}
+
+	data := map[string]interface{}{
+		"courseId":     slot.CourseID,
+		"title":        title,
+		"slotId":       slot.ID,
+		"bookingId":    booking.ID,
+		"userId":       booking.UserID,
+		"instructorId": slot.InstructorID,
+		"startsAt":     slot.StartsAt,
+		"endsAt":       slot.EndsAt,
+		"location":     slot.Location,
+		"meetingUrl":   slot.MeetingURL,
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+
+	if err := s.events.Publish(TopicEnrollmentEvents, eventType, "Course", slot.CourseID, booking.UserID.String(), data); err != nil {
+		utils.Error("Failed to publish office-hours event", map[string]interface{}{
+			"error":     err.Error(),
+			"eventType": eventType,
+			"bookingID": booking.ID,
+		})
+	}
+}
+
+func (s *OfficeHoursService) courseTitles(slots []models.OfficeHourSlot) (map[uuid.UUID]string, error) {
+	titles := make(map[uuid.UUID]string)
+	if len(slots) == 0 {
+		return titles, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(slots))
+	for _, slot := range slots {
+		ids = append(ids, slot.CourseID)
+	}
+
+	var courses []models.Course
+	if err := s.db.Select("id", "title").Where("id IN ?", ids).Find(&courses).Error; err != nil {
+		return nil, fmt.Errorf("failed to load course titles: %w", err)
+	}
+	for _, course := range courses {
+		titles[course.ID] = course.Title
+	}
+	return titles, nil
+}
+
+func (s *OfficeHoursService) loadSlot(db *gorm.DB, courseID, slotID uuid.UUID) (*models.OfficeHourSlot, error) {
+	var slot models.OfficeHourSlot
+	if err := db.Where("id = ? AND course_id = ?", slotID, courseID).First(&slot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSlotNotFound
+		}
+		return nil, fmt.Errorf("failed to get office-hours slot: %w", err)
+	}
+	return &slot, nil
+}
+
+func (s *OfficeHoursService) loadCourse(courseID uuid.UUID) (*models.Course, error) {
+	var course models.Course
+	if err := s.db.Select("id", "title").First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	return &course, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"gorm.io/gorm"
+)
+
+// ErrCourseNotFound is returned when a policy check targets a missing course
+var ErrCourseNotFound = errors.New("course not found")
+
+// PolicyService decides what a user may do on a course. Course owners hold
+// every permission; collaborators hold the permissions they were granted.
+type PolicyService struct {
+	db *gorm.DB
+}
+
+// NewPolicyService creates a new PolicyService
+func NewPolicyService() *PolicyService {
+	return &PolicyService{db: config.DB}
+}
+
+// Can reports whether userID holds perm on courseID
+func (s *PolicyService) Can(userID, courseID uuid.UUID, perm models.CoursePermission) (bool, error) {
+	var course models.Course
+	if err := s.db.Select("id", "instructor_id").First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrCourseNotFound
+		}
+		return false, fmt.Errorf("failed to load course: %w", err)
+	}
+
+	if course.InstructorID == userID {
+		return true, nil
+	}
+
+	var collaborator models.CourseCollaborator
+	if err := s.db.Where("course_id = ? AND user_id = ?", courseID, userID).First(&collaborator).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load collaborator: %w", err)
+	}
+
+	return collaborator.HasPermission(perm), nil
+}
+
+// IsOwner reports whether userID owns courseID
+func (s *PolicyService) IsOwner(userID, courseID uuid.UUID) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.Course{}).
+		Where("id = ? AND instructor_id = ?", courseID, userID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check course ownership: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetCollaborators lists the collaborators of a course
+func (s *PolicyService) GetCollaborators(courseID uuid.UUID) ([]models.CourseCollaborator, error) {
+	var collaborators []models.CourseCollaborator
+	if err := s.db.Where("course_id = ?", courseID).Order("created_at ASC").Find(&collaborators).Error; err != nil {
+		return nil, fmt.Errorf("failed to get collaborators: %w", err)
+	}
+	return collaborators, nil
+}
+
+// SetCollaborator grants exactly perms to userID on courseID, replacing any previous grant
+func (s *PolicyService) SetCollaborator(courseID, userID, grantedBy uuid.UUID, perms []models.CoursePermission) (*models.CourseCollaborator, error) {
+	for _, perm := range perms {
+		if !isKnownPermission(perm) {
+			return nil, fmt.Errorf("unknown permission: %s", perm)
+		}
+	}
+
+	var collaborator models.CourseCollaborator
+	err := s.db.Where("course_id = ? AND user_id = ?", courseID, userID).First(&collaborator).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load collaborator: %w", err)
+	}
+
+	collaborator.CourseID = courseID
+	collaborator.UserID = userID
+	collaborator.Permissions = perms
+	collaborator.GrantedBy = grantedBy
+
+	if err := s.db.Save(&collaborator).Error; err != nil {
+		return nil, fmt.Errorf("failed to save collaborator: %w", err)
+	}
+	return &collaborator, nil
+}
+
+// RemoveCollaborator revokes every permission userID holds on courseID
+func (s *PolicyService) RemoveCollaborator(courseID, userID uuid.UUID) error {
+	return s.db.Where("course_id = ? AND user_id = ?", courseID, userID).
+		Delete(&models.CourseCollaborator{}).Error
+}
+
+func isKnownPermission(perm models.CoursePermission) bool {
+	for _, known := range models.AllCoursePermissions {
+		if known == perm {
+			return true
+		}
+	}
+	return false
+}
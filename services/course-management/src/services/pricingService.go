@@ -0,0 +1,234 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+)
+
+// iso4217 lists the active ISO 4217 currency codes
+var iso4217 = toCodeSet(`AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB
+BRL BSD BTN BWP BYN BZD CAD CDF CHF CLP CNY COP CRC CUP CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD
+FKP GBP GEL GHS GIP GMD GNF GTQ GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR
+KMF KPW KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MYR
+MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK
+SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD UYU UZS VES
+VND VUV WST XAF XCD XOF XPF YER ZAR ZMW ZWL`)
+
+// zeroDecimalCurrencies have no minor unit
+var zeroDecimalCurrencies = toCodeSet(`BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX VND VUV XAF XOF XPF`)
+
+// regionCurrencies maps a visitor's region to the currency shown when no
+// X-Currency header is sent
+var regionCurrencies = map[string]string{
+	"US": "USD", "CA": "CAD", "MX": "MXN", "BR": "BRL", "AR": "ARS", "CL": "CLP", "CO": "COP",
+	"GB": "GBP", "IE": "EUR", "FR": "EUR", "DE": "EUR", "ES": "EUR", "IT": "EUR", "NL": "EUR",
+	"BE": "EUR", "PT": "EUR", "AT": "EUR", "FI": "EUR", "GR": "EUR", "CH": "CHF", "SE": "SEK",
+	"NO": "NOK", "DK": "DKK", "PL": "PLN", "CZ": "CZK", "TR": "TRY", "NG": "NGN", "GH": "GHS",
+	"KE": "KES", "ZA": "ZAR", "EG": "EGP", "MA": "MAD", "AE": "AED", "SA": "SAR", "IL": "ILS",
+	"IN": "INR", "PK": "PKR", "BD": "BDT", "CN": "CNY", "JP": "JPY", "KR": "KRW", "SG": "SGD",
+	"ID": "IDR", "MY": "MYR", "PH": "PHP", "TH": "THB", "VN": "VND", "AU": "AUD", "NZ": "NZD",
+}
+
+func toCodeSet(codes string) map[string]bool {
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(codes) {
+		set[code] = true
+	}
+	return set
+}
+
+// NormalizeCurrency upper-cases code and reports whether it is a valid ISO 4217 code
+func NormalizeCurrency(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return code, iso4217[code]
+}
+
+// NormalizeRegion upper-cases an ISO 3166-1 alpha-2 region, returning false otherwise
+func NormalizeRegion(region string) (string, bool) {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if len(region) != 2 || region[0] < 'A' || region[0] > 'Z' || region[1] < 'A' || region[1] > 'Z' {
+		return "", false
+	}
+	return region, true
+}
+
+// PricingService manages regional course prices and resolves the price a
+// visitor sees.
+type PricingService struct {
+	db    *gorm.DB
+	rates map[string]float64
+}
+
+// NewPricingService creates a new PricingService. Exchange rates are read
+// from EXCHANGE_RATES as a JSON object of units per USD, e.g. {"EUR":0.92}.
+func NewPricingService() *PricingService {
+	rates := map[string]float64{"USD": 1}
+	if raw := os.Getenv("EXCHANGE_RATES"); raw != "" {
+		var configured map[string]float64
+		if err := json.Unmarshal([]byte(raw), &configured); err != nil {
+			utils.Warn("Ignoring invalid EXCHANGE_RATES", map[string]interface{}{"error": err.Error()})
+		}
+		for code, rate := range configured {
+			if code, ok := NormalizeCurrency(code); ok && rate > 0 {
+				rates[code] = rate
+			}
+		}
+	}
+
+	return &PricingService{db: config.DB, rates: rates}
+}
+
+// GetPrices lists the explicit prices for a course
+func (s *PricingService) GetPrices(courseID uuid.UUID) ([]models.CoursePrice, error) {
+	var prices []models.CoursePrice
+	if err := s.db.Where("course_id = ?", courseID).Order("currency ASC, region ASC").Find(&prices).Error; err != nil {
+		return nil, fmt.Errorf("failed to get course prices: %w", err)
+	}
+	return prices, nil
+}
+
+// ValidatePrices normalizes currency and region codes in place and rejects
+// unknown codes, negative amounts and duplicate currency/region pairs
+func (s *PricingService) ValidatePrices(prices []models.CoursePrice) error {
+	seen := make(map[string]bool)
+	for i := range prices {
+		currency, ok := NormalizeCurrency(prices[i].Currency)
+		if !ok {
+			return fmt.Errorf("invalid currency code: %s", prices[i].Currency)
+		}
+		prices[i].Currency = currency
+
+		if prices[i].Region != "" {
+			region, ok := NormalizeRegion(prices[i].Region)
+			if !ok {
+				return fmt.Errorf("invalid region code: %s", prices[i].Region)
+			}
+			prices[i].Region = region
+		}
+
+		if prices[i].Amount < 0 {
+			return fmt.Errorf("price for %s must not be negative", currency)
+		}
+		prices[i].Amount = roundForCurrency(prices[i].Amount, currency)
+
+		key := prices[i].Currency + "/" + prices[i].Region
+		if seen[key] {
+			return fmt.Errorf("duplicate price for %s", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ReplacePrices replaces every explicit price of the course with prices
+func (s *PricingService) ReplacePrices(courseID uuid.UUID, prices []models.CoursePrice) error {
+	if err := s.ValidatePrices(prices); err != nil {
+		return err
+	}
+	for i := range prices {
+		prices[i].ID = uuid.Nil
+		prices[i].CourseID = courseID
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseID).Delete(&models.CoursePrice{}).Error; err != nil {
+			return fmt.Errorf("failed to clear course prices: %w", err)
+		}
+		if len(prices) > 0 {
+			if err := tx.Create(&prices).Error; err != nil {
+				return fmt.Errorf("failed to save course prices: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ResolveDisplayPrices sets DisplayPrice on each course for the requested
+// currency and region. Either may be empty: without a currency the region's
+// local currency is used, and without either the base price is shown.
+func (s *PricingService) ResolveDisplayPrices(courses []models.Course, currency, region string) error {
+	if len(courses) == 0 {
+		return nil
+	}
+	if currency == "" && region != "" {
+		currency = regionCurrencies[region]
+	}
+
+	var prices []models.CoursePrice
+	if currency != "" {
+		ids := make([]uuid.UUID, len(courses))
+		for i, course := range courses {
+			ids[i] = course.ID
+		}
+		if err := s.db.Where("course_id IN ? AND currency = ?", ids, currency).Find(&prices).Error; err != nil {
+			return fmt.Errorf("failed to load course prices: %w", err)
+		}
+	}
+
+	byCourse := make(map[uuid.UUID][]models.CoursePrice)
+	for _, price := range prices {
+		byCourse[price.CourseID] = append(byCourse[price.CourseID], price)
+	}
+
+	for i := range courses {
+		courses[i].DisplayPrice = s.resolve(&courses[i], byCourse[courses[i].ID], currency, region)
+	}
+	return nil
+}
+
+func (s *PricingService) resolve(course *models.Course, prices []models.CoursePrice, currency, region string) *models.DisplayPrice {
+	baseCurrency := course.Currency
+	if baseCurrency == "" {
+		baseCurrency = "USD"
+	}
+	base := &models.DisplayPrice{Amount: course.Price, Currency: baseCurrency, Source: models.PriceSourceBase}
+
+	if currency == "" || currency == baseCurrency && len(prices) == 0 {
+		return base
+	}
+
+	var anyRegion *models.CoursePrice
+	for i := range prices {
+		if region != "" && prices[i].Region == region {
+			return &models.DisplayPrice{Amount: prices[i].Amount, Currency: currency, Region: region, Source: models.PriceSourceRegional}
+		}
+		if prices[i].Region == "" {
+			anyRegion = &prices[i]
+		}
+	}
+	if anyRegion != nil {
+		return &models.DisplayPrice{Amount: anyRegion.Amount, Currency: currency, Source: models.PriceSourceCurrency}
+	}
+	if currency == baseCurrency {
+		return base
+	}
+
+	fromRate, okFrom := s.rates[baseCurrency]
+	toRate, okTo := s.rates[currency]
+	if !okFrom || !okTo {
+		return base
+	}
+
+	converted := course.Price / fromRate * toRate
+	return &models.DisplayPrice{
+		Amount:   roundForCurrency(converted, currency),
+		Currency: currency,
+		Source:   models.PriceSourceConverted,
+	}
+}
+
+func roundForCurrency(amount float64, currency string) float64 {
+	if zeroDecimalCurrencies[currency] {
+		return math.Round(amount)
+	}
+	return math.Round(amount*100) / 100
+}
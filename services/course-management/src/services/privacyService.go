@@ -0,0 +1,371 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"github.com/modex/shared/exports"
+	"gorm.io/gorm"
+)
+
+// UserDataExport gathers every record tied to a user for a GDPR access request
+type UserDataExport struct {
+	UserID          uuid.UUID                   `json:"userId"`
+	GeneratedAt     time.Time                   `json:"generatedAt"`
+	CoursesAuthored []models.Course             `json:"coursesAuthored"`
+	Collaborations  []models.CourseCollaborator `json:"collaborations"`
+	GrantsIssued    []models.CourseCollaborator `json:"grantsIssued"`
+	Reviews         []models.CourseReview       `json:"reviews"`
+	Completions     []models.LessonCompletion   `json:"lessonCompletions"`
+	Bookings        []models.OfficeHourBooking  `json:"officeHourBookings"`
+	Reservations    []models.SeatReservation    `json:"seatReservations"`
+	Waitlists       []models.WaitlistEntry      `json:"waitlists"`
+	Assessments     json.RawMessage             `json:"assessments,omitempty"`
+	Warnings        []string                    `json:"warnings,omitempty"`
+}
+
+// UserErasureResult summarises what erasure changed in course-management
+type UserErasureResult struct {
+	UserID                uuid.UUID          `json:"userId"`
+	DeletedCourses        []uuid.UUID        `json:"deletedCourses"`
+	AnonymizedCourses     []uuid.UUID        `json:"anonymizedCourses"`
+	RemovedCollaborations int64              `json:"removedCollaborations"`
+	DeletedReviews        int64              `json:"deletedReviews"`
+	DeletedCompletions    int64              `json:"deletedLessonCompletions"`
+	DeletedBookings       int64              `json:"deletedOfficeHourBookings"`
+	DeletedReservations   int64              `json:"deletedSeatReservations"`
+	DeletedWaitlist       int64              `json:"deletedWaitlistEntries"`
+	Assessments           *AssessmentErasure `json:"assessments,omitempty"`
+}
+
+// Assessment erasure is retried with a doubling delay before erasure fails
+const (
+	assessmentErasureAttempts = 3
+	assessmentErasureBackoff  = time.Second
+)
+
+// PrivacyService implements GDPR data export and erasure for course data.
+// Assessment data is erased through the assessment service's internal API;
+// other services erase their own data when they receive USER_ERASURE_REQUESTED.
+type PrivacyService struct {
+	db            *gorm.DB
+	cache         *CacheService
+	courseService *CourseService
+	assessments   *AssessmentClient
+	seats         *SeatService
+	events        *EventPublisher
+}
+
+// NewPrivacyService creates a new PrivacyService
+func NewPrivacyService() *PrivacyService {
+	return &PrivacyService{
+		db:            config.DB,
+		cache:         NewCacheService(),
+		courseService: NewCourseService(),
+		assessments:   NewAssessmentClient(),
+		seats:         NewSeatService(),
+		events:        NewEventPublisher(),
+	}
+}
+
+// ExportUserData collects the user's course-management data along with their
+// assessment data. An unreachable assessment service is reported as a warning
+// rather than failing the whole export.
+func (s *PrivacyService) ExportUserData(ctx context.Context, userID uuid.UUID) (*UserDataExport, error) {
+	export := &UserDataExport{UserID: userID, GeneratedAt: time.Now().UTC()}
+
+	if err := s.db.Preload("Tags").Preload("Prerequisites").
+		Preload("Modules", func(db *gorm.DB) *gorm.DB { return db.Order("order_index ASC") }).
+		Preload("Modules.Lessons", func(db *gorm.DB) *gorm.DB { return db.Order("order_index ASC") }).
+		Where("instructor_id = ?", userID).
+		Find(&export.CoursesAuthored).Error; err != nil {
+		return nil, fmt.Errorf("failed to export authored courses: %w", err)
+	}
+
+	if err := s.db.Where("user_id = ?", userID).Find(&export.Collaborations).Error; err != nil {
+		return nil, fmt.Errorf("failed to export collaborations: %w", err)
+	}
+
+	if err := s.db.Where("granted_by = ?", userID).Find(&export.GrantsIssued).Error; err != nil {
+		return nil, fmt.Errorf("failed to export issued grants: %w", err)
+	}
+
+	// Deleted reviews are kept until erasure, so they belong in the export too
+	if err := s.db.Unscoped().Where("user_id = ?", userID).Order("created_at ASC").Find(&export.Reviews).Error; err != nil {
+		return nil, fmt.Errorf("failed to export reviews: %w", err)
+	}
+
+	if err := s.db.Where("user_id = ?", userID).Order("completed_at ASC").Find(&export.Completions).Error; err != nil {
+		return nil, fmt.Errorf("failed to export lesson completions: %w", err)
+	}
+
+	if err := s.db.Preload("Slot").Where("user_id = ?", userID).Order("created_at ASC").Find(&export.Bookings).Error; err != nil {
+		return nil, fmt.Errorf("failed to export office hour bookings: %w", err)
+	}
+
+	if err := s.db.Where("user_id = ?", userID.String()).Order("created_at ASC").Find(&export.Reservations).Error; err != nil {
+		return nil, fmt.Errorf("failed to export seat reservations: %w", err)
+	}
+
+	if err := s.db.Where("user_id = ?", userID).Order("joined_at ASC").Find(&export.Waitlists).Error; err != nil {
+		return nil, fmt.Errorf("failed to export waitlist entries: %w", err)
+	}
+
+	assessments, err := s.assessments.ExportUserData(ctx, userID.String())
+	if err != nil {
+		utils.Warn("Assessment data missing from privacy export", map[string]interface{}{
+			"error":  err.Error(),
+			"userID": userID,
+		})
+		export.Warnings = append(export.Warnings, "assessment data unavailable: "+err.Error())
+	} else {
+		export.Assessments = assessments
+	}
+
+	return export, nil
+}
+
+// WriteArchive writes the export as a zip archive with one JSON file per section
+func (s *PrivacyService) WriteArchive(w io.Writer, export *UserDataExport) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"manifest.json", map[string]interface{}{
+			"userId":      export.UserID,
+			"generatedAt": export.GeneratedAt,
+			"warnings":    export.Warnings,
+		}},
+		{"courses_authored.json", export.CoursesAuthored},
+		{"collaborations.json", export.Collaborations},
+		{"grants_issued.json", export.GrantsIssued},
+		{"reviews.json", export.Reviews},
+		{"lesson_completions.json", export.Completions},
+		{"office_hour_bookings.json", export.Bookings},
+		{"seat_reservations.json", export.Reservations},
+		{"waitlists.json", export.Waitlists},
+	}
+	if export.Assessments != nil {
+		files = append(files, struct {
+			name string
+			data interface{}
+		}{"assessments.json", export.Assessments})
+	}
+
+	for _, file := range files {
+		entry, err := archive.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("failed to add %s to archive: %w", file.name, err)
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// EraseUserData erases the user's assessment data, removes them from
+// course-management and announces the erasure so other services can do the
+// same. Courses that were never published are deleted; published courses
+// stay available to enrolled students but lose their link to the author.
+// If the assessment service can't erase its data nothing else is changed, so
+// the request can be repeated.
+func (s *PrivacyService) EraseUserData(ctx context.Context, userID, requestedBy uuid.UUID) (*UserErasureResult, error) {
+	result := &UserErasureResult{UserID: userID}
+
+	assessments, err := s.eraseAssessmentData(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to erase assessment data: %w", err)
+	}
+	result.Assessments = assessments
+
+	var courses []models.Course
+	if err := s.db.Select("id", "status", "published_at").
+		Where("instructor_id = ?", userID).
+		Find(&courses).Error; err != nil {
+		return nil, fmt.Errorf("failed to find authored courses: %w", err)
+	}
+
+	for _, course := range courses {
+		if course.Status == models.CourseStatusDraft && course.PublishedAt == nil {
+			result.DeletedCourses = append(result.DeletedCourses, course.ID)
+		} else {
+			result.AnonymizedCourses = append(result.AnonymizedCourses, course.ID)
+		}
+	}
+
+	for _, id := range result.DeletedCourses {
+		if err := s.courseService.DeleteCourse(id); err != nil {
+			return nil, fmt.Errorf("failed to delete draft course %s: %w", id, err)
+		}
+	}
+
+	// Held and confirmed seats are given back first so the Redis counters and
+	// the waitlist see them freed
+	var reservations []models.SeatReservation
+	if err := s.db.Select("id", "course_id").
+		Where("user_id = ? AND status IN ?", userID.String(), []models.ReservationStatus{models.ReservationHeld, models.ReservationConfirmed}).
+		Find(&reservations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find seat reservations: %w", err)
+	}
+	for _, reservation := range reservations {
+		if _, err := s.seats.Release(ctx, reservation.CourseID, reservation.ID); err != nil {
+			return nil, fmt.Errorf("failed to release seat reservation %s: %w", reservation.ID, err)
+		}
+	}
+
+	var reviewedCourses []uuid.UUID
+	if err := s.db.Unscoped().Model(&models.CourseReview{}).Where("user_id = ?", userID).
+		Distinct().Pluck("course_id", &reviewedCourses).Error; err != nil {
+		return nil, fmt.Errorf("failed to find reviews: %w", err)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if len(result.AnonymizedCourses) > 0 {
+			if err := tx.Model(&models.Course{}).
+				Where("id IN ?", result.AnonymizedCourses).
+				UpdateColumns(map[string]interface{}{
+					"instructor_id": uuid.Nil,
+					"version":       gorm.Expr("version + 1"),
+				}).Error; err != nil {
+				return fmt.Errorf("failed to anonymize courses: %w", err)
+			}
+		}
+
+		removed := tx.Where("user_id = ?", userID).Delete(&models.CourseCollaborator{})
+		if removed.Error != nil {
+			return fmt.Errorf("failed to remove collaborations: %w", removed.Error)
+		}
+		result.RemovedCollaborations = removed.RowsAffected
+
+		if err := tx.Model(&models.CourseCollaborator{}).
+			Where("granted_by = ?", userID).
+			Update("granted_by", uuid.Nil).Error; err != nil {
+			return fmt.Errorf("failed to anonymize issued grants: %w", err)
+		}
+
+		reviews := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.CourseReview{})
+		if reviews.Error != nil {
+			return fmt.Errorf("failed to delete reviews: %w", reviews.Error)
+		}
+		result.DeletedReviews = reviews.RowsAffected
+		for _, courseID := range reviewedCourses {
+			if err := refreshRating(tx, courseID); err != nil {
+				return err
+			}
+		}
+
+		completions := tx.Where("user_id = ?", userID).Delete(&models.LessonCompletion{})
+		if completions.Error != nil {
+			return fmt.Errorf("failed to delete lesson completions: %w", completions.Error)
+		}
+		result.DeletedCompletions = completions.RowsAffected
+
+		// Active bookings give their place in the slot back before going
+		if err := tx.Model(&models.OfficeHourSlot{}).
+			Where("id IN (?)", tx.Model(&models.OfficeHourBooking{}).Select("slot_id").
+				Where("user_id = ? AND status = ?", userID, models.BookingBooked)).
+			UpdateColumn("booked", gorm.Expr("GREATEST(booked - 1, 0)")).Error; err != nil {
+			return fmt.Errorf("failed to free booked office hours: %w", err)
+		}
+		bookings := tx.Where("user_id = ?", userID).Delete(&models.OfficeHourBooking{})
+		if bookings.Error != nil {
+			return fmt.Errorf("failed to delete office hour bookings: %w", bookings.Error)
+		}
+		result.DeletedBookings = bookings.RowsAffected
+
+		seats := tx.Where("user_id = ?", userID.String()).Delete(&models.SeatReservation{})
+		if seats.Error != nil {
+			return fmt.Errorf("failed to delete seat reservations: %w", seats.Error)
+		}
+		result.DeletedReservations = seats.RowsAffected
+
+		waitlist := tx.Where("user_id = ?", userID).Delete(&models.WaitlistEntry{})
+		if waitlist.Error != nil {
+			return fmt.Errorf("failed to delete waitlist entries: %w", waitlist.Error)
+		}
+		result.DeletedWaitlist = waitlist.RowsAffected
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range append(result.AnonymizedCourses, reviewedCourses...) {
+		s.cache.InvalidateCourse(id.String())
+	}
+	s.cache.InvalidateAllCourses()
+
+	if err := s.events.Publish(TopicUserEvents, "USER_ERASURE_REQUESTED", "User", userID, requestedBy.String(), map[string]interface{}{
+		"userId":      userID,
+		"requestedBy": requestedBy,
+		"requestedAt": time.Now().UTC(),
+	}); err != nil {
+		return result, fmt.Errorf("course data erased but other services were not notified: %w", err)
+	}
+
+	return result, nil
+}
+
+// eraseAssessmentData asks the assessment service to erase userID's data,
+// retrying while it is unreachable or failing
+func (s *PrivacyService) eraseAssessmentData(ctx context.Context, userID uuid.UUID) (*AssessmentErasure, error) {
+	delay := assessmentErasureBackoff
+	for attempt := 1; ; attempt++ {
+		erased, err := s.assessments.EraseUserData(ctx, userID.String())
+		if err == nil {
+			return erased, nil
+		}
+
+		var serviceErr *ServiceError
+		retryable := !errors.As(err, &serviceErr) || serviceErr.StatusCode >= http.StatusInternalServerError
+		if !retryable || attempt == assessmentErasureAttempts {
+			return nil, err
+		}
+
+		utils.Warn("Retrying assessment data erasure", map[string]interface{}{
+			"error":   err.Error(),
+			"userID":  userID,
+			"attempt": attempt,
+		})
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// exportUserDataJob builds the user data archive for an export job
+func (s *PrivacyService) exportUserDataJob(ctx context.Context, job *models.ExportJob, w io.Writer) (string, string, error) {
+	userID, err := exports.ParamUUID(job, "userId")
+	if err != nil {
+		return "", "", err
+	}
+
+	export, err := s.ExportUserData(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.WriteArchive(w, export); err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("user-%s-export.zip", userID), exportContentTypeZip, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"gorm.io/gorm"
+)
+
+// ProvisioningService applies identity lifecycle changes (SCIM provisioning
+// and deprovisioning) to course ownership.
+type ProvisioningService struct {
+	db    *gorm.DB
+	cache *CacheService
+}
+
+// NewProvisioningService creates a new ProvisioningService
+func NewProvisioningService() *ProvisioningService {
+	return &ProvisioningService{
+		db:    config.DB,
+		cache: NewCacheService(),
+	}
+}
+
+// TransferOwnership moves every course owned by fromID to toID and returns
+// the IDs of the courses that changed hands.
+func (s *ProvisioningService) TransferOwnership(fromID, toID uuid.UUID) ([]uuid.UUID, error) {
+	if fromID == toID {
+		return nil, fmt.Errorf("successor must differ from the deprovisioned user")
+	}
+
+	var courseIDs []uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Course{}).
+			Where("instructor_id = ?", fromID).
+			Pluck("id", &courseIDs).Error; err != nil {
+			return fmt.Errorf("failed to find owned courses: %w", err)
+		}
+
+		if len(courseIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Course{}).
+			Where("id IN ?", courseIDs).
+			Update("instructor_id", toID).Error; err != nil {
+			return fmt.Errorf("failed to transfer course ownership: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range courseIDs {
+		s.cache.InvalidateCourse(id.String())
+	}
+
+	return courseIDs, nil
+}
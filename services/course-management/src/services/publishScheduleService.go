@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	publishSchedulerLockKey = "publish-scheduler:lock"
+	// DefaultPublishSchedulerInterval applies when PUBLISH_SCHEDULER_INTERVAL is unset
+	DefaultPublishSchedulerInterval = time.Minute
+)
+
+var (
+	// ErrPublishAtInPast is returned when scheduling a publish time that has passed
+	ErrPublishAtInPast = errors.New("publishAt must be in the future")
+	// ErrCourseAlreadyPublished is returned when scheduling a course that is live
+	ErrCourseAlreadyPublished = errors.New("course is already published")
+	// ErrCourseNotPublishable is returned when a course is suspended or has
+	// no lessons to publish
+	ErrCourseNotPublishable = errors.New("course cannot be published")
+	// ErrNoPublishScheduled is returned when cancelling a schedule that doesn't exist
+	ErrNoPublishScheduled = errors.New("course has no scheduled publish")
+)
+
+// PublishScheduleService publishes courses at the time their instructor chose
+type PublishScheduleService struct {
+	db     *gorm.DB
+	cache  *CacheService
+	events *EventPublisher
+}
+
+// NewPublishScheduleService creates a new PublishScheduleService
+func NewPublishScheduleService() *PublishScheduleService {
+	return &PublishScheduleService{
+		db:     config.DB,
+		cache:  NewCacheService(),
+		events: NewEventPublisher(),
+	}
+}
+
+// PublishSchedulerInterval reads PUBLISH_SCHEDULER_INTERVAL as a Go duration.
+// "0" or "off" disables scheduled publishing.
+func PublishSchedulerInterval() time.Duration {
+	raw := os.Getenv("PUBLISH_SCHEDULER_INTERVAL")
+	if raw == "" {
+		return DefaultPublishSchedulerInterval
+	}
+	if raw == "off" {
+		return 0
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		utils.Warn("Invalid PUBLISH_SCHEDULER_INTERVAL, using default", map[string]interface{}{
+			"value": raw,
+		})
+		return DefaultPublishSchedulerInterval
+	}
+	return interval
+}
+
+// Schedule sets or moves the time a draft course will be published
+func (s *PublishScheduleService) Schedule(courseID uuid.UUID, publishAt time.Time) (*models.Course, error) {
+	if !publishAt.After(time.Now()) {
+		return nil, ErrPublishAtInPast
+	}
+
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status == models.CourseStatusPublished {
+		return nil, ErrCourseAlreadyPublished
+	}
+	if err := s.checkPublishable(course); err != nil {
+		return nil, err
+	}
+
+	publishAt = publishAt.UTC()
+	if err := s.db.Model(&models.Course{}).Where("id = ?", courseID).
+		Update("publish_at", publishAt).Error; err != nil {
+		return nil, fmt.Errorf("failed to schedule publish: %w", err)
+	}
+	course.PublishAt = &publishAt
+
+	s.cache.InvalidateCourse(courseID.String())
+	return course, nil
+}
+
+// Cancel clears a course's scheduled publish time
+func (s *PublishScheduleService) Cancel(courseID uuid.UUID) error {
+	result := s.db.Model(&models.Course{}).
+		Where("id = ? AND publish_at IS NOT NULL", courseID).
+		Update("publish_at", nil)
+	if result.Error != nil {
+		return fmt.Errorf("failed to cancel scheduled publish: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if _, err := s.loadCourse(courseID); err != nil {
+			return err
+		}
+		return ErrNoPublishScheduled
+	}
+
+	s.cache.InvalidateCourse(courseID.String())
+	return nil
+}
+
+// StartScheduler publishes due courses each interval until ctx is cancelled.
+// A Redis lock keeps replicas from publishing the same course twice.
+func (s *PublishScheduleService) StartScheduler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				acquired, err := config.RedisClient.SetNX(ctx, publishSchedulerLockKey, "1", interval/2).Result()
+				if err != nil || !acquired {
+					continue
+				}
+				s.PublishDue(ctx)
+			}
+		}
+	}()
+}
+
+// PublishDue publishes every course whose scheduled time has passed. Courses
+// that were suspended or emptied since scheduling have their schedule cleared
+// instead.
+func (s *PublishScheduleService) PublishDue(ctx context.Context) {
+	var due []models.Course
+	if err := s.db.Where("publish_at IS NOT NULL AND publish_at <= ? AND status <> ?", time.Now().UTC(), models.CourseStatusPublished).
+		Find(&due).Error; err != nil {
+		utils.Error("Failed to list courses due for publishing", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for i := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.publishScheduled(&due[i]); err != nil {
+			utils.Error("Scheduled publish failed", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": due[i].ID,
+			})
+		}
+	}
+}
+
+func (s *PublishScheduleService) publishScheduled(course *models.Course) error {
+	if err := s.checkPublishable(course); err != nil {
+		utils.Warn("Skipping scheduled publish", map[string]interface{}{
+			"courseID": course.ID,
+			"reason":   err.Error(),
+		})
+		return s.db.Model(&models.Course{}).Where("id = ?", course.ID).Update("publish_at", nil).Error
+	}
+
+	now := time.Now()
+	// The publish_at condition stops a cancel or manual publish that raced
+	// this run from being overwritten
+	result := s.db.Model(&models.Course{}).
+		Where("id = ? AND publish_at = ?", course.ID, course.PublishAt).
+		Updates(map[string]interface{}{
+			"status":       models.CourseStatusPublished,
+			"is_published": true,
+			"published_at": now,
+			"publish_at":   nil,
+			"version":      gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to publish course: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	s.cache.InvalidateCourse(course.ID.String())
+	s.cache.InvalidateAllCourses() // includes cached catalog feeds
+
+	if err := s.events.Publish(TopicCourseEvents, "COURSE_PUBLISHED", "Course", course.ID, "", map[string]interface{}{
+		"title":          course.Title,
+		"instructorId":   course.InstructorID,
+		"publishedAt":    now,
+		"scheduled":      true,
+		"organizationId": course.OrganizationID,
+	}); err != nil {
+		utils.Warn("Failed to publish course published event", map[string]interface{}{
+			"error":    err.Error(),
+			"courseID": course.ID,
+		})
+	}
+
+	utils.Info("Published scheduled course", map[string]interface{}{
+		"courseID": course.ID,
+	})
+	return nil
+}
+
+// checkPublishable applies the same rules as a manual publish: the course
+// must not be suspended and needs at least one lesson
+func (s *PublishScheduleService) checkPublishable(course *models.Course) error {
+	if course.SuspendedAt != nil {
+		return ErrCourseNotPublishable
+	}
+
+	var lessonCount int64
+	if err := s.db.Model(&models.Lesson{}).
+		Where("module_id IN (SELECT id FROM modules WHERE course_id = ? AND deleted_at IS NULL)", course.ID).
+		Count(&lessonCount).Error; err != nil {
+		return fmt.Errorf("failed to count lessons: %w", err)
+	}
+	if lessonCount == 0 {
+		return ErrCourseNotPublishable
+	}
+	return nil
+}
+
+func (s *PublishScheduleService) loadCourse(courseID uuid.UUID) (*models.Course, error) {
+	var course models.Course
+	if err := s.db.First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	return &course, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const residencyCacheTTL = 10 * time.Minute
+
+var (
+	// ErrInvalidRegion is returned for regions outside config.Regions
+	ErrInvalidRegion = errors.New("unsupported data residency region")
+	// ErrResidencyLocked is returned when moving an organization that already
+	// has a region; its data would be left behind in the old shard
+	ErrResidencyLocked = errors.New("organization data residency is already set; moving regions requires a data migration")
+)
+
+// Placement tells a service where an organization's data lives
+type Placement struct {
+	OrganizationID uuid.UUID `json:"organizationId"`
+	Region         string    `json:"region"`
+	Bucket         string    `json:"bucket,omitempty"`
+	Configured     bool      `json:"configured"` // false when the default region applies
+}
+
+// ResidencyService manages per-organization data residency and routes
+// database access and storage to the organization's region
+type ResidencyService struct {
+	db *gorm.DB
+}
+
+// NewResidencyService creates a new ResidencyService
+func NewResidencyService() *ResidencyService {
+	return &ResidencyService{db: config.DB}
+}
+
+// Resolve returns where an organization's data lives. Placements are cached
+// since every request for a pinned organization needs one.
+func (s *ResidencyService) Resolve(ctx context.Context, orgID uuid.UUID) (*Placement, error) {
+	key := config.CacheKey("residency", orgID.String())
+	if cached, err := config.RedisClient.Get(ctx, key).Bytes(); err == nil {
+		var placement Placement
+		if json.Unmarshal(cached, &placement) == nil {
+			return &placement, nil
+		}
+	}
+
+	placement := &Placement{OrganizationID: orgID, Region: config.DefaultRegion()}
+
+	var residency models.OrganizationResidency
+	err := s.db.First(&residency, "organization_id = ?", orgID).Error
+	switch {
+	case err == nil:
+		placement.Region = residency.Region
+		placement.Configured = true
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to get data residency: %w", err)
+	}
+	placement.Bucket = config.RegionBucket(placement.Region)
+
+	if payload, err := json.Marshal(placement); err == nil {
+		if err := config.RedisClient.Set(ctx, key, payload, residencyCacheTTL).Err(); err != nil {
+			utils.Warn("Failed to cache data residency", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	return placement, nil
+}
+
+// DB returns the database shard holding an organization's data
+func (s *ResidencyService) DB(ctx context.Context, orgID uuid.UUID) (*gorm.DB, error) {
+	placement, err := s.Resolve(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return config.RegionDB(placement.Region).WithContext(ctx), nil
+}
+
+// SetRegion pins an organization to a region. Setting the region it already
+// has is a no-op; moving it elsewhere is refused.
+func (s *ResidencyService) SetRegion(ctx context.Context, orgID uuid.UUID, region string, updatedBy *uuid.UUID) (*Placement, error) {
+	if !config.ValidRegion(region) {
+		return nil, ErrInvalidRegion
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.OrganizationResidency
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&existing, "organization_id = ?", orgID).Error
+		switch {
+		case err == nil:
+			if existing.Region != region {
+				return ErrResidencyLocked
+			}
+			return nil
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.OrganizationResidency{
+			OrganizationID: orgID,
+			Region:         region,
+			UpdatedBy:      updatedBy,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrResidencyLocked) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to set data residency: %w", err)
+	}
+
+	config.RedisClient.Del(ctx, config.CacheKey("residency", orgID.String()))
+	utils.Info("Organization data residency set", map[string]interface{}{
+		"organizationID": orgID,
+		"region":         region,
+	})
+	return s.Resolve(ctx, orgID)
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultReviewEditWindow applies when REVIEW_EDIT_WINDOW is unset
+	DefaultReviewEditWindow = 7 * 24 * time.Hour
+	// DefaultReviewHourlyLimit applies when REVIEW_HOURLY_LIMIT is unset
+	DefaultReviewHourlyLimit = 5
+
+	eligibleCacheTTL   = 30 * time.Minute
+	ineligibleCacheTTL = 2 * time.Minute
+)
+
+var (
+	// ErrInvalidRating is returned for ratings outside 1-5
+	ErrInvalidRating = errors.New("rating must be between 1 and 5")
+	// ErrReviewNotEligible is returned when the student hasn't completed the course
+	ErrReviewNotEligible = errors.New("only students who completed the course can review it")
+	// ErrAlreadyReviewed is returned for a second review of the same course
+	ErrAlreadyReviewed = errors.New("you have already reviewed this course")
+	// ErrReviewNotFound is returned for unknown reviews or reviews by someone else
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrReviewEditWindowClosed is returned when editing a review too long after posting
+	ErrReviewEditWindowClosed = errors.New("review can no longer be edited")
+	// ErrReviewRateLimited is returned when a student posts too many reviews in an hour
+	ErrReviewRateLimited = errors.New("too many reviews posted, try again later")
+)
+
+// ReviewInput is the editable content of a review
+type ReviewInput struct {
+	Rating int
+	Title  string
+	Body   string
+}
+
+// ReviewService accepts reviews only from students with a completed
+// enrollment, one per student per course, and keeps each course's rating
+// summary current
+type ReviewService struct {
+	db          *gorm.DB
+	cache       *CacheService
+	enrollments *EnrollmentClient
+	editWindow  time.Duration
+	hourlyLimit int64
+}
+
+// NewReviewService creates a new ReviewService
+func NewReviewService() *ReviewService {
+	editWindow := DefaultReviewEditWindow
+	if raw := os.Getenv("REVIEW_EDIT_WINDOW"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed >= 0 {
+			editWindow = parsed
+		}
+	}
+
+	hourlyLimit := int64(DefaultReviewHourlyLimit)
+	if raw := os.Getenv("REVIEW_HOURLY_LIMIT"); raw != "" {
+		if parsed, err := strconv.ParseInt(raw, 10, 64); err == nil && parsed > 0 {
+			hourlyLimit = parsed
+		}
+	}
+
+	return &ReviewService{
+		db:          config.DB,
+		cache:       NewCacheService(),
+		enrollments: NewEnrollmentClient(),
+		editWindow:  editWindow,
+		hourlyLimit: hourlyLimit,
+	}
+}
+
+// ListReviews returns a course's reviews, newest first
+func (s *ReviewService) ListReviews(courseID uuid.UUID, pageSize, offset int) ([]models.CourseReview, int64, error) {
+	query := s.db.Model(&models.CourseReview{}).Where("course_id = ?", courseID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	var reviews []models.CourseReview
+	if err := query.Order("created_at DESC").Limit(pageSize).Offset(offset).Find(&reviews).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	return reviews, total, nil
+}
+
+// CreateReview posts a review after checking the student completed the course
+func (s *ReviewService) CreateReview(ctx context.Context, courseID, userID uuid.UUID, input ReviewInput) (*models.CourseReview, error) {
+	input, err := normalizeReview(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var course models.Course
+	if err := s.db.Select("id", "instructor_id").First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	if course.InstructorID == userID {
+		return nil, ErrReviewNotEligible
+	}
+
+	var existing int64
+	if err := s.db.Unscoped().Model(&models.CourseReview{}).
+		Where("course_id = ? AND user_id = ?", courseID, userID).
+		Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check existing review: %w", err)
+	}
+	if existing > 0 {
+		return nil, ErrAlreadyReviewed
+	}
+
+	eligible, err := s.hasCompleted(ctx, courseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !eligible {
+		return nil, ErrReviewNotEligible
+	}
+
+	if err := s.takeRateLimit(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	review := models.CourseReview{
+		CourseID:      courseID,
+		UserID:        userID,
+		Rating:        input.Rating,
+		Title:         input.Title,
+		Body:          input.Body,
+		EditableUntil: time.Now().UTC().Add(s.editWindow),
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&review).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+				return ErrAlreadyReviewed
+			}
+			return fmt.Errorf("failed to create review: %w", err)
+		}
+		return refreshRating(tx, courseID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.InvalidateCourse(courseID.String())
+	return &review, nil
+}
+
+// UpdateReview edits the student's own review while the edit window is open
+func (s *ReviewService) UpdateReview(courseID, reviewID, userID uuid.UUID, input ReviewInput) (*models.CourseReview, error) {
+	input, err := normalizeReview(input)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.ownReview(courseID, reviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(review.EditableUntil) {
+		return nil, ErrReviewEditWindowClosed
+	}
+
+	review.Rating = input.Rating
+	review.Title = input.Title
+	review.Body = input.Body
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(review).Select("rating", "title", "body").Updates(review).Error; err != nil {
+			return fmt.Errorf("failed to update review: %w", err)
+		}
+		return refreshRating(tx, courseID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.InvalidateCourse(courseID.String())
+	return review, nil
+}
+
+// DeleteReview removes the student's own review. The student can't post
+// another one for the same course.
+func (s *ReviewService) DeleteReview(courseID, reviewID, userID uuid.UUID) error {
+	review, err := s.ownReview(courseID, reviewID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(review).Error; err != nil {
+			return fmt.Errorf("failed to delete review: %w", err)
+		}
+		return refreshRating(tx, courseID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.cache.InvalidateCourse(courseID.String())
+	return nil
+}
+
+func (s *ReviewService) ownReview(courseID, reviewID, userID uuid.UUID) (*models.CourseReview, error) {
+	var review models.CourseReview
+	if err := s.db.Where("id = ? AND course_id = ? AND user_id = ?", reviewID, courseID, userID).
+		First(&review).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return &review, nil
+}
+
+// hasCompleted asks the enrollment service whether the student completed the
+// course. Answers are cached briefly, negative ones for less time so a
+// student who just finished isn't kept waiting.
+func (s *ReviewService) hasCompleted(ctx context.Context, courseID, userID uuid.UUID) (bool, error) {
+	key := config.CacheKey("review-eligible", courseID.String(), userID.String())
+	if cached, err := config.RedisClient.Get(ctx, key).Result(); err == nil {
+		return cached == "1", nil
+	}
+
+	completed, err := s.enrollments.HasCompleted(ctx, userID.String(), courseID.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to verify enrollment: %w", err)
+	}
+
+	value, ttl := "0", ineligibleCacheTTL
+	if completed {
+		value, ttl = "1", eligibleCacheTTL
+	}
+	if err := config.RedisClient.Set(ctx, key, value, ttl).Err(); err != nil {
+		utils.Warn("Failed to cache review eligibility", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	return completed, nil
+}
+
+// takeRateLimit counts a review against the student's hourly allowance
+func (s *ReviewService) takeRateLimit(ctx context.Context, userID uuid.UUID) error {
+	key := config.CacheKey("review-rate", userID.String())
+	count, err := config.RedisClient.Incr(ctx, key).Result()
+	if err != nil {
+		// Don't block reviews when Redis is unavailable
+		utils.Warn("Failed to check review rate limit", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	if count == 1 {
+		config.RedisClient.Expire(ctx, key, time.Hour)
+	}
+	if count > s.hourlyLimit {
+		return ErrReviewRateLimited
+	}
+	return nil
+}
+
+func normalizeReview(input ReviewInput) (ReviewInput, error) {
+	if input.Rating < 1 || input.Rating > 5 {
+		return input, ErrInvalidRating
+	}
+	input.Title = strings.TrimSpace(input.Title)
+	input.Body = strings.TrimSpace(input.Body)
+	return input, nil
+}
+
+// refreshRating recomputes a course's rating average and review count
+func refreshRating(tx *gorm.DB, courseID uuid.UUID) error {
+	var summary struct {
+		Average float64
+		Count   int
+	}
+	if err := tx.Model(&models.CourseReview{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("course_id = ?", courseID).
+		Scan(&summary).Error; err != nil {
+		return fmt.Errorf("failed to summarise reviews: %w", err)
+	}
+
+	if err := tx.Model(&models.Course{}).Where("id = ?", courseID).
+		UpdateColumns(map[string]interface{}{
+			"rating_average": summary.Average,
+			"review_count":   summary.Count,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update course rating: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,529 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultSeatHoldTTL applies when SEAT_HOLD_TTL is unset
+	DefaultSeatHoldTTL = 15 * time.Minute
+	// DefaultSeatReconcileInterval applies when SEAT_RECONCILE_INTERVAL is unset
+	DefaultSeatReconcileInterval = 5 * time.Minute
+	seatReconcileLockKey         = "seats:reconcile:lock"
+	// seatReconcileAttempts bounds rebuilds lost to concurrent counter updates
+	seatReconcileAttempts = 5
+)
+
+var (
+	// ErrCourseFull is returned when every seat is taken or held
+	ErrCourseFull = errors.New("course is full")
+	// ErrReservationNotFound is returned for unknown reservations
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationExpired is returned when confirming a hold that timed out
+	ErrReservationExpired = errors.New("reservation has expired")
+	// ErrReservationClosed is returned when acting on a released or expired reservation
+	ErrReservationClosed = errors.New("reservation is no longer active")
+)
+
+// reserveScript drops expired holds, then adds a hold if confirmed seats plus
+// the other live holds are under capacity. It returns -1 when the counters are
+// missing and need rebuilding from Postgres, 0 when the course is full and 1
+// when the hold was taken. The hold itself may already be present if a
+// rebuild picked it up from Postgres, so it is never counted against itself.
+var reserveScript = redis.NewScript(`
+local confirmed = redis.call('GET', KEYS[1])
+if not confirmed then
+  return -1
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+redis.call('ZREM', KEYS[2], ARGV[4])
+local max = tonumber(ARGV[2])
+if max > 0 and tonumber(confirmed) + redis.call('ZCARD', KEYS[2]) >= max then
+  return 0
+end
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[4])
+return 1
+`)
+
+// confirmScript turns a live hold into a confirmed seat. It returns 0 if the
+// hold has already expired.
+var confirmScript = redis.NewScript(`
+local expiry = redis.call('ZSCORE', KEYS[2], ARGV[2])
+if not expiry or tonumber(expiry) <= tonumber(ARGV[1]) then
+  redis.call('ZREM', KEYS[2], ARGV[2])
+  return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[2])
+redis.call('INCR', KEYS[1])
+return 1
+`)
+
+// SeatAvailability summarises a course's capacity
+type SeatAvailability struct {
+	CourseID    uuid.UUID `json:"courseId"`
+	MaxStudents int       `json:"maxStudents"` // 0 = unlimited
+	Confirmed   int64     `json:"confirmed"`
+	Held        int64     `json:"held"`
+	Available   *int64    `json:"available,omitempty"`
+}
+
+// SeatService stops concurrent checkouts from overselling MaxStudents. Each
+// checkout reserves a seat; the hold is confirmed on enrollment, released
+// when abandoned, and lapses on its own after SEAT_HOLD_TTL.
+type SeatService struct {
+	db       *gorm.DB
+	waitlist *WaitlistService
+	holdTTL  time.Duration
+}
+
+// NewSeatService creates a new SeatService
+func NewSeatService() *SeatService {
+	holdTTL := DefaultSeatHoldTTL
+	if raw := os.Getenv("SEAT_HOLD_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			holdTTL = parsed
+		}
+	}
+
+	seats := &SeatService{
+		db:      config.DB,
+		holdTTL: holdTTL,
+	}
+	seats.waitlist = newWaitlistService(seats)
+	return seats
+}
+
+// SeatReconcileInterval reads SEAT_RECONCILE_INTERVAL as a Go duration. "0"
+// or "off" disables the background reconciler.
+func SeatReconcileInterval() time.Duration {
+	raw := os.Getenv("SEAT_RECONCILE_INTERVAL")
+	if raw == "" {
+		return DefaultSeatReconcileInterval
+	}
+	if raw == "off" {
+		return 0
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		utils.Warn("Invalid SEAT_RECONCILE_INTERVAL, using default", map[string]interface{}{
+			"value": raw,
+		})
+		return DefaultSeatReconcileInterval
+	}
+	return interval
+}
+
+// Reserve holds a seat for a student. A student with a live hold on the
+// course gets that hold back rather than taking a second seat; the unique
+// index on held reservations settles concurrent requests for the same
+// student. The reservation row is written before the Redis hold so a
+// reconcile never sees a hold it can't find in Postgres.
+func (s *SeatService) Reserve(ctx context.Context, courseID uuid.UUID, userID string) (*models.SeatReservation, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A timed-out hold the reconciler hasn't reached yet would still hold the
+	// student's slot in the index
+	now := time.Now().UTC()
+	if err := s.db.Model(&models.SeatReservation{}).
+		Where("course_id = ? AND user_id = ? AND status = ? AND expires_at <= ?",
+			courseID, userID, models.ReservationHeld, now).
+		Update("status", models.ReservationExpired).Error; err != nil {
+		return nil, fmt.Errorf("failed to expire old reservation: %w", err)
+	}
+
+	existing, err := s.liveHold(courseID, userID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing reservation: %w", err)
+	}
+
+	reservation := models.SeatReservation{
+		ID:        uuid.New(),
+		CourseID:  courseID,
+		UserID:    userID,
+		Status:    models.ReservationHeld,
+		ExpiresAt: now.Add(s.holdTTL),
+	}
+
+	if err := s.db.Create(&reservation).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+			// A concurrent request for the same student took the hold first
+			if existing, err := s.liveHold(courseID, userID); err == nil {
+				return existing, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to save reservation: %w", err)
+	}
+
+	taken, err := s.runReserve(ctx, course, &reservation)
+	if err == nil && taken == -1 {
+		if err = s.Reconcile(ctx, courseID); err == nil {
+			taken, err = s.runReserve(ctx, course, &reservation)
+		}
+	}
+	if err != nil || taken != 1 {
+		s.db.Delete(&reservation)
+		config.RedisClient.ZRem(ctx, seatHoldsKey(courseID), reservation.ID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve seat: %w", err)
+		}
+		return nil, ErrCourseFull
+	}
+	return &reservation, nil
+}
+
+// Confirm converts a hold into a taken seat once the student has enrolled
+func (s *SeatService) Confirm(ctx context.Context, courseID, reservationID uuid.UUID) (*models.SeatReservation, error) {
+	reservation, err := s.loadReservation(courseID, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	switch reservation.Status {
+	case models.ReservationConfirmed:
+		return reservation, nil
+	case models.ReservationHeld:
+	default:
+		return nil, ErrReservationClosed
+	}
+
+	// Postgres is updated before the Redis counter so a concurrent reconcile
+	// can only ever count the seat early, never miss it
+	now := time.Now().UTC()
+	result := s.db.Model(reservation).Where("status = ?", models.ReservationHeld).
+		Updates(map[string]interface{}{
+			"status":       models.ReservationConfirmed,
+			"confirmed_at": now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to save confirmation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Confirmed, released or expired by someone else in the meantime
+		current, err := s.loadReservation(courseID, reservationID)
+		if err != nil {
+			return nil, err
+		}
+		if current.Status != models.ReservationConfirmed {
+			return nil, ErrReservationClosed
+		}
+		return current, nil
+	}
+
+	confirmed, err := confirmScript.Run(ctx, config.RedisClient,
+		[]string{seatConfirmedKey(courseID), seatHoldsKey(courseID)},
+		time.Now().UnixMilli(), reservationID.String()).Int()
+	if err != nil {
+		s.db.Model(reservation).Where("status = ?", models.ReservationConfirmed).
+			Updates(map[string]interface{}{"status": models.ReservationHeld, "confirmed_at": nil})
+		return nil, fmt.Errorf("failed to confirm seat: %w", err)
+	}
+	if confirmed == 0 {
+		s.db.Model(reservation).Where("status = ?", models.ReservationConfirmed).
+			Updates(map[string]interface{}{"status": models.ReservationExpired, "confirmed_at": nil})
+		return nil, ErrReservationExpired
+	}
+
+	reservation.Status = models.ReservationConfirmed
+	reservation.ConfirmedAt = &now
+	return reservation, nil
+}
+
+// Release gives a seat back, either an abandoned hold or a confirmed seat
+// whose student withdrew. Freeing a confirmed seat offers it to the waitlist.
+// Only the caller whose update releases the reservation touches the Redis
+// counters, so concurrent releases can't free the same seat twice.
+func (s *SeatService) Release(ctx context.Context, courseID, reservationID uuid.UUID) (*models.SeatReservation, error) {
+	reservation, err := s.loadReservation(courseID, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := reservation.Status
+	if previous != models.ReservationHeld && previous != models.ReservationConfirmed {
+		return reservation, nil
+	}
+
+	now := time.Now().UTC()
+	result := s.db.Model(reservation).Where("status = ?", previous).
+		Updates(map[string]interface{}{
+			"status":      models.ReservationReleased,
+			"released_at": now,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to save release: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// Released, confirmed or expired by someone else in the meantime
+		return s.loadReservation(courseID, reservationID)
+	}
+	reservation.Status = models.ReservationReleased
+	reservation.ReleasedAt = &now
+
+	// The reconciler corrects the counters if Redis can't be updated now
+	wasConfirmed := previous == models.ReservationConfirmed
+	if wasConfirmed {
+		err = config.RedisClient.Decr(ctx, seatConfirmedKey(courseID)).Err()
+	} else {
+		err = config.RedisClient.ZRem(ctx, seatHoldsKey(courseID), reservationID.String()).Err()
+	}
+	if err != nil {
+		utils.Warn("Failed to update seat counters after release", map[string]interface{}{
+			"error":         err.Error(),
+			"courseID":      courseID,
+			"reservationID": reservationID,
+		})
+	}
+
+	if wasConfirmed {
+		confirmed, err := s.countConfirmed(courseID)
+		if err == nil {
+			_, err = s.waitlist.PromoteWaiting(courseID, int(confirmed))
+		}
+		if err != nil {
+			utils.Warn("Failed to promote waitlist after seat release", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": courseID,
+			})
+		}
+	}
+	return reservation, nil
+}
+
+// GetAvailability reports how many seats are taken, held and free
+func (s *SeatService) GetAvailability(ctx context.Context, courseID uuid.UUID) (*SeatAvailability, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed, err := config.RedisClient.Get(ctx, seatConfirmedKey(courseID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		if err = s.Reconcile(ctx, courseID); err == nil {
+			confirmed, err = config.RedisClient.Get(ctx, seatConfirmedKey(courseID)).Int64()
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seat counter: %w", err)
+	}
+
+	held, err := config.RedisClient.ZCount(ctx, seatHoldsKey(courseID),
+		strconv.FormatInt(time.Now().UnixMilli(), 10), "+inf").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seat holds: %w", err)
+	}
+
+	availability := &SeatAvailability{
+		CourseID:    courseID,
+		MaxStudents: course.MaxStudents,
+		Confirmed:   confirmed,
+		Held:        held,
+	}
+	if course.MaxStudents > 0 {
+		available := int64(course.MaxStudents) - confirmed - held
+		if available < 0 {
+			available = 0
+		}
+		availability.Available = &available
+	}
+	return availability, nil
+}
+
+// Reconcile marks timed-out holds expired in Postgres and rebuilds the
+// course's Redis counters from it. Holds are read from Redis before Postgres,
+// and only holds seen in Redis are removed, so a hold taken while reconciling
+// (whose row Reserve writes first) is never dropped. The counters are watched
+// while Postgres is read, and the rebuild retried if a confirm or release
+// changes them meanwhile, so their updates aren't overwritten.
+func (s *SeatService) Reconcile(ctx context.Context, courseID uuid.UUID) error {
+	now := time.Now().UTC()
+	if err := s.db.Model(&models.SeatReservation{}).
+		Where("course_id = ? AND status = ? AND expires_at <= ?", courseID, models.ReservationHeld, now).
+		Update("status", models.ReservationExpired).Error; err != nil {
+		return fmt.Errorf("failed to expire holds: %w", err)
+	}
+
+	for attempt := 0; attempt < seatReconcileAttempts; attempt++ {
+		err := config.RedisClient.Watch(ctx, func(tx *redis.Tx) error {
+			return s.rebuildCounters(ctx, tx, courseID, now)
+		}, seatConfirmedKey(courseID), seatHoldsKey(courseID))
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to rebuild seat counters: counters kept changing")
+}
+
+// rebuildCounters replaces the course's counters with what Postgres holds,
+// in a transaction that fails if the watched counters have changed
+func (s *SeatService) rebuildCounters(ctx context.Context, tx *redis.Tx, courseID uuid.UUID, now time.Time) error {
+	cached, err := tx.ZRange(ctx, seatHoldsKey(courseID), 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read seat holds: %w", err)
+	}
+
+	confirmed, err := s.countConfirmed(courseID)
+	if err != nil {
+		return err
+	}
+
+	var holds []models.SeatReservation
+	if err := s.db.Select("id", "expires_at").
+		Where("course_id = ? AND status = ?", courseID, models.ReservationHeld).
+		Find(&holds).Error; err != nil {
+		return fmt.Errorf("failed to list holds: %w", err)
+	}
+
+	live := make(map[string]bool, len(holds))
+	members := make([]redis.Z, len(holds))
+	for i, hold := range holds {
+		live[hold.ID.String()] = true
+		members[i] = redis.Z{Score: float64(hold.ExpiresAt.UnixMilli()), Member: hold.ID.String()}
+	}
+	var stale []interface{}
+	for _, member := range cached {
+		if !live[member] {
+			stale = append(stale, member)
+		}
+	}
+
+	_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, seatConfirmedKey(courseID), confirmed, 0)
+		if len(stale) > 0 {
+			pipe.ZRem(ctx, seatHoldsKey(courseID), stale...)
+		}
+		if len(members) > 0 {
+			pipe.ZAdd(ctx, seatHoldsKey(courseID), members...)
+		}
+		pipe.ZRemRangeByScore(ctx, seatHoldsKey(courseID), "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.TxFailedErr) {
+		return fmt.Errorf("failed to rebuild seat counters: %w", err)
+	}
+	return err
+}
+
+// StartReconciler reconciles every course with reservations each interval
+// until ctx is cancelled
+func (s *SeatService) StartReconciler(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				acquired, err := config.RedisClient.SetNX(ctx, seatReconcileLockKey, "1", interval/2).Result()
+				if err != nil || !acquired {
+					continue
+				}
+				s.reconcileAll(ctx)
+			}
+		}
+	}()
+}
+
+func (s *SeatService) reconcileAll(ctx context.Context) {
+	var courseIDs []uuid.UUID
+	if err := s.db.Model(&models.SeatReservation{}).
+		Where("status IN ?", []models.ReservationStatus{models.ReservationHeld, models.ReservationConfirmed}).
+		Distinct().Pluck("course_id", &courseIDs).Error; err != nil {
+		utils.Error("Failed to list courses for seat reconciliation", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	for _, courseID := range courseIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.Reconcile(ctx, courseID); err != nil {
+			utils.Error("Seat reconciliation failed", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": courseID,
+			})
+		}
+	}
+}
+
+func (s *SeatService) runReserve(ctx context.Context, course *models.Course, reservation *models.SeatReservation) (int, error) {
+	return reserveScript.Run(ctx, config.RedisClient,
+		[]string{seatConfirmedKey(course.ID), seatHoldsKey(course.ID)},
+		time.Now().UnixMilli(), course.MaxStudents,
+		reservation.ExpiresAt.UnixMilli(), reservation.ID.String()).Int()
+}
+
+// liveHold returns the student's unexpired hold on the course
+func (s *SeatService) liveHold(courseID uuid.UUID, userID string) (*models.SeatReservation, error) {
+	var hold models.SeatReservation
+	if err := s.db.Where("course_id = ? AND user_id = ? AND status = ? AND expires_at > ?",
+		courseID, userID, models.ReservationHeld, time.Now().UTC()).First(&hold).Error; err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (s *SeatService) countConfirmed(courseID uuid.UUID) (int64, error) {
+	var confirmed int64
+	if err := s.db.Model(&models.SeatReservation{}).
+		Where("course_id = ? AND status = ?", courseID, models.ReservationConfirmed).
+		Count(&confirmed).Error; err != nil {
+		return 0, fmt.Errorf("failed to count confirmed seats: %w", err)
+	}
+	return confirmed, nil
+}
+
+func (s *SeatService) loadReservation(courseID, reservationID uuid.UUID) (*models.SeatReservation, error) {
+	var reservation models.SeatReservation
+	if err := s.db.Where("id = ? AND course_id = ?", reservationID, courseID).First(&reservation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	return &reservation, nil
+}
+
+func (s *SeatService) loadCourse(courseID uuid.UUID) (*models.Course, error) {
+	var course models.Course
+	if err := s.db.Select("id", "max_students").First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	return &course, nil
+}
+
+func seatConfirmedKey(courseID uuid.UUID) string {
+	return config.CacheKey("seats", courseID.String(), "confirmed")
+}
+
+func seatHoldsKey(courseID uuid.UUID) string {
+	return config.CacheKey("seats", courseID.String(), "holds")
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ServiceError carries a downstream service's status and message
+type ServiceError struct {
+	Service    string
+	StatusCode int
+	Message    string
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("%s service returned %d: %s", e.Service, e.StatusCode, e.Message)
+}
+
+// serviceClient sends authenticated JSON requests to another internal service
+type serviceClient struct {
+	name       string
+	baseURL    string
+	serviceKey string
+	httpClient *http.Client
+}
+
+// newServiceClient reads the base URL from urlEnv, falling back to defaultURL
+func newServiceClient(name, urlEnv, defaultURL string) serviceClient {
+	baseURL := os.Getenv(urlEnv)
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+
+	return serviceClient{
+		name:       name,
+		baseURL:    baseURL,
+		serviceKey: os.Getenv("INTERNAL_SERVICE_KEY"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// do sends a JSON request and decodes the JSON response into out when non-nil
+func (c *serviceClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s request: %w", c.name, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build %s request: %w", c.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.serviceKey != "" {
+		req.Header.Set("X-Service-Key", c.serviceKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s service unavailable: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", c.name, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var payload struct {
+			Message string `json:"message"`
+			Error   string `json:"error"`
+		}
+		json.Unmarshal(respBody, &payload)
+		message := payload.Message
+		if message == "" {
+			message = payload.Error
+		}
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return &ServiceError{Service: c.name, StatusCode: resp.StatusCode, Message: message}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", c.name, err)
+		}
+	}
+
+	return nil
+}
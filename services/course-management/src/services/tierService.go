@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrTierNotFound is returned for unknown or inactive tier keys
+	ErrTierNotFound = errors.New("subscription tier not found")
+	// ErrTierExists is returned when creating a tier whose key is taken
+	ErrTierExists = errors.New("subscription tier already exists")
+)
+
+var tierKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,49}$`)
+
+// Entitlement reports whether a subscriber's tiers grant access to a course
+type Entitlement struct {
+	CourseID   uuid.UUID `json:"courseId"`
+	Entitled   bool      `json:"entitled"`
+	GrantedBy  string    `json:"grantedBy,omitempty"` // the subscriber tier that grants access
+	IncludedIn []string  `json:"includedIn"`          // tiers the course is flagged in
+}
+
+// TierService manages subscription tiers and the courses they include
+type TierService struct {
+	db *gorm.DB
+}
+
+// NewTierService creates a new TierService
+func NewTierService() *TierService {
+	return &TierService{db: config.DB}
+}
+
+// ValidTierKey reports whether key can be used as a tier key
+func ValidTierKey(key string) bool {
+	return tierKeyPattern.MatchString(key)
+}
+
+// ListTiers returns active tiers ordered by rank
+func (s *TierService) ListTiers() ([]models.SubscriptionTier, error) {
+	var tiers []models.SubscriptionTier
+	if err := s.db.Where("is_active = ?", true).Order("rank ASC, key ASC").Find(&tiers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list subscription tiers: %w", err)
+	}
+	return tiers, nil
+}
+
+// CreateTier stores a new tier
+func (s *TierService) CreateTier(tier *models.SubscriptionTier) error {
+	var count int64
+	if err := s.db.Model(&models.SubscriptionTier{}).Where("key = ?", tier.Key).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check subscription tier: %w", err)
+	}
+	if count > 0 {
+		return ErrTierExists
+	}
+
+	if err := s.db.Create(tier).Error; err != nil {
+		return fmt.Errorf("failed to create subscription tier: %w", err)
+	}
+	return nil
+}
+
+// GetTier loads a tier by key, active or not
+func (s *TierService) GetTier(key string) (*models.SubscriptionTier, error) {
+	var tier models.SubscriptionTier
+	if err := s.db.Where("key = ?", key).First(&tier).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTierNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription tier: %w", err)
+	}
+	return &tier, nil
+}
+
+// SaveTier persists changes to an existing tier
+func (s *TierService) SaveTier(tier *models.SubscriptionTier) error {
+	if err := s.db.Save(tier).Error; err != nil {
+		return fmt.Errorf("failed to update subscription tier: %w", err)
+	}
+	return nil
+}
+
+// DeleteTier removes a tier and unflags every course included in it
+func (s *TierService) DeleteTier(key string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("key = ?", key).Delete(&models.SubscriptionTier{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete subscription tier: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTierNotFound
+		}
+
+		if err := tx.Where("tier_key = ?", key).Delete(&models.CourseTier{}).Error; err != nil {
+			return fmt.Errorf("failed to remove course tiers: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetCourseTiers replaces the tiers a course is included in. Every key must
+// name an active tier.
+func (s *TierService) SetCourseTiers(courseID uuid.UUID, keys []string) ([]models.CourseTier, error) {
+	keys = uniqueStrings(keys)
+	if len(keys) > 0 {
+		var found int64
+		if err := s.db.Model(&models.SubscriptionTier{}).
+			Where("key IN ? AND is_active = ?", keys, true).
+			Count(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to check subscription tiers: %w", err)
+		}
+		if int(found) != len(keys) {
+			return nil, ErrTierNotFound
+		}
+	}
+
+	courseTiers := make([]models.CourseTier, len(keys))
+	for i, key := range keys {
+		courseTiers[i] = models.CourseTier{CourseID: courseID, TierKey: key}
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseID).Delete(&models.CourseTier{}).Error; err != nil {
+			return fmt.Errorf("failed to clear course tiers: %w", err)
+		}
+		if len(courseTiers) > 0 {
+			if err := tx.Create(&courseTiers).Error; err != nil {
+				return fmt.Errorf("failed to save course tiers: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return courseTiers, nil
+}
+
+// CoveredTierKeys returns the keys of every active tier a subscriber to key
+// can use: the tier itself and all lower-ranked active tiers.
+func (s *TierService) CoveredTierKeys(key string) ([]string, error) {
+	tier, err := s.GetTier(key)
+	if err != nil {
+		return nil, err
+	}
+	if !tier.IsActive {
+		return nil, ErrTierNotFound
+	}
+
+	var keys []string
+	if err := s.db.Model(&models.SubscriptionTier{}).
+		Where("is_active = ? AND rank <= ?", true, tier.Rank).
+		Pluck("key", &keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve subscription tiers: %w", err)
+	}
+	return keys, nil
+}
+
+// CheckEntitlement reports whether a subscriber holding subscriberTiers may
+// access the course through a subscription. Unknown or inactive subscriber
+// tiers grant nothing.
+func (s *TierService) CheckEntitlement(courseID uuid.UUID, subscriberTiers []string) (*Entitlement, error) {
+	entitlement := &Entitlement{CourseID: courseID, IncludedIn: []string{}}
+
+	if err := s.db.Model(&models.CourseTier{}).
+		Where("course_id = ?", courseID).
+		Order("tier_key ASC").
+		Pluck("tier_key", &entitlement.IncludedIn).Error; err != nil {
+		return nil, fmt.Errorf("failed to get course tiers: %w", err)
+	}
+	if len(entitlement.IncludedIn) == 0 {
+		return entitlement, nil
+	}
+
+	included := make(map[string]bool, len(entitlement.IncludedIn))
+	for _, key := range entitlement.IncludedIn {
+		included[key] = true
+	}
+
+	for _, key := range uniqueStrings(subscriberTiers) {
+		covered, err := s.CoveredTierKeys(key)
+		if errors.Is(err, ErrTierNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, coveredKey := range covered {
+			if included[coveredKey] {
+				entitlement.Entitled = true
+				entitlement.GrantedBy = key
+				return entitlement, nil
+			}
+		}
+	}
+
+	return entitlement, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"gorm.io/gorm"
+)
+
+// ErrTranslationNotFound is returned when a course has no translation for a locale
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// DefaultLocale is used for courses that don't declare a language
+const DefaultLocale = "en"
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// NormalizeLocale canonicalises a BCP 47 language tag to "ll" or "ll-RR",
+// returning false for anything else.
+func NormalizeLocale(locale string) (string, bool) {
+	parts := strings.SplitN(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-", 2)
+	normalized := strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		normalized += "-" + strings.ToUpper(parts[1])
+	}
+	return normalized, localePattern.MatchString(normalized)
+}
+
+// ParseAcceptLanguage returns the locales in an Accept-Language header, most
+// preferred first. Wildcards, q=0 entries and malformed tags are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale, ok := NormalizeLocale(fields[0])
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{locale: locale, q: q})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+
+	locales := make([]string, len(entries))
+	for i, entry := range entries {
+		locales[i] = entry.locale
+	}
+	return locales
+}
+
+// TranslationService manages localized course metadata
+type TranslationService struct {
+	db *gorm.DB
+}
+
+// NewTranslationService creates a new TranslationService
+func NewTranslationService() *TranslationService {
+	return &TranslationService{db: config.DB}
+}
+
+// GetTranslations lists every translation of a course
+func (s *TranslationService) GetTranslations(courseID uuid.UUID) ([]models.CourseTranslation, error) {
+	var translations []models.CourseTranslation
+	if err := s.db.Where("course_id = ?", courseID).Order("locale ASC").Find(&translations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get translations: %w", err)
+	}
+	return translations, nil
+}
+
+// SaveTranslation creates or replaces the translation for translation.Locale
+func (s *TranslationService) SaveTranslation(translation *models.CourseTranslation) error {
+	var existing models.CourseTranslation
+	err := s.db.Where("course_id = ? AND locale = ?", translation.CourseID, translation.Locale).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to load translation: %w", err)
+	}
+	if err == nil {
+		translation.ID = existing.ID
+		translation.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.db.Save(translation).Error; err != nil {
+		return fmt.Errorf("failed to save translation: %w", err)
+	}
+	return nil
+}
+
+// DeleteTranslation removes the translation for locale
+func (s *TranslationService) DeleteTranslation(courseID uuid.UUID, locale string) error {
+	result := s.db.Where("course_id = ? AND locale = ?", courseID, locale).Delete(&models.CourseTranslation{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete translation: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTranslationNotFound
+	}
+	return nil
+}
+
+// LocalizeCourse overlays the best translation for the preferred locales and
+// returns the locale the course is now presented in.
+func (s *TranslationService) LocalizeCourse(course *models.Course, preferred []string) (string, error) {
+	courses := []models.Course{*course}
+	locales, err := s.LocalizeCourses(courses, preferred)
+	if err != nil {
+		return courseLocale(course), err
+	}
+	*course = courses[0]
+	return locales[0], nil
+}
+
+// LocalizeCourses overlays translations on each course in place, falling back
+// to the course's default locale when nothing preferred is available. The
+// returned slice holds the locale applied to each course.
+func (s *TranslationService) LocalizeCourses(courses []models.Course, preferred []string) ([]string, error) {
+	applied := make([]string, len(courses))
+	for i := range courses {
+		applied[i] = courseLocale(&courses[i])
+	}
+	if len(preferred) == 0 || len(courses) == 0 {
+		return applied, nil
+	}
+
+	ids := make([]uuid.UUID, len(courses))
+	for i, course := range courses {
+		ids[i] = course.ID
+	}
+
+	var translations []models.CourseTranslation
+	if err := s.db.Where("course_id IN ?", ids).Find(&translations).Error; err != nil {
+		return applied, fmt.Errorf("failed to load translations: %w", err)
+	}
+
+	byCourse := make(map[uuid.UUID]map[string]models.CourseTranslation)
+	for _, t := range translations {
+		if byCourse[t.CourseID] == nil {
+			byCourse[t.CourseID] = make(map[string]models.CourseTranslation)
+		}
+		byCourse[t.CourseID][t.Locale] = t
+	}
+
+	for i := range courses {
+		available := byCourse[courses[i].ID]
+		if len(available) == 0 {
+			continue
+		}
+
+		translation, ok := matchTranslation(applied[i], available, preferred)
+		if !ok {
+			continue
+		}
+
+		courses[i].Title = translation.Title
+		courses[i].Description = translation.Description
+		if translation.MetaTitle != "" {
+			courses[i].MetaTitle = translation.MetaTitle
+		}
+		if translation.MetaDescription != "" {
+			courses[i].MetaDescription = translation.MetaDescription
+		}
+		applied[i] = translation.Locale
+	}
+
+	return applied, nil
+}
+
+// matchTranslation walks the preferred locales in order. An exact match wins,
+// then a match on the base language; reaching the course's own language
+// means the untranslated fields are preferred.
+func matchTranslation(defaultLocale string, available map[string]models.CourseTranslation, preferred []string) (models.CourseTranslation, bool) {
+	defaultBase := baseLanguage(defaultLocale)
+
+	for _, locale := range preferred {
+		if t, ok := available[locale]; ok {
+			return t, true
+		}
+
+		base := baseLanguage(locale)
+		if base == defaultBase {
+			return models.CourseTranslation{}, false
+		}
+		if t, ok := available[base]; ok {
+			return t, true
+		}
+		var regional []string
+		for candidate := range available {
+			if baseLanguage(candidate) == base {
+				regional = append(regional, candidate)
+			}
+		}
+		if len(regional) > 0 {
+			sort.Strings(regional)
+			return available[regional[0]], true
+		}
+	}
+
+	return models.CourseTranslation{}, false
+}
+
+func courseLocale(course *models.Course) string {
+	if locale, ok := NormalizeLocale(course.Language); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+func baseLanguage(locale string) string {
+	return strings.SplitN(locale, "-", 2)[0]
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned when a row was modified since the caller read it
+var ErrVersionConflict = errors.New("resource was modified by another request")
+
+// UpdateWithVersion saves all columns of model only if the stored row is still
+// at expectedVersion. The caller sets the model's Version to expectedVersion+1
+// before calling; ErrVersionConflict is returned when no row matched.
+func UpdateWithVersion(tx *gorm.DB, model interface{}, expectedVersion int) error {
+	result := tx.Model(model).
+		Where("version = ?", expectedVersion).
+		Select("*").
+		Omit(clause.Associations, "created_at").
+		Updates(model)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
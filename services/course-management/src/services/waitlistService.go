@@ -0,0 +1,381 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/course-management/src/config"
+	"github.com/modex/course-management/src/models"
+	"github.com/modex/course-management/src/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reasons an enrollment is refused
+const (
+	EnrollmentReasonUnavailable     = "not_available"
+	EnrollmentReasonDeadlinePassed  = "deadline_passed"
+	EnrollmentReasonCourseFull      = "course_full"
+	EnrollmentReasonWaitlistPending = "waitlist_pending"
+)
+
+var (
+	// ErrWaitlistClosed is returned when joining the waitlist of a course that
+	// has no capacity limit or can't be enrolled in
+	ErrWaitlistClosed = errors.New("course does not have an open waitlist")
+	// ErrAlreadyWaitlisted is returned when a student is already waiting
+	ErrAlreadyWaitlisted = errors.New("already on the waitlist")
+	// ErrNotWaitlisted is returned when a student isn't waiting for the course
+	ErrNotWaitlisted = errors.New("not on the waitlist")
+)
+
+// EnrollmentCheck tells the enrollment service whether a student may enroll
+type EnrollmentCheck struct {
+	CourseID           uuid.UUID  `json:"courseId"`
+	Allowed            bool       `json:"allowed"`
+	Reason             string     `json:"reason,omitempty"`
+	MaxStudents        int        `json:"maxStudents"` // 0 = unlimited
+	Enrolled           int        `json:"enrolled"`
+	SeatsRemaining     *int       `json:"seatsRemaining,omitempty"`
+	EnrollmentDeadline *time.Time `json:"enrollmentDeadline,omitempty"`
+	WaitlistOpen       bool       `json:"waitlistOpen"`
+	Waiting            int64      `json:"waiting"`
+}
+
+// WaitlistService enforces enrollment deadlines and capacity and manages the
+// queue of students waiting for a seat
+type WaitlistService struct {
+	db     *gorm.DB
+	events *EventPublisher
+	seats  *SeatService
+}
+
+// NewWaitlistService creates a new WaitlistService
+func NewWaitlistService() *WaitlistService {
+	return NewSeatService().waitlist
+}
+
+// newWaitlistService creates the WaitlistService that seats holds promoted
+// students' seats with
+func newWaitlistService(seats *SeatService) *WaitlistService {
+	return &WaitlistService{
+		db:     config.DB,
+		events: NewEventPublisher(),
+		seats:  seats,
+	}
+}
+
+// CheckEnrollment decides whether userID may enroll given the course's
+// current enrollment count, which only the enrollment service knows.
+// Students who didn't come through the waitlist can't take a seat while
+// others are still waiting for one.
+func (s *WaitlistService) CheckEnrollment(courseID uuid.UUID, userID *uuid.UUID, enrolled int) (*EnrollmentCheck, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	waiting, err := s.countWaiting(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &EnrollmentCheck{
+		CourseID:           course.ID,
+		MaxStudents:        course.MaxStudents,
+		Enrolled:           enrolled,
+		EnrollmentDeadline: course.EnrollmentDeadline,
+		Waiting:            waiting,
+	}
+
+	switch {
+	case course.Status != models.CourseStatusPublished || course.SuspendedAt != nil:
+		check.Reason = EnrollmentReasonUnavailable
+		return check, nil
+	case deadlinePassed(course):
+		check.Reason = EnrollmentReasonDeadlinePassed
+		return check, nil
+	}
+
+	if course.MaxStudents <= 0 {
+		check.Allowed = true
+		return check, nil
+	}
+
+	check.WaitlistOpen = true
+	remaining := course.MaxStudents - enrolled
+	if remaining < 0 {
+		remaining = 0
+	}
+	check.SeatsRemaining = &remaining
+
+	switch {
+	case remaining == 0:
+		check.Reason = EnrollmentReasonCourseFull
+	case waiting > 0 && !s.isPromoted(courseID, userID):
+		check.Reason = EnrollmentReasonWaitlistPending
+	default:
+		check.Allowed = true
+	}
+	return check, nil
+}
+
+// Join adds a student to the back of a course's waitlist
+func (s *WaitlistService) Join(courseID, userID uuid.UUID) (*models.WaitlistEntry, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.MaxStudents <= 0 || course.Status != models.CourseStatusPublished ||
+		course.SuspendedAt != nil || deadlinePassed(course) {
+		return nil, ErrWaitlistClosed
+	}
+
+	var entry models.WaitlistEntry
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("course_id = ? AND user_id = ?", courseID, userID).
+			First(&entry).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			entry = models.WaitlistEntry{
+				CourseID: courseID,
+				UserID:   userID,
+				Status:   models.WaitlistWaiting,
+				JoinedAt: time.Now().UTC(),
+			}
+			return tx.Create(&entry).Error
+		case err != nil:
+			return err
+		case entry.Status == models.WaitlistWaiting:
+			return ErrAlreadyWaitlisted
+		}
+
+		// Rejoining after leaving or a lapsed promotion goes to the back of the line
+		entry.Status = models.WaitlistWaiting
+		entry.JoinedAt = time.Now().UTC()
+		entry.PromotedAt = nil
+		return tx.Save(&entry).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrAlreadyWaitlisted) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to join waitlist: %w", err)
+	}
+
+	if entry.Position, err = s.position(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Leave removes a student from a course's waitlist
+func (s *WaitlistService) Leave(courseID, userID uuid.UUID) error {
+	result := s.db.Model(&models.WaitlistEntry{}).
+		Where("course_id = ? AND user_id = ? AND status = ?", courseID, userID, models.WaitlistWaiting).
+		Update("status", models.WaitlistLeft)
+	if result.Error != nil {
+		return fmt.Errorf("failed to leave waitlist: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotWaitlisted
+	}
+	return nil
+}
+
+// GetEntry returns a student's waitlist entry and, while waiting, their place in line
+func (s *WaitlistService) GetEntry(courseID, userID uuid.UUID) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	if err := s.db.Where("course_id = ? AND user_id = ? AND status <> ?", courseID, userID, models.WaitlistLeft).
+		First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotWaitlisted
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+
+	if entry.Status == models.WaitlistWaiting {
+		position, err := s.position(&entry)
+		if err != nil {
+			return nil, err
+		}
+		entry.Position = position
+	}
+	return &entry, nil
+}
+
+// GetWaitlist lists the students waiting for a course in line order
+func (s *WaitlistService) GetWaitlist(courseID uuid.UUID) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	if err := s.db.Where("course_id = ? AND status = ?", courseID, models.WaitlistWaiting).
+		Order("joined_at ASC, id ASC").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get waitlist: %w", err)
+	}
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+	return entries, nil
+}
+
+// PromoteWaiting offers freed seats to the students at the front of the
+// line. The enrollment service calls this with its current count whenever a
+// seat frees up. Each promoted student gets a seat hold, which their checkout
+// picks up, so seats already offered aren't offered again until the hold
+// lapses. Each promotion emits WAITLIST_PROMOTED so the student can be
+// enrolled and notified.
+func (s *WaitlistService) PromoteWaiting(courseID uuid.UUID, enrolled int) ([]models.WaitlistEntry, error) {
+	course, err := s.loadCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Status != models.CourseStatusPublished || course.SuspendedAt != nil {
+		return []models.WaitlistEntry{}, nil
+	}
+
+	var promoted []models.WaitlistEntry
+	reservations := make(map[uuid.UUID]*models.SeatReservation)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("course_id = ? AND status = ?", courseID, models.WaitlistWaiting).
+			Order("joined_at ASC, id ASC")
+		if course.MaxStudents > 0 {
+			offered, err := s.countOffered(tx, courseID)
+			if err != nil {
+				return err
+			}
+			free := course.MaxStudents - enrolled - int(offered)
+			if free <= 0 {
+				return nil
+			}
+			query = query.Limit(free)
+		}
+		var candidates []models.WaitlistEntry
+		if err := query.Find(&candidates).Error; err != nil {
+			return err
+		}
+
+		for _, entry := range candidates {
+			reservation, err := s.seats.Reserve(config.Ctx, courseID, entry.UserID.String())
+			if errors.Is(err, ErrCourseFull) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			reservations[entry.ID] = reservation
+			promoted = append(promoted, entry)
+		}
+		if len(promoted) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		ids := make([]uuid.UUID, len(promoted))
+		for i := range promoted {
+			ids[i] = promoted[i].ID
+			promoted[i].Status = models.WaitlistPromoted
+			promoted[i].PromotedAt = &now
+		}
+		return tx.Model(&models.WaitlistEntry{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":      models.WaitlistPromoted,
+				"promoted_at": now,
+			}).Error
+	})
+	if err != nil {
+		// Holds taken for promotions that didn't happen go back straight away
+		for _, reservation := range reservations {
+			s.seats.Release(config.Ctx, courseID, reservation.ID)
+		}
+		return nil, fmt.Errorf("failed to promote waitlist: %w", err)
+	}
+
+	for _, entry := range promoted {
+		if err := s.events.Publish(TopicEnrollmentEvents, "WAITLIST_PROMOTED", "Course", courseID, entry.UserID.String(), map[string]interface{}{
+			"courseId":        courseID,
+			"userId":          entry.UserID,
+			"waitlistEntryId": entry.ID,
+			"title":           course.Title,
+			"promotedAt":      entry.PromotedAt,
+			"reservationId":   reservations[entry.ID].ID,
+			"holdExpiresAt":   reservations[entry.ID].ExpiresAt,
+		}); err != nil {
+			utils.Error("Failed to publish waitlist promotion", map[string]interface{}{
+				"error":    err.Error(),
+				"courseID": courseID,
+				"userID":   entry.UserID,
+			})
+		}
+	}
+
+	if promoted == nil {
+		promoted = []models.WaitlistEntry{}
+	}
+	return promoted, nil
+}
+
+func (s *WaitlistService) position(entry *models.WaitlistEntry) (int, error) {
+	var ahead int64
+	if err := s.db.Model(&models.WaitlistEntry{}).
+		Where("course_id = ? AND status = ? AND (joined_at < ? OR (joined_at = ? AND id < ?))",
+			entry.CourseID, models.WaitlistWaiting, entry.JoinedAt, entry.JoinedAt, entry.ID).
+		Count(&ahead).Error; err != nil {
+		return 0, fmt.Errorf("failed to get waitlist position: %w", err)
+	}
+	return int(ahead) + 1, nil
+}
+
+func (s *WaitlistService) countWaiting(courseID uuid.UUID) (int64, error) {
+	var waiting int64
+	if err := s.db.Model(&models.WaitlistEntry{}).
+		Where("course_id = ? AND status = ?", courseID, models.WaitlistWaiting).
+		Count(&waiting).Error; err != nil {
+		return 0, fmt.Errorf("failed to count waitlist: %w", err)
+	}
+	return waiting, nil
+}
+
+// countOffered counts promoted students still holding the seat they were
+// offered. Offers whose hold lapsed or was confirmed no longer count.
+func (s *WaitlistService) countOffered(tx *gorm.DB, courseID uuid.UUID) (int64, error) {
+	var offered int64
+	if err := tx.Model(&models.WaitlistEntry{}).
+		Where("course_id = ? AND status = ?", courseID, models.WaitlistPromoted).
+		Where("EXISTS (?)", tx.Model(&models.SeatReservation{}).Select("1").
+			Where("seat_reservations.course_id = course_waitlist.course_id AND seat_reservations.user_id = course_waitlist.user_id::text").
+			Where("seat_reservations.status = ? AND seat_reservations.expires_at > ?", models.ReservationHeld, time.Now().UTC())).
+		Count(&offered).Error; err != nil {
+		return 0, fmt.Errorf("failed to count outstanding offers: %w", err)
+	}
+	return offered, nil
+}
+
+func (s *WaitlistService) isPromoted(courseID uuid.UUID, userID *uuid.UUID) bool {
+	if userID == nil {
+		return false
+	}
+	var count int64
+	s.db.Model(&models.WaitlistEntry{}).
+		Where("course_id = ? AND user_id = ? AND status = ?", courseID, *userID, models.WaitlistPromoted).
+		Count(&count)
+	return count > 0
+}
+
+func (s *WaitlistService) loadCourse(courseID uuid.UUID) (*models.Course, error) {
+	var course models.Course
+	if err := s.db.First(&course, courseID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound
+		}
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	return &course, nil
+}
+
+func deadlinePassed(course *models.Course) bool {
+	return course.EnrollmentDeadline != nil && time.Now().After(*course.EnrollmentDeadline)
+}
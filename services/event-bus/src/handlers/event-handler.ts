@@ -4,6 +4,7 @@ import { EventStore } from '../store/event-store'
 import { NotificationService } from '../integrations/notification-service'
 import { AnalyticsService } from '../integrations/analytics-service'
 import { AuditService } from '../integrations/audit-service'
+import { WebhookDispatcher } from '../integrations/webhook-dispatcher'
 
 export interface EventHandler {
   handle(event: DomainEvent): Promise<void>
@@ -14,17 +15,20 @@ export class DomainEventHandler implements EventHandler {
   private notificationService: NotificationService
   private analyticsService: AnalyticsService
   private auditService: AuditService
+  private webhookDispatcher?: WebhookDispatcher
 
   constructor(
     eventStore: EventStore,
     notificationService: NotificationService,
     analyticsService: AnalyticsService,
-    auditService: AuditService
+    auditService: AuditService,
+    webhookDispatcher?: WebhookDispatcher
   ) {
     this.eventStore = eventStore
     this.notificationService = notificationService
     this.analyticsService = analyticsService
     this.auditService = auditService
+    this.webhookDispatcher = webhookDispatcher
   }
 
   async handle(event: DomainEvent): Promise<void> {
@@ -40,6 +44,14 @@ export class DomainEventHandler implements EventHandler {
         case 'STUDENT_ENROLLED':
           await this.handleStudentEnrolled(event as any)
           break
+        case 'WAITLIST_PROMOTED':
+          await this.handleWaitlistPromoted(event as any)
+          break
+        case 'OFFICE_HOURS_BOOKED':
+        case 'OFFICE_HOURS_CANCELLED':
+        case 'OFFICE_HOURS_REMINDER':
+          await this.handleOfficeHours(event as any)
+          break
         case 'COURSE_COMPLETED':
           await this.handleCourseCompleted(event as any)
           break
@@ -55,6 +67,16 @@ export class DomainEventHandler implements EventHandler {
         case 'COURSE_PUBLISHED':
           await this.handleCoursePublished(event as any)
           break
+        case 'COURSE_SUSPENDED':
+        case 'COURSE_FORCE_UNPUBLISHED':
+          await this.handleCourseModerated(event as any)
+          break
+        case 'COURSE_LINKS_BROKEN':
+          await this.handleCourseLinksBroken(event as any)
+          break
+        case 'QUOTA_WARNING':
+          await this.handleQuotaWarning(event as any)
+          break
         default:
           logger.info('No specific handler for event type', { eventType: event.eventType })
       }
@@ -67,6 +89,14 @@ export class DomainEventHandler implements EventHandler {
         await this.auditService.logEvent(event)
       }
 
+      // Fan out to integrators' webhooks; delivery problems are retried
+      // separately and must not fail the event
+      if (this.webhookDispatcher) {
+        await this.webhookDispatcher.dispatch(event).catch(error =>
+          logger.error('Failed to dispatch webhooks', { eventType: event.eventType, error: error.message })
+        )
+      }
+
     } catch (error) {
       logger.error('Failed to handle event', {
         eventType: event.eventType,
@@ -137,6 +167,77 @@ export class DomainEventHandler implements EventHandler {
     })
   }
 
+  private async handleWaitlistPromoted(event: any): Promise<void> {
+    await this.notificationService.sendNotification({
+      recipientId: event.data.userId,
+      type: 'email',
+      template: 'waitlist_promoted',
+      subject: 'A seat opened up for you',
+      content: `Good news! A seat is now available in "${event.data.title}". You've been moved off the waitlist.`,
+      metadata: {
+        courseId: event.data.courseId,
+        waitlistEntryId: event.data.waitlistEntryId
+      },
+      priority: 'high'
+    })
+
+    logger.info('Waitlist promotion notification sent', {
+      courseId: event.data.courseId,
+      userId: event.data.userId
+    })
+  }
+
+  private async handleOfficeHours(event: any): Promise<void> {
+    const { data } = event
+    const when = new Date(data.startsAt).toUTCString()
+    const where = data.meetingUrl || data.location
+
+    let subject: string
+    let content: string
+    let priority: 'medium' | 'high' = 'medium'
+    switch (event.eventType) {
+      case 'OFFICE_HOURS_BOOKED':
+        subject = 'Office hours booked'
+        content = `You're booked into office hours for "${data.title}" on ${when}.`
+        break
+      case 'OFFICE_HOURS_REMINDER':
+        subject = 'Office hours start soon'
+        content = `Your office hours for "${data.title}" start at ${when}.`
+        priority = 'high'
+        break
+      default:
+        subject = 'Office hours cancelled'
+        content = data.reason === 'slot_cancelled'
+          ? `The instructor cancelled office hours for "${data.title}" on ${when}.`
+          : `Your office-hours booking for "${data.title}" on ${when} was cancelled.`
+        priority = data.reason === 'slot_cancelled' ? 'high' : 'medium'
+    }
+    if (where && event.eventType !== 'OFFICE_HOURS_CANCELLED') {
+      content += ` Join at ${where}.`
+    }
+
+    await this.notificationService.sendNotification({
+      recipientId: data.userId,
+      type: 'email',
+      template: event.eventType.toLowerCase(),
+      subject,
+      content,
+      metadata: {
+        courseId: data.courseId,
+        slotId: data.slotId,
+        bookingId: data.bookingId,
+        startsAt: data.startsAt
+      },
+      priority
+    })
+
+    logger.info('Office hours notification sent', {
+      eventType: event.eventType,
+      courseId: data.courseId,
+      userId: data.userId
+    })
+  }
+
   private async handleCourseCompleted(event: any): Promise<void> {
     // Send completion certificate
     await this.notificationService.sendNotification({
@@ -148,7 +249,9 @@ export class DomainEventHandler implements EventHandler {
       metadata: {
         courseId: event.data.courseId,
         completionDate: event.data.completionDate,
-        certificateId: event.data.certificateId
+        certificateId: event.data.certificateId,
+        finalScore: event.data.finalScore,
+        finalGrade: event.data.finalGrade?.label
       },
       priority: 'high'
     })
@@ -282,13 +385,91 @@ export class DomainEventHandler implements EventHandler {
     })
   }
 
+  private async handleCourseModerated(event: any): Promise<void> {
+    const suspended = event.eventType === 'COURSE_SUSPENDED'
+
+    // Tell the instructor why their course was taken down
+    await this.notificationService.sendNotification({
+      recipientId: event.data.instructorId,
+      type: 'email',
+      template: suspended ? 'course_suspended' : 'course_force_unpublished',
+      subject: suspended ? 'Your course has been suspended' : 'Your course has been unpublished',
+      content: suspended
+        ? `Your course has been suspended by a moderator and is closed to new enrollments. Reason: ${event.data.reason}`
+        : `Your course has been unpublished by a moderator. Reason: ${event.data.reason}`,
+      metadata: {
+        courseId: event.aggregateId,
+        reason: event.data.reason
+      },
+      priority: 'high'
+    })
+
+    logger.info('Course moderation notification sent', {
+      courseId: event.aggregateId,
+      eventType: event.eventType
+    })
+  }
+
+  private async handleCourseLinksBroken(event: any): Promise<void> {
+    await this.notificationService.sendNotification({
+      recipientId: event.data.instructorId,
+      type: 'email',
+      template: 'course_links_broken',
+      subject: 'Broken links found in your course',
+      content: `We found ${event.data.brokenCount} broken link(s) or missing media in "${event.data.title}". Check the course's link report for details.`,
+      metadata: {
+        courseId: event.aggregateId,
+        brokenCount: event.data.brokenCount
+      },
+      priority: 'medium'
+    })
+
+    logger.info('Broken link notification sent', {
+      courseId: event.aggregateId,
+      brokenCount: event.data.brokenCount
+    })
+  }
+
+  private async handleQuotaWarning(event: any): Promise<void> {
+    const { quota, threshold, used, limit, plan } = event.data
+
+    // Addressed to the organization; the notification service delivers it to its admins
+    await this.notificationService.sendNotification({
+      recipientId: event.aggregateId,
+      type: 'email',
+      template: 'quota_warning',
+      subject: `You've used ${threshold}% of your ${quota} quota`,
+      content: `Your organization has used ${used} of its ${limit} ${quota} allowance on the ${plan} plan. Requests will be refused once it is used up.`,
+      metadata: {
+        organizationId: event.aggregateId,
+        recipientType: 'organization',
+        quota,
+        threshold,
+        used,
+        limit
+      },
+      priority: threshold >= 95 ? 'high' : 'medium'
+    })
+
+    logger.info('Quota warning notification sent', {
+      organizationId: event.aggregateId,
+      quota,
+      threshold
+    })
+  }
+
   private isCriticalEvent(event: DomainEvent): boolean {
     const criticalEvents = [
       'USER_REGISTERED',
+      'USER_ERASURE_REQUESTED',
+      'COURSE_SUSPENDED',
+      'COURSE_REINSTATED',
+      'COURSE_FORCE_UNPUBLISHED',
       'PAYMENT_COMPLETED',
       'PAYMENT_FAILED',
       'COURSE_COMPLETED',
-      'REFUND_PROCESSED'
+      'REFUND_PROCESSED',
+      'QUOTA_EXCEEDED'
     ]
     return criticalEvents.includes(event.eventType)
   }
@@ -55,6 +55,9 @@ import { PostgresEventStore } from './store/event-store'
 import { NotificationService } from './integrations/notification-service'
 import { AnalyticsService } from './integrations/analytics-service'
 import { AuditService } from './integrations/audit-service'
+import { WebhookDispatcher } from './integrations/webhook-dispatcher'
+import { WebhookStore } from './store/webhook-store'
+import { createWebhookRouter } from './routes/webhooks'
 // Mock routers since route files don't exist
 const healthRouter = require('express').Router()
 const eventRouter = require('express').Router()
@@ -130,6 +133,8 @@ app.use((req, res, next) => {
 let eventProducer: EventProducer | undefined
 let eventConsumer: EventConsumer | undefined
 let eventStore: PostgresEventStore | undefined
+const webhookStore = new WebhookStore()
+const webhookDispatcher = new WebhookDispatcher(webhookStore)
 
 async function initializeServices() {
   try {
@@ -146,7 +151,8 @@ async function initializeServices() {
       eventStore,
       notificationService,
       analyticsService,
-      auditService
+      auditService,
+      webhookDispatcher
     )
     
     // Initialize Kafka producer
@@ -172,6 +178,9 @@ async function initializeServices() {
     
     // Start consuming
     await eventConsumer.startConsuming()
+
+    // Retry failed webhook deliveries
+    webhookDispatcher.start()
     
     logger.info('Event Bus services initialized successfully')
   } catch (error) {
@@ -187,6 +196,7 @@ app.locals.eventStore = eventStore
 // Routes
 app.use('/health', healthRouter)
 app.use('/api/v1/events', eventRouter)
+app.use('/api/v1/webhooks', createWebhookRouter(webhookStore, webhookDispatcher))
 
 // 404 handler
 app.use('*', (req, res) => {
@@ -223,6 +233,9 @@ async function gracefulShutdown(signal: string) {
     if (eventConsumer) {
       await eventConsumer.disconnect()
     }
+
+    webhookDispatcher.stop()
+    await webhookStore.close()
     
     if (eventProducer) {
       await eventProducer.disconnect()
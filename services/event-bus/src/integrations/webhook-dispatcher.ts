@@ -0,0 +1,181 @@
+import axios from 'axios'
+import http from 'http'
+import https from 'https'
+import { createHmac } from 'crypto'
+import { logger } from '../utils/logger'
+import { checkPublicUrl, publicLookup } from '../utils/network'
+import { DomainEvent } from '../types/events'
+import { DueWebhookDelivery, WEBHOOK_EVENTS, WebhookAttemptResult } from '../types/webhooks'
+import { WebhookStore } from '../store/webhook-store'
+
+const MAX_BACKOFF_MS = 6 * 60 * 60 * 1000 // 6 hours
+
+// Agents that only connect to public addresses, whatever the endpoint's DNS
+// says at delivery time
+const httpAgent = new http.Agent({ lookup: publicLookup } as http.AgentOptions)
+const httpsAgent = new https.Agent({ lookup: publicLookup } as https.AgentOptions)
+
+// Delivers subscribed events to integrators' endpoints. Each request body is
+// signed with HMAC-SHA256 over "<timestamp>.<body>" using the subscription's
+// secret and sent as X-Modex-Signature: t=<timestamp>,v1=<hex>. Failed
+// deliveries are retried with exponential backoff and dead-lettered after
+// WEBHOOK_MAX_ATTEMPTS.
+export class WebhookDispatcher {
+  private store: WebhookStore
+  private maxAttempts: number
+  private retryBaseMs: number
+  private timeoutMs: number
+  private pollIntervalMs: number
+  private timer?: NodeJS.Timeout
+
+  constructor(store: WebhookStore) {
+    this.store = store
+    this.maxAttempts = parseInt(process.env.WEBHOOK_MAX_ATTEMPTS || '8')
+    this.retryBaseMs = parseInt(process.env.WEBHOOK_RETRY_BASE_MS || '30000')
+    this.timeoutMs = parseInt(process.env.WEBHOOK_TIMEOUT_MS || '10000')
+    this.pollIntervalMs = parseInt(process.env.WEBHOOK_POLL_INTERVAL_MS || '15000')
+  }
+
+  start(): void {
+    this.timer = setInterval(() => {
+      this.processDue().catch(error => logger.error('Webhook retry run failed', { error: error.message }))
+    }, this.pollIntervalMs)
+  }
+
+  stop(): void {
+    if (this.timer) {
+      clearInterval(this.timer)
+    }
+  }
+
+  // Records a delivery for every active subscription to the event in the
+  // event's organization and makes the first attempt in the background.
+  // Events that don't say which organization they belong to go nowhere.
+  async dispatch(event: DomainEvent): Promise<void> {
+    const name = WEBHOOK_EVENTS[event.eventType]
+    if (!name) return
+
+    const organizationId = eventOrganization(event)
+    if (!organizationId) {
+      logger.debug('Skipping webhooks for event without an organization', { eventId: event.id, event: name })
+      return
+    }
+
+    const subscriptions = await this.store.getActiveSubscriptions(name, organizationId)
+    if (subscriptions.length === 0) return
+
+    const payload = {
+      id: event.id,
+      event: name,
+      createdAt: new Date(event.timestamp).toISOString(),
+      data: (event as any).data
+    }
+
+    for (const subscription of subscriptions) {
+      const delivery = await this.store.createDelivery(subscription.id, event.id, name, payload, this.leaseUntil())
+      if (!delivery) continue
+
+      this.attempt(delivery).catch(error =>
+        logger.error('Webhook delivery failed', { deliveryId: delivery.id, error: error.message })
+      )
+    }
+  }
+
+  // Sends pending deliveries whose retry time has come
+  async processDue(batchSize: number = 50): Promise<void> {
+    const due = await this.store.claimDueDeliveries(batchSize, this.leaseUntil())
+    await Promise.all(due.map(delivery =>
+      this.attempt(delivery).catch(error =>
+        logger.error('Webhook delivery failed', { deliveryId: delivery.id, error: error.message })
+      )
+    ))
+  }
+
+  // Replays a delivery from the log, including dead-lettered ones
+  async redeliver(deliveryId: string, subscriptionId: string): Promise<boolean> {
+    if (!(await this.store.requeueDelivery(deliveryId, subscriptionId))) {
+      return false
+    }
+    await this.processDue()
+    return true
+  }
+
+  private async attempt(delivery: DueWebhookDelivery): Promise<void> {
+    const result = await this.send(delivery)
+
+    if (result.succeeded) {
+      await this.store.recordAttempt(delivery.id, 'succeeded', result.statusCode, undefined, null)
+      logger.info('Webhook delivered', { deliveryId: delivery.id, event: delivery.event })
+      return
+    }
+
+    const attempts = delivery.attempts + 1
+    if (attempts >= this.maxAttempts) {
+      await this.store.recordAttempt(delivery.id, 'dead_letter', result.statusCode, result.error, null)
+      logger.warn('Webhook delivery dead-lettered', {
+        deliveryId: delivery.id,
+        subscriptionId: delivery.subscriptionId,
+        attempts,
+        error: result.error
+      })
+      return
+    }
+
+    await this.store.recordAttempt(delivery.id, 'pending', result.statusCode, result.error, this.nextAttemptAt(attempts))
+  }
+
+  private async send(delivery: DueWebhookDelivery): Promise<WebhookAttemptResult> {
+    const body = JSON.stringify(delivery.payload)
+    const timestamp = Math.floor(Date.now() / 1000)
+
+    try {
+      const blocked = checkPublicUrl(new URL(delivery.url))
+      if (blocked) {
+        return { succeeded: false, error: blocked }
+      }
+
+      const response = await axios.post(delivery.url, body, {
+        headers: {
+          'Content-Type': 'application/json',
+          'User-Agent': 'Modex-Webhooks/1.0',
+          'X-Modex-Event': delivery.event,
+          'X-Modex-Delivery': delivery.id,
+          'X-Modex-Signature': `t=${timestamp},v1=${signPayload(delivery.secret, timestamp, body)}`
+        },
+        timeout: this.timeoutMs,
+        maxRedirects: 0,
+        httpAgent,
+        httpsAgent,
+        proxy: false,
+        validateStatus: () => true
+      })
+
+      if (response.status >= 200 && response.status < 300) {
+        return { succeeded: true, statusCode: response.status }
+      }
+      return { succeeded: false, statusCode: response.status, error: `Endpoint responded with ${response.status}` }
+    } catch (error) {
+      return { succeeded: false, error: error.message }
+    }
+  }
+
+  // Doubles the wait after each failure, with up to 10% jitter
+  private nextAttemptAt(attempts: number): Date {
+    const backoff = Math.min(this.retryBaseMs * Math.pow(2, attempts - 1), MAX_BACKOFF_MS)
+    return new Date(Date.now() + backoff + Math.random() * backoff * 0.1)
+  }
+
+  private leaseUntil(): Date {
+    return new Date(Date.now() + this.timeoutMs * 2)
+  }
+}
+
+// The organization an event belongs to, as stamped by the publishing service
+function eventOrganization(event: DomainEvent): string | undefined {
+  const organizationId = (event as any).data?.organizationId || event.metadata?.organizationId
+  return organizationId ? String(organizationId) : undefined
+}
+
+export function signPayload(secret: string, timestamp: number, body: string): string {
+  return createHmac('sha256', secret).update(`${timestamp}.${body}`).digest('hex')
+}
@@ -173,20 +173,30 @@ export class EventProducer {
       // User events
       'USER_REGISTERED': 'user-events',
       'USER_PROFILE_UPDATED': 'user-events',
+      'USER_ERASURE_REQUESTED': 'user-events',
       
       // Course events
       'COURSE_CREATED': 'course-events',
       'COURSE_UPDATED': 'course-events',
       'COURSE_PUBLISHED': 'course-events',
+      'COURSE_SUSPENDED': 'course-events',
+      'COURSE_REINSTATED': 'course-events',
+      'COURSE_FORCE_UNPUBLISHED': 'course-events',
+      'COURSE_LINKS_BROKEN': 'course-events',
       
       // Enrollment events
       'STUDENT_ENROLLED': 'enrollment-events',
+      'WAITLIST_PROMOTED': 'enrollment-events',
+      'OFFICE_HOURS_BOOKED': 'enrollment-events',
+      'OFFICE_HOURS_CANCELLED': 'enrollment-events',
+      'OFFICE_HOURS_REMINDER': 'enrollment-events',
       'LESSON_COMPLETED': 'enrollment-events',
       'COURSE_COMPLETED': 'enrollment-events',
       
       // Assessment events
       'ASSESSMENT_ATTEMPTED': 'assessment-events',
       'ASSESSMENT_COMPLETED': 'assessment-events',
+      'SUBMISSION_GRADED': 'assessment-events',
       
       // Payment events
       'PAYMENT_INITIATED': 'payment-events',
@@ -199,9 +209,13 @@ export class EventProducer {
       
       // Content events
       'CONTENT_UPLOADED': 'content-events',
+      'CONTENT_DELETED': 'content-events',
+      'CONTENT_PROCESSED': 'content-events',
       
       // System events
-      'SYSTEM_HEALTH_CHECK': 'system-events'
+      'SYSTEM_HEALTH_CHECK': 'system-events',
+      'QUOTA_EXCEEDED': 'system-events',
+      'QUOTA_WARNING': 'system-events'
     }
 
     return topicMap[eventType] || 'general-events'
@@ -0,0 +1,213 @@
+import express from 'express'
+import { randomBytes } from 'crypto'
+import { logger } from '../utils/logger'
+import { checkPublicUrl } from '../utils/network'
+import { WebhookStore } from '../store/webhook-store'
+import { WebhookDispatcher } from '../integrations/webhook-dispatcher'
+import { WEBHOOK_EVENTS, WebhookDeliveryStatus, WebhookSubscriptionInput } from '../types/webhooks'
+
+const SUPPORTED_EVENTS = Object.values(WEBHOOK_EVENTS)
+const DELIVERY_STATUSES: WebhookDeliveryStatus[] = ['pending', 'succeeded', 'dead_letter']
+const WEBHOOK_ROLES = (process.env.WEBHOOK_ROLES || 'admin,integrator').split(',').map(role => role.trim())
+
+// Subscriptions belong to the caller the API gateway identified in x-user-id
+// and receive only the events of their organization (x-organization-id).
+// Only admins and integrators may manage them.
+export function createWebhookRouter(store: WebhookStore, dispatcher: WebhookDispatcher): express.Router {
+  const router = express.Router()
+
+  router.use((req, res, next) => {
+    if (!req.get('x-user-id')) {
+      return res.status(401).json({ error: 'Unauthorized', message: 'Authentication required' })
+    }
+    if (!WEBHOOK_ROLES.includes(req.get('x-user-role') || '')) {
+      return res.status(403).json({ error: 'Forbidden', message: 'Webhook subscriptions are limited to admins and integrators' })
+    }
+    if (!req.get('x-organization-id')) {
+      return res.status(403).json({ error: 'Forbidden', message: 'Webhook subscriptions require an organization' })
+    }
+    next()
+  })
+
+  router.get('/events', (req, res) => {
+    res.json({ data: SUPPORTED_EVENTS })
+  })
+
+  router.post('/', async (req, res, next) => {
+    try {
+      const error = validateSubscription(req.body, false)
+      if (error) {
+        return res.status(400).json({ error: 'Bad Request', message: error })
+      }
+
+      const subscription = await store.createSubscription(ownerId(req), organizationId(req), req.body, generateSecret())
+      logger.info('Webhook subscription created', {
+        subscriptionId: subscription.id,
+        organizationId: subscription.organizationId,
+        events: subscription.events
+      })
+
+      // The secret is only returned here and when rotated
+      res.status(201).json({ data: subscription })
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  router.get('/', async (req, res, next) => {
+    try {
+      res.json({ data: await store.listSubscriptions(ownerId(req)) })
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  router.get('/:id', async (req, res, next) => {
+    try {
+      const subscription = await store.getSubscription(req.params.id, ownerId(req))
+      if (!subscription) {
+        return res.status(404).json({ error: 'Not Found', message: 'Webhook subscription not found' })
+      }
+      res.json({ data: subscription })
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  router.patch('/:id', async (req, res, next) => {
+    try {
+      const error = validateSubscription(req.body, true)
+      if (error) {
+        return res.status(400).json({ error: 'Bad Request', message: error })
+      }
+
+      const subscription = await store.updateSubscription(req.params.id, ownerId(req), req.body)
+      if (!subscription) {
+        return res.status(404).json({ error: 'Not Found', message: 'Webhook subscription not found' })
+      }
+      res.json({ data: subscription })
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  router.delete('/:id', async (req, res, next) => {
+    try {
+      if (!(await store.deleteSubscription(req.params.id, ownerId(req)))) {
+        return res.status(404).json({ error: 'Not Found', message: 'Webhook subscription not found' })
+      }
+      res.status(204).send()
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  router.post('/:id/rotate-secret', async (req, res, next) => {
+    try {
+      const subscription = await store.rotateSecret(req.params.id, ownerId(req), generateSecret())
+      if (!subscription) {
+        return res.status(404).json({ error: 'Not Found', message: 'Webhook subscription not found' })
+      }
+      res.json({ data: subscription })
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  // Delivery log, newest first; ?status=dead_letter lists the dead-letter queue
+  router.get('/:id/deliveries', async (req, res, next) => {
+    try {
+      const subscription = await store.getSubscription(req.params.id, ownerId(req))
+      if (!subscription) {
+        return res.status(404).json({ error: 'Not Found', message: 'Webhook subscription not found' })
+      }
+
+      const status = req.query.status as WebhookDeliveryStatus | undefined
+      if (status && !DELIVERY_STATUSES.includes(status)) {
+        return res.status(400).json({ error: 'Bad Request', message: `status must be one of ${DELIVERY_STATUSES.join(', ')}` })
+      }
+
+      const limit = Math.min(Math.max(parseInt(req.query.limit as string) || 50, 1), 200)
+      const offset = Math.max(parseInt(req.query.offset as string) || 0, 0)
+      const { deliveries, total } = await store.listDeliveries(subscription.id, status, offset, limit)
+
+      res.json({ data: deliveries, pagination: { offset, limit, total } })
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  router.post('/:id/deliveries/:deliveryId/redeliver', async (req, res, next) => {
+    try {
+      const subscription = await store.getSubscription(req.params.id, ownerId(req))
+      if (!subscription) {
+        return res.status(404).json({ error: 'Not Found', message: 'Webhook subscription not found' })
+      }
+
+      if (!(await dispatcher.redeliver(req.params.deliveryId, subscription.id))) {
+        return res.status(404).json({ error: 'Not Found', message: 'Delivery not found or already succeeded' })
+      }
+      res.status(202).json({ message: 'Delivery queued' })
+    } catch (error) {
+      next(error)
+    }
+  })
+
+  return router
+}
+
+function ownerId(req: express.Request): string {
+  return req.get('x-user-id') as string
+}
+
+function organizationId(req: express.Request): string {
+  return req.get('x-organization-id') as string
+}
+
+function generateSecret(): string {
+  return `whsec_${randomBytes(32).toString('hex')}`
+}
+
+function validateSubscription(body: Partial<WebhookSubscriptionInput>, partial: boolean): string | null {
+  if (!body || typeof body !== 'object') {
+    return 'Request body is required'
+  }
+
+  if (body.url !== undefined || !partial) {
+    if (typeof body.url !== 'string') {
+      return 'url is required'
+    }
+    let parsed: URL
+    try {
+      parsed = new URL(body.url)
+    } catch {
+      return 'url must be a valid URL'
+    }
+    const allowHttp = process.env.WEBHOOK_ALLOW_HTTP === 'true'
+    if (parsed.protocol !== 'https:' && !(allowHttp && parsed.protocol === 'http:')) {
+      return 'url must use https'
+    }
+    const blocked = checkPublicUrl(parsed)
+    if (blocked) {
+      return blocked
+    }
+  }
+
+  if (body.events !== undefined || !partial) {
+    if (!Array.isArray(body.events) || body.events.length === 0) {
+      return 'events must be a non-empty array'
+    }
+    const unsupported = body.events.filter(event => !SUPPORTED_EVENTS.includes(event))
+    if (unsupported.length > 0) {
+      return `Unsupported events: ${unsupported.join(', ')}. Supported: ${SUPPORTED_EVENTS.join(', ')}`
+    }
+  }
+
+  if (body.active !== undefined && typeof body.active !== 'boolean') {
+    return 'active must be a boolean'
+  }
+  if (body.description !== undefined && typeof body.description !== 'string') {
+    return 'description must be a string'
+  }
+  return null
+}
@@ -0,0 +1,302 @@
+// Mock PostgreSQL dependency
+class MockPool {
+  async query(sql: string, params?: any[]) { return { rows: [] as any[], rowCount: 0 } }
+  async end() {}
+}
+
+const Pool = MockPool as any
+import {
+  DueWebhookDelivery,
+  WebhookDelivery,
+  WebhookDeliveryStatus,
+  WebhookSubscription,
+  WebhookSubscriptionInput,
+  WebhookSubscriptionWithSecret
+} from '../types/webhooks'
+import { logger } from '../utils/logger'
+
+export class WebhookStore {
+  private pool: MockPool
+
+  constructor() {
+    this.pool = new Pool({
+      connectionString: process.env.DATABASE_URL,
+      ssl: process.env.NODE_ENV === 'production' ? { rejectUnauthorized: false } : false,
+      max: 10,
+      idleTimeoutMillis: 30000,
+      connectionTimeoutMillis: 2000,
+    })
+
+    this.initializeDatabase()
+  }
+
+  private async initializeDatabase(): Promise<void> {
+    try {
+      await this.pool.query(`
+        CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+          id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+          owner_id VARCHAR(255) NOT NULL,
+          organization_id VARCHAR(255) NOT NULL,
+          url TEXT NOT NULL,
+          events TEXT[] NOT NULL,
+          secret VARCHAR(100) NOT NULL,
+          description TEXT,
+          active BOOLEAN NOT NULL DEFAULT TRUE,
+          created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+          updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+        );
+
+        CREATE TABLE IF NOT EXISTS webhook_deliveries (
+          id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+          subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+          event_id VARCHAR(255) NOT NULL,
+          event VARCHAR(100) NOT NULL,
+          payload JSONB NOT NULL,
+          status VARCHAR(20) NOT NULL DEFAULT 'pending',
+          attempts INTEGER NOT NULL DEFAULT 0,
+          next_attempt_at TIMESTAMP WITH TIME ZONE,
+          last_status_code INTEGER,
+          last_error TEXT,
+          delivered_at TIMESTAMP WITH TIME ZONE,
+          created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+          updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+          UNIQUE(subscription_id, event_id)
+        );
+
+        -- Subscriptions created before deliveries were scoped to a tenant
+        -- have no organization and receive nothing until recreated
+        ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS organization_id VARCHAR(255);
+
+        CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_owner_id ON webhook_subscriptions(owner_id);
+        CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_organization_id ON webhook_subscriptions(organization_id);
+        CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_events ON webhook_subscriptions USING GIN(events);
+        CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+        CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription ON webhook_deliveries(subscription_id, created_at);
+      `)
+      logger.info('Webhook store database initialized')
+    } catch (error) {
+      logger.error('Failed to initialize webhook store database', error)
+      throw error
+    }
+  }
+
+  async createSubscription(
+    ownerId: string,
+    organizationId: string,
+    input: WebhookSubscriptionInput,
+    secret: string
+  ): Promise<WebhookSubscriptionWithSecret> {
+    const result = await this.pool.query(
+      `INSERT INTO webhook_subscriptions (owner_id, organization_id, url, events, secret, description, active)
+       VALUES ($1, $2, $3, $4, $5, $6, $7)
+       RETURNING *`,
+      [ownerId, organizationId, input.url, input.events, secret, input.description || null, input.active ?? true]
+    )
+    return { ...this.mapSubscription(result.rows[0]), secret: result.rows[0].secret }
+  }
+
+  async listSubscriptions(ownerId: string): Promise<WebhookSubscription[]> {
+    const result = await this.pool.query(
+      'SELECT * FROM webhook_subscriptions WHERE owner_id = $1 ORDER BY created_at DESC',
+      [ownerId]
+    )
+    return result.rows.map(row => this.mapSubscription(row))
+  }
+
+  async getSubscription(id: string, ownerId: string): Promise<WebhookSubscription | null> {
+    const result = await this.pool.query(
+      'SELECT * FROM webhook_subscriptions WHERE id = $1 AND owner_id = $2',
+      [id, ownerId]
+    )
+    return result.rows.length > 0 ? this.mapSubscription(result.rows[0]) : null
+  }
+
+  async updateSubscription(id: string, ownerId: string, changes: Partial<WebhookSubscriptionInput>): Promise<WebhookSubscription | null> {
+    const result = await this.pool.query(
+      `UPDATE webhook_subscriptions
+       SET url = COALESCE($3, url),
+           events = COALESCE($4, events),
+           description = COALESCE($5, description),
+           active = COALESCE($6, active),
+           updated_at = NOW()
+       WHERE id = $1 AND owner_id = $2
+       RETURNING *`,
+      [id, ownerId, changes.url ?? null, changes.events ?? null, changes.description ?? null, changes.active ?? null]
+    )
+    return result.rows.length > 0 ? this.mapSubscription(result.rows[0]) : null
+  }
+
+  async rotateSecret(id: string, ownerId: string, secret: string): Promise<WebhookSubscriptionWithSecret | null> {
+    const result = await this.pool.query(
+      `UPDATE webhook_subscriptions SET secret = $3, updated_at = NOW()
+       WHERE id = $1 AND owner_id = $2
+       RETURNING *`,
+      [id, ownerId, secret]
+    )
+    return result.rows.length > 0
+      ? { ...this.mapSubscription(result.rows[0]), secret: result.rows[0].secret }
+      : null
+  }
+
+  async deleteSubscription(id: string, ownerId: string): Promise<boolean> {
+    const result = await this.pool.query(
+      'DELETE FROM webhook_subscriptions WHERE id = $1 AND owner_id = $2',
+      [id, ownerId]
+    )
+    return result.rowCount > 0
+  }
+
+  // Active subscriptions to event within one organization; events are never
+  // delivered across tenants
+  async getActiveSubscriptions(event: string, organizationId: string): Promise<WebhookSubscription[]> {
+    const result = await this.pool.query(
+      'SELECT * FROM webhook_subscriptions WHERE active AND organization_id = $2 AND $1 = ANY(events)',
+      [event, organizationId]
+    )
+    return result.rows.map(row => this.mapSubscription(row))
+  }
+
+  // Records a delivery leased until leaseUntil so the retry loop leaves it
+  // alone while the first attempt is in flight. Returns null if the event was
+  // already recorded for this subscription.
+  async createDelivery(
+    subscriptionId: string,
+    eventId: string,
+    event: string,
+    payload: Record<string, any>,
+    leaseUntil: Date
+  ): Promise<DueWebhookDelivery | null> {
+    const result = await this.pool.query(
+      `WITH inserted AS (
+         INSERT INTO webhook_deliveries (subscription_id, event_id, event, payload, next_attempt_at)
+         VALUES ($1, $2, $3, $4, $5)
+         ON CONFLICT (subscription_id, event_id) DO NOTHING
+         RETURNING *
+       )
+       SELECT inserted.*, s.url, s.secret
+       FROM inserted JOIN webhook_subscriptions s ON s.id = inserted.subscription_id`,
+      [subscriptionId, eventId, event, JSON.stringify(payload), leaseUntil]
+    )
+    return result.rows.length > 0 ? this.mapDueDelivery(result.rows[0]) : null
+  }
+
+  // Claims pending deliveries whose retry time has come, leasing them until
+  // leaseUntil so concurrent replicas don't send them twice
+  async claimDueDeliveries(limit: number, leaseUntil: Date): Promise<DueWebhookDelivery[]> {
+    const result = await this.pool.query(
+      `UPDATE webhook_deliveries d
+       SET next_attempt_at = $2, updated_at = NOW()
+       FROM (
+         SELECT wd.id FROM webhook_deliveries wd
+         JOIN webhook_subscriptions ws ON ws.id = wd.subscription_id AND ws.active
+         WHERE wd.status = 'pending' AND wd.next_attempt_at <= NOW()
+         ORDER BY wd.next_attempt_at
+         LIMIT $1
+         FOR UPDATE OF wd SKIP LOCKED
+       ) due, webhook_subscriptions s
+       WHERE d.id = due.id AND s.id = d.subscription_id
+       RETURNING d.*, s.url, s.secret`,
+      [limit, leaseUntil]
+    )
+    return result.rows.map(row => this.mapDueDelivery(row))
+  }
+
+  async recordAttempt(
+    id: string,
+    status: WebhookDeliveryStatus,
+    statusCode: number | undefined,
+    error: string | undefined,
+    nextAttemptAt: Date | null
+  ): Promise<void> {
+    await this.pool.query(
+      `UPDATE webhook_deliveries
+       SET status = $2,
+           attempts = attempts + 1,
+           last_status_code = $3,
+           last_error = $4,
+           next_attempt_at = $5,
+           delivered_at = CASE WHEN $2 = 'succeeded' THEN NOW() ELSE delivered_at END,
+           updated_at = NOW()
+       WHERE id = $1`,
+      [id, status, statusCode ?? null, error ?? null, nextAttemptAt]
+    )
+  }
+
+  async listDeliveries(
+    subscriptionId: string,
+    status: WebhookDeliveryStatus | undefined,
+    offset: number,
+    limit: number
+  ): Promise<{ deliveries: WebhookDelivery[]; total: number }> {
+    const params: any[] = [subscriptionId]
+    let filter = 'subscription_id = $1'
+    if (status) {
+      params.push(status)
+      filter += ' AND status = $2'
+    }
+
+    const count = await this.pool.query(`SELECT COUNT(*) AS total FROM webhook_deliveries WHERE ${filter}`, params)
+    const result = await this.pool.query(
+      `SELECT * FROM webhook_deliveries WHERE ${filter}
+       ORDER BY created_at DESC
+       OFFSET $${params.length + 1} LIMIT $${params.length + 2}`,
+      [...params, offset, limit]
+    )
+
+    return {
+      deliveries: result.rows.map(row => this.mapDelivery(row)),
+      total: parseInt(count.rows[0]?.total || '0')
+    }
+  }
+
+  // Puts a dead-lettered or failing delivery back in line to be sent now
+  async requeueDelivery(id: string, subscriptionId: string): Promise<boolean> {
+    const result = await this.pool.query(
+      `UPDATE webhook_deliveries
+       SET status = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+       WHERE id = $1 AND subscription_id = $2 AND status <> 'succeeded'`,
+      [id, subscriptionId]
+    )
+    return result.rowCount > 0
+  }
+
+  async close(): Promise<void> {
+    await this.pool.end()
+  }
+
+  private mapSubscription(row: any): WebhookSubscription {
+    return {
+      id: row.id,
+      ownerId: row.owner_id,
+      organizationId: row.organization_id,
+      url: row.url,
+      events: row.events,
+      description: row.description || undefined,
+      active: row.active,
+      createdAt: new Date(row.created_at),
+      updatedAt: new Date(row.updated_at)
+    }
+  }
+
+  private mapDelivery(row: any): WebhookDelivery {
+    return {
+      id: row.id,
+      subscriptionId: row.subscription_id,
+      eventId: row.event_id,
+      event: row.event,
+      payload: row.payload,
+      status: row.status,
+      attempts: row.attempts,
+      nextAttemptAt: row.next_attempt_at ? new Date(row.next_attempt_at) : undefined,
+      lastStatusCode: row.last_status_code ?? undefined,
+      lastError: row.last_error || undefined,
+      deliveredAt: row.delivered_at ? new Date(row.delivered_at) : undefined,
+      createdAt: new Date(row.created_at),
+      updatedAt: new Date(row.updated_at)
+    }
+  }
+
+  private mapDueDelivery(row: any): DueWebhookDelivery {
+    return { ...this.mapDelivery(row), url: row.url, secret: row.secret }
+  }
+}
@@ -30,6 +30,16 @@ export interface UserProfileUpdatedEvent extends BaseEvent {
   }
 }
 
+export interface UserErasureRequestedEvent extends BaseEvent {
+  eventType: 'USER_ERASURE_REQUESTED'
+  aggregateType: 'User'
+  data: {
+    userId: string
+    requestedBy: string
+    requestedAt: string
+  }
+}
+
 // Course Events
 export interface CourseCreatedEvent extends BaseEvent {
   eventType: 'COURSE_CREATED'
@@ -61,6 +71,54 @@ export interface CoursePublishedEvent extends BaseEvent {
   data: {
     title: string
     instructorId: string
+    publishedAt?: string
+    scheduled?: boolean
+    organizationId?: string
+  }
+}
+
+export interface CourseSuspendedEvent extends BaseEvent {
+  eventType: 'COURSE_SUSPENDED'
+  aggregateType: 'Course'
+  data: {
+    courseId: string
+    instructorId: string
+    reason: string
+    suspendedAt: string
+    enrollmentsFrozen: boolean
+  }
+}
+
+export interface CourseReinstatedEvent extends BaseEvent {
+  eventType: 'COURSE_REINSTATED'
+  aggregateType: 'Course'
+  data: {
+    courseId: string
+    instructorId: string
+    reason: string
+  }
+}
+
+export interface CourseForceUnpublishedEvent extends BaseEvent {
+  eventType: 'COURSE_FORCE_UNPUBLISHED'
+  aggregateType: 'Course'
+  data: {
+    courseId: string
+    instructorId: string
+    reason: string
+  }
+}
+
+export interface CourseLinksBrokenEvent extends BaseEvent {
+  eventType: 'COURSE_LINKS_BROKEN'
+  aggregateType: 'Course'
+  data: {
+    courseId: string
+    instructorId: string
+    title: string
+    brokenCount: number
+    totalLinks: number
+    checkedAt: string
   }
 }
 
@@ -76,6 +134,53 @@ export interface StudentEnrolledEvent extends BaseEvent {
   }
 }
 
+export interface WaitlistPromotedEvent extends BaseEvent {
+  eventType: 'WAITLIST_PROMOTED'
+  aggregateType: 'Course'
+  data: {
+    courseId: string
+    userId: string
+    waitlistEntryId: string
+    title: string
+    promotedAt: string
+    reservationId: string // seat held for the student until holdExpiresAt
+    holdExpiresAt: string
+  }
+}
+
+interface OfficeHoursBookingData {
+  courseId: string
+  title: string
+  slotId: string
+  bookingId: string
+  userId: string
+  instructorId: string
+  startsAt: string
+  endsAt: string
+  location?: string
+  meetingUrl?: string
+}
+
+export interface OfficeHoursBookedEvent extends BaseEvent {
+  eventType: 'OFFICE_HOURS_BOOKED'
+  aggregateType: 'Course'
+  data: OfficeHoursBookingData
+}
+
+export interface OfficeHoursCancelledEvent extends BaseEvent {
+  eventType: 'OFFICE_HOURS_CANCELLED'
+  aggregateType: 'Course'
+  data: OfficeHoursBookingData & {
+    reason: 'booking_cancelled' | 'slot_cancelled'
+  }
+}
+
+export interface OfficeHoursReminderEvent extends BaseEvent {
+  eventType: 'OFFICE_HOURS_REMINDER'
+  aggregateType: 'Course'
+  data: OfficeHoursBookingData
+}
+
 export interface LessonCompletedEvent extends BaseEvent {
   eventType: 'LESSON_COMPLETED'
   aggregateType: 'Enrollment'
@@ -96,6 +201,13 @@ export interface CourseCompletedEvent extends BaseEvent {
     studentId: string
     completionDate: Date
     finalScore?: number
+    finalGrade?: {
+      scale: 'letter' | 'pass_fail' | 'percentage' | 'gpa'
+      percentage: number
+      label: string
+      gpaPoints?: number
+      passed: boolean
+    }
     certificateId?: string
   }
 }
@@ -127,6 +239,22 @@ export interface AssessmentCompletedEvent extends BaseEvent {
   }
 }
 
+export interface SubmissionGradedEvent extends BaseEvent {
+  eventType: 'SUBMISSION_GRADED'
+  aggregateType: 'Submission'
+  data: {
+    submissionId: string
+    assessmentId: string
+    courseId: string
+    studentId: string
+    attemptNumber: number
+    score: number
+    maxScore: number
+    passed: boolean
+    organizationId?: string
+  }
+}
+
 // Payment Events
 export interface PaymentInitiatedEvent extends BaseEvent {
   eventType: 'PAYMENT_INITIATED'
@@ -199,8 +327,34 @@ export interface ContentUploadedEvent extends BaseEvent {
     courseId: string
     fileName: string
     fileType: string
-    fileSize: number
+    fileSize: number // bytes stored, counted against the organization's storage quota
     uploadedBy: string
+    organizationId?: string
+  }
+}
+
+export interface ContentDeletedEvent extends BaseEvent {
+  eventType: 'CONTENT_DELETED'
+  aggregateType: 'Content'
+  data: {
+    contentId: string
+    courseId: string
+    fileSize: number
+    deletedBy: string
+    organizationId?: string
+  }
+}
+
+export interface ContentProcessedEvent extends BaseEvent {
+  eventType: 'CONTENT_PROCESSED'
+  aggregateType: 'Content'
+  data: {
+    contentId: string
+    courseId: string
+    status: 'ready' | 'failed'
+    renditions?: string[]
+    error?: string
+    organizationId?: string
   }
 }
 
@@ -216,21 +370,60 @@ export interface SystemHealthCheckEvent extends BaseEvent {
   }
 }
 
+export interface QuotaExceededEvent extends BaseEvent {
+  eventType: 'QUOTA_EXCEEDED'
+  aggregateType: 'Organization'
+  data: {
+    organizationId: string
+    quota: 'requestsPerDay' | 'uploadsPerDay' | 'storageBytes' | 'concurrentExamTakers'
+    limit: number
+    used: number
+    plan: string
+  }
+}
+
+export interface QuotaWarningEvent extends BaseEvent {
+  eventType: 'QUOTA_WARNING'
+  aggregateType: 'Organization'
+  data: {
+    organizationId: string
+    quota: 'requestsPerDay' | 'uploadsPerDay' | 'storageBytes' | 'concurrentExamTakers'
+    threshold: number // percent of the limit reached
+    limit: number
+    used: number
+    plan: string
+  }
+}
+
 export type DomainEvent = 
   | UserRegisteredEvent
   | UserProfileUpdatedEvent
+  | UserErasureRequestedEvent
   | CourseCreatedEvent
   | CourseUpdatedEvent
   | CoursePublishedEvent
+  | CourseSuspendedEvent
+  | CourseReinstatedEvent
+  | CourseForceUnpublishedEvent
+  | CourseLinksBrokenEvent
   | StudentEnrolledEvent
+  | WaitlistPromotedEvent
+  | OfficeHoursBookedEvent
+  | OfficeHoursCancelledEvent
+  | OfficeHoursReminderEvent
   | LessonCompletedEvent
   | CourseCompletedEvent
   | AssessmentAttemptedEvent
   | AssessmentCompletedEvent
+  | SubmissionGradedEvent
   | PaymentInitiatedEvent
   | PaymentCompletedEvent
   | PaymentFailedEvent
   | RefundProcessedEvent
   | NotificationRequestedEvent
   | ContentUploadedEvent
+  | ContentDeletedEvent
+  | ContentProcessedEvent
   | SystemHealthCheckEvent
+  | QuotaExceededEvent
+  | QuotaWarningEvent
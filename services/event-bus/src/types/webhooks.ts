@@ -0,0 +1,60 @@
+// Domain events integrators can subscribe to, keyed by internal event type
+export const WEBHOOK_EVENTS: Record<string, string> = {
+  COURSE_PUBLISHED: 'course.published',
+  SUBMISSION_GRADED: 'submission.graded',
+  CONTENT_PROCESSED: 'content.processed'
+}
+
+export type WebhookDeliveryStatus = 'pending' | 'succeeded' | 'dead_letter'
+
+export interface WebhookSubscription {
+  id: string
+  ownerId: string
+  organizationId: string
+  url: string
+  events: string[]
+  description?: string
+  active: boolean
+  createdAt: Date
+  updatedAt: Date
+}
+
+// The signing secret is only read when delivering and shown once on creation
+export interface WebhookSubscriptionWithSecret extends WebhookSubscription {
+  secret: string
+}
+
+export interface WebhookSubscriptionInput {
+  url: string
+  events: string[]
+  description?: string
+  active?: boolean
+}
+
+export interface WebhookDelivery {
+  id: string
+  subscriptionId: string
+  eventId: string
+  event: string
+  payload: Record<string, any>
+  status: WebhookDeliveryStatus
+  attempts: number
+  nextAttemptAt?: Date
+  lastStatusCode?: number
+  lastError?: string
+  deliveredAt?: Date
+  createdAt: Date
+  updatedAt: Date
+}
+
+// A delivery claimed for sending, with the endpoint it goes to
+export interface DueWebhookDelivery extends WebhookDelivery {
+  url: string
+  secret: string
+}
+
+export interface WebhookAttemptResult {
+  succeeded: boolean
+  statusCode?: number
+  error?: string
+}
@@ -0,0 +1,118 @@
+import dns from 'dns'
+import { BlockList, isIP } from 'net'
+
+// Outbound requests to user-supplied URLs (webhooks) must not reach the
+// platform's own network. WEBHOOK_ALLOW_PRIVATE_NETWORKS=true lifts the
+// restriction for local development.
+function privateNetworksAllowed(): boolean {
+  return process.env.WEBHOOK_ALLOW_PRIVATE_NETWORKS === 'true'
+}
+
+// Everything that isn't public unicast: this network, private ranges,
+// carrier-grade NAT, loopback, link-local (cloud metadata), benchmarking,
+// documentation, multicast and reserved space
+const IPV4_BLOCKED: [string, number][] = [
+  ['0.0.0.0', 8], ['10.0.0.0', 8], ['100.64.0.0', 10], ['127.0.0.0', 8],
+  ['169.254.0.0', 16], ['172.16.0.0', 12], ['192.0.0.0', 24], ['192.0.2.0', 24],
+  ['192.168.0.0', 16], ['198.18.0.0', 15], ['198.51.100.0', 24], ['203.0.113.0', 24],
+  ['224.0.0.0', 3]
+]
+
+// Unspecified and loopback, IPv4-compatible and IPv4-mapped space, NAT64,
+// unique local, link-local, documentation and multicast. Addresses embedding
+// an IPv4 address are also checked as that address.
+const IPV6_BLOCKED: [string, number][] = [
+  ['::', 96], ['::ffff:0:0', 96], ['64:ff9b::', 96], ['64:ff9b:1::', 48],
+  ['100::', 64], ['2001:db8::', 32], ['fc00::', 7], ['fe80::', 10], ['ff00::', 8]
+]
+
+// Kept apart because a BlockList matches IPv4 addresses against IPv6 rules
+// for the ranges that embed them
+const blockedIPv4 = new BlockList()
+for (const [network, prefix] of IPV4_BLOCKED) blockedIPv4.addSubnet(network, prefix, 'ipv4')
+const blockedIPv6 = new BlockList()
+for (const [network, prefix] of IPV6_BLOCKED) blockedIPv6.addSubnet(network, prefix, 'ipv6')
+
+// Expands an IPv6 address, in any notation, to its eight 16-bit groups
+function ipv6Groups(ip: string): number[] {
+  let address = ip.toLowerCase()
+  const dotted = address.match(/^(.*:)(\d+\.\d+\.\d+\.\d+)$/)
+  if (dotted) {
+    const [a, b, c, d] = dotted[2].split('.').map(Number)
+    address = `${dotted[1]}${((a << 8) | b).toString(16)}:${((c << 8) | d).toString(16)}`
+  }
+
+  const [head, tail] = address.split('::')
+  const left = head ? head.split(':') : []
+  const right = tail ? tail.split(':') : []
+  const missing = address.includes('::') ? 8 - left.length - right.length : 0
+  return [...left, ...Array(missing).fill('0'), ...right].map((group) => parseInt(group, 16))
+}
+
+// The IPv4 address embedded in an IPv4-mapped, IPv4-compatible or NAT64
+// IPv6 address, in whichever notation it was written
+function embeddedIPv4(ip: string): string | null {
+  const groups = ipv6Groups(ip)
+  const high = groups.slice(0, 6)
+  const mapped = high.slice(0, 5).every((group) => group === 0) && (high[5] === 0 || high[5] === 0xffff)
+  const nat64 = high[0] === 0x64 && high[1] === 0xff9b && high.slice(2).every((group) => group === 0)
+  if (!mapped && !nat64) {
+    return null
+  }
+  return [groups[6] >> 8, groups[6] & 0xff, groups[7] >> 8, groups[7] & 0xff].join('.')
+}
+
+// Reports whether ip is anything but a public unicast address. Anything that
+// doesn't parse as an address counts as private.
+export function isPrivateAddress(ip: string): boolean {
+  const address = ip.replace(/^\[|\]$/g, '').split('%')[0]
+  switch (isIP(address)) {
+    case 4:
+      return blockedIPv4.check(address, 'ipv4')
+    case 6: {
+      const ipv4 = embeddedIPv4(address)
+      return blockedIPv6.check(address, 'ipv6') || (ipv4 !== null && blockedIPv4.check(ipv4, 'ipv4'))
+    }
+    default:
+      return true
+  }
+}
+
+// Rejects URLs whose host is obviously internal: localhost names and literal
+// private addresses. Hostnames are checked again after resolution when the
+// request is made, since DNS can point anywhere.
+export function checkPublicUrl(url: URL): string | null {
+  if (privateNetworksAllowed()) return null
+
+  const hostname = url.hostname.replace(/^\[|\]$/g, '').toLowerCase()
+  if (hostname === 'localhost' || hostname.endsWith('.localhost')) {
+    return 'url must not point to localhost'
+  }
+  if (isIP(hostname) && isPrivateAddress(hostname)) {
+    return 'url must not point to a private or loopback address'
+  }
+  return null
+}
+
+// A dns.lookup replacement for HTTP agents that refuses to connect to private
+// addresses. Checking the address the socket actually connects to closes the
+// gap between validating a hostname and resolving it again later.
+export function publicLookup(
+  hostname: string,
+  options: dns.LookupOptions,
+  callback: (error: NodeJS.ErrnoException | null, address: string | dns.LookupAddress[], family?: number) => void
+): void {
+  dns.lookup(hostname, { ...options, all: true }, (error, addresses) => {
+    if (error) {
+      return callback(error, '')
+    }
+    const blocked = !privateNetworksAllowed() && addresses.find(entry => isPrivateAddress(entry.address))
+    if (blocked) {
+      return callback(new Error(`${hostname} resolves to private address ${blocked.address}`), '')
+    }
+    if (options.all) {
+      return callback(null, addresses)
+    }
+    callback(null, addresses[0].address, addresses[0].family)
+  })
+}
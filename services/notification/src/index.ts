@@ -19,6 +19,8 @@ import { PushProvider } from './providers/push-provider'
 import { NotificationService } from './services/notification-service'
 import { NotificationRepository } from './repositories/notification-repository'
 import { NotificationQueue } from './queue/notification-queue'
+import { DigestService } from './services/digest-service'
+import { CalendarClient } from './integrations/calendar-client'
 // Simple circuit breaker implementation
 class CircuitBreaker {
   private failureCount = 0
@@ -114,6 +116,7 @@ app.use((req, res, next) => {
 let notificationService: NotificationService
 let notificationQueue: NotificationQueue
 let repository: NotificationRepository
+let digestService: DigestService
 
 async function initializeServices() {
   try {
@@ -155,6 +158,10 @@ async function initializeServices() {
     // Initialize queue
     notificationQueue = new NotificationQueue(notificationService)
 
+    // Daily and weekly deadline digests
+    digestService = new DigestService(repository, notificationService, notificationQueue, new CalendarClient())
+    digestService.start()
+
     // Verify connections
     await emailProvider.verifyConnection()
     await smsProvider.validateConfig()
@@ -203,6 +210,26 @@ app.post('/api/v1/notifications/send', async (req: any, res: any) => {
   }
 })
 
+// Preview the digest a user would receive
+app.get('/api/v1/digests/:userId', async (req: any, res: any) => {
+  try {
+    if (!digestService) {
+      return res.status(503).json({ error: 'Service not initialized' })
+    }
+
+    const frequency = req.query.frequency || 'weekly'
+    if (frequency !== 'daily' && frequency !== 'weekly') {
+      return res.status(400).json({ error: 'frequency must be daily or weekly' })
+    }
+
+    const digest = await digestService.buildDigest(req.params.userId, frequency)
+    res.json(digest)
+  } catch (error: any) {
+    logger.error('Failed to build digest', error)
+    res.status(500).json({ error: 'Failed to build digest' })
+  }
+})
+
 // 404 handler
 app.use('*', (req, res) => {
   res.status(404).json({
@@ -235,6 +262,10 @@ async function gracefulShutdown(signal: string) {
   logger.info(`Received ${signal}, starting graceful shutdown`)
   
   try {
+    if (digestService) {
+      digestService.stop()
+    }
+
     if (notificationQueue) {
       await notificationQueue.close()
     }
@@ -0,0 +1,38 @@
+import axios from 'axios'
+import { CalendarEvent } from '../types/notification'
+import { logger } from '../utils/logger'
+
+export class CalendarClient {
+  private baseUrl: string
+  private apiKey: string
+
+  constructor() {
+    this.baseUrl = process.env.CALENDAR_SERVICE_URL || 'http://api-gateway:3000'
+    this.apiKey = process.env.CALENDAR_SERVICE_API_KEY || 'dev-key'
+  }
+
+  // Returns the student's calendar entries between from and to. Errors are
+  // rethrown so a digest is never sent with a silently missing section.
+  async getUserEvents(userId: string, from: Date, to: Date): Promise<CalendarEvent[]> {
+    try {
+      const response = await axios.get(`${this.baseUrl}/api/v1/calendar/users/${encodeURIComponent(userId)}/events`, {
+        params: {
+          from: from.toISOString(),
+          to: to.toISOString()
+        },
+        headers: {
+          'Authorization': `Bearer ${this.apiKey}`
+        },
+        timeout: 10000
+      })
+
+      return response.data?.events || []
+    } catch (error) {
+      logger.error('Failed to fetch calendar events', {
+        userId,
+        error: error.message
+      })
+      throw error
+    }
+  }
+}
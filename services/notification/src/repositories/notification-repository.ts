@@ -57,6 +57,8 @@ class Redis {
   }
 }
 import { 
+  DigestFrequency,
+  DigestRecipient,
   NotificationLog, 
   NotificationPreferences, 
   NotificationTemplate 
@@ -447,6 +449,30 @@ export class NotificationRepository {
     return result.rows
   }
 
+  async getDigestRecipients(frequency: DigestFrequency): Promise<DigestRecipient[]> {
+    const result = await this.pool.query(
+      `SELECT user_id, timezone FROM notification_preferences
+       WHERE frequency = $1 AND email = true`,
+      [frequency]
+    )
+
+    return result.rows.map(row => ({
+      userId: row.user_id,
+      timezone: row.timezone || 'UTC'
+    }))
+  }
+
+  // Records that a user's digest for a period has gone out. Returns false if
+  // it was already recorded, so overlapping runs don't send it twice.
+  async claimDigest(userId: string, periodKey: string, ttlSeconds: number): Promise<boolean> {
+    const key = `digest:${userId}:${periodKey}`
+    if (await this.redis.get(key)) {
+      return false
+    }
+    await this.redis.setex(key, ttlSeconds, '1')
+    return true
+  }
+
   async close(): Promise<void> {
     await this.pool.end()
     await this.redis.quit()
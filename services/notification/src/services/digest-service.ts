@@ -0,0 +1,215 @@
+import {
+  CalendarEvent,
+  Digest,
+  DigestFrequency,
+  DigestRecipient,
+  NotificationRequest
+} from '../types/notification'
+import { NotificationRepository } from '../repositories/notification-repository'
+import { NotificationQueue } from '../queue/notification-queue'
+import { CalendarClient } from '../integrations/calendar-client'
+import { NotificationService } from './notification-service'
+import { logger } from '../utils/logger'
+
+const DAY_MS = 24 * 60 * 60 * 1000
+
+const PERIOD_MS: Record<DigestFrequency, number> = {
+  daily: DAY_MS,
+  weekly: 7 * DAY_MS
+}
+
+const EVENT_LABELS: Record<CalendarEvent['type'], string> = {
+  assessment_due: 'Assessment closes',
+  enrollment_deadline: 'Enrollment closes',
+  lesson_unlock: 'Lesson unlocks',
+  office_hours: 'Office hours',
+  announcement: 'Announcement'
+}
+
+// Builds and sends each student's daily or weekly email of upcoming
+// deadlines and new announcements. Digests go out at DIGEST_HOUR in the
+// student's own timezone; weekly digests on Mondays.
+export class DigestService {
+  private repository: NotificationRepository
+  private notificationService: NotificationService
+  private queue: NotificationQueue
+  private calendar: CalendarClient
+  private sendHour: number
+  private timer?: NodeJS.Timeout
+
+  constructor(
+    repository: NotificationRepository,
+    notificationService: NotificationService,
+    queue: NotificationQueue,
+    calendar: CalendarClient
+  ) {
+    this.repository = repository
+    this.notificationService = notificationService
+    this.queue = queue
+    this.calendar = calendar
+    this.sendHour = parseInt(process.env.DIGEST_HOUR || '8')
+  }
+
+  // Checks for due digests at the top of every hour
+  start(): void {
+    const untilNextHour = 60 * 60 * 1000 - (Date.now() % (60 * 60 * 1000))
+    this.timer = setTimeout(() => {
+      this.runDue().catch(error => logger.error('Digest run failed', { error: error.message }))
+      this.timer = setInterval(() => {
+        this.runDue().catch(error => logger.error('Digest run failed', { error: error.message }))
+      }, 60 * 60 * 1000)
+    }, untilNextHour)
+  }
+
+  stop(): void {
+    if (this.timer) {
+      clearTimeout(this.timer)
+      clearInterval(this.timer)
+    }
+  }
+
+  async runDue(now: Date = new Date()): Promise<void> {
+    let sent = 0
+    for (const frequency of ['daily', 'weekly'] as DigestFrequency[]) {
+      const recipients = await this.repository.getDigestRecipients(frequency)
+      for (const recipient of recipients) {
+        if (!this.isDue(recipient, frequency, now)) continue
+        try {
+          if (await this.sendDigest(recipient, frequency, now)) sent++
+        } catch (error) {
+          logger.error('Failed to send digest', {
+            userId: recipient.userId,
+            frequency,
+            error: error.message
+          })
+        }
+      }
+    }
+
+    logger.info('Digest run completed', { sent })
+  }
+
+  // Deadlines are the calendar entries in the coming period; announcements
+  // are the ones posted during the period just ended
+  async buildDigest(userId: string, frequency: DigestFrequency, now: Date = new Date()): Promise<Digest> {
+    const periodStart = new Date(now.getTime() - PERIOD_MS[frequency])
+    const periodEnd = new Date(now.getTime() + PERIOD_MS[frequency])
+    const events = await this.calendar.getUserEvents(userId, periodStart, periodEnd)
+
+    const byDate = (a: CalendarEvent, b: CalendarEvent) =>
+      new Date(a.at).getTime() - new Date(b.at).getTime()
+
+    return {
+      userId,
+      frequency,
+      periodStart,
+      periodEnd,
+      deadlines: events
+        .filter(event => event.type !== 'announcement' && new Date(event.at) >= now)
+        .sort(byDate),
+      announcements: events
+        .filter(event => event.type === 'announcement' && new Date(event.at) < now)
+        .sort(byDate)
+    }
+  }
+
+  private async sendDigest(recipient: DigestRecipient, frequency: DigestFrequency, now: Date): Promise<boolean> {
+    const digest = await this.buildDigest(recipient.userId, frequency, now)
+    if (digest.deadlines.length === 0 && digest.announcements.length === 0) {
+      return false
+    }
+
+    const periodKey = `${frequency}:${this.localDate(recipient.timezone, now)}`
+    if (!(await this.repository.claimDigest(recipient.userId, periodKey, PERIOD_MS[frequency] / 1000))) {
+      return false
+    }
+
+    const request: NotificationRequest = {
+      recipientId: recipient.userId,
+      type: 'email',
+      template: 'deadline_digest',
+      subject: frequency === 'daily' ? 'Your day ahead on Modex' : 'Your week ahead on Modex',
+      content: this.renderText(digest, recipient.timezone),
+      metadata: {
+        periodLabel: frequency === 'daily' ? 'today' : 'this week',
+        deadlinesHtml: this.renderHtml(digest.deadlines, recipient.timezone, 'No upcoming deadlines.'),
+        announcementsHtml: this.renderHtml(digest.announcements, recipient.timezone, 'No new announcements.'),
+        deadlineCount: digest.deadlines.length,
+        announcementCount: digest.announcements.length
+      },
+      priority: 'low'
+    }
+
+    request.id = await this.notificationService.send(request)
+    await this.queue.addNotification(request)
+    return true
+  }
+
+  private isDue(recipient: DigestRecipient, frequency: DigestFrequency, now: Date): boolean {
+    const parts = new Intl.DateTimeFormat('en-US', {
+      timeZone: recipient.timezone,
+      hour: 'numeric',
+      hour12: false,
+      weekday: 'short'
+    }).formatToParts(now)
+    const hour = parseInt(parts.find(part => part.type === 'hour')?.value || '0') % 24
+    const weekday = parts.find(part => part.type === 'weekday')?.value
+
+    if (hour !== this.sendHour) return false
+    return frequency === 'daily' || weekday === 'Mon'
+  }
+
+  private localDate(timezone: string, now: Date): string {
+    return new Intl.DateTimeFormat('en-CA', { timeZone: timezone }).format(now)
+  }
+
+  private formatDate(value: string, timezone: string): string {
+    return new Intl.DateTimeFormat('en-US', {
+      timeZone: timezone,
+      weekday: 'short',
+      month: 'short',
+      day: 'numeric',
+      hour: 'numeric',
+      minute: '2-digit'
+    }).format(new Date(value))
+  }
+
+  private renderText(digest: Digest, timezone: string): string {
+    const line = (event: CalendarEvent) =>
+      `- ${EVENT_LABELS[event.type]}: ${event.title}${event.courseTitle ? ` (${event.courseTitle})` : ''} - ${this.formatDate(event.at, timezone)}`
+
+    const sections: string[] = []
+    if (digest.deadlines.length > 0) {
+      sections.push(['Upcoming deadlines:', ...digest.deadlines.map(line)].join('\n'))
+    }
+    if (digest.announcements.length > 0) {
+      sections.push(['New announcements:', ...digest.announcements.map(line)].join('\n'))
+    }
+    return sections.join('\n\n')
+  }
+
+  // The email templates only substitute values, so lists are rendered here
+  private renderHtml(events: CalendarEvent[], timezone: string, empty: string): string {
+    if (events.length === 0) {
+      return `<p>${empty}</p>`
+    }
+
+    const items = events.map(event => {
+      const title = event.url
+        ? `<a href="${escapeHtml(event.url)}">${escapeHtml(event.title)}</a>`
+        : escapeHtml(event.title)
+      const course = event.courseTitle ? ` <span class="course">${escapeHtml(event.courseTitle)}</span>` : ''
+      return `<li><strong>${EVENT_LABELS[event.type]}:</strong> ${title}${course} <span class="when">${this.formatDate(event.at, timezone)}</span></li>`
+    })
+    return `<ul>${items.join('')}</ul>`
+  }
+}
+
+function escapeHtml(value: string): string {
+  return value
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+    .replace(/'/g, '&#39;')
+}
@@ -76,3 +76,31 @@ export interface NotificationPreferences {
   quietHoursEnd?: string
   timezone: string
 }
+
+export type DigestFrequency = 'daily' | 'weekly'
+
+// An entry from the calendar aggregation: a dated item in one of the
+// student's courses
+export interface CalendarEvent {
+  id: string
+  type: 'assessment_due' | 'enrollment_deadline' | 'lesson_unlock' | 'office_hours' | 'announcement'
+  title: string
+  courseId?: string
+  courseTitle?: string
+  at: string
+  url?: string
+}
+
+export interface DigestRecipient {
+  userId: string
+  timezone: string
+}
+
+export interface Digest {
+  userId: string
+  frequency: DigestFrequency
+  periodStart: Date
+  periodEnd: Date
+  deadlines: CalendarEvent[]
+  announcements: CalendarEvent[]
+}
@@ -31,7 +31,7 @@ const limiter = rateLimit({
 app.use(limiter);
 
 // Body parsing middleware
-app.use('/webhooks', express.raw({ type: 'application/json' })); // Raw body for webhooks
+app.use('/api/webhooks', express.raw({ type: 'application/json' })); // Raw body for webhook signatures
 app.use(express.json({ limit: '10mb' }));
 app.use(express.urlencoded({ extended: true, limit: '10mb' }));
 
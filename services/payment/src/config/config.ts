@@ -22,6 +22,8 @@ export const config = {
   STRIPE_SECRET_KEY: process.env.STRIPE_SECRET_KEY || '',
   STRIPE_PUBLISHABLE_KEY: process.env.STRIPE_PUBLISHABLE_KEY || '',
   STRIPE_WEBHOOK_SECRET: process.env.STRIPE_WEBHOOK_SECRET || '',
+  // How far a webhook's timestamp may be from now before it is rejected
+  WEBHOOK_TOLERANCE_SECONDS: parseInt(process.env.WEBHOOK_TOLERANCE_SECONDS || '300'),
   
   // CORS configuration
   CORS_ORIGINS: process.env.CORS_ORIGINS?.split(',') || ['http://localhost:3000'],
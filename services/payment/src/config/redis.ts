@@ -64,6 +64,12 @@ class RedisClient {
     return await this.client.get(key);
   }
 
+  // Sets key only if it doesn't exist yet; returns whether it was set
+  async setNX(key: string, value: string, expireInSeconds: number): Promise<boolean> {
+    await this.ensureConnection();
+    return (await this.client.set(key, value, { NX: true, EX: expireInSeconds })) === 'OK';
+  }
+
   async del(key: string): Promise<number> {
     await this.ensureConnection();
     return await this.client.del(key);
@@ -1,6 +1,5 @@
 import { Request, Response } from 'express';
 import { paymentService, CreatePaymentData, CreateSubscriptionData } from '../services/paymentService';
-import { stripeService } from '../services/stripeService';
 import { config } from '../config/config';
 
 interface AuthenticatedRequest extends Request {
@@ -255,17 +254,11 @@ export class PaymentController {
     }
   }
 
+  // The body was verified by verifyStripeWebhook and is still raw
   async handleWebhook(req: Request, res: Response): Promise<void> {
     try {
-      const signature = req.headers['stripe-signature'] as string;
-      
-      if (!signature) {
-        res.status(400).json({ error: 'Missing Stripe signature' });
-        return;
-      }
+      const event = JSON.parse(req.body.toString('utf8'));
 
-      const event = await stripeService.constructWebhookEvent(req.body, signature);
-      
       await paymentService.handleWebhookEvent(event);
 
       res.json({ received: true });
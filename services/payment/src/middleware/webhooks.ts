@@ -0,0 +1,31 @@
+import { Request, Response, NextFunction } from 'express';
+import { WebhookVerifier, createWebhookVerificationMiddleware, stripeScheme } from '@modex/webhook-verification';
+import { config } from '../config/config';
+import { redisClient } from '../config/redis';
+
+// Replay nonces live in Redis so every replica rejects a repeated delivery
+const nonceStore = {
+  claim: (key: string, ttlSeconds: number) => redisClient.setNX(key, '1', ttlSeconds),
+};
+
+let stripeWebhooks: ReturnType<typeof createWebhookVerificationMiddleware> | undefined;
+
+// Checks the Stripe-Signature header, the event's age and that it hasn't been
+// delivered before. Built on first use so the service starts without a secret.
+export const verifyStripeWebhook = (req: Request, res: Response, next: NextFunction): void => {
+  if (!config.STRIPE_WEBHOOK_SECRET) {
+    res.status(500).json({ error: 'STRIPE_WEBHOOK_SECRET is required for webhook verification' });
+    return;
+  }
+
+  if (!stripeWebhooks) {
+    stripeWebhooks = createWebhookVerificationMiddleware(new WebhookVerifier({
+      name: 'stripe',
+      secret: config.STRIPE_WEBHOOK_SECRET,
+      scheme: stripeScheme(),
+      toleranceSeconds: config.WEBHOOK_TOLERANCE_SECONDS,
+      nonceStore,
+    }));
+  }
+  stripeWebhooks(req, res, next);
+};
@@ -1,6 +1,7 @@
 import { Router } from 'express';
 import { paymentController } from '../controllers/paymentController';
 import { authenticateToken } from '../middleware/auth';
+import { verifyStripeWebhook } from '../middleware/webhooks';
 
 const router = Router();
 
@@ -18,7 +19,7 @@ router.delete('/subscriptions/:subscriptionId', authenticateToken, paymentContro
 // Payment methods routes
 router.get('/payment-methods', authenticateToken, paymentController.getPaymentMethods.bind(paymentController));
 
-// Webhook route (no authentication required)
-router.post('/webhooks/stripe', paymentController.handleWebhook.bind(paymentController));
+// Webhook route (verified by signature instead of authentication)
+router.post('/webhooks/stripe', verifyStripeWebhook, paymentController.handleWebhook.bind(paymentController));
 
 export default router;
\ No newline at end of file
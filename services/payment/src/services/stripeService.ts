@@ -116,18 +116,6 @@ class StripeService {
     return await this.stripe.subscriptions.retrieve(subscriptionId);
   }
 
-  async constructWebhookEvent(body: Buffer, signature: string): Promise<Stripe.Event> {
-    if (!config.STRIPE_WEBHOOK_SECRET) {
-      throw new Error('STRIPE_WEBHOOK_SECRET is required for webhook verification');
-    }
-    
-    return this.stripe.webhooks.constructEvent(
-      body,
-      signature,
-      config.STRIPE_WEBHOOK_SECRET
-    );
-  }
-
   async listCustomerPaymentMethods(customerId: string): Promise<Stripe.PaymentMethod[]> {
     const paymentMethods = await this.stripe.paymentMethods.list({
       customer: customerId,
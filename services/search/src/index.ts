@@ -1,6 +1,7 @@
 import express from "express";
 import cors from "cors";
 import helmet from "helmet";
+import { parseAcceptLanguage } from "./utils/locale";
 
 const compression = () => (req: any, res: any, next: any) => next();
 const rateLimit = (options: any) => (req: any, res: any, next: any) => next();
@@ -133,6 +134,7 @@ interface SearchRequest {
     page?: number;
     size?: number;
   };
+  locales?: string[];
 }
 
 interface AutocompleteRequest {
@@ -513,11 +515,22 @@ app.post(
       .optional()
       .isInt({ min: 1, max: 100 })
       .withMessage("Size must be between 1 and 100"),
+    body("locales")
+      .optional()
+      .isArray()
+      .withMessage("Locales must be an array"),
   ],
   handleValidationErrors,
   async (req: any, res: any) => {
     try {
-      const searchRequest: SearchRequest = req.body;
+      // Explicit locales win; otherwise rank by the browser's languages
+      const searchRequest: SearchRequest = {
+        ...req.body,
+        locales:
+          req.body.locales ||
+          parseAcceptLanguage(req.headers["accept-language"]),
+      };
+      res.set("Vary", "Accept-Language");
 
       const result = await searchService.search(
         searchRequest,
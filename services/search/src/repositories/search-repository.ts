@@ -296,7 +296,12 @@ export class SearchRepository {
           i.id as instructor_id,
           i.name as instructor_name,
           i.email as instructor_email,
-          i.rating as instructor_rating
+          i.rating as instructor_rating,
+          (
+            SELECT json_object_agg(ct.locale, json_build_object('title', ct.title, 'description', ct.description))
+            FROM course_translations ct
+            WHERE ct.course_id = c.id
+          ) as translations
         FROM courses c
         LEFT JOIN instructors i ON c.instructor_id = i.id
         WHERE c.status = 'published'
@@ -326,6 +331,7 @@ export class SearchRepository {
         reviewCount: row.review_count,
         enrollmentCount: row.enrollment_count,
         language: row.language,
+        translations: row.translations || {},
         tags: row.tags || [],
         skills: row.skills || [],
         prerequisites: row.prerequisites || [],
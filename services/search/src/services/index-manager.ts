@@ -1,6 +1,7 @@
 import { ElasticsearchClient } from './elasticsearch-client'
 import { SearchType, IndexTemplate, IndexSettings, IndexMapping } from '../types/search'
 import { logger } from '../utils/logger'
+import { SEARCH_LOCALES, buildLocaleAnalysis } from '../utils/locale'
 
 export class IndexManager {
   private esClient: ElasticsearchClient
@@ -96,6 +97,8 @@ export class IndexManager {
   }
 
   private getIndexSettings(type: SearchType): IndexSettings {
+    const localeAnalysis = buildLocaleAnalysis()
+
     const baseSettings: IndexSettings = {
       numberOfShards: this.environment === 'production' ? 3 : 1,
       numberOfReplicas: this.environment === 'production' ? 2 : 0,
@@ -129,7 +132,8 @@ export class IndexManager {
               'content_stop',
               'content_stemmer'
             ]
-          }
+          },
+          ...localeAnalysis.analyzer
         },
         tokenizer: {
           autocomplete_tokenizer: {
@@ -167,7 +171,8 @@ export class IndexManager {
           content_stemmer: {
             type: 'stemmer',
             language: 'english'
-          }
+          },
+          ...localeAnalysis.filter
         }
       }
     }
@@ -263,6 +268,10 @@ export class IndexManager {
           reviewCount: { type: 'integer' },
           enrollmentCount: { type: 'integer' },
           language: { type: 'keyword' },
+          availableLocales: { type: 'keyword' },
+          i18n: {
+            properties: this.getLocalizedTextMapping()
+          },
           skills: {
             type: 'text',
             analyzer: 'search_analyzer',
@@ -466,6 +475,36 @@ export class IndexManager {
     }
   }
 
+  // One sub-object per supported locale, each analyzed in its own language
+  private getLocalizedTextMapping(): IndexMapping {
+    const mapping: IndexMapping = {}
+
+    for (const locale of Object.keys(SEARCH_LOCALES)) {
+      mapping[locale] = {
+        properties: {
+          title: {
+            type: 'text',
+            analyzer: `${locale}_analyzer`,
+            fields: {
+              keyword: { type: 'keyword' },
+              autocomplete: {
+                type: 'text',
+                analyzer: 'autocomplete_analyzer',
+                search_analyzer: `${locale}_analyzer`
+              }
+            }
+          },
+          description: {
+            type: 'text',
+            analyzer: `${locale}_analyzer`
+          }
+        }
+      }
+    }
+
+    return mapping
+  }
+
   private async waitForTask(taskId: string, maxWaitTime: number = 300000): Promise<void> {
     const startTime = Date.now()
     
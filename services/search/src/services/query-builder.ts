@@ -25,21 +25,23 @@ export class QueryBuilder {
       timeout: '30s'
     }
 
+    const locales = request.locales || []
+
     // Build main query
-    query.query = this.buildMainQuery(request.query, request.filters)
+    query.query = this.buildMainQuery(request.query, request.filters, locales)
 
     // Add sorting
     if (request.sort && request.sort.length > 0) {
-      query.sort = this.buildSort(request.sort)
+      query.sort = this.buildSort(request.sort, locales)
     }
 
     // Add highlighting
     if (request.highlight) {
-      query.highlight = this.buildHighlight()
+      query.highlight = this.buildHighlight(locales)
     }
 
     // Add aggregations for facets
-    query.aggs = this.buildAggregations(request.filters)
+    query.aggs = this.buildAggregations(request.filters, locales)
 
     // Add suggestions
     if (request.suggestions) {
@@ -105,7 +107,7 @@ export class QueryBuilder {
     return query
   }
 
-  private buildMainQuery(queryString: string, filters?: SearchFilters): any {
+  private buildMainQuery(queryString: string, filters?: SearchFilters, locales: string[] = []): any {
     const boolQuery: any = {
       bool: {
         must: [],
@@ -117,7 +119,7 @@ export class QueryBuilder {
 
     // Main text search
     if (queryString && queryString.trim()) {
-      const textQuery = this.buildTextQuery(queryString)
+      const textQuery = this.buildTextQuery(queryString, locales)
       boolQuery.bool.must.push(textQuery)
     } else {
       // Match all if no query
@@ -134,19 +136,47 @@ export class QueryBuilder {
     return {
       function_score: {
         query: boolQuery,
-        functions: this.buildScoreFunctions(),
+        functions: this.buildScoreFunctions(locales),
         score_mode: 'multiply',
         boost_mode: 'multiply'
       }
     }
   }
 
-  private buildTextQuery(queryString: string): any {
+  private buildTextQuery(queryString: string, locales: string[] = []): any {
     const cleanQuery = queryString.trim()
 
+    const queries: any[] = []
+
+    // Localized titles and descriptions, analyzed in their own language so
+    // inflected and accented forms match for non-English speakers
+    if (locales.length > 0) {
+      queries.push(
+        {
+          multi_match: {
+            query: cleanQuery,
+            type: 'phrase',
+            fields: locales.flatMap(locale => [`i18n.${locale}.title^10`, `i18n.${locale}.description^3`]),
+            boost: 3
+          }
+        },
+        {
+          multi_match: {
+            query: cleanQuery,
+            type: 'best_fields',
+            fields: locales.flatMap(locale => [`i18n.${locale}.title^5`, `i18n.${locale}.description^2`]),
+            fuzziness: 'AUTO',
+            operator: this.config.defaultOperator,
+            boost: 2
+          }
+        }
+      )
+    }
+
     return {
       dis_max: {
         queries: [
+          ...queries,
           // Exact phrase match (highest priority)
           {
             multi_match: {
@@ -294,6 +324,15 @@ export class QueryBuilder {
       })
     }
 
+    // Locale filter: documents available in any of the given locales
+    if (filters.locales && filters.locales.length > 0) {
+      filterQueries.push({
+        terms: {
+          availableLocales: filters.locales
+        }
+      })
+    }
+
     // Tags filter
     if (filters.tags && filters.tags.length > 0) {
       filterQueries.push({
@@ -343,7 +382,7 @@ export class QueryBuilder {
     return filterQueries
   }
 
-  private buildSort(sortOptions: SortOption[]): any[] {
+  private buildSort(sortOptions: SortOption[], locales: string[] = []): any[] {
     const sortQueries: any[] = []
 
     for (const sort of sortOptions) {
@@ -379,7 +418,36 @@ export class QueryBuilder {
             unmapped_type: 'long'
           }
         })
+      } else if (sort.field === 'locale') {
+        // Documents in the most preferred locale first, then the next, then the rest
+        sortQueries.push({
+          _script: {
+            type: 'number',
+            order: sort.order,
+            script: {
+              lang: 'painless',
+              source: `
+                if (!doc.containsKey('availableLocales')) { return params.locales.size(); }
+                for (int i = 0; i < params.locales.size(); i++) {
+                  if (doc['availableLocales'].contains(params.locales[i])) { return i; }
+                }
+                return params.locales.size();
+              `,
+              params: { locales }
+            }
+          }
+        })
       } else if (sort.field === 'alphabetical') {
+        // Sort on the localized title where one exists
+        if (locales.length > 0) {
+          sortQueries.push({
+            [`i18n.${locales[0]}.title.keyword`]: {
+              order: sort.order,
+              missing: '_last',
+              unmapped_type: 'keyword'
+            }
+          })
+        }
         sortQueries.push({ 
           'title.keyword': { 
             order: sort.order,
@@ -406,7 +474,13 @@ export class QueryBuilder {
     return sortQueries
   }
 
-  private buildHighlight(): any {
+  private buildHighlight(locales: string[] = []): any {
+    const localizedFields: Record<string, any> = {}
+    for (const locale of locales) {
+      localizedFields[`i18n.${locale}.title`] = { fragment_size: 200, number_of_fragments: 1 }
+      localizedFields[`i18n.${locale}.description`] = { fragment_size: 150, number_of_fragments: 2 }
+    }
+
     return {
       pre_tags: ['<mark>'],
       post_tags: ['</mark>'],
@@ -428,13 +502,20 @@ export class QueryBuilder {
         searchableContent: {
           fragment_size: 150,
           number_of_fragments: 2
-        }
+        },
+        ...localizedFields
       }
     }
   }
 
-  private buildAggregations(filters?: SearchFilters): any {
-    return {
+  private buildAggregations(filters?: SearchFilters, locales: string[] = []): any {
+    const aggregations: any = {
+      locales: {
+        terms: {
+          field: 'availableLocales',
+          size: 20
+        }
+      },
       categories: {
         terms: {
           field: 'category.keyword',
@@ -492,6 +573,24 @@ export class QueryBuilder {
         }
       }
     }
+
+    // Count categories, difficulty and tags only over documents available in
+    // the user's locales, so facets don't offer options that lead to results
+    // the user can't read
+    if (locales.length > 0) {
+      const localized = ['categories', 'difficulty', 'tags']
+      const nested: any = {}
+      for (const name of localized) {
+        nested[name] = aggregations[name]
+        delete aggregations[name]
+      }
+      aggregations.in_locale = {
+        filter: { terms: { availableLocales: locales } },
+        aggs: nested
+      }
+    }
+
+    return aggregations
   }
 
   private buildSuggestions(query: string): any {
@@ -525,8 +624,8 @@ export class QueryBuilder {
     }
   }
 
-  private buildScoreFunctions(): any[] {
-    return [
+  private buildScoreFunctions(locales: string[] = []): any[] {
+    const functions: any[] = [
       // Boost newer content
       {
         gauss: {
@@ -557,6 +656,28 @@ export class QueryBuilder {
         }
       }
     ]
+
+    // Boost documents in the preferred locale, and to a lesser degree those in
+    // any other accepted locale
+    if (locales.length > 0) {
+      functions.push({
+        filter: { term: { availableLocales: locales[0] } },
+        weight: 2
+      })
+    }
+    if (locales.length > 1) {
+      functions.push({
+        filter: {
+          bool: {
+            filter: { terms: { availableLocales: locales.slice(1) } },
+            must_not: { term: { availableLocales: locales[0] } }
+          }
+        },
+        weight: 1.5
+      })
+    }
+
+    return functions
   }
 
   // Specialized query builders for different search types
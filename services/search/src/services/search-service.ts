@@ -16,6 +16,7 @@ import { SearchRepository } from '../repositories/search-repository'
 import { IndexManager } from './index-manager'
 import { QueryBuilder } from './query-builder'
 import { logger } from '../utils/logger'
+import { toSearchLocale } from '../utils/locale'
 import Redis from 'ioredis'
 import { v4 as uuidv4 } from 'uuid'
 import * as natural from 'natural'
@@ -340,10 +341,20 @@ export class SearchService {
         size: Math.min(request.pagination?.size || this.config.defaultSize, this.config.maxSize)
       },
       highlight: request.highlight !== false,
-      suggestions: request.suggestions !== false
+      suggestions: request.suggestions !== false,
+      locales: this.normalizeLocales(request.locales)
     }
   }
 
+  // Keep supported locales only, in order and without duplicates
+  private normalizeLocales(locales?: string[]): string[] {
+    const normalized = (locales || [])
+      .map(locale => toSearchLocale(locale))
+      .filter((locale): locale is string => locale !== null)
+
+    return [...new Set(normalized)]
+  }
+
   private getTargetIndices(types?: SearchType[]): string[] {
     if (!types || types.length === 0) {
       return Object.values(SearchType).map(type => this.getIndexName(type))
@@ -377,7 +388,8 @@ export class SearchService {
       page: request.pagination?.page || 1,
       size: request.pagination?.size || this.config.defaultSize,
       took: esResponse.took,
-      facets
+      facets,
+      locale: request.locales?.[0]
     }
   }
 
@@ -398,6 +410,9 @@ export class SearchService {
           key: bucket.key,
           count: bucket.doc_count
         }))
+      } else if (key === 'in_locale') {
+        // Locale-filtered facets are reported under their usual names
+        Object.assign(facets, this.extractFacets(agg))
       }
     }
 
@@ -536,9 +551,35 @@ export class SearchService {
     processed.titleBoost = document.title || document.name || ''
     processed.contentBoost = document.description || document.content || ''
 
+    if (type === SearchType.COURSE) {
+      Object.assign(processed, this.buildLocalizedFields(document))
+      delete processed.translations
+    }
+
     return processed
   }
 
+  // Index the course's own text under its language and each translation under
+  // its locale, so every variant is analyzed with the matching analyzer
+  private buildLocalizedFields(document: any): { i18n: Record<string, any>; availableLocales: string[] } {
+    const i18n: Record<string, any> = {}
+
+    const ownLocale = toSearchLocale(document.language)
+    if (ownLocale) {
+      i18n[ownLocale] = { title: document.title, description: document.description }
+    }
+
+    for (const [tag, text] of Object.entries(document.translations || {})) {
+      const locale = toSearchLocale(tag)
+      // Regional variants share an analyzer; the first one seen wins
+      if (locale && !i18n[locale]) {
+        i18n[locale] = { title: (text as any).title, description: (text as any).description }
+      }
+    }
+
+    return { i18n, availableLocales: Object.keys(i18n) }
+  }
+
   private extractSearchableContent(document: any, type: SearchType): string {
     const content: string[] = []
 
@@ -6,6 +6,7 @@ export interface SearchRequest {
   pagination?: Pagination
   highlight?: boolean
   suggestions?: boolean
+  locales?: string[] // preferred search locales, most preferred first
 }
 
 export enum SearchType {
@@ -25,6 +26,7 @@ export interface SearchFilters {
   price?: PriceRange
   rating?: number
   language?: string[]
+  locales?: string[]
   tags?: string[]
   dateRange?: DateRange
   author?: string
@@ -65,6 +67,7 @@ export interface SearchResponse<T = any> {
   took: number
   suggestions?: string[]
   facets?: SearchFacets
+  locale?: string
 }
 
 export interface SearchResult<T = any> {
@@ -82,6 +85,7 @@ export interface SearchFacets {
   price?: FacetBucket[]
   rating?: FacetBucket[]
   tags?: FacetBucket[]
+  locales?: FacetBucket[]
 }
 
 export interface FacetBucket {
@@ -107,6 +111,7 @@ export interface CourseDocument {
   reviewCount: number
   enrollmentCount: number
   language: string
+  translations?: Record<string, LocalizedText>
   tags: string[]
   skills: string[]
   prerequisites: string[]
@@ -117,6 +122,11 @@ export interface CourseDocument {
   publishedAt?: Date
 }
 
+export interface LocalizedText {
+  title: string
+  description?: string
+}
+
 export interface LessonDocument {
   id: string
   courseId: string
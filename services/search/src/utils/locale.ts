@@ -0,0 +1,93 @@
+// Languages with a dedicated analyzer, keyed by the primary language subtag.
+// Values are the Elasticsearch stemmer/stopword language names.
+export const SEARCH_LOCALES: Record<string, string> = {
+  en: 'english',
+  fr: 'french',
+  es: 'spanish',
+  de: 'german',
+  pt: 'portuguese',
+  it: 'italian',
+  nl: 'dutch'
+}
+
+export const DEFAULT_SEARCH_LOCALE = 'en'
+
+// Light stemmers are less aggressive, which keeps short course titles intact
+const STEMMERS: Record<string, string> = {
+  en: 'english',
+  fr: 'light_french',
+  es: 'light_spanish',
+  de: 'light_german',
+  pt: 'light_portuguese',
+  it: 'light_italian',
+  nl: 'dutch'
+}
+
+// Languages whose articles elide onto the next word (l'analyse, dell'arte)
+const ELISION_ARTICLES: Record<string, string[]> = {
+  fr: ['l', 'm', 't', 'qu', 'n', 's', 'j', 'd', 'c', 'jusqu', 'quoiqu', 'lorsqu', 'puisqu'],
+  it: ['c', 'l', 'all', 'dall', 'dell', 'nell', 'sull', 'coll', 'pell', 'gl', 'agl', 'dagl', 'degl', 'negl', 'sugl', 'un', 'm', 't', 's', 'v', 'd']
+}
+
+// Reduce a BCP 47 tag such as "fr-CA" to a supported search locale, or null
+export function toSearchLocale(tag?: string | null): string | null {
+  if (!tag) {
+    return null
+  }
+  const language = tag.trim().replace('_', '-').split('-')[0].toLowerCase()
+  return SEARCH_LOCALES[language] ? language : null
+}
+
+// Supported search locales from an Accept-Language header, most preferred
+// first and without duplicates. Wildcards and q=0 entries are ignored.
+export function parseAcceptLanguage(header?: string): string[] {
+  if (!header) {
+    return []
+  }
+
+  const entries = header
+    .split(',')
+    .map((part, index) => {
+      const [tag, ...params] = part.trim().split(';')
+      const qParam = params.map(p => p.trim()).find(p => p.startsWith('q='))
+      const q = qParam ? parseFloat(qParam.slice(2)) : 1
+      return { locale: toSearchLocale(tag), q: isNaN(q) ? 0 : q, index }
+    })
+    .filter(entry => entry.locale && entry.q > 0)
+    .sort((a, b) => b.q - a.q || a.index - b.index)
+
+  return [...new Set(entries.map(entry => entry.locale as string))]
+}
+
+// Analyzer and token filter definitions for every supported locale. Each
+// locale gets "<locale>_analyzer" for full text and relies on the shared
+// autocomplete tokenizer for prefixes.
+export function buildLocaleAnalysis(): { analyzer: Record<string, any>; filter: Record<string, any> } {
+  const analyzer: Record<string, any> = {}
+  const filter: Record<string, any> = {}
+
+  for (const [locale, language] of Object.entries(SEARCH_LOCALES)) {
+    const filters = ['lowercase']
+
+    if (ELISION_ARTICLES[locale]) {
+      filter[`${locale}_elision`] = {
+        type: 'elision',
+        articles_case: true,
+        articles: ELISION_ARTICLES[locale]
+      }
+      filters.unshift(`${locale}_elision`)
+    }
+
+    filter[`${locale}_stop`] = { type: 'stop', stopwords: `_${language}_` }
+    filter[`${locale}_stemmer`] = { type: 'stemmer', language: STEMMERS[locale] }
+
+    // Fold accents last so the stemmers still see the original spelling
+    analyzer[`${locale}_analyzer`] = {
+      type: 'custom',
+      tokenizer: 'standard',
+      filter: [...filters, `${locale}_stop`, `${locale}_stemmer`, 'asciifolding']
+    }
+  }
+
+  return { analyzer, filter }
+}
@@ -0,0 +1,352 @@
+// Package exports runs exports too slow to build within a request. Clients
+// create a job, poll it and download the result through an expiring link to
+// object storage; files are deleted once their retention has passed. Each
+// service registers the kinds of export it can build.
+package exports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/modex/shared/objectstore"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is returned for unknown jobs and other users' jobs
+	ErrNotFound = errors.New("export not found")
+	// ErrNotReady is returned when downloading a job that hasn't succeeded
+	ErrNotReady = errors.New("export is not ready")
+	// ErrExpired is returned when downloading a job whose file was cleaned up
+	ErrExpired = errors.New("export has expired")
+	// ErrNotConfigured is returned when no export bucket or credentials are set
+	ErrNotConfigured = objectstore.ErrNotConfigured
+)
+
+const (
+	// DefaultRetention applies when Options.Retention is zero
+	DefaultRetention = 24 * time.Hour
+	// DefaultLinkTTL applies when Options.LinkTTL is zero
+	DefaultLinkTTL = 15 * time.Minute
+	// Timeout bounds one export; jobs still running after it are failed by Cleanup
+	Timeout = 30 * time.Minute
+
+	defaultWorkers  = 2
+	objectKeyPrefix = "exports"
+	cleanupBatch    = 500
+)
+
+// slots caps how many exports build at once across the process. EXPORT_WORKERS
+// sets the cap.
+var slots = make(chan struct{}, workers())
+
+// Status tracks an export job
+type Status string
+
+const (
+	Pending   Status = "pending"
+	Running   Status = "running"
+	Succeeded Status = "succeeded"
+	Failed    Status = "failed"
+	Expired   Status = "expired" // file deleted after the retention period
+)
+
+// Job is an export built in the background and uploaded to object storage,
+// where it is kept until ExpiresAt
+type Job struct {
+	ID          uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Kind        string                 `gorm:"type:varchar(50);not null" json:"kind"`
+	Status      Status                 `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	RequestedBy uuid.UUID              `gorm:"type:uuid;not null;index" json:"requestedBy"`
+	Params      map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"params,omitempty"`
+	ObjectKey   string                 `gorm:"type:varchar(500)" json:"-"`
+	FileName    string                 `gorm:"type:varchar(255)" json:"fileName,omitempty"`
+	ContentType string                 `gorm:"type:varchar(100)" json:"contentType,omitempty"`
+	SizeBytes   int64                  `gorm:"not null;default:0" json:"sizeBytes"`
+	Error       string                 `gorm:"type:text" json:"error,omitempty"`
+	StartedAt   *time.Time             `gorm:"type:timestamp" json:"startedAt,omitempty"`
+	CompletedAt *time.Time             `gorm:"type:timestamp" json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time             `gorm:"type:timestamp;index" json:"expiresAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp" json:"updatedAt"`
+}
+
+func (Job) TableName() string {
+	return "export_jobs"
+}
+
+// Exporter writes one kind of export to w and names the file it produced
+type Exporter func(ctx context.Context, job *Job, w io.Writer) (fileName, contentType string, err error)
+
+// Logger receives the service's log lines about exports
+type Logger interface {
+	Info(message string, fields map[string]interface{})
+	Error(message string, fields map[string]interface{})
+}
+
+// Options configures a Service
+type Options struct {
+	Retention time.Duration // how long files are kept; DefaultRetention when zero
+	LinkTTL   time.Duration // how long download links last; DefaultLinkTTL when zero
+	Logger    Logger        // the standard logger when nil
+}
+
+// Service creates, builds and cleans up export jobs
+type Service struct {
+	db        *gorm.DB
+	store     *objectstore.Store
+	exporters map[string]Exporter
+	retention time.Duration
+	linkTTL   time.Duration
+	logger    Logger
+}
+
+// NewService creates a Service that builds the given kinds of export and
+// stores them in store
+func NewService(db *gorm.DB, store *objectstore.Store, exporters map[string]Exporter, opts Options) *Service {
+	s := &Service{
+		db:        db,
+		store:     store,
+		exporters: exporters,
+		retention: opts.Retention,
+		linkTTL:   opts.LinkTTL,
+		logger:    opts.Logger,
+	}
+	if s.retention <= 0 {
+		s.retention = DefaultRetention
+	}
+	if s.linkTTL <= 0 {
+		s.linkTTL = DefaultLinkTTL
+	}
+	if s.logger == nil {
+		s.logger = stdLogger{}
+	}
+	return s
+}
+
+// Create records a job and starts building it in the background
+func (s *Service) Create(kind string, requestedBy uuid.UUID, params map[string]interface{}) (*Job, error) {
+	if _, ok := s.exporters[kind]; !ok {
+		return nil, fmt.Errorf("unknown export kind %q", kind)
+	}
+	if !s.store.Configured() {
+		return nil, ErrNotConfigured
+	}
+
+	job := &Job{
+		Kind:        kind,
+		Status:      Pending,
+		RequestedBy: requestedBy,
+		Params:      params,
+	}
+	if err := s.db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create export job: %w", err)
+	}
+
+	go s.run(job.ID)
+	return job, nil
+}
+
+// Get returns a job to the user who requested it, or to an admin
+func (s *Service) Get(jobID, userID uuid.UUID, isAdmin bool) (*Job, error) {
+	var job Job
+	if err := s.db.First(&job, jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get export job: %w", err)
+	}
+	if job.RequestedBy != userID && !isAdmin {
+		return nil, ErrNotFound
+	}
+	return &job, nil
+}
+
+// DownloadURL returns an expiring link to a finished export's file
+func (s *Service) DownloadURL(job *Job) (string, time.Time, error) {
+	switch {
+	case job.Status == Expired:
+		return "", time.Time{}, ErrExpired
+	case job.Status != Succeeded:
+		return "", time.Time{}, ErrNotReady
+	}
+
+	ttl := s.linkTTL
+	if job.ExpiresAt != nil {
+		remaining := time.Until(*job.ExpiresAt)
+		if remaining <= 0 {
+			return "", time.Time{}, ErrExpired
+		}
+		if remaining < ttl {
+			ttl = remaining
+		}
+	}
+
+	link, err := s.store.PresignGet(job.ObjectKey, job.FileName, ttl)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return link, time.Now().Add(ttl), nil
+}
+
+// Cleanup fails jobs that have run past Timeout, such as those whose replica
+// stopped mid-export, and deletes files past their retention. It returns how
+// many files it deleted.
+func (s *Service) Cleanup(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+
+	if err := s.db.Model(&Job{}).
+		Where("status IN ? AND created_at < ?", []Status{Pending, Running}, now.Add(-Timeout)).
+		Updates(map[string]interface{}{"status": Failed, "error": "export timed out", "completed_at": now}).Error; err != nil {
+		return 0, fmt.Errorf("failed to fail timed-out exports: %w", err)
+	}
+
+	var jobs []Job
+	if err := s.db.Where("status = ? AND expires_at <= ?", Succeeded, now).
+		Limit(cleanupBatch).Find(&jobs).Error; err != nil {
+		return 0, fmt.Errorf("failed to list expired exports: %w", err)
+	}
+
+	expired := 0
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.store.Delete(ctx, job.ObjectKey); err != nil {
+			s.logger.Error("Failed to delete expired export", map[string]interface{}{
+				"error": err.Error(),
+				"jobID": job.ID,
+			})
+			continue
+		}
+		s.db.Model(&job).Update("status", Expired)
+		expired++
+	}
+	return expired, nil
+}
+
+func (s *Service) run(jobID uuid.UUID) {
+	slots <- struct{}{}
+	defer func() { <-slots }()
+
+	now := time.Now().UTC()
+	claimed := s.db.Model(&Job{}).
+		Where("id = ? AND status = ?", jobID, Pending).
+		Updates(map[string]interface{}{"status": Running, "started_at": now})
+	if claimed.Error != nil || claimed.RowsAffected == 0 {
+		return
+	}
+
+	var job Job
+	if err := s.db.First(&job, jobID).Error; err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	if err := s.build(ctx, &job); err != nil {
+		s.logger.Error("Export failed", map[string]interface{}{
+			"error": err.Error(),
+			"jobID": job.ID,
+			"kind":  job.Kind,
+		})
+		s.db.Model(&job).Updates(map[string]interface{}{
+			"status":       Failed,
+			"error":        err.Error(),
+			"completed_at": time.Now().UTC(),
+		})
+		return
+	}
+
+	s.logger.Info("Export finished", map[string]interface{}{
+		"jobID":     job.ID,
+		"kind":      job.Kind,
+		"sizeBytes": job.SizeBytes,
+	})
+}
+
+// build writes the export to a temporary file, since uploads need the size
+// up front, then uploads it and marks the job succeeded
+func (s *Service) build(ctx context.Context, job *Job) error {
+	file, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	fileName, contentType, err := s.exporters[job.Kind](ctx, job, file)
+	if err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s/%s/%s/%s", objectKeyPrefix, job.Kind, job.ID, fileName)
+	if err := s.store.Put(ctx, key, contentType, file, size); err != nil {
+		return err
+	}
+
+	completedAt := time.Now().UTC()
+	expiresAt := completedAt.Add(s.retention)
+	job.Status = Succeeded
+	job.ObjectKey = key
+	job.FileName = fileName
+	job.ContentType = contentType
+	job.SizeBytes = size
+	job.CompletedAt = &completedAt
+	job.ExpiresAt = &expiresAt
+
+	return s.db.Model(job).Updates(map[string]interface{}{
+		"status":       job.Status,
+		"object_key":   key,
+		"file_name":    fileName,
+		"content_type": contentType,
+		"size_bytes":   size,
+		"completed_at": completedAt,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+// ParamUUID reads a UUID the job was created with
+func ParamUUID(job *Job, name string) (uuid.UUID, error) {
+	raw, _ := job.Params[name].(string)
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("export job is missing a valid %s", name)
+	}
+	return id, nil
+}
+
+func workers() int {
+	if workers, err := strconv.Atoi(os.Getenv("EXPORT_WORKERS")); err == nil && workers > 0 {
+		return workers
+	}
+	return defaultWorkers
+}
+
+// stdLogger writes to the standard logger
+type stdLogger struct{}
+
+func (stdLogger) Info(message string, fields map[string]interface{}) {
+	log.Printf("%s %v", message, fields)
+}
+
+func (stdLogger) Error(message string, fields map[string]interface{}) {
+	log.Printf("%s %v", message, fields)
+}
@@ -0,0 +1,186 @@
+// Package idempotency replays the stored response of a POST request whose
+// Idempotency-Key has been seen before, so network retries don't create
+// duplicates. It is shared by the Go services so they behave the same way.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultTTL is how long a stored response is replayed for
+const DefaultTTL = 24 * time.Hour
+
+const (
+	header     = "Idempotency-Key"
+	lockTTL    = 30 * time.Second
+	maxKeySize = 255
+)
+
+// Options configures the middleware
+type Options struct {
+	// Client returns the Redis client responses are stored in. It is called
+	// per request so services can connect after registering routes.
+	Client func() *redis.Client
+	// TTL is how long responses are kept; DefaultTTL when zero
+	TTL time.Duration
+	// OnStoreError reports Redis failures. Requests still go through.
+	OnStoreError func(message string, err error)
+}
+
+// storedResponse is the response replayed for repeated keys
+type storedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType"`
+	Body        []byte `json:"body"`
+	RequestHash string `json:"requestHash"`
+}
+
+// bodyRecorder captures the response body while still writing it to the client
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Middleware honors the Idempotency-Key header on POST requests. The first
+// response for a key is stored for the TTL and replayed verbatim for any
+// retry carrying the same key and payload. Keys are scoped to the caller's
+// user_id, or their IP when unauthenticated.
+func Middleware(opts Options) gin.HandlerFunc {
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	onStoreError := opts.OnStoreError
+	if onStoreError == nil {
+		onStoreError = func(string, error) {}
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(header)
+		if key == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxKeySize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(sum[:])
+
+		client := opts.Client()
+		ctx := context.Background()
+		scope := c.GetString("user_id")
+		if scope == "" {
+			scope = c.ClientIP()
+		}
+		cacheKey := fmt.Sprintf("idempotency:%s:%s:%s", scope, c.FullPath(), key)
+		lockKey := cacheKey + ":lock"
+
+		// Replay a stored response if this key has been seen before
+		if replay(c, client, cacheKey, requestHash) {
+			return
+		}
+
+		acquired, err := client.SetNX(ctx, lockKey, requestHash, lockTTL).Result()
+		if err != nil {
+			// Without Redis we can't guarantee idempotency; let the request through
+			onStoreError("Idempotency store unavailable", err)
+			c.Next()
+			return
+		}
+		if !acquired {
+			c.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is already in progress"})
+			c.Abort()
+			return
+		}
+		defer client.Del(ctx, lockKey)
+
+		// The request holding the lock before us may have stored its response
+		// and released the lock between our first read and taking the lock
+		if replay(c, client, cacheKey, requestHash) {
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Server errors are not stored so that a retry can still succeed
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		data, err := json.Marshal(storedResponse{
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			RequestHash: requestHash,
+		})
+		if err != nil {
+			return
+		}
+
+		if err := client.Set(ctx, cacheKey, data, ttl).Err(); err != nil {
+			onStoreError(fmt.Sprintf("Failed to store idempotent response for key %s", key), err)
+		}
+	}
+}
+
+// replay writes the response stored under cacheKey, or rejects the request
+// if the key was used with a different payload. It reports whether the
+// request was answered.
+func replay(c *gin.Context, client *redis.Client, cacheKey, requestHash string) bool {
+	data, err := client.Get(context.Background(), cacheKey).Bytes()
+	if err != nil {
+		return false
+	}
+
+	var stored storedResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return false
+	}
+	if stored.RequestHash != requestHash {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request payload"})
+		c.Abort()
+		return true
+	}
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(stored.Status, stored.ContentType, stored.Body)
+	c.Abort()
+	return true
+}
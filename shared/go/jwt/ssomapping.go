@@ -0,0 +1,133 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// roleRank orders platform roles; the highest role any rule grants wins
+var roleRank = map[string]int{
+	"student":    0,
+	"instructor": 1,
+	"admin":      2,
+}
+
+// SSOMappingRule matches an IdP claim value to a Modex role and/or organization
+type SSOMappingRule struct {
+	Claim          string `json:"claim"`
+	Value          string `json:"value"`
+	Role           string `json:"role,omitempty"`
+	OrganizationID string `json:"organizationId,omitempty"`
+}
+
+// SSOMapping maps SSO/OIDC claims such as groups and departments to roles
+// and organizations. It reads the same SSO_CLAIM_MAPPING configuration as the
+// API gateway.
+type SSOMapping struct {
+	Rules       []SSOMappingRule `json:"rules"`
+	DefaultRole string           `json:"defaultRole"`
+}
+
+var (
+	defaultMapping     *SSOMapping
+	defaultMappingOnce sync.Once
+)
+
+// DefaultSSOMapping returns the mapping configured by SSO_CLAIM_MAPPING
+// (inline JSON) or SSO_CLAIM_MAPPING_FILE, loaded on first use
+func DefaultSSOMapping() *SSOMapping {
+	defaultMappingOnce.Do(func() {
+		defaultMapping = LoadSSOMapping()
+	})
+	return defaultMapping
+}
+
+// LoadSSOMapping reads the mapping from the environment. Invalid rules are
+// skipped, and an unreadable mapping maps everyone to student.
+func LoadSSOMapping() *SSOMapping {
+	empty := &SSOMapping{DefaultRole: "student"}
+
+	raw := os.Getenv("SSO_CLAIM_MAPPING")
+	if raw == "" {
+		if path := os.Getenv("SSO_CLAIM_MAPPING_FILE"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				log.Printf("Failed to read SSO claim mapping file: %v", err)
+				return empty
+			}
+			raw = string(data)
+		}
+	}
+	if raw == "" {
+		return empty
+	}
+
+	var parsed struct {
+		Rules       []SSOMappingRule `json:"rules"`
+		DefaultRole interface{}      `json:"defaultRole"`
+	}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		log.Printf("Invalid SSO claim mapping JSON: %v", err)
+		return empty
+	}
+
+	mapping := &SSOMapping{DefaultRole: "student"}
+	if role, ok := parsed.DefaultRole.(string); ok && isPlatformRole(role) {
+		mapping.DefaultRole = role
+	}
+	for _, rule := range parsed.Rules {
+		if rule.Claim == "" || rule.Value == "" || (rule.Role != "" && !isPlatformRole(rule.Role)) {
+			log.Printf("Ignoring invalid SSO claim mapping rule: %+v", rule)
+			continue
+		}
+		mapping.Rules = append(mapping.Rules, rule)
+	}
+	return mapping
+}
+
+// Apply sets claims' role and organization from the rules their raw claims
+// match. Values compare case-insensitively, the highest matching role wins
+// and the first matching organization wins.
+func (m *SSOMapping) Apply(claims *Claims) {
+	role := m.DefaultRole
+	organizationID := ""
+	for _, rule := range m.Rules {
+		if !claimMatches(claims.Raw[rule.Claim], rule.Value) {
+			continue
+		}
+		if rule.Role != "" && roleRank[rule.Role] > roleRank[role] {
+			role = rule.Role
+		}
+		if rule.OrganizationID != "" && organizationID == "" {
+			organizationID = rule.OrganizationID
+		}
+	}
+
+	claims.Role = role
+	if organizationID != "" {
+		claims.OrganizationID = organizationID
+	}
+}
+
+func isPlatformRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
+
+// claimMatches reports whether a claim, a single value or a list, holds value
+func claimMatches(claim interface{}, value string) bool {
+	values, ok := claim.([]interface{})
+	if !ok {
+		values = []interface{}{claim}
+	}
+	for _, v := range values {
+		if v != nil && strings.EqualFold(fmt.Sprint(v), value) {
+			return true
+		}
+	}
+	return false
+}
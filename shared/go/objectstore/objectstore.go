@@ -0,0 +1,277 @@
+// Package objectstore keeps generated files, such as data exports, in S3.
+package objectstore
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured is returned when no bucket or credentials are set
+var ErrNotConfigured = errors.New("object storage is not configured")
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Store is a minimal S3 client covering what exports need: upload,
+// expiring download links and delete. Requests are signed with AWS
+// Signature Version 4. S3_ENDPOINT points it at an S3-compatible store such
+// as MinIO, addressed path-style.
+type Store struct {
+	bucket    string
+	region    string
+	endpoint  string
+	pathStyle bool
+	accessKey string
+	secretKey string
+	client    *http.Client
+}
+
+// New creates a Store for bucket using the AWS_* environment
+func New(bucket string) *Store {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	store := &Store{
+		bucket:    bucket,
+		region:    region,
+		accessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		client:    &http.Client{Timeout: 5 * time.Minute},
+	}
+	if endpoint := os.Getenv("S3_ENDPOINT"); endpoint != "" {
+		store.endpoint = strings.TrimRight(endpoint, "/")
+		store.pathStyle = true
+	} else {
+		store.endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
+	}
+	return store
+}
+
+// Configured reports whether the store has a bucket and credentials
+func (s *Store) Configured() bool {
+	return s.bucket != "" && s.accessKey != "" && s.secretKey != ""
+}
+
+// Put uploads size bytes from body to key
+func (s *Store) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	if !s.Configured() {
+		return ErrNotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, time.Now().UTC())
+
+	return s.send(req, "upload")
+}
+
+// Delete removes key; deleting a missing object is not an error
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if !s.Configured() {
+		return ErrNotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, time.Now().UTC())
+
+	return s.send(req, "delete")
+}
+
+// PresignGet returns a link that downloads key as fileName until ttl passes
+func (s *Store) PresignGet(key, fileName string, ttl time.Duration) (string, error) {
+	if !s.Configured() {
+		return "", ErrNotConfigured
+	}
+
+	now := time.Now().UTC()
+	u, err := url.Parse(s.objectURL(key))
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.accessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", fmt.Sprint(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if fileName != "" {
+		query.Set("response-content-disposition", contentDisposition(fileName))
+	}
+
+	canonicalQuery := canonicalQueryString(query)
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(now, canonical)
+	return u.String(), nil
+}
+
+func (s *Store) send(req *http.Request, action string) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("object %s failed: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 && !(req.Method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("object %s failed: %s: %s", action, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (s *Store) objectURL(key string) string {
+	path := "/" + uriEncode(key, false)
+	if s.pathStyle {
+		path = "/" + uriEncode(s.bucket, true) + path
+	}
+	return s.endpoint + path
+}
+
+// sign adds a SigV4 Authorization header, leaving the payload unsigned so
+// the body can be streamed
+func (s *Store) sign(req *http.Request, now time.Time) {
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQueryString(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, s.scope(now), signedHeaders, s.signature(now, canonical)))
+}
+
+func (s *Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+func (s *Store) signature(now time.Time, canonicalRequest string) string {
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		s.scope(now),
+		hex.EncodeToString(hashed[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// contentDisposition builds an attachment header for fileName. Clients that
+// understand RFC 5987 use filename*, which carries the name exactly; others
+// get a quoted ASCII fallback with quotes and backslashes escaped and any
+// other unsafe character replaced.
+func contentDisposition(fileName string) string {
+	var fallback strings.Builder
+	for _, r := range fileName {
+		switch {
+		case r == '"' || r == '\\':
+			fallback.WriteByte('\\')
+			fallback.WriteRune(r)
+		case r < 0x20 || r >= 0x7f:
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback.String(), uriEncode(fileName, true))
+}
+
+// uriEncode percent-encodes everything but RFC 3986 unreserved characters,
+// and slashes too when encodeSlash is set, as SigV4 requires
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
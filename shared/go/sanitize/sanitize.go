@@ -0,0 +1,272 @@
+// Package sanitize cleans untrusted HTML written by instructors and students
+// before it is shown to other users.
+package sanitize
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedTags maps each tag the sanitizer keeps to the attributes it may carry.
+// Tags not listed are unwrapped (their text is kept) unless they appear in
+// droppedTags.
+var allowedTags = map[string][]string{
+	"p": nil, "br": nil, "hr": nil, "div": nil, "span": nil,
+	"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil,
+	"strong": nil, "b": nil, "em": nil, "i": nil, "u": nil, "s": nil, "del": nil, "ins": nil,
+	"sub": nil, "sup": nil, "mark": nil, "small": nil, "kbd": nil,
+	"blockquote": nil, "pre": nil, "code": {"class"},
+	"ul": nil, "ol": {"start"}, "li": nil, "dl": nil, "dt": nil, "dd": nil,
+	"table": nil, "caption": nil, "thead": nil, "tbody": nil, "tfoot": nil, "tr": nil,
+	"th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+	"figure": nil, "figcaption": nil, "details": nil, "summary": nil,
+	"abbr":   {"title"},
+	"a":      {"href", "title"},
+	"img":    {"src", "alt", "title", "width", "height"},
+	"iframe": {"src", "width", "height", "title"},
+}
+
+// droppedTags are removed together with everything inside them
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"object": true, "embed": true, "applet": true, "svg": true, "math": true,
+	"head": true, "title": true, "textarea": true, "select": true, "button": true,
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+var (
+	codeClassPattern = regexp.MustCompile(`^language-[a-zA-Z0-9_+#-]{1,30}$`)
+	dimensionPattern = regexp.MustCompile(`^[0-9]{1,4}%?$`)
+	spanPattern      = regexp.MustCompile(`^[0-9]{1,3}$`)
+)
+
+// Policy controls what the sanitizer lets through beyond the static
+// tag allowlist.
+type Policy struct {
+	// EmbedHosts are the hosts iframes may load from. Iframes pointing
+	// anywhere else are removed.
+	EmbedHosts map[string]bool
+	// ImageProxyURL, when set, is prefixed to the escaped URL of every
+	// external image so learners' browsers never fetch third-party hosts.
+	ImageProxyURL string
+}
+
+// SanitizeHTML rewrites untrusted HTML so only allowlisted tags and
+// attributes remain, with URLs restricted to safe schemes.
+func (p *Policy) SanitizeHTML(input string) string {
+	var out strings.Builder
+	var open []string
+	tokenizer := html.NewTokenizer(strings.NewReader(input))
+
+	// Name and nesting depth of a dropped element being skipped
+	skipTag, skipDepth := "", 0
+
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or a malformed tail the tokenizer can't recover from
+			break
+		}
+		token := tokenizer.Token()
+
+		if skipDepth > 0 {
+			switch {
+			case tt == html.StartTagToken && token.Data == skipTag:
+				skipDepth++
+			case tt == html.EndTagToken && token.Data == skipTag:
+				skipDepth--
+			}
+			continue
+		}
+
+		switch tt {
+		case html.TextToken:
+			out.WriteString(html.EscapeString(token.Data))
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedTags[token.Data] {
+				if tt == html.StartTagToken {
+					skipTag, skipDepth = token.Data, 1
+				}
+				continue
+			}
+			if token.Data == "iframe" {
+				// Iframe content is fallback text, never rendered
+				if tt == html.StartTagToken {
+					skipTag, skipDepth = token.Data, 1
+				}
+				if embed, ok := p.sanitizeEmbed(token); ok {
+					out.WriteString(embed)
+				}
+				continue
+			}
+			attrs, ok := allowedTags[token.Data]
+			if !ok {
+				continue
+			}
+			out.WriteString(p.renderStartTag(token, attrs))
+			if !voidTags[token.Data] && tt == html.StartTagToken {
+				open = append(open, token.Data)
+			}
+
+		case html.EndTagToken:
+			// Close back to the matching open tag, ignoring strays
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != token.Data {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					out.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		out.WriteString("</" + open[i] + ">")
+	}
+	return out.String()
+}
+
+func (p *Policy) renderStartTag(token html.Token, allowed []string) string {
+	var b strings.Builder
+	b.WriteString("<" + token.Data)
+
+	external := false
+	for _, attr := range token.Attr {
+		if !containsString(allowed, attr.Key) {
+			continue
+		}
+		value := strings.TrimSpace(attr.Val)
+
+		switch attr.Key {
+		case "href":
+			var ok bool
+			if value, external, ok = SafeURL(value, true); !ok {
+				continue
+			}
+		case "src":
+			var ok bool
+			if value, external, ok = SafeURL(value, false); !ok {
+				continue
+			}
+			if external {
+				value = p.ProxyImage(value)
+			}
+		case "class":
+			if !codeClassPattern.MatchString(value) {
+				continue
+			}
+		case "width", "height":
+			if !dimensionPattern.MatchString(value) {
+				continue
+			}
+		case "colspan", "rowspan", "start":
+			if !spanPattern.MatchString(value) {
+				continue
+			}
+		}
+		writeAttr(&b, attr.Key, value)
+	}
+
+	switch token.Data {
+	case "a":
+		if external {
+			writeAttr(&b, "target", "_blank")
+			writeAttr(&b, "rel", "nofollow noopener noreferrer")
+		}
+	case "img":
+		writeAttr(&b, "loading", "lazy")
+	}
+
+	b.WriteString(">")
+	return b.String()
+}
+
+// sanitizeEmbed keeps an iframe only when it loads over https from an
+// allowed host, and sandboxes it.
+func (p *Policy) sanitizeEmbed(token html.Token) (string, bool) {
+	var src string
+	for _, attr := range token.Attr {
+		if attr.Key == "src" {
+			src = strings.TrimSpace(attr.Val)
+		}
+	}
+	u, err := url.Parse(src)
+	if err != nil || u.Scheme != "https" || !p.EmbedHosts[strings.ToLower(u.Hostname())] {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString("<iframe")
+	writeAttr(&b, "src", u.String())
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "width", "height":
+			if dimensionPattern.MatchString(attr.Val) {
+				writeAttr(&b, attr.Key, attr.Val)
+			}
+		case "title":
+			writeAttr(&b, attr.Key, attr.Val)
+		}
+	}
+	writeAttr(&b, "sandbox", "allow-scripts allow-same-origin allow-presentation")
+	writeAttr(&b, "allow", "fullscreen; picture-in-picture")
+	writeAttr(&b, "referrerpolicy", "strict-origin-when-cross-origin")
+	writeAttr(&b, "loading", "lazy")
+	b.WriteString("></iframe>")
+	return b.String(), true
+}
+
+// ProxyImage routes an external image URL through ImageProxyURL when one is set
+func (p *Policy) ProxyImage(src string) string {
+	if p.ImageProxyURL == "" || strings.HasPrefix(src, p.ImageProxyURL) {
+		return src
+	}
+	return p.ImageProxyURL + url.QueryEscape(src)
+}
+
+// SafeURL accepts relative URLs and absolute http(s) URLs, plus mailto when
+// allowMailto is set. It reports whether the URL points off-site.
+func SafeURL(raw string, allowMailto bool) (string, bool, bool) {
+	if raw == "" {
+		return "", false, false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false, false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "":
+		// Reject protocol-relative URLs, which are external in disguise
+		if strings.HasPrefix(raw, "//") {
+			return "", false, false
+		}
+		return u.String(), false, true
+	case "http", "https":
+		return u.String(), true, true
+	case "mailto":
+		return u.String(), false, allowMailto
+	default:
+		return "", false, false
+	}
+}
+
+func writeAttr(b *strings.Builder, key, value string) {
+	b.WriteString(" " + key + `="` + html.EscapeString(value) + `"`)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
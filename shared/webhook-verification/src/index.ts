@@ -0,0 +1,3 @@
+export * from './verifier'
+export * from './nonce-store'
+export * from './middleware'
@@ -0,0 +1,31 @@
+import { Request, Response, NextFunction } from 'express'
+import { VerifiedWebhook, WebhookVerificationError, WebhookVerifier } from './verifier'
+
+export interface WebhookRequest extends Request {
+  webhook?: VerifiedWebhook
+}
+
+// Rejects webhooks that fail verification before they reach the handler.
+// The route must receive the raw body, e.g. via express.raw(), since
+// signatures are computed over the exact bytes sent.
+export function createWebhookVerificationMiddleware(verifier: WebhookVerifier) {
+  return async (req: WebhookRequest, res: Response, next: NextFunction) => {
+    if (!Buffer.isBuffer(req.body) && typeof req.body !== 'string') {
+      next(new Error('Webhook verification needs the raw request body'))
+      return
+    }
+
+    try {
+      req.webhook = await verifier.verify(req.body, req.headers)
+      next()
+    } catch (error) {
+      if (error instanceof WebhookVerificationError) {
+        // A replay was already handled once; anything else wasn't sent by the provider
+        const status = error.reason === 'replayed' ? 409 : 400
+        res.status(status).json({ error: error.message, reason: error.reason })
+        return
+      }
+      next(error)
+    }
+  }
+}
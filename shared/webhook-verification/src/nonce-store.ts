@@ -0,0 +1,44 @@
+// Remembers nonces of webhooks already accepted, so a captured request
+// can't be replayed while its timestamp is still within tolerance
+export interface NonceStore {
+  // Records the nonce and returns true, or returns false if it was already seen
+  claim(key: string, ttlSeconds: number): Promise<boolean>
+}
+
+// The subset of node-redis and ioredis clients the Redis store needs
+export interface RedisLike {
+  set(key: string, value: string, ...args: any[]): Promise<any>
+}
+
+// Stores nonces in Redis so every replica of a service shares them. Both
+// node-redis ({ NX, EX } options) and ioredis ('EX', ttl, 'NX') are supported.
+export class RedisNonceStore implements NonceStore {
+  constructor(private client: RedisLike, private flavor: 'node-redis' | 'ioredis' = 'node-redis') {}
+
+  async claim(key: string, ttlSeconds: number): Promise<boolean> {
+    const result = this.flavor === 'ioredis'
+      ? await this.client.set(key, '1', 'EX', ttlSeconds, 'NX')
+      : await this.client.set(key, '1', { NX: true, EX: ttlSeconds })
+    return result === 'OK'
+  }
+}
+
+// Keeps nonces in process memory, for development and single-replica services
+export class MemoryNonceStore implements NonceStore {
+  private seen = new Map<string, number>()
+
+  async claim(key: string, ttlSeconds: number): Promise<boolean> {
+    const now = Date.now()
+    for (const [seenKey, expiresAt] of this.seen) {
+      if (expiresAt <= now) {
+        this.seen.delete(seenKey)
+      }
+    }
+
+    if (this.seen.has(key)) {
+      return false
+    }
+    this.seen.set(key, now + ttlSeconds * 1000)
+    return true
+  }
+}
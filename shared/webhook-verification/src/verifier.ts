@@ -0,0 +1,166 @@
+import { createHmac, timingSafeEqual } from 'crypto'
+import { MemoryNonceStore, NonceStore } from './nonce-store'
+
+export type WebhookFailureReason =
+  | 'missing_signature'
+  | 'invalid_signature'
+  | 'timestamp_out_of_tolerance'
+  | 'replayed'
+
+export class WebhookVerificationError extends Error {
+  constructor(public reason: WebhookFailureReason, message: string) {
+    super(message)
+    this.name = 'WebhookVerificationError'
+  }
+}
+
+export type Headers = Record<string, string | string[] | undefined>
+
+// What a scheme found in a request's headers
+export interface ParsedSignature {
+  timestamp?: number
+  signatures: string[]
+  id?: string // a delivery ID the sender signed along with the body
+}
+
+// A signature scheme pulls the timestamp, candidate signatures and any signed
+// delivery ID out of a request's headers and says what was signed
+export interface SignatureScheme {
+  parse(headers: Headers): ParsedSignature | null
+  signedPayload(parsed: ParsedSignature, body: string): string
+}
+
+// Stripe's `Stripe-Signature: t=<unix>,v1=<hex>[,v1=<hex>]` over `<t>.<body>`
+export const stripeScheme = (header: string = 'stripe-signature'): SignatureScheme => ({
+  parse(headers) {
+    const value = headerValue(headers, header)
+    if (!value) {
+      return null
+    }
+
+    let timestamp: number | undefined
+    const signatures: string[] = []
+    for (const part of value.split(',')) {
+      const [key, val] = part.split('=', 2).map((s) => s.trim())
+      if (key === 't') {
+        timestamp = parseInt(val)
+      } else if (key === 'v1' && val) {
+        signatures.push(val)
+      }
+    }
+    return { timestamp, signatures }
+  },
+  signedPayload: (parsed, body) => `${parsed.timestamp}.${body}`
+})
+
+export interface HmacSchemeOptions {
+  signatureHeader?: string // hex HMAC-SHA256, optionally prefixed with `sha256=`
+  timestampHeader?: string // unix seconds
+  idHeader?: string        // a unique delivery ID
+}
+
+// The scheme Modex asks providers without their own to use: an HMAC-SHA256
+// of `<id>.<timestamp>.<body>` with the delivery ID and timestamp in headers.
+// The signed delivery ID is the nonce, so retries of one delivery are caught
+// however often they are re-signed. Providers that send no ID sign
+// `<timestamp>.<body>`.
+export const hmacScheme = (options: HmacSchemeOptions = {}): SignatureScheme => {
+  const signatureHeader = options.signatureHeader || 'x-webhook-signature'
+  const timestampHeader = options.timestampHeader || 'x-webhook-timestamp'
+  const idHeader = options.idHeader || 'x-webhook-id'
+
+  return {
+    parse(headers) {
+      const signature = headerValue(headers, signatureHeader)
+      if (!signature) {
+        return null
+      }
+      const timestamp = headerValue(headers, timestampHeader)
+      const id = headerValue(headers, idHeader)
+      return {
+        timestamp: timestamp ? parseInt(timestamp) : undefined,
+        signatures: [signature.replace(/^sha256=/, '')],
+        id
+      }
+    },
+    signedPayload: (parsed, body) =>
+      [parsed.id, parsed.timestamp, body].filter((part) => part !== undefined).join('.')
+  }
+}
+
+export interface WebhookVerifierConfig {
+  name: string // the webhook's source, e.g. 'stripe'; namespaces its nonces
+  secret: string
+  scheme: SignatureScheme
+  toleranceSeconds?: number
+  nonceStore?: NonceStore
+}
+
+export interface VerifiedWebhook {
+  timestamp?: number
+  nonce?: string
+}
+
+const DEFAULT_TOLERANCE_SECONDS = 300
+
+// Verifies inbound webhooks: the signature must match one made with the
+// shared secret, the timestamp must be within tolerance of now, and the
+// nonce must not have been seen before. The nonce is the signed delivery ID
+// when the scheme has one, otherwise the signature we computed ourselves, so
+// re-encoding or padding the signature header can't make a replay look new.
+// Nonces are kept for twice the tolerance, by which point a replay would fail
+// the timestamp check anyway.
+export class WebhookVerifier {
+  private tolerance: number
+  private nonceStore: NonceStore
+
+  constructor(private config: WebhookVerifierConfig) {
+    if (!config.secret) {
+      throw new Error(`A secret is required to verify ${config.name} webhooks`)
+    }
+    this.tolerance = config.toleranceSeconds ?? DEFAULT_TOLERANCE_SECONDS
+    this.nonceStore = config.nonceStore || new MemoryNonceStore()
+  }
+
+  async verify(body: Buffer | string, headers: Headers): Promise<VerifiedWebhook> {
+    const parsed = this.config.scheme.parse(headers)
+    if (!parsed || parsed.signatures.length === 0) {
+      throw new WebhookVerificationError('missing_signature', 'Missing webhook signature')
+    }
+
+    const payload = this.config.scheme.signedPayload(parsed, typeof body === 'string' ? body : body.toString('utf8'))
+    const expected = createHmac('sha256', this.config.secret).update(payload).digest()
+    if (!parsed.signatures.some((signature) => signatureMatches(signature, expected))) {
+      throw new WebhookVerificationError('invalid_signature', 'Webhook signature does not match')
+    }
+
+    if (this.tolerance > 0) {
+      if (parsed.timestamp === undefined || isNaN(parsed.timestamp) ||
+          Math.abs(Date.now() / 1000 - parsed.timestamp) > this.tolerance) {
+        throw new WebhookVerificationError('timestamp_out_of_tolerance', 'Webhook timestamp is outside the allowed tolerance')
+      }
+    }
+
+    const nonce = parsed.id || expected.toString('hex')
+    const ttl = Math.max(this.tolerance * 2, 60)
+    const first = await this.nonceStore.claim(`webhook:nonce:${this.config.name}:${nonce}`, ttl)
+    if (!first) {
+      throw new WebhookVerificationError('replayed', 'Webhook has already been received')
+    }
+
+    return { timestamp: parsed.timestamp, nonce }
+  }
+}
+
+const signatureMatches = (signature: string, expected: Buffer): boolean => {
+  if (!/^[0-9a-f]+$/i.test(signature)) {
+    return false
+  }
+  const actual = Buffer.from(signature, 'hex')
+  return actual.length === expected.length && timingSafeEqual(actual, expected)
+}
+
+const headerValue = (headers: Headers, name: string): string | undefined => {
+  const value = headers[name.toLowerCase()]
+  return Array.isArray(value) ? value[0] : value
+}